@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/ratelimit"
+)
+
+// Config настройки сервиса из файла -config.
+type Config struct {
+	// RateLimits ограничение запросов. Если раздела нет в файле, действуют квоты по умолчанию.
+	RateLimits *ratelimit.Config `json:"rate_limits"`
+}
+
+const (
+	defaultRouteRequests = 600
+	catalogRequests      = 60
+	ipRequests           = 1200
+)
+
+// defaultRateLimits квоты по умолчанию: каталог и рейтинг читают всю таблицу роботов, поэтому ограничены сильнее.
+func defaultRateLimits() *ratelimit.Config {
+	return &ratelimit.Config{
+		Routes: map[string]ratelimit.Quota{
+			ratelimit.DefaultRoute:    {Requests: defaultRouteRequests, Period: time.Minute},
+			"GET /robots":             {Requests: catalogRequests, Period: time.Minute},
+			"GET /robots/leaderboard": {Requests: catalogRequests, Period: time.Minute},
+		},
+		IP: ratelimit.Quota{Requests: ipRequests, Period: time.Minute},
+	}
+}
+
+// LoadConfig читает настройки из файла path, пустой path означает настройки по умолчанию.
+// Неизвестные поля в файле считаются ошибкой, чтобы опечатка не отключала настройку.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read config %s: %s", path, err)
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+
+		if err = dec.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("can't parse config %s: %s", path, err)
+		}
+	}
+
+	if cfg.RateLimits == nil {
+		cfg.RateLimits = defaultRateLimits()
+	}
+
+	return cfg, nil
+}
@@ -1,31 +1,45 @@
 package handlers
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/middleware"
+	"gitlab.com/hitchpock/tfs-course-work/internal/calendar"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
 	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
 	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
 	"gitlab.com/hitchpock/tfs-course-work/web"
 
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+const (
+	quoteTimeout            = time.Second
+	defaultLeaderboardLimit = 10
+	maxLimit                = robot.MaxLimit
+)
+
+var errPriceUnavailable = errors.New("price service is unavailable")
+
 // SignInData структура аунтификации пользователя.
 type SignInData struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
-func errorJSON(stringErr string) string {
-	return fmt.Sprintf("{\n\t\"error\": \"%s\"\n}", stringErr)
-}
-
 // CreatePasswordHash создает хэш пароля пользователя.
 func CreatePasswordHash(password string) (string, error) {
 	pwdHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -39,6 +53,7 @@ func CheckPasswordHash(hash, password string) bool {
 }
 
 // getUserFromBody считывает данные из запроса и возвращает указатель на объект пользователя из тела запроса.
+// Ошибки разбора и проверки полей возвращаются как *validation.Error.
 func getUserFromBody(body io.Reader) (*user.User, error) {
 	b, err := ioutil.ReadAll(body)
 	if err != nil {
@@ -47,7 +62,7 @@ func getUserFromBody(body io.Reader) (*user.User, error) {
 
 	var u user.User
 	if err := u.CreateFromJSON(b); err != nil {
-		return nil, fmt.Errorf("func CreateFromJSON is crashed: %s", err)
+		return nil, err
 	}
 
 	return &u, nil
@@ -55,46 +70,340 @@ func getUserFromBody(body io.Reader) (*user.User, error) {
 
 // getSignInData считывает данные из запроса возвращает указатель на объект для аунтификации.
 func getSignInDataFromBody(body io.Reader) (*SignInData, error) {
-	b, err := ioutil.ReadAll(body)
-	if err != nil {
-		return nil, fmt.Errorf("func ioutil.ReadAll is crashed: %s", err)
-	}
-
 	var u SignInData
-	if err = json.Unmarshal(b, &u); err != nil {
-		return nil, fmt.Errorf("invalid input: %s", err)
+	if err := validation.DecodeJSON(body, &u); err != nil {
+		return nil, err
 	}
 
 	return &u, nil
 }
 
 // getRobotFromBody считывает данные из запроса и возвращает указатель на робота.
+// Поля только для чтения, неизвестные поля и значения неверного типа возвращаются как *validation.Error.
 func getRobotFromBody(body io.Reader) (*robot.Robot, error) {
-	b, err := ioutil.ReadAll(body)
+	var in robotInput
+	if err := validation.DecodeJSON(body, &in); err != nil {
+		return nil, err
+	}
+
+	var r robot.Robot
+	in.apply(&r)
+
+	return &r, nil
+}
+
+// publishRobotChanged сообщает торговому сервису и подписчикам websocket об изменении робота.
+func (h *Handler) publishRobotChanged(robotID int, reqID, remoteAddr string) {
+	if err := h.events.Publish(event.Event{Type: event.RobotChanged, RobotID: robotID}); err != nil {
+		h.logger.Warnw("func events.Publish return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
+	}
+}
+
+// queryLimit читает параметр limit запроса, по умолчанию возвращает def.
+func queryLimit(r *http.Request, def int) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return def, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxLimit {
+		return 0, fmt.Errorf("%w: limit must be between 1 and %d, got %q", robot.ErrInvalidQuery, maxLimit, value)
+	}
+
+	return limit, nil
+}
+
+// robotsView модель html представления страницы роботов со ссылками на первую и следующую страницы.
+type robotsView struct {
+	Robots []robot.Robot
+	First  string
+	Next   string
+}
+
+// findRobots отправляет страницу видимых пользователю роботов по query-параметрам запроса в формате из заголовка Accept.
+// Если ownerUserID задан, выбираются только роботы этого пользователя. Ссылка на следующую страницу передается в заголовке Link.
+func (h *Handler) findRobots(w http.ResponseWriter, r *http.Request, ownerUserID *int) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+
+	format, err := negotiate(w, r, robotListFormats...)
 	if err != nil {
-		return nil, fmt.Errorf("func ioutil.ReadAll is crashed: %s", err)
+		h.fail(w, r, "unsupported response format", err)
+		return
 	}
 
-	if !validQuery(b, []byte("owner_user_id"), []byte("is_favourite"), []byte("is_active")) { //nolint:misspell
-		return nil, errors.New("ivalid input")
+	q, err := robot.ParseQuery(r.URL.Query())
+	if err != nil {
+		h.fail(w, r, "invalid query", err)
+		return
 	}
 
-	var r robot.Robot
-	if err = json.Unmarshal(b, &r); err != nil || r.OwnerUserID == 0 {
-		return nil, fmt.Errorf("invalid input: %s", err)
+	if ownerUserID != nil {
+		q.OwnerUserID = ownerUserID
 	}
 
-	return &r, nil
+	token := r.Context().Value(tokenKey{}).(string)
+	sessionToken, _ := session.DecodeToken(token)
+	q.ViewerID = &sessionToken.UserID
+
+	page, err := h.robotStorage.Find(q)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.Find return with error", err)
+		return
+	}
+
+	values := r.URL.Query()
+	values.Del("cursor")
+	first := r.URL.Path + "?" + values.Encode()
+
+	var next string
+
+	if page.NextCursor != "" {
+		values.Set("cursor", page.NextCursor)
+		next = r.URL.Path + "?" + values.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+	}
+
+	h.writeRobots(w, format, page.Robots, robotsView{Robots: page.Robots, First: first, Next: next}, reqID, remoteAddr)
+}
+
+// robotListFormats форматы списков роботов.
+var robotListFormats = []string{applicationJSON, textHTML, textCSV, applicationNDJSON}
+
+// writeRobots отправляет список роботов в формате format, view — модель html представления.
+func (h *Handler) writeRobots(w http.ResponseWriter, format string, robots []robot.Robot, view robotsView,
+	reqID, remoteAddr string) {
+	switch format {
+	case textHTML:
+		w.Header().Set("Content-Type", textHTML)
+		w.WriteHeader(http.StatusOK)
+		renderTemplate(w, "listrobots", "base", view)
+	case textCSV, applicationNDJSON:
+		h.writeExport(w, format, robotsExport(robots), reqID, remoteAddr)
+	default:
+		h.writeJSON(w, http.StatusOK, robots, reqID, remoteAddr)
+	}
+}
+
+// ownRobot находит робота и проверяет, что он принадлежит пользователю из токена.
+// При ошибке ответ уже отправлен и возвращается false.
+func (h *Handler) ownRobot(w http.ResponseWriter, r *http.Request, robotID int) (*robot.Robot, bool) {
+	return h.ownRobotFrom(w, r, robotID, h.robotStorage.FindByID, false)
+}
+
+// robotToChange находит робота пользователя, которого меняет запрос, и требует заголовок If-Match с его ETag.
+// Ревизию найденного робота запрос передает хранилищу, которое проверяет ее при изменении.
+func (h *Handler) robotToChange(w http.ResponseWriter, r *http.Request, robotID int) (*robot.Robot, bool) {
+	return h.ownRobotFrom(w, r, robotID, h.robotStorage.FindByID, true)
+}
+
+// ownRobotFrom находит робота функцией find и проверяет, что он принадлежит пользователю из токена
+// и совпадает с ETag из заголовка If-Match. Без заголовка проверка не выполняется, если required равен false.
+func (h *Handler) ownRobotFrom(w http.ResponseWriter, r *http.Request, robotID int,
+	find func(robotID int) (*robot.Robot, error), required bool) (*robot.Robot, bool) {
+	rob, err := findOwnRobot(r.Context(), robotID, find)
+	if err != nil {
+		h.fail(w, r, "can't find own robot", err)
+		return nil, false
+	}
+
+	if err = checkIfMatch(r, robotETag(rob), required); err != nil {
+		h.fail(w, r, "robot precondition failed", err)
+		return nil, false
+	}
+
+	return rob, true
+}
+
+// findOwnRobot находит робота функцией find и проверяет, что он принадлежит пользователю сессии из ctx.
+func findOwnRobot(ctx context.Context, robotID int, find func(robotID int) (*robot.Robot, error)) (*robot.Robot, error) {
+	userID := sessionUserID(ctx)
+
+	rob, err := find(robotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rob.OwnerUserID != userID {
+		return nil, fmt.Errorf("%w: user %d doesn't own robot %d", errForbidden, userID, robotID)
+	}
+
+	return rob, nil
+}
+
+// visibleRobot находит робота, которого видит пользователь из токена, с учетом токена ссылки
+// из query-параметра share. Невидимый робот не отличается от несуществующего.
+// При ошибке ответ уже отправлен и возвращается false.
+func (h *Handler) visibleRobot(w http.ResponseWriter, r *http.Request, robotID int) (*robot.Robot, bool) {
+	rob, err := h.robotStorage.FindVisible(robotID, sessionUserID(r.Context()), r.URL.Query().Get("share"))
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FindVisible return with error", err)
+		return nil, false
+	}
+
+	return rob, true
+}
+
+// sessionUserID возвращает пользователя сессии, которую проверила аутентификация.
+func sessionUserID(ctx context.Context) int {
+	token := ctx.Value(tokenKey{}).(string)
+	sessionToken, _ := session.DecodeToken(token)
+
+	return sessionToken.UserID
+}
+
+// writeJSON отправляет v в формате JSON с кодом code.
+func (h *Handler) writeJSON(w http.ResponseWriter, code int, v interface{}, reqID, remoteAddr string) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		h.logger.Warnw("unable to marshal response", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
+		sendError(w, "error on server", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(code)
+
+	if _, err = w.Write(b); err != nil {
+		h.logger.Warnw("unable to write response", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
+	}
+}
+
+// closeBeforeDelete останавливает торгующего робота и закрывает его открытую позицию по текущей котировке.
+// Торговый процесс торгует по своей копии робота до конца раунда, но хранилище сохраняет его сделки только
+// у торгующих роботов, а закрытие позиции — только у остановленного робота с открытой позицией. Поэтому позиция
+// закрывается один раз, и остановленный робот не открывает новую. Пока робот не остановлен, торговый процесс
+// может сам закрыть позицию, поэтому после остановки робот читается заново и позиция закрывается по его
+// свежему состоянию.
+// Остановка выполняется, только если ревизия робота равна revision, нулевая revision не проверяется.
+// Возвращает ревизию, которую должен проверить SoftDelete: revision или ревизию после изменений самого запроса.
+func (h *Handler) closeBeforeDelete(ctx context.Context, rob *robot.Robot, revision int) (int, error) {
+	if rob.Status.IsTrading() {
+		err := h.robotStorage.Transition(rob.RobotID, robot.ActionStop, robot.SourceUser, revision)
+		if err != nil {
+			return 0, fmt.Errorf("func robotStorage.Transition return with error: %w", err)
+		}
+
+		if err = h.reread(rob, &revision); err != nil {
+			return 0, err
+		}
+	}
+
+	if rob.IsBuying {
+		return revision, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, quoteTimeout)
+	defer cancel()
+
+	quote, err := h.instruments.GetQuote(ctx, &fintech.QuoteRequest{Ticker: rob.Ticker})
+	if err != nil {
+		return 0, fmt.Errorf("%w: grpc func GetQuote return with error: %s", errPriceUnavailable, err)
+	}
+
+	rob.Sell(quote.SellPrice)
+
+	deal := rob.NewDeal(robot.SideSell, quote.SellPrice, time.Now())
+	if err = h.robotStorage.ClosePosition(rob, deal); err != nil {
+		return 0, fmt.Errorf("func robotStorage.ClosePosition return with error: %w", err)
+	}
+
+	if err = h.reread(rob, &revision); err != nil {
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+// reread заменяет rob его текущим состоянием из хранилища после изменения, которое сделал сам запрос,
+// и переносит новую ревизию в revision, если она проверяется.
+func (h *Handler) reread(rob *robot.Robot, revision *int) error {
+	current, err := h.robotStorage.FindByID(rob.RobotID)
+	if err != nil {
+		return fmt.Errorf("func robotStorage.FindByID return with error: %w", err)
+	}
+
+	*rob = *current
+
+	if *revision != 0 {
+		*revision = rob.Revision
+	}
+
+	return nil
 }
 
-func validQuery(body []byte, subslices ...[]byte) bool {
-	for _, subslice := range subslices {
-		if !bytes.Contains(body, subslice) {
-			return false
+// prepareNewRobot проверяет робота, которого создает пользователь userID, и сбрасывает поля,
+// которые задает сервис. Ошибка объясняет, почему робота создать нельзя, статус ответа для нее дает classify.
+func (h *Handler) prepareNewRobot(ctx context.Context, userID int, rob *robot.Robot) error {
+	if userID != rob.OwnerUserID {
+		return fmt.Errorf("%w: robot must belong to user %d", errForbidden, userID)
+	}
+
+	if rob.Lots == 0 {
+		rob.Lots = 1
+	}
+
+	fields := fieldErrors(rob.Validate())
+
+	if rob.Schedule != "" {
+		if _, err := calendar.ParseRecurring(rob.Schedule); err != nil {
+			fields.Add("schedule", "is not a valid schedule")
+		}
+	}
+
+	if rob.Ticker != "" {
+		if err := h.checkTicker(ctx, rob.Ticker); err != nil {
+			if !errors.Is(err, robot.ErrUnknownTicker) {
+				return fmt.Errorf("%w: %s", errPriceUnavailable, err)
+			}
+
+			fields.Add("ticker", "is unknown")
 		}
 	}
 
-	return true
+	if err := fields.Err(robot.ErrInvalidRobot); err != nil {
+		return err
+	}
+
+	rob.FactYield = 0.0
+	rob.DealsCount = 0
+	rob.ParentRobotID = 0
+	rob.IsActive = false
+	rob.IsFavourite = false
+	rob.IsMirror = false
+	rob.ClonedFromID = 0
+	rob.TemplateID = 0
+	rob.Status = rob.InitialStatus()
+
+	return rob.SetVisibility(rob.Visibility)
+}
+
+// fieldErrors возвращает ошибки полей из err, если они есть.
+func fieldErrors(err error) validation.Fields {
+	var verr *validation.Error
+	if errors.As(err, &verr) {
+		return append(validation.Fields(nil), verr.Fields...)
+	}
+
+	return nil
+}
+
+// checkTicker проверяет, что сервис котировок знает тикер.
+func (h *Handler) checkTicker(ctx context.Context, ticker string) error {
+	ctx, cancel := context.WithTimeout(ctx, quoteTimeout)
+	defer cancel()
+
+	_, err := h.instruments.GetQuote(ctx, &fintech.QuoteRequest{Ticker: ticker})
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("%w: %q", robot.ErrUnknownTicker, ticker)
+	}
+
+	if err != nil {
+		return fmt.Errorf("grpc func GetQuote return with error: %s", err)
+	}
+
+	return nil
 }
 
 func renderTemplate(w http.ResponseWriter, name string, template string, viewModel interface{}) {
@@ -108,9 +417,3 @@ func renderTemplate(w http.ResponseWriter, name string, template string, viewMod
 		sendError(w, "error on server", http.StatusInternalServerError)
 	}
 }
-
-func sendError(w http.ResponseWriter, error string, code int) {
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.WriteHeader(code)
-	fmt.Fprintln(w, errorJSON(error))
-}
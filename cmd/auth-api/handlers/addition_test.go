@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
 )
 
 func TestBuildErrorJSON(t *testing.T) {
@@ -39,34 +40,55 @@ func TestGetUserFromBody(t *testing.T) {
 	userWithoutField := []byte(`{"first_name":"Ivan","email":"example@example.com","password":"1234"}`)
 	_, err = getUserFromBody(bytes.NewBuffer(userWithoutField))
 	assert.NotNil(err)
+
+	invalidUsers := map[string]string{
+		"id":       `{"id":5,"first_name":"Ivan","last_name":"Ivanov","email":"example@example.com","password":"1234"}`,
+		"email":    `{"first_name":"Ivan","last_name":"Ivanov","email":"example","password":"1234"}`,
+		"birthday": `{"first_name":"Ivan","last_name":"Ivanov","birthday":"02.01.1980","email":"example@example.com","password":"1234"}`,
+	}
+
+	for field, body := range invalidUsers {
+		_, err = getUserFromBody(bytes.NewBufferString(body))
+
+		var verr *validation.Error
+		if assert.True(errors.As(err, &verr), "getUserFromBody(%q) = %v, want field error", body, err) {
+			assert.Equal(field, verr.Fields[0].Field)
+		}
+	}
 }
 
-func TestValidQuery(t *testing.T) {
+func TestGetRobotFromBody(t *testing.T) {
 	type testCase struct {
-		Name      string
-		In        []byte
-		Subslices [][]byte
-		Expected  bool
+		Name          string
+		In            string
+		ExpectedField string
 	}
 
 	assert := assert.New(t)
 
 	testCases := []testCase{
-		{Name: "valid query", In: []byte(`{\t"email": example,\n\t"password": 1234}`),
-			Subslices: [][]byte{[]byte("email"), []byte("password")}, Expected: true},
-		{Name: "invalid query", In: []byte(`{\t"emal": example,\n\t"password": 1234}`),
-			Subslices: [][]byte{[]byte("email"), []byte("password")}, Expected: false},
-		{Name: "empty query", In: []byte(""),
-			Subslices: [][]byte{[]byte("email"), []byte("password")}, Expected: false},
+		{Name: "valid robot", In: `{"owner_user_id":1,"ticker":"AAPL","buy_price":10,"sell_price":12}`},
+		{Name: "without optional fields", In: `{"ticker":"AAPL"}`},
+		{Name: "unknown field", In: `{"ticker":"AAPL","tiker":"AAPL"}`, ExpectedField: "tiker"},
+		{Name: "read-only field", In: `{"ticker":"AAPL","deals_count":3}`, ExpectedField: "deals_count"},
+		{Name: "wrong type", In: `{"ticker":"AAPL","lots":"two"}`, ExpectedField: "lots"},
+		{Name: "empty body", In: ``, ExpectedField: "body"},
 	}
 
 	for _, tc := range testCases {
 		tc := tc
 
 		t.Run(tc.Name, func(t *testing.T) {
-			actual := validQuery(tc.In, tc.Subslices...)
-
-			assert.Equal(tc.Expected, actual, "validQuery(%q, %v) = %v, want = %v", tc.In, tc.Subslices, actual, tc.Expected)
+			_, err := getRobotFromBody(bytes.NewBufferString(tc.In))
+			if tc.ExpectedField == "" {
+				assert.NoError(err)
+				return
+			}
+
+			var verr *validation.Error
+			if assert.True(errors.As(err, &verr), "getRobotFromBody(%q) = %v, want field error", tc.In, err) {
+				assert.Equal(tc.ExpectedField, verr.Fields[0].Field)
+			}
 		})
 	}
 }
@@ -84,7 +106,7 @@ func TestGetSignInDataFromBody(t *testing.T) {
 		{Name: "invalid body", In: []byte(`{"email":"test@test.com","password":"1234"}`),
 			ExpectedUser: &SignInData{Email: "test@test.com", Password: "1234"}},
 		{Name: "invalid email field", In: []byte(`{"emil":"test@test.com","password":"1234"}`),
-			ExpectedUser: &SignInData{Email: "", Password: "1234"}},
+			ExpectedUser: nil},
 		{Name: "invalid form", In: []byte(``),
 			ExpectedUser: nil},
 	}
@@ -99,28 +121,3 @@ func TestGetSignInDataFromBody(t *testing.T) {
 		})
 	}
 }
-
-func TestErrorJSON(t *testing.T) {
-	type testCase struct {
-		Name     string
-		In       string
-		Expected string
-	}
-
-	assert := assert.New(t)
-
-	testCases := []testCase{
-		{Name: "correct", In: "error", Expected: "{\n\t\"error\": \"error\"\n}"},
-		{Name: "empty", In: "", Expected: "{\n\t\"error\": \"\"\n}"},
-	}
-
-	for _, tc := range testCases {
-		tc := tc
-
-		t.Run(tc.Name, func(t *testing.T) {
-			actual := errorJSON(tc.In)
-
-			assert.Equal(tc.Expected, actual, "errorJSON(%q) = %q, want %q", tc.In, actual, tc.Expected)
-		})
-	}
-}
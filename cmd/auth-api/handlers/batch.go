@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+// batchCreateRequest тело запроса на пакетное создание роботов.
+type batchCreateRequest struct {
+	Atomic bool         `json:"atomic"`
+	Robots []robotInput `json:"robots"`
+}
+
+// batchActionRequest тело запроса на пакетное действие над роботами.
+type batchActionRequest struct {
+	Atomic   bool  `json:"atomic"`
+	RobotIDs []int `json:"robot_ids"`
+}
+
+// batchItem результат операции над одним роботом пакета.
+type batchItem struct {
+	RobotID int                     `json:"robot_id,omitempty"`
+	Status  int                     `json:"status"`
+	Code    string                  `json:"code,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+	Fields  []validation.FieldError `json:"fields,omitempty"`
+	Robot   *robot.Robot            `json:"robot,omitempty"`
+}
+
+// batchResponse ответ на пакетный запрос, результаты идут в порядке элементов запроса.
+type batchResponse struct {
+	Atomic    bool        `json:"atomic"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Results   []batchItem `json:"results"`
+}
+
+// BatchCreateRobots создает роботов пакетом. Каждый робот проверяется так же, как в CreateRobot.
+func (h *Handler) BatchCreateRobots(w http.ResponseWriter, r *http.Request) {
+	token := r.Context().Value(tokenKey{}).(string)
+	sessionToken, _ := session.DecodeToken(token)
+
+	var req batchCreateRequest
+	if err := decodeBatch(r, &req, func() int { return len(req.Robots) }); err != nil {
+		h.fail(w, r, "invalid batch", err)
+		return
+	}
+
+	robots := make([]*robot.Robot, len(req.Robots))
+	errs := make([]error, len(req.Robots))
+
+	for i := range req.Robots {
+		robots[i] = &robot.Robot{}
+		req.Robots[i].apply(robots[i])
+
+		errs[i] = h.prepareNewRobot(r.Context(), sessionToken.UserID, robots[i])
+	}
+
+	h.createRobots(w, r, sessionToken.UserID, req.Atomic, robots, errs)
+}
+
+// createRobots сохраняет проверенных роботов пакетом и отправляет результат. errs содержит ошибки проверки,
+// роботы с ошибками не сохраняются.
+func (h *Handler) createRobots(w http.ResponseWriter, r *http.Request, userID int, atomic bool, robots []*robot.Robot,
+	errs []error) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+
+	if !atomic || !robot.AbortBatch(errs) {
+		pending, indexes := pendingRobots(robots, errs)
+
+		created, err := h.robotStorage.CreateBatch(pending, atomic)
+		if err != nil {
+			h.fail(w, r, "func robotStorage.CreateBatch return with error", err)
+			return
+		}
+
+		mergeBatch(errs, indexes, created, atomic)
+	}
+
+	resp := batchResponse{Atomic: atomic, Results: make([]batchItem, 0, len(robots))}
+
+	for i, rob := range robots {
+		item := h.batchItem(rob.RobotID, errs[i], http.StatusCreated, reqID, remoteAddr)
+		if errs[i] == nil {
+			item.Robot = rob
+		}
+
+		resp.add(item)
+	}
+
+	h.logger.Infow("batch create robots", "user", userID, "succeeded", resp.Succeeded, "failed", resp.Failed,
+		"trackingID", reqID, "RealIP", remoteAddr)
+	h.writeJSON(w, resp.code(), resp, reqID, remoteAddr)
+}
+
+// BatchActivateRobots запускает роботов пользователя пакетом.
+func (h *Handler) BatchActivateRobots(w http.ResponseWriter, r *http.Request) {
+	h.applyBatchAction(w, r, robot.ActionActivate)
+}
+
+// BatchDeactivateRobots приостанавливает роботов пользователя пакетом.
+func (h *Handler) BatchDeactivateRobots(w http.ResponseWriter, r *http.Request) {
+	h.applyBatchAction(w, r, robot.ActionDeactivate)
+}
+
+// BatchDeleteRobots помещает роботов пользователя в корзину пакетом. Вне атомарного режима торгующие роботы
+// сначала останавливаются и закрывают позиции, как в DeleteRobot. Сделку нельзя откатить, поэтому атомарный
+// пакет удаляет только остановленных роботов, а на торгующих отвечает 409 Conflict.
+func (h *Handler) BatchDeleteRobots(w http.ResponseWriter, r *http.Request) {
+	h.applyBatchAction(w, r, robot.ActionDelete)
+}
+
+// applyBatchAction выполняет действие над роботами пользователя пакетом.
+func (h *Handler) applyBatchAction(w http.ResponseWriter, r *http.Request, action robot.Action) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	token := r.Context().Value(tokenKey{}).(string)
+	sessionToken, _ := session.DecodeToken(token)
+
+	var req batchActionRequest
+	if err := decodeBatch(r, &req, func() int { return len(req.RobotIDs) }); err != nil {
+		h.fail(w, r, "invalid batch", err)
+		return
+	}
+
+	errs := make([]error, len(req.RobotIDs))
+
+	for i, robotID := range req.RobotIDs {
+		errs[i] = h.prepareBatchAction(r.Context(), sessionToken.UserID, robotID, action, req.Atomic)
+	}
+
+	if !req.Atomic || !robot.AbortBatch(errs) {
+		var pending, indexes []int
+
+		for i, robotID := range req.RobotIDs {
+			if errs[i] == nil {
+				pending = append(pending, robotID)
+				indexes = append(indexes, i)
+			}
+		}
+
+		applied, err := h.robotStorage.TransitionBatch(pending, action, robot.SourceUser, req.Atomic)
+		if err != nil {
+			h.fail(w, r, "func robotStorage.TransitionBatch return with error", err)
+			return
+		}
+
+		mergeBatch(errs, indexes, applied, req.Atomic)
+	}
+
+	resp := batchResponse{Atomic: req.Atomic, Results: make([]batchItem, 0, len(req.RobotIDs))}
+
+	for i, robotID := range req.RobotIDs {
+		resp.add(h.batchItem(robotID, errs[i], http.StatusOK, reqID, remoteAddr))
+
+		if errs[i] == nil {
+			h.publishRobotChanged(robotID, reqID, remoteAddr)
+		}
+	}
+
+	h.logger.Infow("batch robot transition", "action", action, "succeeded", resp.Succeeded, "failed", resp.Failed,
+		"trackingID", reqID, "RealIP", remoteAddr)
+	h.writeJSON(w, resp.code(), resp, reqID, remoteAddr)
+}
+
+// prepareBatchAction проверяет, что робот принадлежит пользователю, и готовит его к удалению вне атомарного режима.
+func (h *Handler) prepareBatchAction(ctx context.Context, userID, robotID int, action robot.Action, atomic bool) error {
+	rob, err := h.robotStorage.FindByID(robotID)
+	if err != nil {
+		return err
+	}
+
+	if rob.OwnerUserID != userID {
+		return fmt.Errorf("%w: user %d doesn't own robot %d", errForbidden, userID, robotID)
+	}
+
+	if action != robot.ActionDelete || atomic {
+		return nil
+	}
+
+	_, err = h.closeBeforeDelete(ctx, rob, 0)
+
+	return err
+}
+
+// batchItem возвращает результат операции над роботом, success — код успешной операции.
+// Статус и код ошибки элемента берутся из errorKinds так же, как для отдельных запросов.
+func (h *Handler) batchItem(robotID int, err error, success int, reqID, remoteAddr string) batchItem {
+	if err == nil {
+		return batchItem{RobotID: robotID, Status: success}
+	}
+
+	e, known := classify(err)
+	if !known {
+		h.logger.Warnw("batch item failed", "error", err, "robotID", robotID, "trackingID", reqID, "RealIP", remoteAddr)
+	}
+
+	return batchItem{RobotID: robotID, Status: e.Status, Code: e.Code, Error: e.Detail, Fields: e.Fields}
+}
+
+// add добавляет результат операции в ответ.
+func (b *batchResponse) add(item batchItem) {
+	if item.Error == "" {
+		b.Succeeded++
+	} else {
+		b.Failed++
+	}
+
+	b.Results = append(b.Results, item)
+}
+
+// code возвращает 200, если все операции пакета выполнены, и 207 Multi-Status, если часть не выполнена.
+func (b *batchResponse) code() int {
+	if b.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+
+	return http.StatusOK
+}
+
+// decodeBatch читает тело пакетного запроса в v, size — число элементов пакета после чтения.
+func decodeBatch(r *http.Request, v interface{}, size func() int) error {
+	defer r.Body.Close()
+
+	if err := validation.DecodeJSON(r.Body, v); err != nil {
+		return err
+	}
+
+	if n := size(); n < 1 || n > robot.MaxBatch {
+		return validation.Field(validation.ErrInvalidInput, "body", "must contain from 1 to %d items, got %d", robot.MaxBatch, n)
+	}
+
+	return nil
+}
+
+// pendingRobots возвращает роботов без ошибок и их индексы в пакете.
+func pendingRobots(robots []*robot.Robot, errs []error) ([]*robot.Robot, []int) {
+	var (
+		pending []*robot.Robot
+		indexes []int
+	)
+
+	for i, rob := range robots {
+		if errs[i] == nil {
+			pending = append(pending, rob)
+			indexes = append(indexes, i)
+		}
+	}
+
+	return pending, indexes
+}
+
+// mergeBatch переносит ошибки хранилища stored в ошибки пакета errs по индексам indexes.
+// Если атомарный пакет не применился в хранилище, отменяются все его операции.
+func mergeBatch(errs []error, indexes []int, stored []error, atomic bool) {
+	for i, err := range stored {
+		errs[indexes[i]] = err
+	}
+
+	if atomic {
+		robot.AbortBatch(errs)
+	}
+}
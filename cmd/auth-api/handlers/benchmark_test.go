@@ -3,6 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 )
@@ -32,11 +34,11 @@ func BenchmarkMarshal(b *testing.B) {
 	}
 }
 
-func BenchmarkSprinf(b *testing.B) {
+func BenchmarkSendError(b *testing.B) {
 	err := "error on server"
 
 	for i := 0; i < b.N; i++ {
-		_ = errorJSON(err)
+		sendError(httptest.NewRecorder(), err, http.StatusInternalServerError)
 	}
 }
 
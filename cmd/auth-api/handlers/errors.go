@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/middleware"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+const (
+	problemJSON     = "application/problem+json"
+	problemType     = "about:blank"
+	requestIDHeader = "X-Request-Id"
+)
+
+// errForbidden пользователь не владеет объектом запроса.
+var errForbidden = errors.New("you have no permission")
+
+// apiError тело ответа с ошибкой в формате RFC 7807 (application/problem+json). Code — машиночитаемый код ошибки,
+// fields — ошибки отдельных полей запроса, request_id совпадает с заголовком X-Request-Id и записями в логе.
+type apiError struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Code      string                  `json:"code"`
+	Detail    string                  `json:"detail,omitempty"`
+	Fields    []validation.FieldError `json:"fields,omitempty"`
+	RequestID string                  `json:"request_id,omitempty"`
+}
+
+// errorKind статус HTTP и код API для ошибки предметной области. Если detail задан, он заменяет
+// текст ошибки в ответе, чтобы подробности хранилища или внешних сервисов не уходили клиенту.
+type errorKind struct {
+	err    error
+	status int
+	code   string
+	detail string
+}
+
+// errorKinds сопоставляет ошибки сервиса статусам HTTP и кодам API. Ошибки проверяются по порядку,
+// поэтому более частные ошибки, например validation.ErrEmptyBody, идут раньше общих.
+var errorKinds = []errorKind{
+	{err: robot.ErrNotFound, status: http.StatusNotFound, code: "not_found", detail: "not found"},
+	{err: user.ErrNotFound, status: http.StatusNotFound, code: "user_not_found", detail: "user not found"},
+	{err: robot.ErrInvalidID, status: http.StatusBadRequest, code: "invalid_id"},
+	{err: robot.ErrInvalidRobot, status: http.StatusBadRequest, code: "invalid_robot"},
+	{err: robot.ErrUnknownTicker, status: http.StatusBadRequest, code: "unknown_ticker"},
+	{err: robot.ErrInvalidQuery, status: http.StatusBadRequest, code: "invalid_query"},
+	{err: robot.ErrUnknownMetric, status: http.StatusBadRequest, code: "unknown_metric"},
+	{err: robot.ErrUnknownPeriod, status: http.StatusBadRequest, code: "unknown_period"},
+	{err: user.ErrInvalidUser, status: http.StatusBadRequest, code: "invalid_user"},
+	{err: validation.ErrEmptyBody, status: http.StatusBadRequest, code: "empty_body"},
+	{err: validation.ErrInvalidInput, status: http.StatusBadRequest, code: "invalid_input"},
+	{err: errForbidden, status: http.StatusForbidden, code: "forbidden"},
+	{err: errNotAcceptable, status: http.StatusNotAcceptable, code: "not_acceptable"},
+	{err: robot.ErrTransition, status: http.StatusConflict, code: "transition_not_allowed"},
+	{err: robot.ErrPositionOpen, status: http.StatusConflict, code: "position_open"},
+	{err: robot.ErrMirror, status: http.StatusConflict, code: "robot_mirror"},
+	{err: user.ErrAlreadyExists, status: http.StatusConflict, code: "user_exists"},
+	{err: errIdempotencyKeyInUse, status: http.StatusConflict, code: "idempotency_key_in_use"},
+	{err: errIdempotencyKeyReused, status: http.StatusUnprocessableEntity, code: "idempotency_key_reused"},
+	{err: robot.ErrConflict, status: http.StatusPreconditionFailed, code: "precondition_failed"},
+	{err: user.ErrConflict, status: http.StatusPreconditionFailed, code: "precondition_failed"},
+	{err: errPreconditionFailed, status: http.StatusPreconditionFailed, code: "precondition_failed"},
+	{err: errPreconditionRequired, status: http.StatusPreconditionRequired, code: "precondition_required"},
+	{err: errVersionRequired, status: http.StatusPreconditionRequired, code: "precondition_required"},
+	{err: robot.ErrBatchAborted, status: http.StatusFailedDependency, code: "batch_aborted"},
+	{err: errRateLimited, status: http.StatusTooManyRequests, code: "rate_limited", detail: errRateLimited.Error()},
+	{err: errPriceUnavailable, status: http.StatusServiceUnavailable, code: "price_unavailable",
+		detail: errPriceUnavailable.Error()},
+}
+
+// classify возвращает ответ для err без общих полей. Неизвестные ошибки считаются ошибками сервера
+// и не раскрывают подробностей, тогда known равен false.
+func classify(err error) (e apiError, known bool) {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.err) {
+			e = apiError{Status: kind.status, Code: kind.code, Detail: kind.detail, Fields: fieldErrors(err)}
+			if e.Detail == "" {
+				e.Detail = err.Error()
+			}
+
+			return e, true
+		}
+	}
+
+	return apiError{Status: http.StatusInternalServerError, Code: statusCode(http.StatusInternalServerError),
+		Detail: "error on server"}, false
+}
+
+// statusCode возвращает код API по умолчанию для статуса HTTP, например not_found для 404.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// fail отправляет ответ с ошибкой err, статус и код берутся из errorKinds. Подробности неизвестных ошибок
+// не уходят клиенту, а только пишутся в лог вместе с msg.
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	e, _ := classify(err)
+
+	h.logger.Warnw(msg, "error", err, "status", e.Status, "trackingID", middleware.GetReqID(r.Context()), "RealIP", r.RemoteAddr)
+	writeProblem(w, e)
+}
+
+// sendError отправляет ошибку с сообщением message и статусом status, код API соответствует статусу.
+func sendError(w http.ResponseWriter, message string, status int) {
+	writeProblem(w, apiError{Status: status, Code: statusCode(status), Detail: message})
+}
+
+// writeProblem дополняет e общими полями и отправляет его как application/problem+json.
+func writeProblem(w http.ResponseWriter, e apiError) {
+	e.Type = problemType
+	e.Title = http.StatusText(e.Status)
+	e.RequestID = w.Header().Get(requestIDHeader)
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		b = []byte(`{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_server_error"}`)
+		e.Status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", problemJSON)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.Status)
+
+	_, _ = w.Write(b)
+}
+
+// exposeRequestID передает идентификатор запроса из middleware.RequestID клиенту в заголовке X-Request-Id.
+func exposeRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := middleware.GetReqID(r.Context()); id != "" {
+			w.Header().Set(requestIDHeader, id)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
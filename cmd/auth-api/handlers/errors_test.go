@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+func TestClassify(t *testing.T) {
+	type testCase struct {
+		Name           string
+		Err            error
+		ExpectedStatus int
+		ExpectedCode   string
+		ExpectedDetail string
+		ExpectedKnown  bool
+	}
+
+	testCases := []testCase{
+		{Name: "Robot not found", Err: fmt.Errorf("%w: can't scan robot: sql: no rows", robot.ErrNotFound),
+			ExpectedStatus: http.StatusNotFound, ExpectedCode: "not_found", ExpectedDetail: "not found", ExpectedKnown: true},
+		{Name: "User not found", Err: fmt.Errorf("%w: 7", user.ErrNotFound),
+			ExpectedStatus: http.StatusNotFound, ExpectedCode: "user_not_found", ExpectedDetail: "user not found", ExpectedKnown: true},
+		{Name: "Invalid id", Err: fmt.Errorf("%w: %q is not a positive integer", robot.ErrInvalidID, "a"),
+			ExpectedStatus: http.StatusBadRequest, ExpectedCode: "invalid_id", ExpectedDetail: `invalid id: "a" is not a positive integer`,
+			ExpectedKnown: true},
+		{Name: "Empty body", Err: validation.ErrEmptyBody,
+			ExpectedStatus: http.StatusBadRequest, ExpectedCode: "empty_body", ExpectedDetail: validation.ErrEmptyBody.Error(), ExpectedKnown: true},
+		{Name: "Forbidden", Err: fmt.Errorf("%w: user 1 doesn't own robot 2", errForbidden),
+			ExpectedStatus: http.StatusForbidden, ExpectedCode: "forbidden", ExpectedDetail: "you have no permission: user 1 doesn't own robot 2",
+			ExpectedKnown: true},
+		{Name: "User exists", Err: fmt.Errorf("%w: example@example.com", user.ErrAlreadyExists),
+			ExpectedStatus: http.StatusConflict, ExpectedCode: "user_exists", ExpectedDetail: "user is already registered: example@example.com",
+			ExpectedKnown: true},
+		{Name: "Price unavailable", Err: fmt.Errorf("%w: dial tcp: connection refused", errPriceUnavailable),
+			ExpectedStatus: http.StatusServiceUnavailable, ExpectedCode: "price_unavailable", ExpectedDetail: errPriceUnavailable.Error(),
+			ExpectedKnown: true},
+		{Name: "Unknown", Err: errors.New("pq: connection reset"),
+			ExpectedStatus: http.StatusInternalServerError, ExpectedCode: "internal_server_error", ExpectedDetail: "error on server"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			e, known := classify(tc.Err)
+			assert.Equal(tc.ExpectedKnown, known)
+			assert.Equal(tc.ExpectedStatus, e.Status)
+			assert.Equal(tc.ExpectedCode, e.Code)
+			assert.Equal(tc.ExpectedDetail, e.Detail)
+		})
+	}
+}
+
+func TestClassifyFields(t *testing.T) {
+	assert := assert.New(t)
+
+	err := validation.Field(robot.ErrInvalidRobot, "lots", "must be positive")
+
+	e, known := classify(err)
+	assert.True(known)
+	assert.Equal(http.StatusBadRequest, e.Status)
+	assert.Equal("invalid_robot", e.Code)
+	assert.Equal([]validation.FieldError{{Field: "lots", Message: "must be positive"}}, e.Fields)
+}
+
+func TestProblemResponse(t *testing.T) {
+	assert := assert.New(t)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, exposeRequestID)
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		sendError(w, `bad "quoted" message`, http.StatusBadRequest)
+	})
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var resp apiError
+	assert.NoError(json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(http.StatusBadRequest, recorder.Code)
+	assert.Equal(problemJSON, recorder.Header().Get("Content-Type"))
+	assert.Equal(apiError{
+		Type:      problemType,
+		Title:     "Bad Request",
+		Status:    http.StatusBadRequest,
+		Code:      "bad_request",
+		Detail:    `bad "quoted" message`,
+		RequestID: recorder.Header().Get(requestIDHeader),
+	}, resp)
+	assert.NotEmpty(resp.RequestID)
+}
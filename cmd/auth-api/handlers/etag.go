@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+)
+
+var (
+	// errPreconditionFailed ETag из If-Match не совпадает с текущим: объект изменился после того, как его прочитали.
+	errPreconditionFailed = errors.New("the resource has changed, fetch it again")
+	// errPreconditionRequired изменение требует заголовка If-Match.
+	errPreconditionRequired = errors.New("If-Match header is required")
+	// errVersionRequired изменение через gRPC требует версии прочитанного объекта.
+	errVersionRequired = errors.New("version of the object that was read is required")
+)
+
+// robotETag возвращает ETag робота. Ревизия меняется при каждом изменении робота, а число подписчиков
+// входит в ETag отдельно, потому что меняется без изменения самого робота.
+func robotETag(rob *robot.Robot) string {
+	return fmt.Sprintf(`"r%d.%d"`, rob.Revision, rob.Followers)
+}
+
+// formatETag возвращает ETag представления объекта в формате format. JSON представление получает ETag объекта,
+// который передают в If-Match, а у других форматов он свой: сильный ETag обещает одинаковые байты ответа,
+// поэтому кэш не должен отдать HTML страницу на запрос JSON с тем же ETag.
+func formatETag(etag, format string) string {
+	if format == applicationJSON {
+		return etag
+	}
+
+	suffix := format[strings.LastIndex(format, "/")+1:]
+
+	return strings.TrimSuffix(etag, `"`) + "-" + suffix + `"`
+}
+
+// robotPageETag возвращает ETag HTML страницы робота. Страница показывает действия, доступные сейчас, а они
+// меняются со временем без изменения робота, например на границе расписания, поэтому тоже входят в ETag.
+func robotPageETag(rob *robot.Robot, actions []robot.Action) string {
+	names := make([]string, 0, len(actions))
+	for _, a := range actions {
+		names = append(names, string(a))
+	}
+
+	return strings.TrimSuffix(formatETag(robotETag(rob), textHTML), `"`) + ";" + strings.Join(names, "+") + `"`
+}
+
+// userETag возвращает ETag пользователя по его версии.
+func userETag(u *user.User) string {
+	return fmt.Sprintf(`"u%d"`, u.Version)
+}
+
+// checkIfMatch сравнивает заголовок If-Match с текущим ETag объекта. Без заголовка запрос выполняется,
+// если required равен false, иначе возвращается errPreconditionRequired.
+func checkIfMatch(r *http.Request, etag string, required bool) error {
+	value := r.Header.Get("If-Match")
+	if value == "" {
+		if required {
+			return errPreconditionRequired
+		}
+
+		return nil
+	}
+
+	if !etagMatch(value, etag, false) {
+		return fmt.Errorf("%w: If-Match %s, current ETag %s", errPreconditionFailed, value, etag)
+	}
+
+	return nil
+}
+
+// checkVersion сравнивает версию объекта из запроса gRPC с текущей, как checkIfMatch сравнивает ETag.
+func checkVersion(expected int64, current int) error {
+	if expected == 0 {
+		return errVersionRequired
+	}
+
+	if expected != int64(current) {
+		return fmt.Errorf("%w: version %d, current version %d", errPreconditionFailed, expected, current)
+	}
+
+	return nil
+}
+
+// notModified отправляет ETag объекта и, если он совпадает с заголовком If-None-Match, ответ 304.
+// Возвращает true, если ответ уже отправлен.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	if value := r.Header.Get("If-None-Match"); value != "" && etagMatch(value, etag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	return false
+}
+
+// etagMatch проверяет, есть ли etag в списке list из заголовка If-Match или If-None-Match, "*" совпадает с любым.
+// При слабом сравнении префикс W/ не учитывается, при сильном слабые ETag не совпадают ни с чем.
+func etagMatch(list, etag string, weak bool) bool {
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+
+		if v == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(v, "W/") {
+			if !weak {
+				continue
+			}
+
+			v = strings.TrimPrefix(v, "W/")
+		}
+
+		if v == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+func TestETagMatch(t *testing.T) {
+	type testCase struct {
+		Name     string
+		List     string
+		Weak     bool
+		Expected bool
+	}
+
+	testCases := []testCase{
+		{Name: "Equal", List: `"r1.0"`, Expected: true},
+		{Name: "Other", List: `"r2.0"`, Expected: false},
+		{Name: "List", List: `"r2.0", "r1.0"`, Expected: true},
+		{Name: "Any", List: `*`, Expected: true},
+		{Name: "Weak in strong comparison", List: `W/"r1.0"`, Expected: false},
+		{Name: "Weak in weak comparison", List: `W/"r1.0"`, Weak: true, Expected: true},
+		{Name: "Unquoted", List: `r1.0`, Expected: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, etagMatch(tc.List, `"r1.0"`, tc.Weak))
+		})
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, &fakeInstruments{tickers: map[string]bool{"AAPL": true}})
+
+	setupSignUp(h, t)
+	owner := "Bearer " + setupUser(h, t, "owner@example.com").Token
+
+	rob := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(robotStorage.Create(rob))
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	getAs := func(accept, path, ifNoneMatch string) (string, int) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", owner)
+		req.Header.Set("Accept", accept)
+
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		return resp.Header.Get("ETag"), resp.StatusCode
+	}
+
+	get := func(path, ifNoneMatch string) (string, int) {
+		return getAs(applicationJSON, path, ifNoneMatch)
+	}
+
+	robotPath := fmt.Sprintf("/api/v1/robot/%d", rob.RobotID)
+
+	etag, code := get(robotPath, "")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(currentETag(t, robotStorage, rob.RobotID), etag)
+
+	_, code = get(robotPath, etag)
+	assert.Equal(http.StatusNotModified, code)
+
+	_, code = get(robotPath, "W/"+etag)
+	assert.Equal(http.StatusNotModified, code, "If-None-Match uses weak comparison")
+
+	page, code := getAs(textHTML, robotPath, etag)
+	assert.Equal(http.StatusOK, code, "HTML page isn't validated by the JSON ETag")
+	assert.NotEqual(etag, page)
+
+	current, err := robotStorage.FindByID(rob.RobotID)
+	if assert.NoError(err) {
+		assert.Equal(robotPageETag(current, current.AllowedActions(time.Now())), page)
+		assert.NotEqual(robotPageETag(current, nil), page, "actions available now are a part of the page ETag")
+	}
+
+	_, code = getAs(textHTML, robotPath, page)
+	assert.Equal(http.StatusNotModified, code)
+
+	assert.NoError(robotStorage.Transition(rob.RobotID, robot.ActionActivate, robot.SourceUser, 0))
+
+	changed, code := get(robotPath, etag)
+	assert.Equal(http.StatusOK, code, "transition changes the robot ETag")
+	assert.NotEqual(etag, changed)
+
+	resp, code := testRequestIfMatch(t, ts, http.MethodPut, robotPath+"/deactivate", owner, etag, nil)
+	resp.Body.Close()
+	assert.Equal(http.StatusPreconditionFailed, code, "If-Match is checked on robot actions")
+
+	resp, code = testRequestIfMatch(t, ts, http.MethodPut, robotPath+"/deactivate", owner, changed, nil)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, code)
+
+	userPath := "/api/v1/users/1"
+	body := `{"first_name":"Oleg","last_name":"Petrov","email":"owner@example.com","password":"1234"}`
+
+	etag, code = get(userPath, "")
+	assert.Equal(http.StatusOK, code)
+
+	_, code = get(userPath, etag)
+	assert.Equal(http.StatusNotModified, code)
+
+	resp, code = testRequestIfMatch(t, ts, http.MethodPut, userPath, owner, "", strings.NewReader(body))
+	resp.Body.Close()
+	assert.Equal(http.StatusPreconditionRequired, code)
+
+	resp, code = testRequestIfMatch(t, ts, http.MethodPut, userPath, owner, etag, strings.NewReader(body))
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, code)
+	assert.NotEqual(etag, resp.Header.Get("ETag"))
+
+	resp, code = testRequestIfMatch(t, ts, http.MethodPut, userPath, owner, etag, strings.NewReader(body))
+	resp.Body.Close()
+	assert.Equal(http.StatusPreconditionFailed, code, "the second update with the same ETag is rejected")
+
+	_, code = get(userPath, etag)
+	assert.Equal(http.StatusOK, code)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+)
+
+// exportFormats форматы списков: JSON, CSV для таблиц и NDJSON, по объекту в строке, для ETL.
+var exportFormats = []string{applicationJSON, textCSV, applicationNDJSON}
+
+// exporter список, который можно выгрузить в CSV и NDJSON.
+type exporter interface {
+	Len() int
+	// Columns возвращает заголовок CSV.
+	Columns() []string
+	// Row возвращает строку CSV i-го элемента.
+	Row(i int) []string
+	// Item возвращает i-й элемент для строки NDJSON.
+	Item(i int) interface{}
+}
+
+// writeExport отправляет список в формате format: text/csv или application/x-ndjson.
+func (h *Handler) writeExport(w http.ResponseWriter, format string, list exporter, reqID, remoteAddr string) {
+	if format == textCSV {
+		w.Header().Set("Content-Type", textCSV+"; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", applicationNDJSON)
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	var err error
+
+	if format == textCSV {
+		cw := csv.NewWriter(w)
+		_ = cw.Write(list.Columns())
+
+		for i := 0; i < list.Len(); i++ {
+			_ = cw.Write(list.Row(i))
+		}
+
+		cw.Flush()
+		err = cw.Error()
+	} else {
+		enc := json.NewEncoder(w)
+
+		for i := 0; i < list.Len() && err == nil; i++ {
+			err = enc.Encode(list.Item(i))
+		}
+	}
+
+	if err != nil {
+		h.logger.Warnw("unable to write response", "error", err, "format", format, "trackingID", reqID, "RealIP", remoteAddr)
+	}
+}
+
+// robotsExport выгрузка роботов.
+type robotsExport []robot.Robot
+
+func (e robotsExport) Len() int {
+	return len(e)
+}
+
+func (e robotsExport) Columns() []string {
+	return []string{"robot_id", "owner_user_id", "parent_robot_id", "status", "ticker", "buy_price", "sell_price",
+		"plan_start", "plan_end", "plan_yield", "fact_yield", "deals_count", "lots", "is_active", "is_favourite",
+		"is_mirror", "visibility", "version", "followers", "created_at"}
+}
+
+func (e robotsExport) Row(i int) []string {
+	r := &e[i]
+
+	return []string{strconv.Itoa(r.RobotID), strconv.Itoa(r.OwnerUserID), strconv.Itoa(r.ParentRobotID),
+		string(r.Status), r.Ticker, formatFloat(r.BuyPrice), formatFloat(r.SellPrice),
+		formatNullTime(r.PlanStart), formatNullTime(r.PlanEnd), formatFloat(r.PlanYield), formatFloat(r.FactYield),
+		strconv.Itoa(r.DealsCount), strconv.Itoa(r.Lots), strconv.FormatBool(r.IsActive),
+		strconv.FormatBool(r.IsFavourite), strconv.FormatBool(r.IsMirror), string(r.Visibility),
+		strconv.Itoa(r.Version), strconv.Itoa(r.Followers), formatNullTime(r.CreatedAt)}
+}
+
+// Item возвращает указатель, чтобы робот кодировался через MarshalJSON, как в ответе JSON.
+func (e robotsExport) Item(i int) interface{} {
+	return &e[i]
+}
+
+// dealsExport выгрузка сделок робота.
+type dealsExport []robot.Deal
+
+func (e dealsExport) Len() int {
+	return len(e)
+}
+
+func (e dealsExport) Columns() []string {
+	return []string{"id", "robot_id", "version", "side", "price", "lots", "created_at"}
+}
+
+func (e dealsExport) Row(i int) []string {
+	d := e[i]
+
+	return []string{strconv.Itoa(d.ID), strconv.Itoa(d.RobotID), strconv.Itoa(d.Version), d.Side,
+		formatFloat(d.Price), strconv.Itoa(d.Lots), d.CreatedAt.Format(time.RFC3339)}
+}
+
+func (e dealsExport) Item(i int) interface{} {
+	return e[i]
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatNullTime возвращает время в RFC 3339 или пустую строку, если времени нет.
+func formatNullTime(t robot.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return t.Time.Format(time.RFC3339)
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/gorilla/websocket"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+// graphqlMaxDepth наибольшая вложенность запроса GraphQL, она ограничивает цепочки parent и owner.
+const graphqlMaxDepth = 8
+
+// graphqlSchemaDef схема GraphQL API. Поля повторяют JSON REST API в camelCase, ID — строки с числом.
+const graphqlSchemaDef = `
+schema {
+	query: Query
+	mutation: Mutation
+	subscription: Subscription
+}
+
+scalar Time
+
+type Query {
+	# Пользователь текущей сессии.
+	me: User!
+	# Робот, которого видит пользователь; робота с видимостью link открывает shareToken.
+	robot(id: ID!, shareToken: String): Robot!
+	# Каталог роботов, которых видит пользователь, как GET /api/v1/robots.
+	robots(filter: RobotFilter, sort: String, desc: Boolean, first: Int, after: String): RobotPage!
+}
+
+type Mutation {
+	activateRobot(id: ID!): Robot!
+	deactivateRobot(id: ID!): Robot!
+	stopRobot(id: ID!): Robot!
+	# Помещает робота в корзину и возвращает удаленного робота.
+	deleteRobot(id: ID!): Robot!
+	restoreRobot(id: ID!): Robot!
+	# Добавляет копию робота в избранное и возвращает копию.
+	favouriteRobot(id: ID!, mirror: Boolean, lots: Int, shareToken: String): Robot!
+	unfollowRobot(id: ID!): Robot!
+}
+
+type Subscription {
+	# Робот сразу и после каждого его изменения. Подписка завершается, когда пользователь перестает видеть робота.
+	robotChanged(id: ID!, shareToken: String): Robot!
+}
+
+input RobotFilter {
+	ticker: String
+	ownerUserId: ID
+	parentRobotId: ID
+	isActive: Boolean
+	isFavourite: Boolean
+	minYield: Float
+	maxYield: Float
+}
+
+type User {
+	id: ID!
+	firstName: String!
+	lastName: String!
+	# Видно только самому пользователю.
+	email: String
+	# Дата в формате 2006-01-02, видна только самому пользователю.
+	birthday: String
+	# Роботы пользователя, которых видит пользователь текущей сессии.
+	robots(filter: RobotFilter, sort: String, desc: Boolean, first: Int, after: String): RobotPage!
+}
+
+type RobotPage {
+	robots: [Robot!]!
+	# Курсор следующей страницы для аргумента after, null на последней странице.
+	nextCursor: String
+}
+
+type Robot {
+	id: ID!
+	ownerUserId: ID!
+	owner: User!
+	parentRobotId: ID
+	# Родитель избранного робота, null, если пользователь его не видит.
+	parent: Robot
+	clonedFromId: ID
+	templateId: ID
+	isFavourite: Boolean!
+	isActive: Boolean!
+	status: String!
+	ticker: String!
+	buyPrice: Float!
+	sellPrice: Float!
+	planStart: Time
+	planEnd: Time
+	planYield: Float!
+	factYield: Float!
+	dealsCount: Int!
+	autoClose: Boolean!
+	schedule: String!
+	version: Int!
+	isMirror: Boolean!
+	lots: Int!
+	followers: Int!
+	visibility: String!
+	activatedAt: Time
+	deactivatedAt: Time
+	createdAt: Time
+	deletedAt: Time
+	# Действия, которые сейчас допускает состояние робота.
+	actions: [String!]!
+	# Сделки робота, доступны только владельцу.
+	deals: [Deal!]
+	# Статистика за период day, week, month, year или all, по умолчанию all.
+	stats(period: String): Stats!
+}
+
+type Deal {
+	id: ID!
+	robotId: ID!
+	version: Int!
+	side: String!
+	price: Float!
+	lots: Int!
+	createdAt: Time!
+}
+
+type Stats {
+	yield: Float!
+	sharpe: Float!
+	deals: Int!
+	drawdown: Float!
+}
+`
+
+type remoteAddrKey struct{}
+
+// graphqlRequest тело запроса GraphQL.
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphqlError ошибка резолвера GraphQL. Сообщение и extensions code, status и fields берутся из errorKinds
+// так же, как ответ REST API.
+type graphqlError struct {
+	e apiError
+}
+
+func (g *graphqlError) Error() string {
+	return g.e.Detail
+}
+
+// Extensions возвращает поле extensions ошибки в ответе GraphQL.
+func (g *graphqlError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"code": g.e.Code, "status": g.e.Status}
+	if len(g.e.Fields) > 0 {
+		ext["fields"] = g.e.Fields
+	}
+
+	return ext
+}
+
+// graphqlError возвращает ошибку для ответа GraphQL и пишет err в лог вместе с msg.
+func (h *Handler) graphqlError(ctx context.Context, msg string, err error) error {
+	e, _ := classify(err)
+
+	h.logger.Warnw(msg, "error", err, "status", e.Status, "trackingID", middleware.GetReqID(ctx), "RealIP", remoteAddr(ctx))
+
+	return &graphqlError{e: e}
+}
+
+// newGraphQLSchema возвращает схему GraphQL с резолверами хэндлера.
+func (h *Handler) newGraphQLSchema() *graphql.Schema {
+	return graphql.MustParseSchema(graphqlSchemaDef, &graphqlResolver{h: h}, graphql.MaxDepth(graphqlMaxDepth))
+}
+
+// GraphQL выполняет запрос или мутацию GraphQL. Ошибки резолверов возвращаются в поле errors со статусом 200,
+// а ошибка в теле запроса — ответом application/problem+json.
+func (h *Handler) GraphQL(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+
+	var req graphqlRequest
+	if err := validation.DecodeJSON(r.Body, &req); err != nil {
+		h.fail(w, r, "invalid graphql request", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if req.Query == "" {
+		h.fail(w, r, "invalid graphql request", validation.Field(validation.ErrInvalidInput, "query", "is required"))
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), remoteAddrKey{}, remoteAddr)
+	resp := h.graphql.Exec(h.withGraphQLLoaders(ctx), req.Query, req.OperationName, req.Variables)
+
+	h.writeJSON(w, http.StatusOK, resp, reqID, remoteAddr)
+}
+
+// Сообщения протокола graphql-ws (subscriptions-transport-ws), который используют клиенты Apollo.
+const (
+	graphqlWSProtocol       = "graphql-ws"
+	graphqlWSConnectionInit = "connection_init"
+	graphqlWSConnectionAck  = "connection_ack"
+	graphqlWSTerminate      = "connection_terminate"
+	graphqlWSStart          = "start"
+	graphqlWSStop           = "stop"
+	graphqlWSData           = "data"
+	graphqlWSError          = "error"
+	graphqlWSComplete       = "complete"
+)
+
+// graphqlWSMessage сообщение протокола graphql-ws.
+type graphqlWSMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// graphqlConn соединение websocket с операциями GraphQL клиента по их ID.
+type graphqlConn struct {
+	h          *Handler
+	conn       *websocket.Conn
+	writeMutex sync.Mutex
+	mutex      sync.Mutex
+	operations map[string]context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// GraphQLSubscriptions выполняет операции GraphQL, в том числе подписки, по websocket с протоколом graphql-ws.
+// Токен сессии проверяет authentication, браузер передает его в query-параметре token.
+func (h *Handler) GraphQLSubscriptions(w http.ResponseWriter, r *http.Request) {
+	up := websocket.Upgrader{
+		Subprotocols: []string{graphqlWSProtocol},
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+
+	conn, err := up.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Warnw("can't upgrade graphql connection", "error", err,
+			"trackingID", middleware.GetReqID(r.Context()), "RealIP", r.RemoteAddr)
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(r.Context(), remoteAddrKey{}, r.RemoteAddr))
+	c := &graphqlConn{h: h, conn: conn, operations: make(map[string]context.CancelFunc)}
+
+	defer func() {
+		cancel()
+		c.wg.Wait()
+		conn.Close()
+	}()
+
+	for {
+		var msg graphqlWSMessage
+		if err = conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case graphqlWSConnectionInit:
+			c.send(graphqlWSMessage{Type: graphqlWSConnectionAck})
+		case graphqlWSStart:
+			c.start(ctx, msg)
+		case graphqlWSStop:
+			c.stop(msg.ID)
+		case graphqlWSTerminate:
+			return
+		}
+	}
+}
+
+// start запускает операцию msg.ID и отправляет клиенту ее результаты, затем complete.
+func (c *graphqlConn) start(ctx context.Context, msg graphqlWSMessage) {
+	var req graphqlRequest
+	if err := json.Unmarshal(msg.Payload, &req); err != nil || req.Query == "" {
+		payload, _ := json.Marshal(map[string]string{"message": "payload must contain a query"})
+		c.send(graphqlWSMessage{ID: msg.ID, Type: graphqlWSError, Payload: payload})
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.h.withGraphQLLoaders(ctx))
+
+	c.mutex.Lock()
+	_, busy := c.operations[msg.ID]
+	if !busy {
+		c.operations[msg.ID] = cancel
+	}
+	c.mutex.Unlock()
+
+	if busy {
+		cancel()
+		payload, _ := json.Marshal(map[string]string{"message": "operation id is already in use"})
+		c.send(graphqlWSMessage{ID: msg.ID, Type: graphqlWSError, Payload: payload})
+
+		return
+	}
+
+	responses, err := c.h.graphql.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		cancel()
+		payload, _ := json.Marshal(map[string]string{"message": err.Error()})
+		c.send(graphqlWSMessage{ID: msg.ID, Type: graphqlWSError, Payload: payload})
+
+		return
+	}
+
+	c.wg.Add(1)
+
+	go func() {
+		defer c.wg.Done()
+		defer cancel()
+
+		for resp := range responses {
+			payload, err := json.Marshal(resp)
+			if err != nil {
+				c.h.logger.Warnw("unable to marshal graphql response", "error", err)
+				continue
+			}
+
+			c.send(graphqlWSMessage{ID: msg.ID, Type: graphqlWSData, Payload: payload})
+		}
+
+		if ctx.Err() == nil {
+			c.send(graphqlWSMessage{ID: msg.ID, Type: graphqlWSComplete})
+		}
+
+		c.stop(msg.ID)
+	}()
+}
+
+// stop отменяет операцию id.
+func (c *graphqlConn) stop(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if cancel, ok := c.operations[id]; ok {
+		cancel()
+		delete(c.operations, id)
+	}
+}
+
+// send отправляет сообщение клиенту, ошибки записи завершают соединение при следующем чтении.
+func (c *graphqlConn) send(msg graphqlWSMessage) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	_ = c.conn.WriteJSON(msg)
+}
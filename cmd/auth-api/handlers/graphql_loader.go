@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+)
+
+type graphqlLoadersKey struct{}
+
+// batchLoader загружает значения по ключам пакетами, как DataLoader. Резолвер списка регистрирует ключи
+// своих элементов через prime, а первый load загружает одним вызовом fetch все зарегистрированные ключи.
+// Результаты хранятся до конца запроса, отсутствующий ключ загружается как nil.
+type batchLoader struct {
+	mutex   sync.Mutex
+	fetch   func(keys []int) (map[int]interface{}, error)
+	pending map[int]bool
+	values  map[int]interface{}
+}
+
+func newBatchLoader(fetch func(keys []int) (map[int]interface{}, error)) *batchLoader {
+	return &batchLoader{fetch: fetch, pending: make(map[int]bool), values: make(map[int]interface{})}
+}
+
+// prime регистрирует ключи для следующей загрузки.
+func (l *batchLoader) prime(keys ...int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, key := range keys {
+		if _, ok := l.values[key]; !ok {
+			l.pending[key] = true
+		}
+	}
+}
+
+// load возвращает значение key. Если его еще нет, key загружается вместе с остальными зарегистрированными ключами.
+// Параллельные вызовы ждут одну загрузку.
+func (l *batchLoader) load(key int) (interface{}, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if v, ok := l.values[key]; ok {
+		return v, nil
+	}
+
+	l.pending[key] = true
+
+	keys := make([]int, 0, len(l.pending))
+	for k := range l.pending {
+		keys = append(keys, k)
+	}
+
+	values, err := l.fetch(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		l.values[k] = values[k]
+		delete(l.pending, k)
+	}
+
+	return l.values[key], nil
+}
+
+// graphqlLoaders загрузчики одного запроса GraphQL от пользователя viewerID: сделки по роботу,
+// видимые пользователю роботы и пользователи. Загрузчики не делятся между запросами, чтобы не отдавать
+// устаревшие данные, у каждого события подписки свои загрузчики.
+type graphqlLoaders struct {
+	h        *Handler
+	viewerID int
+	deals    *batchLoader
+	robots   *batchLoader
+	users    *batchLoader
+}
+
+func (h *Handler) newGraphQLLoaders(viewerID int) *graphqlLoaders {
+	l := &graphqlLoaders{h: h, viewerID: viewerID}
+	l.deals = newBatchLoader(l.fetchDeals)
+	l.robots = newBatchLoader(l.fetchRobots)
+	l.users = newBatchLoader(l.fetchUsers)
+
+	return l
+}
+
+// withGraphQLLoaders возвращает контекст с загрузчиками для пользователя сессии из ctx.
+func (h *Handler) withGraphQLLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, graphqlLoadersKey{}, h.newGraphQLLoaders(sessionUserID(ctx)))
+}
+
+func loadersFrom(ctx context.Context) *graphqlLoaders {
+	return ctx.Value(graphqlLoadersKey{}).(*graphqlLoaders)
+}
+
+func (l *graphqlLoaders) fetchDeals(robotIDs []int) (map[int]interface{}, error) {
+	deals, err := l.h.robotStorage.FindDealsByRobotIDs(robotIDs)
+	if err != nil {
+		return nil, fmt.Errorf("func robotStorage.FindDealsByRobotIDs return with error: %s", err)
+	}
+
+	byRobot := make(map[int][]robot.Deal, len(robotIDs))
+	for _, d := range deals {
+		byRobot[d.RobotID] = append(byRobot[d.RobotID], d)
+	}
+
+	values := make(map[int]interface{}, len(robotIDs))
+	for _, id := range robotIDs {
+		values[id] = byRobot[id]
+	}
+
+	return values, nil
+}
+
+func (l *graphqlLoaders) fetchRobots(robotIDs []int) (map[int]interface{}, error) {
+	robots, err := l.h.robotStorage.FindVisibleByIDs(robotIDs, l.viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("func robotStorage.FindVisibleByIDs return with error: %s", err)
+	}
+
+	values := make(map[int]interface{}, len(robots))
+	for i := range robots {
+		values[robots[i].RobotID] = &robots[i]
+	}
+
+	return values, nil
+}
+
+func (l *graphqlLoaders) fetchUsers(userIDs []int) (map[int]interface{}, error) {
+	users, err := l.h.userStorage.FindByIDs(userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("func userStorage.FindByIDs return with error: %s", err)
+	}
+
+	values := make(map[int]interface{}, len(users))
+	for i := range users {
+		values[users[i].ID] = &users[i]
+	}
+
+	return values, nil
+}
+
+// robot возвращает резолвер робота и регистрирует ключи, которые понадобятся его полям.
+func (l *graphqlLoaders) robot(rob *robot.Robot) *robotResolver {
+	l.deals.prime(rob.RobotID)
+	l.users.prime(rob.OwnerUserID)
+
+	if rob.ParentRobotID != 0 {
+		l.robots.prime(rob.ParentRobotID)
+	}
+
+	return &robotResolver{rob: rob, l: l}
+}
+
+// robotList возвращает резолверы роботов списка, их сделки, владельцы и родители загрузятся одним пакетом.
+func (l *graphqlLoaders) robotList(robots []robot.Robot) []*robotResolver {
+	resolvers := make([]*robotResolver, len(robots))
+	for i := range robots {
+		resolvers[i] = l.robot(&robots[i])
+	}
+
+	return resolvers
+}
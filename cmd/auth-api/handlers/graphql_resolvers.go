@@ -0,0 +1,632 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+)
+
+// graphqlResolver корневой резолвер схемы GraphQL: запросы, мутации и подписки. Запросы и мутации
+// проверяют владельца и видимость робота так же, как REST API.
+type graphqlResolver struct {
+	h *Handler
+}
+
+type robotIDArgs struct {
+	ID graphql.ID
+}
+
+type visibleRobotArgs struct {
+	ID         graphql.ID
+	ShareToken *string
+}
+
+// robotsArgs фильтры, сортировка и страница списка роботов.
+type robotsArgs struct {
+	Filter *robotFilter
+	Sort   *string
+	Desc   *bool
+	First  *int32
+	After  *string
+}
+
+type robotFilter struct {
+	Ticker        *string
+	OwnerUserID   *graphql.ID
+	ParentRobotID *graphql.ID
+	IsActive      *bool
+	IsFavourite   *bool //nolint:misspell
+	MinYield      *float64
+	MaxYield      *float64
+}
+
+// values переводит аргументы в query-параметры REST API, чтобы проверять их одинаково.
+func (args *robotsArgs) values() url.Values {
+	values := url.Values{}
+
+	setString := func(name string, value *string) {
+		if value != nil {
+			values.Set(name, *value)
+		}
+	}
+
+	setString("sort", args.Sort)
+	setString("cursor", args.After)
+
+	if args.Desc != nil && *args.Desc {
+		values.Set("order", robot.OrderDesc)
+	}
+
+	if args.First != nil {
+		values.Set("limit", strconv.Itoa(int(*args.First)))
+	}
+
+	if f := args.Filter; f != nil {
+		setString("ticker", f.Ticker)
+		setString("user", (*string)(f.OwnerUserID))
+		setString("parent", (*string)(f.ParentRobotID))
+
+		if f.IsActive != nil {
+			values.Set("is_active", strconv.FormatBool(*f.IsActive))
+		}
+
+		if f.IsFavourite != nil {
+			values.Set("is_favourite", strconv.FormatBool(*f.IsFavourite)) //nolint:misspell
+		}
+
+		if f.MinYield != nil {
+			values.Set("min_yield", strconv.FormatFloat(*f.MinYield, 'f', -1, 64))
+		}
+
+		if f.MaxYield != nil {
+			values.Set("max_yield", strconv.FormatFloat(*f.MaxYield, 'f', -1, 64))
+		}
+	}
+
+	return values
+}
+
+// graphqlID возвращает положительный ID из аргумента запроса.
+func graphqlID(id graphql.ID) (int, error) {
+	n, err := strconv.Atoi(string(id))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%w: %q is not a positive integer", robot.ErrInvalidID, id)
+	}
+
+	return n, nil
+}
+
+func idOf(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
+
+// Me возвращает пользователя текущей сессии.
+func (r *graphqlResolver) Me(ctx context.Context) (*userResolver, error) {
+	l := loadersFrom(ctx)
+
+	u, err := r.h.userStorage.FindByID(l.viewerID)
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "func userStorage.FindByID return with error", err)
+	}
+
+	return &userResolver{u: u, l: l}, nil
+}
+
+// Robot возвращает робота, которого видит пользователь, с учетом токена ссылки.
+func (r *graphqlResolver) Robot(ctx context.Context, args visibleRobotArgs) (*robotResolver, error) {
+	l := loadersFrom(ctx)
+
+	rob, err := r.visible(l, args)
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "can't find visible robot", err)
+	}
+
+	return l.robot(rob), nil
+}
+
+func (r *graphqlResolver) visible(l *graphqlLoaders, args visibleRobotArgs) (*robot.Robot, error) {
+	robotID, err := graphqlID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var shareToken string
+	if args.ShareToken != nil {
+		shareToken = *args.ShareToken
+	}
+
+	return r.h.robotStorage.FindVisible(robotID, l.viewerID, shareToken)
+}
+
+// Robots возвращает страницу каталога роботов, которых видит пользователь.
+func (r *graphqlResolver) Robots(ctx context.Context, args robotsArgs) (*robotPageResolver, error) {
+	return findRobotPage(ctx, loadersFrom(ctx), "GET /robots", args.values())
+}
+
+// findRobotPage возвращает страницу роботов по query-параметрам values среди роботов, которых видит пользователь.
+// Запрос расходует квоту маршрута REST API route, который повторяет.
+func findRobotPage(ctx context.Context, l *graphqlLoaders, route string,
+	values url.Values) (*robotPageResolver, error) {
+	if err := l.h.chargeRoute(ctx, route); err != nil {
+		return nil, l.h.graphqlError(ctx, "rate limit exceeded", err)
+	}
+
+	q, err := robot.ParseQuery(values)
+	if err != nil {
+		return nil, l.h.graphqlError(ctx, "invalid query", err)
+	}
+
+	q.ViewerID = &l.viewerID
+
+	page, err := l.h.robotStorage.Find(q)
+	if err != nil {
+		return nil, l.h.graphqlError(ctx, "func robotStorage.Find return with error", err)
+	}
+
+	resolver := &robotPageResolver{robots: l.robotList(page.Robots)}
+	if page.NextCursor != "" {
+		resolver.nextCursor = &page.NextCursor
+	}
+
+	return resolver, nil
+}
+
+// ActivateRobot запускает робота пользователя.
+func (r *graphqlResolver) ActivateRobot(ctx context.Context, args robotIDArgs) (*robotResolver, error) {
+	return r.transition(ctx, args.ID, robot.ActionActivate)
+}
+
+// DeactivateRobot приостанавливает робота пользователя.
+func (r *graphqlResolver) DeactivateRobot(ctx context.Context, args robotIDArgs) (*robotResolver, error) {
+	return r.transition(ctx, args.ID, robot.ActionDeactivate)
+}
+
+// StopRobot завершает работу робота пользователя.
+func (r *graphqlResolver) StopRobot(ctx context.Context, args robotIDArgs) (*robotResolver, error) {
+	return r.transition(ctx, args.ID, robot.ActionStop)
+}
+
+// transition выполняет действие над роботом пользователя и возвращает робота в новом состоянии.
+func (r *graphqlResolver) transition(ctx context.Context, id graphql.ID, action robot.Action) (*robotResolver, error) {
+	rob, err := r.own(ctx, id, r.h.robotStorage.FindByID)
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "can't find own robot", err)
+	}
+
+	if err = r.h.robotStorage.Transition(rob.RobotID, action, robot.SourceUser, 0); err != nil {
+		return nil, r.h.graphqlError(ctx, "func robotStorage.Transition return with error", err)
+	}
+
+	r.h.logger.Infow("robot transition", "action", action, "robotID", rob.RobotID,
+		"trackingID", middleware.GetReqID(ctx), "RealIP", remoteAddr(ctx))
+	r.h.publishRobotChanged(rob.RobotID, middleware.GetReqID(ctx), remoteAddr(ctx))
+
+	return r.found(ctx, rob.RobotID, r.h.robotStorage.FindByID)
+}
+
+// DeleteRobot помещает робота пользователя в корзину и возвращает удаленного робота.
+func (r *graphqlResolver) DeleteRobot(ctx context.Context, args robotIDArgs) (*robotResolver, error) {
+	rob, err := r.own(ctx, args.ID, r.h.robotStorage.FindByID)
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "can't find own robot", err)
+	}
+
+	if _, err = r.h.closeBeforeDelete(ctx, rob, 0); err != nil {
+		return nil, r.h.graphqlError(ctx, "can't close robot position before delete", err)
+	}
+
+	if err = r.h.robotStorage.SoftDelete(rob.RobotID, 0); err != nil {
+		return nil, r.h.graphqlError(ctx, "func robotStorage.SoftDelete return with error", err)
+	}
+
+	r.h.logger.Infow("'soft delete' robot", "userID", rob.OwnerUserID,
+		"trackingID", middleware.GetReqID(ctx), "RealIP", remoteAddr(ctx))
+	r.h.publishRobotChanged(rob.RobotID, middleware.GetReqID(ctx), remoteAddr(ctx))
+
+	return r.found(ctx, rob.RobotID, r.h.robotStorage.FindDeletedByID)
+}
+
+// RestoreRobot возвращает робота пользователя из корзины.
+func (r *graphqlResolver) RestoreRobot(ctx context.Context, args robotIDArgs) (*robotResolver, error) {
+	rob, err := r.own(ctx, args.ID, r.h.robotStorage.FindDeletedByID)
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "can't find own deleted robot", err)
+	}
+
+	if err = r.h.robotStorage.Restore(rob.RobotID); err != nil {
+		return nil, r.h.graphqlError(ctx, "func robotStorage.Restore return with error", err)
+	}
+
+	r.h.publishRobotChanged(rob.RobotID, middleware.GetReqID(ctx), remoteAddr(ctx))
+
+	return r.found(ctx, rob.RobotID, r.h.robotStorage.FindByID)
+}
+
+type favouriteArgs struct { //nolint:misspell
+	ID         graphql.ID
+	Mirror     *bool
+	Lots       *int32
+	ShareToken *string
+}
+
+// FavouriteRobot добавляет копию видимого пользователю робота в его избранное и возвращает копию.
+func (r *graphqlResolver) FavouriteRobot(ctx context.Context, args favouriteArgs) (*robotResolver, error) { //nolint:misspell
+	l := loadersFrom(ctx)
+
+	rob, err := r.visible(l, visibleRobotArgs{ID: args.ID, ShareToken: args.ShareToken})
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "can't find visible robot", err)
+	}
+
+	var follow robot.Follow
+	if args.Mirror != nil {
+		follow.Mirror = *args.Mirror
+	}
+
+	if args.Lots != nil {
+		follow.Lots = int(*args.Lots)
+	}
+
+	follower, err := r.h.robotStorage.FavouriteRobot(rob.RobotID, l.viewerID, follow, 0)
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "func robotStorage.FavouriteRobot return with error", err)
+	}
+
+	r.h.publishRobotChanged(rob.RobotID, middleware.GetReqID(ctx), remoteAddr(ctx))
+	r.h.publishRobotChanged(follower.RobotID, middleware.GetReqID(ctx), remoteAddr(ctx))
+
+	return l.robot(follower), nil
+}
+
+// UnfollowRobot отключает повторение родителя у робота пользователя.
+func (r *graphqlResolver) UnfollowRobot(ctx context.Context, args robotIDArgs) (*robotResolver, error) {
+	rob, err := r.own(ctx, args.ID, r.h.robotStorage.FindByID)
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "can't find own robot", err)
+	}
+
+	if !rob.IsMirror {
+		err = fmt.Errorf("%w: robot %d doesn't follow its parent", robot.ErrMirror, rob.RobotID)
+		return nil, r.h.graphqlError(ctx, "robot doesn't follow its parent", err)
+	}
+
+	if err = r.h.robotStorage.Unfollow(rob.RobotID, 0); err != nil {
+		return nil, r.h.graphqlError(ctx, "func robotStorage.Unfollow return with error", err)
+	}
+
+	r.h.publishRobotChanged(rob.RobotID, middleware.GetReqID(ctx), remoteAddr(ctx))
+
+	if rob.ParentRobotID != 0 {
+		r.h.publishRobotChanged(rob.ParentRobotID, middleware.GetReqID(ctx), remoteAddr(ctx))
+	}
+
+	return r.found(ctx, rob.RobotID, r.h.robotStorage.FindByID)
+}
+
+// RobotChanged отправляет робота сразу и после каждого его изменения. Подписка завершается,
+// когда пользователь перестает видеть робота или клиент ее отменяет.
+func (r *graphqlResolver) RobotChanged(ctx context.Context, args visibleRobotArgs) (<-chan *robotResolver, error) {
+	viewerID := sessionUserID(ctx)
+	l := r.h.newGraphQLLoaders(viewerID)
+
+	if _, err := r.visible(l, args); err != nil {
+		return nil, r.h.graphqlError(ctx, "can't find visible robot", err)
+	}
+
+	robotID, _ := graphqlID(args.ID)
+
+	var shareToken string
+	if args.ShareToken != nil {
+		shareToken = *args.ShareToken
+	}
+
+	// Подписка идет раньше первого чтения робота, чтобы не пропустить изменение между ними.
+	changed, stop := r.h.wsocket.watch(robotID)
+	robots := make(chan *robotResolver)
+
+	go func() {
+		defer close(robots)
+		defer stop()
+
+		for {
+			rob, err := r.h.robotStorage.FindVisible(robotID, viewerID, shareToken)
+			if err != nil {
+				r.h.logger.Infow("robot subscription is over", "robotID", robotID, "error", err)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case robots <- r.h.newGraphQLLoaders(viewerID).robot(rob):
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			}
+		}
+	}()
+
+	return robots, nil
+}
+
+// own находит робота функцией find и проверяет, что он принадлежит пользователю текущей сессии.
+func (r *graphqlResolver) own(ctx context.Context, id graphql.ID,
+	find func(robotID int) (*robot.Robot, error)) (*robot.Robot, error) {
+	robotID, err := graphqlID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOwnRobot(ctx, robotID, find)
+}
+
+// found возвращает робота после изменения, find выбирает неудаленного или удаленного робота.
+func (r *graphqlResolver) found(ctx context.Context, robotID int,
+	find func(robotID int) (*robot.Robot, error)) (*robotResolver, error) {
+	rob, err := find(robotID)
+	if err != nil {
+		return nil, r.h.graphqlError(ctx, "can't find changed robot", err)
+	}
+
+	return loadersFrom(ctx).robot(rob), nil
+}
+
+// userResolver пользователь. Email и дата рождения видны только самому пользователю.
+type userResolver struct {
+	u *user.User
+	l *graphqlLoaders
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return idOf(r.u.ID)
+}
+
+func (r *userResolver) FirstName() string {
+	return r.u.FirstName
+}
+
+func (r *userResolver) LastName() string {
+	return r.u.LastName
+}
+
+func (r *userResolver) self() bool {
+	return r.u.ID == r.l.viewerID
+}
+
+func (r *userResolver) Email() *string {
+	if !r.self() {
+		return nil
+	}
+
+	return &r.u.Email
+}
+
+func (r *userResolver) Birthday() *string {
+	if !r.self() || r.u.Birthday.IsZero() {
+		return nil
+	}
+
+	birthday := r.u.Birthday.Format(user.BirthdayLayout)
+
+	return &birthday
+}
+
+// Robots возвращает страницу роботов пользователя, которых видит пользователь текущей сессии.
+func (r *userResolver) Robots(ctx context.Context, args robotsArgs) (*robotPageResolver, error) {
+	values := args.values()
+	values.Set("user", strconv.Itoa(r.u.ID))
+
+	return findRobotPage(ctx, r.l, "GET /users/{id}/robots", values)
+}
+
+type robotPageResolver struct {
+	robots     []*robotResolver
+	nextCursor *string
+}
+
+func (r *robotPageResolver) Robots() []*robotResolver {
+	return r.robots
+}
+
+// NextCursor курсор следующей страницы, null на последней странице.
+func (r *robotPageResolver) NextCursor() *string {
+	return r.nextCursor
+}
+
+// robotResolver робот. Владелец, родитель и сделки загружаются пакетами вместе с остальными роботами запроса.
+type robotResolver struct {
+	rob *robot.Robot
+	l   *graphqlLoaders
+}
+
+func (r *robotResolver) ID() graphql.ID           { return idOf(r.rob.RobotID) }
+func (r *robotResolver) OwnerUserID() graphql.ID  { return idOf(r.rob.OwnerUserID) }
+func (r *robotResolver) IsFavourite() bool        { return r.rob.IsFavourite } //nolint:misspell
+func (r *robotResolver) IsActive() bool           { return r.rob.IsActive }
+func (r *robotResolver) Status() string           { return string(r.rob.Status) }
+func (r *robotResolver) Ticker() string           { return r.rob.Ticker }
+func (r *robotResolver) BuyPrice() float64        { return r.rob.BuyPrice }
+func (r *robotResolver) SellPrice() float64       { return r.rob.SellPrice }
+func (r *robotResolver) PlanStart() *graphql.Time { return graphqlTime(r.rob.PlanStart) }
+func (r *robotResolver) PlanEnd() *graphql.Time   { return graphqlTime(r.rob.PlanEnd) }
+func (r *robotResolver) PlanYield() float64       { return r.rob.PlanYield }
+func (r *robotResolver) FactYield() float64       { return r.rob.FactYield }
+func (r *robotResolver) DealsCount() int32        { return int32(r.rob.DealsCount) }
+func (r *robotResolver) AutoClose() bool          { return r.rob.AutoClose }
+func (r *robotResolver) Schedule() string         { return r.rob.Schedule }
+func (r *robotResolver) Version() int32           { return int32(r.rob.Version) }
+func (r *robotResolver) IsMirror() bool           { return r.rob.IsMirror }
+func (r *robotResolver) Lots() int32              { return int32(r.rob.Lots) }
+func (r *robotResolver) Followers() int32         { return int32(r.rob.Followers) }
+func (r *robotResolver) Visibility() string       { return string(r.rob.Visibility) }
+func (r *robotResolver) ActivatedAt() *graphql.Time {
+	return graphqlTime(r.rob.ActivatedAt)
+}
+func (r *robotResolver) DeactivatedAt() *graphql.Time {
+	return graphqlTime(r.rob.DeactivatedAt)
+}
+func (r *robotResolver) CreatedAt() *graphql.Time { return graphqlTime(r.rob.CreatedAt) }
+func (r *robotResolver) DeletedAt() *graphql.Time { return graphqlTime(r.rob.DeletedAt) }
+
+func (r *robotResolver) ParentRobotID() *graphql.ID {
+	return optionalID(r.rob.ParentRobotID)
+}
+
+func (r *robotResolver) ClonedFromID() *graphql.ID {
+	return optionalID(r.rob.ClonedFromID)
+}
+
+func (r *robotResolver) TemplateID() *graphql.ID {
+	return optionalID(r.rob.TemplateID)
+}
+
+// Actions действия, которые сейчас допускает состояние робота.
+func (r *robotResolver) Actions() []string {
+	actions := r.rob.AllowedActions(time.Now())
+
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = string(a)
+	}
+
+	return names
+}
+
+// Owner возвращает владельца робота.
+func (r *robotResolver) Owner(ctx context.Context) (*userResolver, error) {
+	v, err := r.l.users.load(r.rob.OwnerUserID)
+	if err != nil {
+		return nil, r.l.h.graphqlError(ctx, "can't load robot owner", err)
+	}
+
+	if v == nil {
+		err = fmt.Errorf("%w: %d", user.ErrNotFound, r.rob.OwnerUserID)
+		return nil, r.l.h.graphqlError(ctx, "can't load robot owner", err)
+	}
+
+	return &userResolver{u: v.(*user.User), l: r.l}, nil
+}
+
+// Parent возвращает родителя избранного робота, null, если робот не копия или пользователь не видит родителя.
+func (r *robotResolver) Parent(ctx context.Context) (*robotResolver, error) {
+	if r.rob.ParentRobotID == 0 {
+		return nil, nil
+	}
+
+	v, err := r.l.robots.load(r.rob.ParentRobotID)
+	if err != nil {
+		return nil, r.l.h.graphqlError(ctx, "can't load parent robot", err)
+	}
+
+	if v == nil {
+		return nil, nil
+	}
+
+	return r.l.robot(v.(*robot.Robot)), nil
+}
+
+// Deals возвращает сделки робота, они доступны только владельцу.
+func (r *robotResolver) Deals(ctx context.Context) (*[]*dealResolver, error) {
+	if r.rob.OwnerUserID != r.l.viewerID {
+		err := fmt.Errorf("%w: user %d doesn't own robot %d", errForbidden, r.l.viewerID, r.rob.RobotID)
+		return nil, r.l.h.graphqlError(ctx, "can't load robot deals", err)
+	}
+
+	deals, err := r.deals()
+	if err != nil {
+		return nil, r.l.h.graphqlError(ctx, "can't load robot deals", err)
+	}
+
+	resolvers := make([]*dealResolver, len(deals))
+	for i := range deals {
+		resolvers[i] = &dealResolver{d: &deals[i]}
+	}
+
+	return &resolvers, nil
+}
+
+func (r *robotResolver) deals() ([]robot.Deal, error) {
+	v, err := r.l.deals.load(r.rob.RobotID)
+	if err != nil {
+		return nil, err
+	}
+
+	deals, _ := v.([]robot.Deal)
+
+	return deals, nil
+}
+
+type statsArgs struct {
+	Period *string
+}
+
+// Stats возвращает статистику робота за период, по умолчанию за все время.
+func (r *robotResolver) Stats(ctx context.Context, args statsArgs) (*statsResolver, error) {
+	var period string
+	if args.Period != nil {
+		period = *args.Period
+	}
+
+	since, err := robot.PeriodStart(period, time.Now())
+	if err != nil {
+		return nil, r.l.h.graphqlError(ctx, "invalid period", err)
+	}
+
+	deals, err := r.deals()
+	if err != nil {
+		return nil, r.l.h.graphqlError(ctx, "can't load robot deals", err)
+	}
+
+	return &statsResolver{st: robot.ComputeStats(r.rob, deals, since)}, nil
+}
+
+type dealResolver struct {
+	d *robot.Deal
+}
+
+func (r *dealResolver) ID() graphql.ID          { return idOf(r.d.ID) }
+func (r *dealResolver) RobotID() graphql.ID     { return idOf(r.d.RobotID) }
+func (r *dealResolver) Version() int32          { return int32(r.d.Version) }
+func (r *dealResolver) Side() string            { return r.d.Side }
+func (r *dealResolver) Price() float64          { return r.d.Price }
+func (r *dealResolver) Lots() int32             { return int32(r.d.Lots) }
+func (r *dealResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.d.CreatedAt} }
+
+type statsResolver struct {
+	st robot.Stats
+}
+
+func (r *statsResolver) Yield() float64    { return r.st.Yield }
+func (r *statsResolver) Sharpe() float64   { return r.st.Sharpe }
+func (r *statsResolver) Deals() int32      { return int32(r.st.Deals) }
+func (r *statsResolver) Drawdown() float64 { return r.st.Drawdown }
+
+// optionalID возвращает ID связанного объекта, для отсутствующей связи — nil.
+func optionalID(id int) *graphql.ID {
+	if id == 0 {
+		return nil
+	}
+
+	gid := idOf(id)
+
+	return &gid
+}
+
+// graphqlTime возвращает время для ответа GraphQL, для незаданного времени — nil.
+func graphqlTime(t robot.NullTime) *graphql.Time {
+	if !t.Valid || t.Time.IsZero() {
+		return nil
+	}
+
+	return &graphql.Time{Time: t.Time}
+}
@@ -0,0 +1,415 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+// countingStorages считают пакетные запросы загрузчиков GraphQL к хранилищам в памяти.
+type countingStorages struct {
+	mutex sync.Mutex
+	calls map[string]int
+}
+
+func (c *countingStorages) count(method string) {
+	c.mutex.Lock()
+	c.calls[method]++
+	c.mutex.Unlock()
+}
+
+func (c *countingStorages) get(method string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.calls[method]
+}
+
+type countingRobots struct {
+	*robot.StorageInMemory
+	counter *countingStorages
+}
+
+func (s countingRobots) FindDealsByRobotIDs(robotIDs []int) ([]robot.Deal, error) {
+	s.counter.count("FindDealsByRobotIDs")
+	return s.StorageInMemory.FindDealsByRobotIDs(robotIDs)
+}
+
+func (s countingRobots) FindVisibleByIDs(robotIDs []int, userID int) ([]robot.Robot, error) {
+	s.counter.count("FindVisibleByIDs")
+	return s.StorageInMemory.FindVisibleByIDs(robotIDs, userID)
+}
+
+type countingUsers struct {
+	*user.StorageInMemory
+	counter *countingStorages
+}
+
+func (s countingUsers) FindByIDs(ids []int) ([]user.User, error) {
+	s.counter.count("FindByIDs")
+	return s.StorageInMemory.FindByIDs(ids)
+}
+
+// graphqlResponse ответ GraphQL API в тестах.
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// errorCodes возвращает коды ошибок из extensions.
+func (r graphqlResponse) errorCodes() []interface{} {
+	codes := make([]interface{}, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		codes = append(codes, e.Extensions["code"])
+	}
+
+	return codes
+}
+
+// setupGraphQL возвращает хранилище роботов, счетчик пакетных запросов и тестовый сервер с роутингом хэндлера.
+// Первый пользователь с ID 0 регистрируется сразу, чтобы пользователи тестов получали положительные ID.
+func setupGraphQL(t *testing.T) (*Handler, *robot.StorageInMemory, *countingStorages, *httptest.Server) {
+	counter := &countingStorages{calls: make(map[string]int)}
+	robotStorage := robot.CreateStorageInMemory()
+	robots := countingRobots{StorageInMemory: robotStorage, counter: counter}
+	ws := NewWebsocket(robots)
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true, "SBER": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(),
+		countingUsers{StorageInMemory: user.CreateStorageInMemory(), counter: counter}, robots,
+		ws, broadcastPublisher{ws: ws}, instruments)
+
+	setupSignUp(h, t)
+
+	ts := httptest.NewServer(h.Routes())
+	t.Cleanup(ts.Close)
+
+	return h, robotStorage, counter, ts
+}
+
+// graphqlQuery выполняет запрос GraphQL от имени токена token.
+func graphqlQuery(t *testing.T, ts *httptest.Server, token, query string) graphqlResponse {
+	body, _ := json.Marshal(graphqlRequest{Query: query})
+	resp, code := testRequestWithAuth(t, ts, http.MethodPost, "/api/v1/graphql", "Bearer "+token, bytes.NewBuffer(body))
+
+	defer resp.Body.Close()
+
+	if code != http.StatusOK {
+		t.Fatalf("graphql request returned %d", code)
+	}
+
+	var result graphqlResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("can't decode graphql response: %s", err)
+	}
+
+	return result
+}
+
+func TestGraphQLDashboard(t *testing.T) {
+	assert := assert.New(t)
+	h, robotStorage, counter, ts := setupGraphQL(t)
+	owner := setupUser(h, t, "owner@example.com")
+	other := setupUser(h, t, "other@example.com")
+
+	parent := &robot.Robot{OwnerUserID: 2, Ticker: "SBER", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(robotStorage.Create(parent))
+
+	for _, ticker := range []string{"AAPL", "SBER"} {
+		rob := &robot.Robot{OwnerUserID: 1, Ticker: ticker, BuyPrice: 10, SellPrice: 20}
+		assert.NoError(robotStorage.Create(rob))
+		assert.NoError(robotStorage.Transition(rob.RobotID, robot.ActionActivate, robot.SourceUser, 0))
+		assert.NoError(robotStorage.Trade(rob, robot.Deal{Side: "buy", Price: 10}))
+	}
+
+	resp := graphqlQuery(t, ts, owner.Token, fmt.Sprintf(`mutation { favouriteRobot(id: "%d") { id } }`, parent.RobotID))
+	assert.Empty(resp.Errors)
+
+	resp = graphqlQuery(t, ts, owner.Token, `{
+		me {
+			id
+			email
+			robots {
+				robots {
+					ticker
+					owner { firstName email }
+					parent { id owner { id email } }
+					deals { side price }
+					stats { deals }
+				}
+				nextCursor
+			}
+		}
+	}`)
+	assert.Empty(resp.Errors)
+
+	var data struct {
+		Me struct {
+			ID     string
+			Email  string
+			Robots struct {
+				Robots []struct {
+					Ticker string
+					Owner  struct {
+						FirstName string
+						Email     *string
+					}
+					Parent *struct {
+						ID    string
+						Owner struct {
+							ID    string
+							Email *string
+						}
+					}
+					Deals []struct {
+						Side  string
+						Price float64
+					}
+				}
+				NextCursor *string
+			}
+		}
+	}
+
+	assert.NoError(json.Unmarshal(resp.Data, &data))
+	assert.Equal("1", data.Me.ID)
+	assert.Equal("owner@example.com", data.Me.Email)
+	assert.Nil(data.Me.Robots.NextCursor)
+
+	robots := data.Me.Robots.Robots
+	if !assert.Len(robots, 3) {
+		return
+	}
+
+	assert.Len(robots[0].Deals, 1)
+	assert.Equal("buy", robots[0].Deals[0].Side)
+	assert.Equal("owner@example.com", *robots[0].Owner.Email)
+	assert.Nil(robots[0].Parent)
+	assert.Empty(robots[2].Deals)
+
+	if assert.NotNil(robots[2].Parent) {
+		assert.Equal(fmt.Sprint(parent.RobotID), robots[2].Parent.ID)
+		assert.Equal("2", robots[2].Parent.Owner.ID)
+		assert.Nil(robots[2].Parent.Owner.Email, "email is visible only to the user")
+	}
+
+	assert.Equal(1, counter.get("FindDealsByRobotIDs"), "deals and stats of all robots are loaded in one batch")
+	assert.Equal(1, counter.get("FindVisibleByIDs"), "parents are loaded in one batch")
+	assert.Equal(2, counter.get("FindByIDs"), "owners are loaded in one batch per level")
+
+	resp = graphqlQuery(t, ts, other.Token, fmt.Sprintf(`{ robot(id: "%d") { ticker deals { id } } }`, parent.RobotID))
+	assert.Empty(resp.Errors)
+	assert.JSONEq(`{"robot":{"ticker":"SBER","deals":[]}}`, string(resp.Data))
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	type testCase struct {
+		Name          string
+		Query         string
+		ExpectedCodes []interface{}
+		ExpectedData  string
+	}
+
+	h, robotStorage, _, ts := setupGraphQL(t)
+	owner := setupUser(h, t, "owner@example.com")
+	other := setupUser(h, t, "other@example.com")
+
+	private := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, Visibility: robot.VisibilityPrivate}
+	public := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+
+	for _, rob := range []*robot.Robot{private, public} {
+		if err := robotStorage.Create(rob); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []testCase{
+		{Name: "Invisible robot", Query: fmt.Sprintf(`{ robot(id: "%d") { id } }`, private.RobotID),
+			ExpectedCodes: []interface{}{"not_found"}, ExpectedData: `null`},
+		{Name: "Invalid id", Query: `{ robot(id: "abc") { id } }`,
+			ExpectedCodes: []interface{}{"invalid_id"}, ExpectedData: `null`},
+		{Name: "Deals of another user robot", Query: fmt.Sprintf(`{ robot(id: "%d") { ticker deals { id } } }`, public.RobotID),
+			ExpectedCodes: []interface{}{"forbidden"}, ExpectedData: `{"robot":{"ticker":"AAPL","deals":null}}`},
+		{Name: "Action on another user robot", Query: fmt.Sprintf(`mutation { activateRobot(id: "%d") { id } }`, public.RobotID),
+			ExpectedCodes: []interface{}{"forbidden"}, ExpectedData: `null`},
+		{Name: "Unfollow missing robot", Query: `mutation { unfollowRobot(id: "999") { id } }`,
+			ExpectedCodes: []interface{}{"not_found"}, ExpectedData: `null`},
+		{Name: "Invalid sort", Query: `{ robots(sort: "nope") { nextCursor } }`,
+			ExpectedCodes: []interface{}{"invalid_query"}, ExpectedData: `null`},
+		{Name: "Unknown period", Query: fmt.Sprintf(`{ robot(id: "%d") { stats(period: "decade") { deals } } }`, public.RobotID),
+			ExpectedCodes: []interface{}{"unknown_period"}, ExpectedData: `null`},
+		{Name: "Unknown field", Query: `{ me { password } }`, ExpectedCodes: []interface{}{nil}},
+		{Name: "Too deep", Query: `{ me { robots { robots { parent { parent { parent { parent { parent { parent { id } } } } } } } } } }`,
+			ExpectedCodes: []interface{}{nil}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			resp := graphqlQuery(t, ts, other.Token, tc.Query)
+			assert.Equal(t, tc.ExpectedCodes, resp.errorCodes(), "errors: %+v", resp.Errors)
+
+			if tc.ExpectedData != "" {
+				assert.JSONEq(t, tc.ExpectedData, string(resp.Data))
+			}
+		})
+	}
+
+	body := bytes.NewBufferString(`{"query":"{ me { id } }","unknown":1}`)
+	resp, code := testRequestWithAuth(t, ts, http.MethodPost, "/api/v1/graphql", "Bearer "+owner.Token, body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	resp, code = testRequestWithAuth(t, ts, http.MethodPost, "/api/v1/graphql", "", bytes.NewBufferString(`{"query":"{ me { id } }"}`))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, code, "graphql requires a session")
+}
+
+func TestGraphQLMutations(t *testing.T) {
+	assert := assert.New(t)
+	h, robotStorage, _, ts := setupGraphQL(t)
+	owner := setupUser(h, t, "owner@example.com")
+
+	rob := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(robotStorage.Create(rob))
+
+	mutate := func(name string) map[string]interface{} {
+		resp := graphqlQuery(t, ts, owner.Token,
+			fmt.Sprintf(`mutation { result: %s(id: "%d") { status isActive deletedAt actions } }`, name, rob.RobotID))
+		assert.Empty(resp.Errors, "%s errors: %+v", name, resp.Errors)
+
+		var data struct {
+			Result map[string]interface{}
+		}
+
+		assert.NoError(json.Unmarshal(resp.Data, &data))
+
+		return data.Result
+	}
+
+	result := mutate("activateRobot")
+	assert.Equal(true, result["isActive"])
+
+	result = mutate("deactivateRobot")
+	assert.Equal(false, result["isActive"])
+
+	result = mutate("stopRobot")
+	assert.Equal(string(robot.StatusStopped), result["status"])
+
+	result = mutate("deleteRobot")
+	assert.NotNil(result["deletedAt"])
+
+	result = mutate("restoreRobot")
+	assert.Nil(result["deletedAt"])
+	assert.Equal(string(robot.StatusStopped), result["status"])
+
+	transitions, err := robotStorage.FindTransitions(rob.RobotID)
+	assert.NoError(err)
+	assert.Len(transitions, 5)
+}
+
+func TestGraphQLSubscription(t *testing.T) {
+	assert := assert.New(t)
+	h, robotStorage, _, ts := setupGraphQL(t)
+	owner := setupUser(h, t, "owner@example.com")
+
+	rob := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(robotStorage.Create(rob))
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/v1/graphql"
+	dialer := websocket.Dialer{Subprotocols: []string{graphqlWSProtocol}}
+
+	_, resp, err := dialer.Dial(wsURL+"?token=wrong", nil)
+	if assert.Error(err) {
+		assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	}
+
+	conn, resp, err := dialer.Dial(wsURL+"?token="+owner.Token, nil)
+	if !assert.NoError(err) {
+		return
+	}
+	defer conn.Close()
+
+	assert.Equal(graphqlWSProtocol, resp.Header.Get("Sec-Websocket-Protocol"))
+
+	read := func() graphqlWSMessage {
+		var msg graphqlWSMessage
+
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		assert.NoError(conn.ReadJSON(&msg))
+
+		return msg
+	}
+
+	assert.NoError(conn.WriteJSON(graphqlWSMessage{Type: graphqlWSConnectionInit}))
+	assert.Equal(graphqlWSConnectionAck, read().Type)
+
+	query := fmt.Sprintf(`subscription { robotChanged(id: "%d") { isActive owner { email } } }`, rob.RobotID)
+	payload, _ := json.Marshal(graphqlRequest{Query: query})
+	assert.NoError(conn.WriteJSON(graphqlWSMessage{ID: "1", Type: graphqlWSStart, Payload: payload}))
+
+	msg := read()
+	assert.Equal(graphqlWSData, msg.Type)
+	assert.Equal("1", msg.ID)
+	assert.JSONEq(`{"data":{"robotChanged":{"isActive":false,"owner":{"email":"owner@example.com"}}}}`, string(msg.Payload))
+
+	mutation := graphqlQuery(t, ts, owner.Token, fmt.Sprintf(`mutation { activateRobot(id: "%d") { id } }`, rob.RobotID))
+	assert.Empty(mutation.Errors)
+
+	msg = read()
+	assert.Equal(graphqlWSData, msg.Type)
+	assert.JSONEq(`{"data":{"robotChanged":{"isActive":true,"owner":{"email":"owner@example.com"}}}}`, string(msg.Payload))
+
+	payload, _ = json.Marshal(graphqlRequest{Query: `{ me { id } }`})
+	assert.NoError(conn.WriteJSON(graphqlWSMessage{ID: "2", Type: graphqlWSStart, Payload: payload}))
+
+	msg = read()
+	assert.Equal(graphqlWSData, msg.Type)
+	assert.JSONEq(`{"data":{"me":{"id":"1"}}}`, string(msg.Payload))
+	assert.Equal(graphqlWSComplete, read().Type, "query completes after its only result")
+
+	mutation = graphqlQuery(t, ts, owner.Token, fmt.Sprintf(`mutation { deleteRobot(id: "%d") { id } }`, rob.RobotID))
+	assert.Empty(mutation.Errors)
+
+	msg = read()
+	assert.Equal(graphqlWSComplete, msg.Type, "subscription is over when the robot is not visible")
+	assert.Equal("1", msg.ID)
+
+	other := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(robotStorage.Create(other))
+
+	query = fmt.Sprintf(`subscription { robotChanged(id: "%d") { isActive } }`, other.RobotID)
+	payload, _ = json.Marshal(graphqlRequest{Query: query})
+	assert.NoError(conn.WriteJSON(graphqlWSMessage{ID: "3", Type: graphqlWSStart, Payload: payload}))
+	assert.Equal(graphqlWSData, read().Type)
+	assert.NoError(conn.WriteJSON(graphqlWSMessage{ID: "3", Type: graphqlWSStop}))
+
+	// Сообщения обрабатываются по порядку, поэтому после ответа на запрос подписка уже отменена.
+	payload, _ = json.Marshal(graphqlRequest{Query: `{ me { id } }`})
+	assert.NoError(conn.WriteJSON(graphqlWSMessage{ID: "4", Type: graphqlWSStart, Payload: payload}))
+	assert.Equal(graphqlWSData, read().Type)
+	assert.Equal(graphqlWSComplete, read().Type)
+
+	mutation = graphqlQuery(t, ts, owner.Token, fmt.Sprintf(`mutation { activateRobot(id: "%d") { id } }`, other.RobotID))
+	assert.Empty(mutation.Errors)
+
+	var none graphqlWSMessage
+
+	_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	assert.Error(conn.ReadJSON(&none), "stopped subscription doesn't send changes: %+v", none)
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/robotapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authMetadata ключ метаданных gRPC с токеном сессии, значение такое же, как у заголовка Authorization.
+const authMetadata = "authorization"
+
+// publicMethods методы gRPC, которые не требуют сессии.
+var publicMethods = map[string]bool{
+	"/robotapi.UserService/SignUp": true,
+	"/robotapi.UserService/SignIn": true,
+}
+
+// grpcCodes коды gRPC для статусов HTTP из errorKinds.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:           codes.InvalidArgument,
+	http.StatusUnauthorized:         codes.Unauthenticated,
+	http.StatusForbidden:            codes.PermissionDenied,
+	http.StatusNotFound:             codes.NotFound,
+	http.StatusConflict:             codes.FailedPrecondition,
+	http.StatusPreconditionFailed:   codes.Aborted,
+	http.StatusPreconditionRequired: codes.FailedPrecondition,
+	http.StatusFailedDependency:     codes.Aborted,
+	http.StatusTooManyRequests:      codes.ResourceExhausted,
+	http.StatusServiceUnavailable:   codes.Unavailable,
+	http.StatusInternalServerError:  codes.Internal,
+}
+
+// GRPCServer возвращает сервер gRPC с RobotService и UserService. Сервер работает с теми же хранилищами
+// и сессиями, что и REST API, и расходует те же квоты запросов. Перехватчики из opts выполняются после
+// проверки сессии и квоты.
+func (h *Handler) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(h.unaryClientRateLimit, h.unaryAuthentication, h.unaryRateLimit),
+		grpc.ChainStreamInterceptor(h.streamClientRateLimit, h.streamAuthentication, h.streamRateLimit),
+	}, opts...)
+
+	srv := grpc.NewServer(opts...)
+	robotapi.RegisterUserServiceServer(srv, &userService{h: h})
+	robotapi.RegisterRobotServiceServer(srv, &robotService{h: h})
+
+	return srv
+}
+
+// unaryAuthentication проверяет сессию перед вызовом метода, как authentication для REST API.
+func (h *Handler) unaryAuthentication(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	ctx, err := h.grpcAuthenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// streamAuthentication проверяет сессию перед открытием потока.
+func (h *Handler) streamAuthentication(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	ctx, err := h.grpcAuthenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// contextStream поток gRPC с контекстом перехватчика, например с токеном проверенной сессии.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcAuthenticate проверяет токен из метаданных authorization и возвращает контекст с токеном сессии.
+func (h *Handler) grpcAuthenticate(ctx context.Context, method string) (context.Context, error) {
+	var value string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authMetadata); len(values) > 0 {
+			value = values[0]
+		}
+	}
+
+	sessionToken, err := h.authenticate(value)
+	if err != nil {
+		e := err.(*authError)
+		h.logger.Warnw(e.msg, "error", e.cause, "method", method, "RealIP", remoteAddr(ctx))
+
+		return nil, status.Error(codes.Unauthenticated, e.msg)
+	}
+
+	return context.WithValue(ctx, tokenKey{}, sessionToken.SessionID), nil
+}
+
+// grpcError возвращает статус gRPC для err. Код и подробности берутся из errorKinds так же, как для REST API,
+// код ошибки API и ошибки полей передаются в деталях статуса как robotapi.Problem.
+func (h *Handler) grpcError(ctx context.Context, msg string, err error) error {
+	e, _ := classify(err)
+	method, _ := grpc.Method(ctx)
+
+	h.logger.Warnw(msg, "error", err, "status", e.Status, "method", method, "RealIP", remoteAddr(ctx))
+
+	code, ok := grpcCodes[e.Status]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	if e.Code == "user_exists" {
+		code = codes.AlreadyExists
+	}
+
+	problem := &robotapi.Problem{Code: e.Code, Detail: e.Detail}
+	for _, f := range e.Fields {
+		problem.Fields = append(problem.Fields, &robotapi.FieldError{Field: f.Field, Message: f.Message})
+	}
+
+	st := status.New(code, e.Detail)
+	if withDetails, err := st.WithDetails(problem); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+// remoteAddr возвращает адрес клиента gRPC или GraphQL для логов.
+func remoteAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+
+	addr, _ := ctx.Value(remoteAddrKey{}).(string)
+
+	return addr
+}
+
+// positiveID проверяет ID из запроса gRPC, как getParamID проверяет ID из URL.
+func positiveID(name string, id int64) (int, error) {
+	if id <= 0 {
+		return 0, fmt.Errorf("%w: %s %d is not a positive integer", robot.ErrInvalidID, name, id)
+	}
+
+	return int(id), nil
+}
+
+// applyRobotParams переносит в rob заданные поля params.
+func applyRobotParams(rob *robot.Robot, params *robotapi.RobotParams) error {
+	if params == nil {
+		return nil
+	}
+
+	var f validation.Fields
+
+	if params.Ticker != nil {
+		rob.Ticker = params.Ticker.Value
+	}
+
+	if params.BuyPrice != nil {
+		rob.BuyPrice = params.BuyPrice.Value
+	}
+
+	if params.SellPrice != nil {
+		rob.SellPrice = params.SellPrice.Value
+	}
+
+	if params.PlanStart != nil {
+		t, err := ptypes.Timestamp(params.PlanStart)
+		if err != nil {
+			f.Add("plan_start", "is not a valid timestamp")
+		}
+
+		rob.PlanStart = robot.NullTime{Time: t, Valid: true}
+	}
+
+	if params.PlanEnd != nil {
+		t, err := ptypes.Timestamp(params.PlanEnd)
+		if err != nil {
+			f.Add("plan_end", "is not a valid timestamp")
+		}
+
+		rob.PlanEnd = robot.NullTime{Time: t, Valid: true}
+	}
+
+	if params.PlanYield != nil {
+		rob.PlanYield = params.PlanYield.Value
+	}
+
+	if params.AutoClose != nil {
+		rob.AutoClose = params.AutoClose.Value
+	}
+
+	if params.Schedule != nil {
+		rob.Schedule = params.Schedule.Value
+	}
+
+	if params.Lots != nil {
+		rob.Lots = int(params.Lots.Value)
+	}
+
+	if params.Visibility != nil {
+		rob.Visibility = robot.Visibility(params.Visibility.Value)
+	}
+
+	return f.Err(robot.ErrInvalidRobot)
+}
+
+// timestampProto возвращает время для ответа gRPC, для незаданного времени — nil.
+func timestampProto(t time.Time, valid bool) *timestamp.Timestamp {
+	if !valid || t.IsZero() {
+		return nil
+	}
+
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return nil
+	}
+
+	return ts
+}
+
+func robotProto(rob *robot.Robot) *robotapi.Robot {
+	actions := rob.AllowedActions(time.Now())
+
+	msg := &robotapi.Robot{
+		RobotId:       int64(rob.RobotID),
+		OwnerUserId:   int64(rob.OwnerUserID),
+		ParentRobotId: int64(rob.ParentRobotID),
+		ClonedFromId:  int64(rob.ClonedFromID),
+		TemplateId:    int64(rob.TemplateID),
+		IsFavourite:   rob.IsFavourite,
+		IsActive:      rob.IsActive,
+		Status:        string(rob.Status),
+		Ticker:        rob.Ticker,
+		BuyPrice:      rob.BuyPrice,
+		SellPrice:     rob.SellPrice,
+		PlanStart:     timestampProto(rob.PlanStart.Time, rob.PlanStart.Valid),
+		PlanEnd:       timestampProto(rob.PlanEnd.Time, rob.PlanEnd.Valid),
+		PlanYield:     rob.PlanYield,
+		FactYield:     rob.FactYield,
+		DealsCount:    int64(rob.DealsCount),
+		AutoClose:     rob.AutoClose,
+		Schedule:      rob.Schedule,
+		Version:       int64(rob.Version),
+		Revision:      int64(rob.Revision),
+		IsMirror:      rob.IsMirror,
+		Lots:          int64(rob.Lots),
+		Followers:     int64(rob.Followers),
+		Visibility:    string(rob.Visibility),
+		ActivatedAt:   timestampProto(rob.ActivatedAt.Time, rob.ActivatedAt.Valid),
+		DeactivatedAt: timestampProto(rob.DeactivatedAt.Time, rob.DeactivatedAt.Valid),
+		CreatedAt:     timestampProto(rob.CreatedAt.Time, rob.CreatedAt.Valid),
+		DeletedAt:     timestampProto(rob.DeletedAt.Time, rob.DeletedAt.Valid),
+		Actions:       make([]string, 0, len(actions)),
+	}
+
+	for _, action := range actions {
+		msg.Actions = append(msg.Actions, string(action))
+	}
+
+	return msg
+}
+
+func robotsProto(robots []robot.Robot) []*robotapi.Robot {
+	msgs := make([]*robotapi.Robot, 0, len(robots))
+	for i := range robots {
+		msgs = append(msgs, robotProto(&robots[i]))
+	}
+
+	return msgs
+}
+
+func statsProto(s robot.Stats) *robotapi.Stats {
+	return &robotapi.Stats{
+		RobotId:     int64(s.RobotID),
+		OwnerUserId: int64(s.OwnerUserID),
+		Ticker:      s.Ticker,
+		Yield:       s.Yield,
+		Sharpe:      s.Sharpe,
+		Deals:       int64(s.Deals),
+		Drawdown:    s.Drawdown,
+		Followers:   int64(s.Followers),
+	}
+}
+
+func sharesProto(view *sharesView) *robotapi.Shares {
+	msg := &robotapi.Shares{Visibility: string(view.Visibility), ShareLink: view.ShareLink,
+		Users: make([]int64, 0, len(view.Users))}
+
+	for _, userID := range view.Users {
+		msg.Users = append(msg.Users, int64(userID))
+	}
+
+	return msg
+}
+
+func transitionProto(t robot.Transition) *robotapi.Transition {
+	return &robotapi.Transition{
+		Id:         int64(t.ID),
+		RobotId:    int64(t.RobotID),
+		Action:     string(t.Action),
+		FromStatus: string(t.FromStatus),
+		ToStatus:   string(t.ToStatus),
+		Source:     t.Source,
+		CreatedAt:  timestampProto(t.CreatedAt, true),
+	}
+}
+
+func versionProto(v robot.Version) *robotapi.Version {
+	return &robotapi.Version{
+		RobotId:   int64(v.RobotID),
+		Version:   int64(v.Version),
+		Ticker:    v.Ticker,
+		BuyPrice:  v.BuyPrice,
+		SellPrice: v.SellPrice,
+		PlanStart: timestampProto(v.PlanStart.Time, v.PlanStart.Valid),
+		PlanEnd:   timestampProto(v.PlanEnd.Time, v.PlanEnd.Valid),
+		PlanYield: v.PlanYield,
+		AutoClose: v.AutoClose,
+		Schedule:  v.Schedule,
+		Lots:      int64(v.Lots),
+		CreatedAt: timestampProto(v.CreatedAt, true),
+	}
+}
+
+func dealProto(d robot.Deal) *robotapi.Deal {
+	return &robotapi.Deal{
+		Id:        int64(d.ID),
+		RobotId:   int64(d.RobotID),
+		Version:   int64(d.Version),
+		Side:      d.Side,
+		Price:     d.Price,
+		Lots:      int64(d.Lots),
+		CreatedAt: timestampProto(d.CreatedAt, true),
+	}
+}
+
+func userProto(u *user.User) *robotapi.User {
+	msg := &robotapi.User{Id: int64(u.ID), FirstName: u.FirstName, LastName: u.LastName, Email: u.Email,
+		Version: int64(u.Version)}
+	if !u.Birthday.IsZero() {
+		msg.Birthday = u.Birthday.Format(user.BirthdayLayout)
+	}
+
+	return msg
+}
@@ -0,0 +1,547 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/robotapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// robotService реализация robotapi.RobotServiceServer. Методы повторяют хэндлеры REST API /api/v1
+// и так же проверяют владельца и видимость робота.
+type robotService struct {
+	h *Handler
+}
+
+// ListRobots возвращает страницу роботов, как GET /api/v1/robots и GET /api/v1/users/{id}/robots.
+func (s *robotService) ListRobots(ctx context.Context, req *robotapi.ListRobotsRequest) (*robotapi.ListRobotsResponse, error) {
+	q, err := robot.ParseQuery(listQueryValues(ctx, req))
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "invalid query", err)
+	}
+
+	userID := sessionUserID(ctx)
+	q.ViewerID = &userID
+
+	page, err := s.h.robotStorage.Find(q)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.Find return with error", err)
+	}
+
+	return &robotapi.ListRobotsResponse{Robots: robotsProto(page.Robots), NextCursor: page.NextCursor}, nil
+}
+
+// listQueryValues переводит запрос списка роботов в query-параметры REST API, чтобы проверять их одинаково.
+func listQueryValues(ctx context.Context, req *robotapi.ListRobotsRequest) url.Values {
+	values := url.Values{}
+
+	set := func(name, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+
+	set("ticker", req.Ticker)
+	set("sort", req.Sort)
+	set("cursor", req.Cursor)
+
+	if req.Desc {
+		values.Set("order", robot.OrderDesc)
+	}
+
+	if req.Limit != 0 {
+		values.Set("limit", strconv.FormatInt(req.Limit, 10))
+	}
+
+	if req.OwnerUserId != nil {
+		values.Set("user", strconv.FormatInt(req.OwnerUserId.Value, 10))
+	}
+
+	if req.Mine {
+		values.Set("user", strconv.Itoa(sessionUserID(ctx)))
+	}
+
+	if req.ParentRobotId != nil {
+		values.Set("parent", strconv.FormatInt(req.ParentRobotId.Value, 10))
+	}
+
+	if req.IsActive != nil {
+		values.Set("is_active", strconv.FormatBool(req.IsActive.Value))
+	}
+
+	if req.IsFavourite != nil {
+		values.Set("is_favourite", strconv.FormatBool(req.IsFavourite.Value)) //nolint:misspell
+	}
+
+	if req.MinYield != nil {
+		values.Set("min_yield", strconv.FormatFloat(req.MinYield.Value, 'f', -1, 64))
+	}
+
+	if req.MaxYield != nil {
+		values.Set("max_yield", strconv.FormatFloat(req.MaxYield.Value, 'f', -1, 64))
+	}
+
+	return values
+}
+
+// ListDeletedRobots возвращает корзину пользователя текущей сессии.
+func (s *robotService) ListDeletedRobots(ctx context.Context,
+	_ *robotapi.ListDeletedRobotsRequest) (*robotapi.ListRobotsResponse, error) {
+	robots, err := s.h.robotStorage.FindDeleted(sessionUserID(ctx))
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.FindDeleted return with error", err)
+	}
+
+	return &robotapi.ListRobotsResponse{Robots: robotsProto(robots)}, nil
+}
+
+// Leaderboard возвращает рейтинг публичных роботов, limit 0 означает размер по умолчанию.
+func (s *robotService) Leaderboard(ctx context.Context, req *robotapi.LeaderboardRequest) (*robotapi.LeaderboardResponse, error) {
+	limit := defaultLeaderboardLimit
+
+	if req.Limit != 0 {
+		if req.Limit < 1 || req.Limit > maxLimit {
+			err := fmt.Errorf("%w: limit must be between 1 and %d, got %d", robot.ErrInvalidQuery, maxLimit, req.Limit)
+			return nil, s.h.grpcError(ctx, "invalid limit", err)
+		}
+
+		limit = int(req.Limit)
+	}
+
+	stats, err := s.h.leaderboard(req.Metric, req.Period, limit)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't build leaderboard", err)
+	}
+
+	resp := &robotapi.LeaderboardResponse{Stats: make([]*robotapi.Stats, 0, len(stats))}
+	for _, st := range stats {
+		resp.Stats = append(resp.Stats, statsProto(st))
+	}
+
+	return resp, nil
+}
+
+// CreateRobot создает робота пользователя текущей сессии и возвращает его.
+func (s *robotService) CreateRobot(ctx context.Context, req *robotapi.RobotParams) (*robotapi.Robot, error) {
+	userID := sessionUserID(ctx)
+	rob := &robot.Robot{OwnerUserID: userID}
+
+	if err := applyRobotParams(rob, req); err != nil {
+		return nil, s.h.grpcError(ctx, "invalid robot params", err)
+	}
+
+	if err := s.h.prepareNewRobot(ctx, userID, rob); err != nil {
+		return nil, s.h.grpcError(ctx, "invalid new robot", err)
+	}
+
+	if err := s.h.robotStorage.Create(rob); err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.Create return with error", err)
+	}
+
+	s.h.logger.Infow("create robot", "user", userID, "RealIP", remoteAddr(ctx))
+
+	return robotProto(rob), nil
+}
+
+// GetRobot возвращает робота, которого видит пользователь, с учетом токена ссылки.
+func (s *robotService) GetRobot(ctx context.Context, req *robotapi.GetRobotRequest) (*robotapi.Robot, error) {
+	rob, err := s.visible(ctx, req.RobotId, req.ShareToken)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find visible robot", err)
+	}
+
+	return robotProto(rob), nil
+}
+
+// EditRobot меняет заданные параметры робота пользователя. Вместо If-Match запрос передает revision
+// прочитанного робота, хранилище проверяет ее еще раз при записи.
+func (s *robotService) EditRobot(ctx context.Context, req *robotapi.EditRobotRequest) (*robotapi.Robot, error) {
+	rob, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	if err = checkVersion(req.Revision, rob.Revision); err != nil {
+		return nil, s.h.grpcError(ctx, "robot precondition failed", err)
+	}
+
+	changes := *rob
+
+	if err = applyRobotParams(&changes, req.Params); err != nil {
+		return nil, s.h.grpcError(ctx, "invalid robot params", err)
+	}
+
+	if err = s.h.checkChanges(ctx, rob, &changes); err != nil {
+		return nil, s.h.grpcError(ctx, "invalid changes", err)
+	}
+
+	edited, err := s.h.robotStorage.Edit(rob.RobotID, &changes)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.Edit return with error", err)
+	}
+
+	s.h.logger.Infow("edit robot", "robotID", rob.RobotID, "version", edited.Version, "RealIP", remoteAddr(ctx))
+	s.h.publishRobotChanged(rob.RobotID, "", remoteAddr(ctx))
+
+	return robotProto(edited), nil
+}
+
+// DeleteRobot помещает робота пользователя в корзину и возвращает удаленного робота.
+func (s *robotService) DeleteRobot(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.Robot, error) {
+	rob, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	if _, err = s.h.closeBeforeDelete(ctx, rob, 0); err != nil {
+		return nil, s.h.grpcError(ctx, "can't close robot position before delete", err)
+	}
+
+	if err = s.h.robotStorage.SoftDelete(rob.RobotID, 0); err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.SoftDelete return with error", err)
+	}
+
+	s.h.logger.Infow("'soft delete' robot", "userID", rob.OwnerUserID)
+	s.h.publishRobotChanged(rob.RobotID, "", remoteAddr(ctx))
+
+	return s.found(ctx, rob.RobotID, s.h.robotStorage.FindDeletedByID)
+}
+
+// RestoreRobot возвращает робота пользователя из корзины.
+func (s *robotService) RestoreRobot(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.Robot, error) {
+	rob, err := s.ownFrom(ctx, req.RobotId, s.h.robotStorage.FindDeletedByID)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own deleted robot", err)
+	}
+
+	if err = s.h.robotStorage.Restore(rob.RobotID); err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.Restore return with error", err)
+	}
+
+	s.h.publishRobotChanged(rob.RobotID, "", remoteAddr(ctx))
+
+	return s.found(ctx, rob.RobotID, s.h.robotStorage.FindByID)
+}
+
+// PurgeRobot окончательно удаляет робота пользователя из корзины.
+func (s *robotService) PurgeRobot(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.PurgeRobotResponse, error) {
+	rob, err := s.ownFrom(ctx, req.RobotId, s.h.robotStorage.FindDeletedByID)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own deleted robot", err)
+	}
+
+	if err = s.h.robotStorage.Purge(rob.RobotID); err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.Purge return with error", err)
+	}
+
+	return &robotapi.PurgeRobotResponse{}, nil
+}
+
+// ActivateRobot запускает робота пользователя.
+func (s *robotService) ActivateRobot(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.Robot, error) {
+	return s.transition(ctx, req.RobotId, robot.ActionActivate)
+}
+
+// DeactivateRobot приостанавливает робота пользователя.
+func (s *robotService) DeactivateRobot(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.Robot, error) {
+	return s.transition(ctx, req.RobotId, robot.ActionDeactivate)
+}
+
+// StopRobot завершает работу робота пользователя.
+func (s *robotService) StopRobot(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.Robot, error) {
+	return s.transition(ctx, req.RobotId, robot.ActionStop)
+}
+
+// transition выполняет действие над роботом пользователя и возвращает робота в новом состоянии.
+func (s *robotService) transition(ctx context.Context, id int64, action robot.Action) (*robotapi.Robot, error) {
+	rob, err := s.own(ctx, id)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	if err = s.h.robotStorage.Transition(rob.RobotID, action, robot.SourceUser, 0); err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.Transition return with error", err)
+	}
+
+	s.h.logger.Infow("robot transition", "action", action, "robotID", rob.RobotID, "RealIP", remoteAddr(ctx))
+	s.h.publishRobotChanged(rob.RobotID, "", remoteAddr(ctx))
+
+	return s.found(ctx, rob.RobotID, s.h.robotStorage.FindByID)
+}
+
+// FavouriteRobot добавляет копию видимого пользователю робота в его избранное.
+func (s *robotService) FavouriteRobot(ctx context.Context, req *robotapi.FavouriteRobotRequest) (*robotapi.Robot, error) { //nolint:misspell
+	rob, err := s.visible(ctx, req.RobotId, req.ShareToken)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find visible robot", err)
+	}
+
+	follow := robot.Follow{Mirror: req.Mirror, Lots: int(req.Lots)}
+
+	follower, err := s.h.robotStorage.FavouriteRobot(rob.RobotID, sessionUserID(ctx), follow, 0)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.FavouriteRobot return with error", err)
+	}
+
+	s.h.publishRobotChanged(rob.RobotID, "", remoteAddr(ctx))
+	s.h.publishRobotChanged(follower.RobotID, "", remoteAddr(ctx))
+
+	return robotProto(follower), nil
+}
+
+// UnfollowRobot отключает повторение родителя у робота пользователя.
+func (s *robotService) UnfollowRobot(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.Robot, error) {
+	rob, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	if !rob.IsMirror {
+		s.h.logger.Warnw("robot doesn't follow its parent", "robotID", rob.RobotID, "RealIP", remoteAddr(ctx))
+		return nil, status.Error(codes.FailedPrecondition, "robot doesn't follow its parent")
+	}
+
+	if err = s.h.robotStorage.Unfollow(rob.RobotID, 0); err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.Unfollow return with error", err)
+	}
+
+	s.h.publishRobotChanged(rob.RobotID, "", remoteAddr(ctx))
+
+	if rob.ParentRobotID != 0 {
+		s.h.publishRobotChanged(rob.ParentRobotID, "", remoteAddr(ctx))
+	}
+
+	return s.found(ctx, rob.RobotID, s.h.robotStorage.FindByID)
+}
+
+// CloneRobot создает копию робота пользователя, заданные параметры переопределяют поля копии.
+func (s *robotService) CloneRobot(ctx context.Context, req *robotapi.EditRobotRequest) (*robotapi.Robot, error) {
+	src, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	base := src.Clone()
+
+	if err = applyRobotParams(&base, req.Params); err != nil {
+		return nil, s.h.grpcError(ctx, "invalid robot params", err)
+	}
+
+	clone, err := s.h.newRobotFrom(ctx, base, nil)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "invalid clone", err)
+	}
+
+	if err = s.h.robotStorage.Create(clone); err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.Create return with error", err)
+	}
+
+	s.h.logger.Infow("clone robot", "robotID", src.RobotID, "cloneID", clone.RobotID, "RealIP", remoteAddr(ctx))
+
+	return robotProto(clone), nil
+}
+
+// RobotTransitions возвращает историю переходов робота пользователя.
+func (s *robotService) RobotTransitions(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.RobotTransitionsResponse, error) {
+	rob, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	transitions, err := s.h.robotStorage.FindTransitions(rob.RobotID)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.FindTransitions return with error", err)
+	}
+
+	resp := &robotapi.RobotTransitionsResponse{Transitions: make([]*robotapi.Transition, 0, len(transitions))}
+	for _, t := range transitions {
+		resp.Transitions = append(resp.Transitions, transitionProto(t))
+	}
+
+	return resp, nil
+}
+
+// RobotVersions возвращает историю параметров робота пользователя.
+func (s *robotService) RobotVersions(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.RobotVersionsResponse, error) {
+	rob, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	versions, err := s.h.robotStorage.FindVersions(rob.RobotID)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.FindVersions return with error", err)
+	}
+
+	resp := &robotapi.RobotVersionsResponse{Versions: make([]*robotapi.Version, 0, len(versions))}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, versionProto(v))
+	}
+
+	return resp, nil
+}
+
+// RobotDeals возвращает сделки робота пользователя.
+func (s *robotService) RobotDeals(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.RobotDealsResponse, error) {
+	rob, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	deals, err := s.h.robotStorage.FindDeals(rob.RobotID)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.FindDeals return with error", err)
+	}
+
+	resp := &robotapi.RobotDealsResponse{Deals: make([]*robotapi.Deal, 0, len(deals))}
+	for _, d := range deals {
+		resp.Deals = append(resp.Deals, dealProto(d))
+	}
+
+	return resp, nil
+}
+
+// RobotStats возвращает статистику видимого пользователю робота за период.
+func (s *robotService) RobotStats(ctx context.Context, req *robotapi.RobotStatsRequest) (*robotapi.Stats, error) {
+	since, err := robot.PeriodStart(req.Period, time.Now())
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "invalid period", err)
+	}
+
+	rob, err := s.visible(ctx, req.RobotId, req.ShareToken)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find visible robot", err)
+	}
+
+	deals, err := s.h.robotStorage.FindDeals(rob.RobotID)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.FindDeals return with error", err)
+	}
+
+	return statsProto(robot.ComputeStats(rob, deals, since)), nil
+}
+
+// RobotShares возвращает настройки доступа к роботу пользователя.
+func (s *robotService) RobotShares(ctx context.Context, req *robotapi.RobotRequest) (*robotapi.Shares, error) {
+	rob, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	return s.shares(ctx, rob)
+}
+
+// ShareRobot открывает робота пользователю и возвращает новые настройки доступа.
+func (s *robotService) ShareRobot(ctx context.Context, req *robotapi.ShareRobotRequest) (*robotapi.Shares, error) {
+	return s.changeShare(ctx, req, s.h.robotStorage.Share)
+}
+
+// UnshareRobot закрывает робота от пользователя и возвращает новые настройки доступа.
+func (s *robotService) UnshareRobot(ctx context.Context, req *robotapi.ShareRobotRequest) (*robotapi.Shares, error) {
+	return s.changeShare(ctx, req, s.h.robotStorage.Unshare)
+}
+
+// changeShare проверяет владельца робота и пользователя из запроса и меняет доступ функцией change.
+func (s *robotService) changeShare(ctx context.Context, req *robotapi.ShareRobotRequest,
+	change func(robotID, userID int) error) (*robotapi.Shares, error) {
+	rob, err := s.own(ctx, req.RobotId)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find own robot", err)
+	}
+
+	if _, err = s.h.userStorage.FindByID(int(req.UserId)); err != nil {
+		return nil, s.h.grpcError(ctx, "func userStorage.FindByID return with error", err)
+	}
+
+	if err = change(rob.RobotID, int(req.UserId)); err != nil {
+		return nil, s.h.grpcError(ctx, "can't change robot share", err)
+	}
+
+	s.h.publishRobotChanged(rob.RobotID, "", remoteAddr(ctx))
+
+	return s.shares(ctx, rob)
+}
+
+func (s *robotService) shares(ctx context.Context, rob *robot.Robot) (*robotapi.Shares, error) {
+	view, err := s.h.shares(rob)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func robotStorage.FindShares return with error", err)
+	}
+
+	return sharesProto(view), nil
+}
+
+// WatchRobot отправляет робота в поток сразу и после каждого его изменения. Поток завершается с ошибкой,
+// когда пользователь перестает видеть робота, и без ошибки, когда клиент закрывает поток.
+func (s *robotService) WatchRobot(req *robotapi.GetRobotRequest, stream robotapi.RobotService_WatchRobotServer) error {
+	ctx := stream.Context()
+
+	robotID, err := positiveID("robot id", req.RobotId)
+	if err != nil {
+		return s.h.grpcError(ctx, "invalid id", err)
+	}
+
+	// Подписка идет раньше первого чтения робота, чтобы не пропустить изменение между ними.
+	changed, stop := s.h.wsocket.watch(robotID)
+	defer stop()
+
+	for {
+		rob, err := s.h.robotStorage.FindVisible(robotID, sessionUserID(ctx), req.ShareToken)
+		if err != nil {
+			return s.h.grpcError(ctx, "func robotStorage.FindVisible return with error", err)
+		}
+
+		if err = stream.Send(robotProto(rob)); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// own находит робота и проверяет, что он принадлежит пользователю текущей сессии.
+func (s *robotService) own(ctx context.Context, id int64) (*robot.Robot, error) {
+	return s.ownFrom(ctx, id, s.h.robotStorage.FindByID)
+}
+
+// ownFrom находит робота функцией find и проверяет, что он принадлежит пользователю текущей сессии.
+func (s *robotService) ownFrom(ctx context.Context, id int64,
+	find func(robotID int) (*robot.Robot, error)) (*robot.Robot, error) {
+	robotID, err := positiveID("robot id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOwnRobot(ctx, robotID, find)
+}
+
+// visible находит робота, которого видит пользователь текущей сессии, с учетом токена ссылки.
+func (s *robotService) visible(ctx context.Context, id int64, shareToken string) (*robot.Robot, error) {
+	robotID, err := positiveID("robot id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.h.robotStorage.FindVisible(robotID, sessionUserID(ctx), shareToken)
+}
+
+// found возвращает робота после изменения, find выбирает неудаленного или удаленного робота.
+func (s *robotService) found(ctx context.Context, robotID int,
+	find func(robotID int) (*robot.Robot, error)) (*robotapi.Robot, error) {
+	rob, err := find(robotID)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "can't find changed robot", err)
+	}
+
+	return robotProto(rob), nil
+}
@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/robotapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// broadcastPublisher публикует изменения роботов сразу подписчикам, как шина событий в main.
+type broadcastPublisher struct {
+	ws *WSClients
+}
+
+func (p broadcastPublisher) Publish(e event.Event) error {
+	p.ws.Broadcast(e.RobotID)
+	return nil
+}
+
+// grpcClients клиенты gRPC API тестового сервера.
+type grpcClients struct {
+	users  robotapi.UserServiceClient
+	robots robotapi.RobotServiceClient
+}
+
+// setupGRPC запускает сервер gRPC хэндлера h в памяти.
+func setupGRPC(t *testing.T, h *Handler) grpcClients {
+	listener := bufconn.Listen(1 << 20)
+	srv := h.GRPCServer()
+
+	go func() { _ = srv.Serve(listener) }()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }))
+	if err != nil {
+		t.Fatalf("can't dial gRPC server: %s", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+
+	return grpcClients{users: robotapi.NewUserServiceClient(conn), robots: robotapi.NewRobotServiceClient(conn)}
+}
+
+// setupGRPCHandler возвращает хэндлер с хранилищами в памяти и первым пользователем с ID 0,
+// чтобы пользователи тестов получали положительные ID.
+func setupGRPCHandler(t *testing.T) (*Handler, grpcClients) {
+	robotStorage := robot.CreateStorageInMemory()
+	ws := NewWebsocket(robotStorage)
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true, "SBER": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		ws, broadcastPublisher{ws: ws}, instruments)
+
+	setupSignUp(h, t)
+
+	return h, setupGRPC(t, h)
+}
+
+// grpcSignIn регистрирует пользователя через gRPC и возвращает контекст с его токеном.
+func grpcSignIn(t *testing.T, clients grpcClients, email string) context.Context {
+	ctx := context.Background()
+
+	_, err := clients.users.SignUp(ctx, &robotapi.UserParams{FirstName: "Petr", LastName: "Petrov", Email: email, Password: "1234"})
+	if err != nil {
+		t.Fatalf("can't sign up %s: %s", email, err)
+	}
+
+	resp, err := clients.users.SignIn(ctx, &robotapi.SignInRequest{Email: email, Password: "1234"})
+	if err != nil {
+		t.Fatalf("can't sign in %s: %s", email, err)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, authMetadata, "Bearer "+resp.Token)
+}
+
+// problemCode возвращает код ошибки API из деталей статуса gRPC.
+func problemCode(err error) (string, []string) {
+	for _, detail := range status.Convert(err).Details() {
+		if problem, ok := detail.(*robotapi.Problem); ok {
+			fields := make([]string, 0, len(problem.Fields))
+			for _, f := range problem.Fields {
+				fields = append(fields, f.Field)
+			}
+
+			return problem.Code, fields
+		}
+	}
+
+	return "", nil
+}
+
+func TestGRPCAuthentication(t *testing.T) {
+	type testCase struct {
+		Name         string
+		Auth         string
+		ExpectedCode codes.Code
+	}
+
+	h, clients := setupGRPCHandler(t)
+	valid := grpcSignIn(t, clients, "first@example.com")
+	md, _ := metadata.FromOutgoingContext(valid)
+	expired := &session.Session{UserID: 0, CreatedAt: time.Now().Add(-time.Hour), ValidUntil: time.Now().Add(-time.Minute)}
+	expired.SessionID = session.CreateToken(expired)
+	_ = h.sessionStorage.Create(expired)
+
+	testCases := []testCase{
+		{Name: "Without token", ExpectedCode: codes.Unauthenticated},
+		{Name: "Not supported scheme", Auth: "Basic 1234", ExpectedCode: codes.Unauthenticated},
+		{Name: "Invalid token", Auth: "Bearer 11111", ExpectedCode: codes.Unauthenticated},
+		{Name: "Old session", Auth: "Bearer " + expired.SessionID, ExpectedCode: codes.Unauthenticated},
+		{Name: "Valid token", Auth: md.Get(authMetadata)[0], ExpectedCode: codes.OK},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.Auth != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, authMetadata, tc.Auth)
+			}
+
+			_, err := clients.users.GetUser(ctx, &robotapi.GetUserRequest{})
+			assert.Equal(t, tc.ExpectedCode, status.Code(err), "error: %v", err)
+
+			_, err = clients.robots.ListRobots(ctx, &robotapi.ListRobotsRequest{})
+			assert.Equal(t, tc.ExpectedCode, status.Code(err), "error: %v", err)
+		})
+	}
+}
+
+func TestGRPCUsers(t *testing.T) {
+	assert := assert.New(t)
+	_, clients := setupGRPCHandler(t)
+	ctx := grpcSignIn(t, clients, "first@example.com")
+
+	_, err := clients.users.SignUp(context.Background(),
+		&robotapi.UserParams{FirstName: "Petr", LastName: "Petrov", Email: "first@example.com", Password: "1234"})
+	assert.Equal(codes.AlreadyExists, status.Code(err))
+
+	_, err = clients.users.SignUp(context.Background(), &robotapi.UserParams{Email: "second", Birthday: "2000-01-01"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	code, fields := problemCode(err)
+	assert.Equal("invalid_user", code)
+	assert.Equal([]string{"first_name", "last_name", "email", "password"}, fields)
+
+	_, err = clients.users.SignIn(context.Background(), &robotapi.SignInRequest{Email: "first@example.com", Password: "4321"})
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	u, err := clients.users.GetUser(ctx, &robotapi.GetUserRequest{})
+	assert.NoError(err)
+	assert.Equal("first@example.com", u.Email)
+
+	params := &robotapi.UserParams{FirstName: "Ivan", LastName: "Ivanov", Birthday: "1990-05-01",
+		Email: "first@example.com", Password: "1234"}
+
+	_, err = clients.users.UpdateUser(ctx, params)
+	assert.Equal(codes.FailedPrecondition, status.Code(err), "version of the user is required")
+
+	params.Version = u.Version
+	updated, err := clients.users.UpdateUser(ctx, params)
+	assert.NoError(err)
+	assert.Equal("Ivan", updated.FirstName)
+	assert.Equal("1990-05-01", updated.Birthday)
+	assert.Equal(u.Version+1, updated.Version)
+
+	_, err = clients.users.UpdateUser(ctx, params)
+	assert.Equal(codes.Aborted, status.Code(err), "the second update with the same version is rejected")
+
+	code, _ = problemCode(err)
+	assert.Equal("precondition_failed", code)
+
+	_, err = clients.users.SignIn(context.Background(), &robotapi.SignInRequest{Email: "first@example.com", Password: "1234"})
+	assert.NoError(err)
+}
+
+func TestGRPCRobots(t *testing.T) {
+	assert := assert.New(t)
+	_, clients := setupGRPCHandler(t)
+	owner := grpcSignIn(t, clients, "owner@example.com")
+	other := grpcSignIn(t, clients, "other@example.com")
+
+	_, err := clients.robots.CreateRobot(owner, &robotapi.RobotParams{Ticker: &wrappers.StringValue{Value: "NOPE"}})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	code, fields := problemCode(err)
+	assert.Equal("invalid_robot", code)
+	assert.Equal([]string{"ticker"}, fields)
+
+	rob, err := clients.robots.CreateRobot(owner, &robotapi.RobotParams{
+		Ticker:     &wrappers.StringValue{Value: "AAPL"},
+		BuyPrice:   &wrappers.DoubleValue{Value: 100},
+		SellPrice:  &wrappers.DoubleValue{Value: 110},
+		Visibility: &wrappers.StringValue{Value: string(robot.VisibilityPrivate)},
+	})
+	assert.NoError(err)
+	assert.Equal(string(robot.StatusDraft), rob.Status)
+	assert.Equal(int64(1), rob.Lots)
+
+	_, err = clients.robots.GetRobot(other, &robotapi.GetRobotRequest{RobotId: rob.RobotId})
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	_, err = clients.robots.ActivateRobot(other, &robotapi.RobotRequest{RobotId: rob.RobotId})
+	assert.Equal(codes.PermissionDenied, status.Code(err))
+
+	_, err = clients.robots.DeactivateRobot(owner, &robotapi.RobotRequest{RobotId: rob.RobotId})
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+
+	_, err = clients.robots.GetRobot(owner, &robotapi.GetRobotRequest{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	active, err := clients.robots.ActivateRobot(owner, &robotapi.RobotRequest{RobotId: rob.RobotId})
+	assert.NoError(err)
+	assert.Equal(string(robot.StatusActive), active.Status)
+
+	_, err = clients.robots.EditRobot(owner, &robotapi.EditRobotRequest{RobotId: rob.RobotId,
+		Params: &robotapi.RobotParams{SellPrice: &wrappers.DoubleValue{Value: 120}}})
+	assert.Equal(codes.FailedPrecondition, status.Code(err), "revision of the robot is required")
+
+	_, err = clients.robots.EditRobot(owner, &robotapi.EditRobotRequest{RobotId: rob.RobotId, Revision: rob.Revision,
+		Params: &robotapi.RobotParams{SellPrice: &wrappers.DoubleValue{Value: 120}}})
+	assert.Equal(codes.Aborted, status.Code(err), "activation changed the robot after it was read")
+
+	edited, err := clients.robots.EditRobot(owner, &robotapi.EditRobotRequest{RobotId: rob.RobotId,
+		Revision: active.Revision, Params: &robotapi.RobotParams{SellPrice: &wrappers.DoubleValue{Value: 120}}})
+	assert.NoError(err)
+	assert.Equal(120.0, edited.SellPrice)
+	assert.Equal(100.0, edited.BuyPrice)
+	assert.Equal(rob.Version+1, edited.Version)
+
+	versions, err := clients.robots.RobotVersions(owner, &robotapi.RobotRequest{RobotId: rob.RobotId})
+	assert.NoError(err)
+	assert.Len(versions.Versions, 2)
+
+	shares, err := clients.robots.ShareRobot(owner, &robotapi.ShareRobotRequest{RobotId: rob.RobotId, UserId: 2})
+	assert.NoError(err)
+	assert.Equal([]int64{2}, shares.Users)
+
+	_, err = clients.robots.EditRobot(owner, &robotapi.EditRobotRequest{RobotId: rob.RobotId, Revision: edited.Revision,
+		Params: &robotapi.RobotParams{Visibility: &wrappers.StringValue{Value: string(robot.VisibilityUsers)}}})
+	assert.NoError(err)
+
+	_, err = clients.robots.GetRobot(other, &robotapi.GetRobotRequest{RobotId: rob.RobotId})
+	assert.NoError(err)
+
+	_, err = clients.robots.EditRobot(other, &robotapi.EditRobotRequest{RobotId: rob.RobotId})
+	assert.Equal(codes.PermissionDenied, status.Code(err))
+
+	clone, err := clients.robots.CloneRobot(owner, &robotapi.EditRobotRequest{RobotId: rob.RobotId,
+		Params: &robotapi.RobotParams{Ticker: &wrappers.StringValue{Value: "SBER"}}})
+	assert.NoError(err)
+	assert.Equal("SBER", clone.Ticker)
+	assert.Equal(rob.RobotId, clone.ClonedFromId)
+
+	mine, err := clients.robots.ListRobots(owner, &robotapi.ListRobotsRequest{Mine: true, Sort: "robot_id", Desc: true})
+	assert.NoError(err)
+	assert.Len(mine.Robots, 2)
+	assert.Equal(clone.RobotId, mine.Robots[0].RobotId)
+
+	_, err = clients.robots.ListRobots(owner, &robotapi.ListRobotsRequest{Sort: "nope"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+
+	deleted, err := clients.robots.DeleteRobot(owner, &robotapi.RobotRequest{RobotId: clone.RobotId})
+	assert.NoError(err)
+	assert.NotNil(deleted.DeletedAt)
+
+	trash, err := clients.robots.ListDeletedRobots(owner, &robotapi.ListDeletedRobotsRequest{})
+	assert.NoError(err)
+	assert.Len(trash.Robots, 1)
+
+	_, err = clients.robots.RestoreRobot(owner, &robotapi.RobotRequest{RobotId: clone.RobotId})
+	assert.NoError(err)
+
+	_, err = clients.robots.PurgeRobot(owner, &robotapi.RobotRequest{RobotId: clone.RobotId})
+	assert.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestGRPCWatchRobot(t *testing.T) {
+	assert := assert.New(t)
+	_, clients := setupGRPCHandler(t)
+	owner := grpcSignIn(t, clients, "owner@example.com")
+
+	rob, err := clients.robots.CreateRobot(owner, &robotapi.RobotParams{Ticker: &wrappers.StringValue{Value: "AAPL"}})
+	assert.NoError(err)
+
+	ctx, cancel := context.WithTimeout(owner, 10*time.Second)
+	defer cancel()
+
+	stream, err := clients.robots.WatchRobot(ctx, &robotapi.GetRobotRequest{RobotId: rob.RobotId})
+	assert.NoError(err)
+
+	first, err := stream.Recv()
+	assert.NoError(err)
+	assert.Equal(string(robot.StatusDraft), first.Status)
+
+	_, err = clients.robots.ActivateRobot(owner, &robotapi.RobotRequest{RobotId: rob.RobotId})
+	assert.NoError(err)
+
+	changed, err := stream.Recv()
+	assert.NoError(err)
+	assert.Equal(string(robot.StatusActive), changed.Status)
+
+	_, err = clients.robots.DeleteRobot(owner, &robotapi.RobotRequest{RobotId: rob.RobotId})
+	assert.NoError(err)
+
+	_, err = stream.Recv()
+	assert.Equal(codes.NotFound, status.Code(err))
+}
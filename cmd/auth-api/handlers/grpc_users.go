@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/robotapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userService реализация robotapi.UserServiceServer поверх хранилищ хэндлера.
+type userService struct {
+	h *Handler
+}
+
+// SignUp регистрирует пользователя, как POST /api/v1/signup.
+func (s *userService) SignUp(ctx context.Context, req *robotapi.UserParams) (*robotapi.User, error) {
+	u, err := newUser(req)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "invalid user", err)
+	}
+
+	if err = s.h.userStorage.Create(u); err != nil {
+		return nil, s.h.grpcError(ctx, "func Create return with error", err)
+	}
+
+	s.h.logger.Infow("signup", "user", u.Email, "RealIP", remoteAddr(ctx))
+
+	return userProto(u), nil
+}
+
+// SignIn открывает сессию пользователя, как POST /api/v1/signin.
+func (s *userService) SignIn(ctx context.Context, req *robotapi.SignInRequest) (*robotapi.SignInResponse, error) {
+	u, err := s.h.userStorage.FindByEmail(req.Email)
+	if err != nil {
+		u = &user.User{}
+	}
+
+	if !CheckPasswordHash(u.Password, req.Password) {
+		s.h.logger.Warnw("incorrect email or password", "RealIP", remoteAddr(ctx))
+		return nil, status.Error(codes.Unauthenticated, "incorrect email or password")
+	}
+
+	ses := session.NewSession(u.ID)
+	if err = s.h.sessionStorage.Create(ses); err != nil {
+		return nil, s.h.grpcError(ctx, "can't add session in storage", err)
+	}
+
+	s.h.logger.Infow("signin", "user", u.Email, "RealIP", remoteAddr(ctx))
+
+	return &robotapi.SignInResponse{Token: ses.SessionID, UserId: int64(u.ID)}, nil
+}
+
+// GetUser возвращает пользователя текущей сессии.
+func (s *userService) GetUser(ctx context.Context, _ *robotapi.GetUserRequest) (*robotapi.User, error) {
+	u, err := s.h.userStorage.FindByID(sessionUserID(ctx))
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func userStorage.FindByID return with error", err)
+	}
+
+	return userProto(u), nil
+}
+
+// UpdateUser заменяет данные пользователя текущей сессии, как PUT /api/v1/users/{id}. Вместо If-Match
+// запрос передает version прочитанного пользователя.
+func (s *userService) UpdateUser(ctx context.Context, req *robotapi.UserParams) (*robotapi.User, error) {
+	userRequest, err := newUser(req)
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "invalid user", err)
+	}
+
+	userSession, err := s.h.userStorage.FindByID(sessionUserID(ctx))
+	if err != nil {
+		return nil, s.h.grpcError(ctx, "func userStorage.FindByID return with error", err)
+	}
+
+	if err = checkVersion(req.Version, userSession.Version); err != nil {
+		return nil, s.h.grpcError(ctx, "user precondition failed", err)
+	}
+
+	if u, _ := s.h.userStorage.FindByEmail(userRequest.Email); u != nil && u.ID != userSession.ID {
+		return nil, s.h.grpcError(ctx, "email is already in use", fmt.Errorf("%w: %s", user.ErrAlreadyExists, userRequest.Email))
+	}
+
+	userSession.Update(userRequest)
+
+	if err = s.h.userStorage.Update(userSession); err != nil {
+		return nil, s.h.grpcError(ctx, "func Update user in storage is crashed", err)
+	}
+
+	s.h.logger.Infow("update user", "user", userSession.Email, "RealIP", remoteAddr(ctx))
+
+	return userProto(userSession), nil
+}
+
+// newUser возвращает проверенного пользователя с хэшем пароля, как getUserFromBody для REST API.
+func newUser(params *robotapi.UserParams) (*user.User, error) {
+	birthday, err := user.ParseBirthday(params.Birthday)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &user.User{
+		FirstName: params.FirstName,
+		LastName:  params.LastName,
+		Birthday:  birthday,
+		Email:     params.Email,
+		Password:  params.Password,
+	}
+
+	if err = u.Validate(); err != nil {
+		return nil, err
+	}
+
+	if u.Password, err = CreatePasswordHash(u.Password); err != nil {
+		return nil, fmt.Errorf("func CreatePasswordHash return with error: %s", err)
+	}
+
+	return u, nil
+}
@@ -1,16 +1,27 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gitlab.com/hitchpock/tfs-course-work/internal/calendar"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	"gitlab.com/hitchpock/tfs-course-work/internal/idempotency"
+	"gitlab.com/hitchpock/tfs-course-work/internal/ratelimit"
 	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
 	"gitlab.com/hitchpock/tfs-course-work/internal/session"
 	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
 	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
 )
 
@@ -23,57 +34,112 @@ type Handler struct {
 	userStorage    user.Storage
 	robotStorage   robot.Storage
 	wsocket        *WSClients
+	events         event.Publisher
+	instruments    fintech.TradingServiceClient
+	specOnce       sync.Once
+	spec           []byte
+	specErr        error
+	graphql        *graphql.Schema
+	idempotency    idempotency.Storage
+	idempotencyTTL time.Duration
+	rateLimits     ratelimit.Storage
+	quotas         map[string]ratelimit.Quota
+	ipQuota        ratelimit.Quota
+	apiKeys        map[string]string
 }
 
 // NewHandler возвращает указатель на новый хэндлер.
-func NewHandler(logger log.Logger, sessions session.Storage, users user.Storage, robots robot.Storage, socket *WSClients) *Handler {
-	return &Handler{
+func NewHandler(logger log.Logger, sessions session.Storage, users user.Storage, robots robot.Storage, socket *WSClients,
+	events event.Publisher, instruments fintech.TradingServiceClient) *Handler {
+	h := &Handler{
 		logger:         logger,
 		sessionStorage: sessions,
 		userStorage:    users,
 		robotStorage:   robots,
 		wsocket:        socket,
+		events:         events,
+		instruments:    instruments,
+		idempotency:    idempotency.CreateStorageInMemory(),
+		idempotencyTTL: idempotency.DefaultTTL,
+		rateLimits:     ratelimit.CreateStorageInMemory(),
 	}
+	h.graphql = h.newGraphQLSchema()
+
+	return h
 }
 
 // Routes возвращает указатель на роутинг сервиса.
 func (h *Handler) Routes() chi.Router {
 	router := chi.NewRouter()
 
-	router.Use(middleware.RequestID)
-	router.Use(middleware.SetHeader("Content-type", "application/json"))
+	router.Use(middleware.RequestID, exposeRequestID)
+	router.Use(middleware.SetHeader("Content-Type", applicationJSON))
 	//router.Use(middleware.Timeout(100 * time.Millisecond))
 
 	router.Route("/api/v1", func(router chi.Router) {
-		router.Post("/signup", h.SignUp)
-		router.Post("/signin", h.SignIn)
+		router.Use(h.clientRateLimited)
+
+		router.With(h.rateLimited).Get("/openapi.json", h.OpenAPI)
+		router.With(h.rateLimited).Post("/signup", h.SignUp)
+		router.With(h.rateLimited).Post("/signin", h.SignIn)
 
 		router.Route("/users/{id}", func(router chi.Router) {
-			router.Use(h.getParamID, h.authentication, h.authorization)
+			router.Use(h.getParamID, h.authentication, h.authorization, h.rateLimited, h.idempotent)
 
 			router.Get("/robots", h.UserRobots)
+			router.Get("/robots/deleted", h.DeletedRobots)
 			router.Put("/", h.UpdateUser)
 			router.Get("/", h.GetUser)
 		})
 
 		router.Route("/", func(router chi.Router) {
-			router.Use(h.authentication)
+			router.Use(h.authentication, h.rateLimited, h.idempotent)
 
 			router.Get("/robots", h.CatalogRobots)
+			router.Get("/robots/leaderboard", h.Leaderboard)
 			router.Post("/robot", h.CreateRobot)
+			router.Post("/robots/batch", h.BatchCreateRobots)
+			router.Post("/robots/batch/activate", h.BatchActivateRobots)
+			router.Post("/robots/batch/deactivate", h.BatchDeactivateRobots)
+			router.Post("/robots/batch/delete", h.BatchDeleteRobots)
+			router.Post("/templates", h.CreateTemplate)
+			router.Get("/templates", h.Templates)
+			router.Post("/graphql", h.GraphQL)
+			router.Get("/graphql", h.GraphQLSubscriptions)
+		})
+
+		router.Route("/templates/{id}", func(router chi.Router) {
+			router.Use(h.getParamID, h.authentication, h.rateLimited, h.idempotent)
+
+			router.Post("/robots", h.InstantiateTemplate)
+			router.Get("/", h.Template)
+			router.Delete("/", h.DeleteTemplate)
 		})
 
 		router.Route("/robot/{id}", func(router chi.Router) {
-			router.Use(h.getParamID, h.authentication)
+			router.Use(h.getParamID, h.authentication, h.rateLimited, h.idempotent)
 
 			router.Put("/favourite", h.FavouriteRobot) //nolint:misspell
+			router.Put("/unfollow", h.UnfollowRobot)
 			router.Put("/activate", h.ActivateRobot)
 			router.Put("/deactivate", h.DeactivateRobot)
+			router.Put("/stop", h.StopRobot)
+			router.Get("/transitions", h.RobotTransitions)
+			router.Get("/versions", h.RobotVersions)
+			router.Get("/deals", h.RobotDeals)
+			router.Get("/stats", h.RobotStats)
+			router.Get("/shares", h.RobotShares)
+			router.Put("/shares/{userID}", h.ShareRobot)
+			router.Delete("/shares/{userID}", h.UnshareRobot)
+			router.Post("/clone", h.CloneRobot)
+			router.Put("/restore", h.RestoreRobot)
+			router.Delete("/purge", h.PurgeRobot)
 			router.Get("/", h.RobotDetails)
+			router.Put("/", h.EditRobot)
 			router.Delete("/", h.DeleteRobot)
 		})
 
-		router.HandleFunc("/wsrobotdetail", h.wsocket.WSRobotDeltail)
+		router.With(h.authentication, h.rateLimited).Get("/wsrobotdetail", h.wsocket.WSRobotDeltail)
 	})
 
 	return router
@@ -86,27 +152,13 @@ func (h *Handler) SignUp(w http.ResponseWriter, r *http.Request) {
 
 	u, err := getUserFromBody(r.Body)
 	if err != nil {
-		h.logger.Warnw("func getUserFromBody is crashed", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "invalid input", http.StatusBadRequest)
-
+		h.fail(w, r, "func getUserFromBody is crashed", err)
 		return
 	}
 	defer r.Body.Close()
 
 	if err = h.userStorage.Create(u); err != nil {
-		h.logger.Warnw("func Create return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-
-		responseJSON := []byte(errorJSON(err.Error()))
-
-		w.WriteHeader(http.StatusConflict)
-
-		if _, e := w.Write(responseJSON); e != nil {
-			h.logger.Warnw("unable to write responseJson", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "error on server", http.StatusInternalServerError)
-
-			return
-		}
-
+		h.fail(w, r, "func Create return with error", err)
 		return
 	}
 
@@ -121,9 +173,7 @@ func (h *Handler) SignIn(w http.ResponseWriter, r *http.Request) {
 
 	u, err := getSignInDataFromBody(r.Body)
 	if err != nil {
-		h.logger.Warnw("func getSignInData is crashed", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "invalid input", http.StatusBadRequest)
-
+		h.fail(w, r, "func getSignInData is crashed", err)
 		return
 	}
 	defer r.Body.Close()
@@ -135,14 +185,8 @@ func (h *Handler) SignIn(w http.ResponseWriter, r *http.Request) {
 
 	ch := CheckPasswordHash(userStorage.Password, u.Password)
 	if err != nil || !ch {
-		responseJSON := []byte(errorJSON("incorrect email or password"))
-
-		w.WriteHeader(http.StatusBadRequest)
-
-		if _, e := w.Write(responseJSON); e != nil {
-			h.logger.Warnw("unable to write responseJSON", "error", e, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "error on server", http.StatusInternalServerError)
-		}
+		h.logger.Warnw("incorrect email or password", "trackingID", reqID, "RealIP", remoteAddr)
+		sendError(w, "incorrect email or password", http.StatusBadRequest)
 
 		return
 	}
@@ -151,9 +195,7 @@ func (h *Handler) SignIn(w http.ResponseWriter, r *http.Request) {
 
 	ses := session.NewSession(userStorage.ID)
 	if err = h.sessionStorage.Create(ses); err != nil {
-		h.logger.Warnw("can't add session in storage", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
-
+		h.fail(w, r, "can't add session in storage", err)
 		return
 	}
 
@@ -161,17 +203,14 @@ func (h *Handler) SignIn(w http.ResponseWriter, r *http.Request) {
 
 	tokenJSON, err := json.Marshal(token)
 	if err != nil {
-		h.logger.Warnw("marhsal tokenJSON is crashed", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
-
+		h.fail(w, r, "marhsal tokenJSON is crashed", err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 
 	if _, err = w.Write(tokenJSON); err != nil {
-		h.logger.Warnw("unable to write tokenJSON", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+		h.fail(w, r, "unable to write tokenJSON", err)
 	}
 }
 
@@ -184,38 +223,31 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	userRequest, err := getUserFromBody(r.Body)
 	if err != nil {
-		h.logger.Warnw("func getUserFromBody return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "invalid input", http.StatusBadRequest)
-
+		h.fail(w, r, "func getUserFromBody return with error", err)
 		return
 	}
 	defer r.Body.Close()
 
 	userSession, err := h.userStorage.FindByID(sessionToken.UserID)
 	if err != nil {
-		responseJSON := []byte(errorJSON(err.Error()))
-
-		w.WriteHeader(http.StatusNotFound)
-
-		if _, err = w.Write(responseJSON); err != nil {
-			h.logger.Warnw("unable to write tokenJSON", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "error on server", http.StatusInternalServerError)
-		}
+		h.fail(w, r, "func userStorage.FindByID return with error", err)
+		return
+	}
 
+	if err = checkIfMatch(r, userETag(userSession), true); err != nil {
+		h.fail(w, r, "user precondition failed", err)
 		return
 	}
 
 	if u, _ := h.userStorage.FindByEmail(userRequest.Email); u != nil && u.ID != userSession.ID {
-		sendError(w, "email is already in use", http.StatusBadRequest)
+		h.fail(w, r, "email is already in use", fmt.Errorf("%w: %s", user.ErrAlreadyExists, userRequest.Email))
 		return
 	}
 
 	userSession.Update(userRequest)
 
 	if err = h.userStorage.Update(userSession); err != nil {
-		h.logger.Infow("func Update user in storage is crashed", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
-
+		h.fail(w, r, "func Update user in storage is crashed", err)
 		return
 	}
 
@@ -223,53 +255,42 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	userResponseJSON, err := userSession.MarshalJSON()
 	if err != nil {
-		h.logger.Warnw("marhsal userResponseJSON is crashed", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
-
+		h.fail(w, r, "marhsal userResponseJSON is crashed", err)
 		return
 	}
 
+	w.Header().Set("ETag", userETag(userSession))
 	w.WriteHeader(http.StatusOK)
 
 	if _, err = w.Write(userResponseJSON); err != nil {
-		h.logger.Warnw("unable to write userResponseJSON", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+		h.fail(w, r, "unable to write userResponseJSON", err)
 	}
 }
 
 // GetUser получает сущность пользователя.
 func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
-	reqID := middleware.GetReqID(r.Context())
-	remoteAddr := r.RemoteAddr
 	userID := r.Context().Value(idKey{}).(int)
 
 	u, err := h.userStorage.FindByID(userID)
 	if err != nil {
-		responseJSON := []byte(errorJSON(err.Error()))
-
-		w.WriteHeader(http.StatusNotFound)
-
-		if _, err = w.Write(responseJSON); err != nil {
-			h.logger.Warnw("unable to write tokenJSON", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "error on server", http.StatusInternalServerError)
-		}
+		h.fail(w, r, "func userStorage.FindByID return with error", err)
+		return
+	}
 
+	if notModified(w, r, userETag(u)) {
 		return
 	}
 
 	userResponseJSON, err := u.MarshalJSON()
 	if err != nil {
-		h.logger.Warnw("marhsal userResponseJSON is crashed", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
-
+		h.fail(w, r, "marhsal userResponseJSON is crashed", err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 
 	if _, err = w.Write(userResponseJSON); err != nil {
-		h.logger.Warnw("unable to write userResponseJSON", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+		h.fail(w, r, "unable to write userResponseJSON", err)
 	}
 }
 
@@ -283,30 +304,18 @@ func (h *Handler) CreateRobot(w http.ResponseWriter, r *http.Request) {
 
 	robotRequest, err := getRobotFromBody(r.Body)
 	if err != nil {
-		h.logger.Warnw("func getRobotFromRequest return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "invalid input", http.StatusBadRequest)
-
+		h.fail(w, r, "func getRobotFromRequest return with error", err)
 		return
 	}
 	defer r.Body.Close()
 
-	if sessionToken.UserID != robotRequest.OwnerUserID {
-		h.logger.Warnw("user have no permission", "userID", sessionToken.UserID, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "you have no permission", http.StatusForbidden)
-
+	if err = h.prepareNewRobot(r.Context(), sessionToken.UserID, robotRequest); err != nil {
+		h.fail(w, r, "invalid new robot", err)
 		return
 	}
 
-	robotRequest.FactYield = 0.0
-	robotRequest.DealsCount = 0
-	robotRequest.ParentRobotID = 0
-	robotRequest.IsActive = false
-	robotRequest.IsFavourite = false
-
-	if err := h.robotStorage.Create(robotRequest); err != nil {
-		h.logger.Warnw("func robotStorage.Create return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "can't create robot", http.StatusInternalServerError)
-
+	if err = h.robotStorage.Create(robotRequest); err != nil {
+		h.fail(w, r, "func robotStorage.Create return with error", err)
 		return
 	}
 
@@ -314,7 +323,8 @@ func (h *Handler) CreateRobot(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
-// DeleteRobot выполняет SoftDelete робота по id.
+// DeleteRobot выполняет SoftDelete робота по id. Торгующий робот сначала останавливается,
+// а его открытая позиция закрывается по текущей котировке.
 func (h *Handler) DeleteRobot(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	remoteAddr := r.RemoteAddr
@@ -322,123 +332,203 @@ func (h *Handler) DeleteRobot(w http.ResponseWriter, r *http.Request) {
 	token := r.Context().Value(tokenKey{}).(string)
 	sessionToken, _ := session.DecodeToken(token)
 
-	robotStorage, err := h.robotStorage.FindByID(robotID)
-	if err != nil {
-		if errors.Is(err, robot.ErrNotFound) {
-			h.logger.Warnw("robot not found", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "robot not found", http.StatusNotFound)
-
-			return
-		}
-
-		h.logger.Warnw("func robotStorage.FindByID return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusNotFound)
-
+	robotStorage, ok := h.robotToChange(w, r, robotID)
+	if !ok {
 		return
 	}
 
-	if robotStorage.OwnerUserID != sessionToken.UserID {
-		h.logger.Warnw("user have no permission", "userID", sessionToken.UserID, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "you have no permission", http.StatusForbidden)
-
+	revision, err := h.closeBeforeDelete(r.Context(), robotStorage, robotStorage.Revision)
+	if err != nil {
+		h.fail(w, r, "can't close robot position before delete", err)
 		return
 	}
 
-	if err = h.robotStorage.SoftDelete(robotID); err != nil {
-		h.logger.Warnw("func robotStorage.SoftDelete return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
-
+	if err := h.robotStorage.SoftDelete(robotID, revision); err != nil {
+		h.fail(w, r, "func robotStorage.SoftDelete return with error", err)
 		return
 	}
 
 	h.logger.Infow("'soft delete' robot", "userID", sessionToken.UserID)
+	h.publishRobotChanged(robotID, reqID, remoteAddr)
 	w.WriteHeader(http.StatusOK)
 }
 
-// UserRobots отправляет список неудаленных пользовательских роботов.
+// UserRobots отправляет страницу неудаленных пользовательских роботов, фильтры и сортировка задаются query-параметрами.
 func (h *Handler) UserRobots(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(idKey{}).(int)
+
+	h.findRobots(w, r, &userID)
+}
+
+// DeletedRobots отправляет корзину пользователя: удаленных роботов, которых еще можно восстановить.
+func (h *Handler) DeletedRobots(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	remoteAddr := r.RemoteAddr
 	userID := r.Context().Value(idKey{}).(int)
 
-	robots, err := h.robotStorage.FindActivatedByUserID(userID)
+	format, err := negotiate(w, r, robotListFormats...)
 	if err != nil {
-		h.logger.Warnw("func robotStorage.FindActivatedByUserID return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+		h.fail(w, r, "unsupported response format", err)
+		return
+	}
 
+	robots, err := h.robotStorage.FindDeleted(userID)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FindDeleted return with error", err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	h.writeRobots(w, format, robots, robotsView{Robots: robots}, reqID, remoteAddr)
+}
 
-	if r.Header.Get("Accept") == textHTML {
-		w.Header().Set("Content-type", textHTML)
-		renderTemplate(w, "listrobots", "base", robots)
+// RestoreRobot возвращает робота пользователя из корзины.
+func (h *Handler) RestoreRobot(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
 
+	if _, ok := h.ownRobotFrom(w, r, robotID, h.robotStorage.FindDeletedByID, false); !ok {
 		return
 	}
 
-	robotsJSON, err := json.Marshal(robots)
+	if err := h.robotStorage.Restore(robotID); err != nil {
+		h.fail(w, r, "func robotStorage.Restore return with error", err)
+		return
+	}
+
+	rob, err := h.robotStorage.FindByID(robotID)
 	if err != nil {
-		h.logger.Warnw("unable to marshal robots", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+		h.fail(w, r, "func robotStorage.FindByID return with error", err)
+		return
+	}
+
+	h.publishRobotChanged(robotID, reqID, remoteAddr)
+	h.writeJSON(w, http.StatusOK, rob, reqID, remoteAddr)
+}
 
+// PurgeRobot окончательно удаляет робота пользователя из корзины.
+func (h *Handler) PurgeRobot(w http.ResponseWriter, r *http.Request) {
+	robotID := r.Context().Value(idKey{}).(int)
+
+	if _, ok := h.ownRobotFrom(w, r, robotID, h.robotStorage.FindDeletedByID, false); !ok {
 		return
 	}
 
-	if _, err = w.Write(robotsJSON); err != nil {
-		h.logger.Warnw("unable to write robotsJSON", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	if err := h.robotStorage.Purge(robotID); err != nil {
+		h.fail(w, r, "func robotStorage.Purge return with error", err)
+		return
 	}
+
+	w.WriteHeader(http.StatusOK)
 }
 
-// CatalogRobots возвращает отсортированный по query-параметрам список роботов.
+// CatalogRobots возвращает отфильтрованную и отсортированную по query-параметрам страницу роботов.
 func (h *Handler) CatalogRobots(w http.ResponseWriter, r *http.Request) {
+	h.findRobots(w, r, nil)
+}
+
+// leaderboardView модель html представления рейтинга роботов.
+type leaderboardView struct {
+	Metric string
+	Period string
+	Stats  []robot.Stats
+}
+
+// Leaderboard возвращает рейтинг публичных роботов по метрике metric за период period.
+func (h *Handler) Leaderboard(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	remoteAddr := r.RemoteAddr
+	metric := r.URL.Query().Get("metric")
+	period := r.URL.Query().Get("period")
 
-	ticker := r.URL.Query().Get("ticker")
-	userID := r.URL.Query().Get("user")
+	format, err := negotiate(w, r, applicationJSON, textHTML)
+	if err != nil {
+		h.fail(w, r, "unsupported response format", err)
+		return
+	}
 
-	robots, err := h.robotStorage.Filter(ticker, userID)
+	limit, err := queryLimit(r, defaultLeaderboardLimit)
 	if err != nil {
-		if errors.Is(err, robot.ErrInvalidID) {
-			h.logger.Warnw("invalid id", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "invalid id", http.StatusInternalServerError)
+		h.fail(w, r, "invalid limit", err)
+		return
+	}
 
-			return
-		}
+	stats, err := h.leaderboard(metric, period, limit)
+	if err != nil {
+		h.fail(w, r, "can't build leaderboard", err)
+		return
+	}
 
-		h.logger.Warnw("func robotStorage.Filter return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	if format == textHTML {
+		w.Header().Set("Content-Type", textHTML)
+		w.WriteHeader(http.StatusOK)
+		renderTemplate(w, "leaderboard", "base", leaderboardView{Metric: metric, Period: period, Stats: stats})
 
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	h.writeJSON(w, http.StatusOK, stats, reqID, remoteAddr)
+}
 
-	if r.Header.Get("Accept") == "text/html" {
-		w.Header().Set("Content-type", "text/html")
-		renderTemplate(w, "listrobots", "base", robots)
+// leaderboard возвращает рейтинг публичных роботов по метрике metric за период period, не больше limit роботов.
+func (h *Handler) leaderboard(metric, period string, limit int) ([]robot.Stats, error) {
+	since, err := robot.PeriodStart(period, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
-		return
+	robots, err := h.robotStorage.FindActivated()
+	if err != nil {
+		return nil, fmt.Errorf("func robotStorage.FindActivated return with error: %s", err)
+	}
+
+	public := make([]robot.Robot, 0, len(robots))
+	ids := make([]int, 0, len(robots))
+
+	for _, rob := range robots {
+		if rob.Visibility == robot.VisibilityPublic {
+			public = append(public, rob)
+			ids = append(ids, rob.RobotID)
+		}
+	}
+
+	deals, err := h.robotStorage.FindDealsByRobotIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("func robotStorage.FindDealsByRobotIDs return with error: %s", err)
 	}
 
-	robotsJSON, err := json.Marshal(robots)
+	return robot.Leaderboard(robot.StatsByRobot(public, deals, since), metric, limit)
+}
+
+// RobotStats возвращает статистику робота за период period, если пользователь видит робота.
+func (h *Handler) RobotStats(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
+
+	since, err := robot.PeriodStart(r.URL.Query().Get("period"), time.Now())
 	if err != nil {
-		h.logger.Warnw("unable to marshal robots", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+		h.fail(w, r, "invalid period", err)
+		return
+	}
 
+	rob, ok := h.visibleRobot(w, r, robotID)
+	if !ok {
 		return
 	}
 
-	if _, err = w.Write(robotsJSON); err != nil {
-		h.logger.Warnw("unable to write robotsJSON", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	deals, err := h.robotStorage.FindDeals(robotID)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FindDeals return with error", err)
+		return
 	}
+
+	h.writeJSON(w, http.StatusOK, robot.ComputeStats(rob, deals, since), reqID, remoteAddr)
 }
 
-// Добаляет робота в список избранных.
+// FavouriteRobot добаляет копию робота в список избранных. В теле запроса можно передать
+// {"mirror": true, "lots": 2}, тогда копия повторяет параметры и сделки родителя в своем размере позиции.
+// Заголовок If-Match с ETag родителя обязателен: копия создается с теми параметрами, которые видел пользователь.
 func (h *Handler) FavouriteRobot(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	remoteAddr := r.RemoteAddr
@@ -446,167 +536,361 @@ func (h *Handler) FavouriteRobot(w http.ResponseWriter, r *http.Request) {
 	token := r.Context().Value(tokenKey{}).(string)
 	sessionToken, _ := session.DecodeToken(token)
 
-	if err := h.robotStorage.FavouriteRobot(robotID, sessionToken.UserID); err != nil {
-		if errors.Is(err, robot.ErrNotFound) {
-			h.logger.Warnw("robot not found", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "robot not found", http.StatusNotFound)
+	var follow robot.Follow
+	if err := validation.DecodeJSON(r.Body, &follow); err != nil && !errors.Is(err, validation.ErrEmptyBody) {
+		h.fail(w, r, "invalid input", err)
+		return
+	}
+	defer r.Body.Close()
 
-			return
-		}
+	parent, ok := h.visibleRobot(w, r, robotID)
+	if !ok {
+		return
+	}
 
-		h.logger.Warnw("func robotStorage.FavouriteRobot return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	if err := checkIfMatch(r, robotETag(parent), true); err != nil {
+		h.fail(w, r, "robot precondition failed", err)
+		return
+	}
 
+	follower, err := h.robotStorage.FavouriteRobot(robotID, sessionToken.UserID, follow, parent.Revision)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FavouriteRobot return with error", err)
 		return
 	}
 
-	h.wsocket.Broadcast(robotID)
-	w.WriteHeader(http.StatusOK)
+	h.publishRobotChanged(robotID, reqID, remoteAddr)
+	h.publishRobotChanged(follower.RobotID, reqID, remoteAddr)
+	h.writeJSON(w, http.StatusOK, follower, reqID, remoteAddr)
 }
 
-// ActivateRobot активирует робота пользователя.
-func (h *Handler) ActivateRobot(w http.ResponseWriter, r *http.Request) {
+// sharesView настройки доступа к роботу для его владельца.
+type sharesView struct {
+	Visibility robot.Visibility `json:"visibility"`
+	ShareLink  string           `json:"share_link,omitempty"`
+	Users      []int            `json:"users"`
+}
+
+// RobotShares возвращает владельцу видимость робота, ссылку для доступа и пользователей, которым робот открыт.
+func (h *Handler) RobotShares(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	remoteAddr := r.RemoteAddr
 	robotID := r.Context().Value(idKey{}).(int)
-	token := r.Context().Value(tokenKey{}).(string)
-	sessionToken, _ := session.DecodeToken(token)
 
-	rob, err := h.robotStorage.FindByID(robotID)
+	rob, ok := h.ownRobot(w, r, robotID)
+	if !ok {
+		return
+	}
+
+	view, err := h.shares(rob)
 	if err != nil {
-		if errors.Is(err, robot.ErrNotFound) {
-			h.logger.Warnw("robot not found", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "robot not found", http.StatusNotFound)
+		h.fail(w, r, "func robotStorage.FindShares return with error", err)
+		return
+	}
 
-			return
-		}
+	h.writeJSON(w, http.StatusOK, view, reqID, remoteAddr)
+}
 
-		h.logger.Warnw("func robotStorage.FavouriteRobot return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+// shares возвращает настройки доступа к роботу rob.
+func (h *Handler) shares(rob *robot.Robot) (*sharesView, error) {
+	users, err := h.robotStorage.FindShares(rob.RobotID)
+	if err != nil {
+		return nil, err
+	}
 
-		return
+	view := &sharesView{Visibility: rob.Visibility, Users: users}
+	if rob.Visibility == robot.VisibilityLink {
+		view.ShareLink = fmt.Sprintf("/api/v1/robot/%d?share=%s", rob.RobotID, rob.ShareToken)
 	}
 
-	if rob.OwnerUserID != sessionToken.UserID {
-		h.logger.Warnw("user have no permission", "userID", sessionToken.UserID, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "you have no permission", http.StatusForbidden)
+	return view, nil
+}
+
+// ShareRobot открывает робота пользователю. Робот виден ему, пока видимость робота users.
+func (h *Handler) ShareRobot(w http.ResponseWriter, r *http.Request) {
+	h.changeShare(w, r, h.robotStorage.Share)
+}
+
+// UnshareRobot закрывает робота от пользователя.
+func (h *Handler) UnshareRobot(w http.ResponseWriter, r *http.Request) {
+	h.changeShare(w, r, h.robotStorage.Unshare)
+}
 
+// changeShare проверяет владельца робота и пользователя из URL и меняет доступ функцией change.
+func (h *Handler) changeShare(w http.ResponseWriter, r *http.Request, change func(robotID, userID int) error) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
+
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.fail(w, r, "invalid user id", fmt.Errorf("%w: user id %q is not an integer", robot.ErrInvalidID, chi.URLParam(r, "userID")))
 		return
 	}
 
-	if rob.IsActive {
-		h.logger.Warnw("robot already activated", "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "robot already activated", http.StatusBadRequest)
+	if _, ok := h.ownRobot(w, r, robotID); !ok {
+		return
+	}
 
+	if _, err = h.userStorage.FindByID(userID); err != nil {
+		h.fail(w, r, "func userStorage.FindByID return with error", err)
 		return
 	}
 
-	if !rob.PlanStart.Valid || !rob.PlanEnd.Valid || (time.Now().After(rob.PlanStart.Time) && time.Now().Before(rob.PlanEnd.Time)) {
-		h.logger.Warnw("time activated in range [plan_start:plan_end] or plan_start/plan_end is NULL", "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "time activated in range [plan_start:plan_end] or plan_start/plan_end is NULL", http.StatusBadRequest)
+	if err = change(robotID, userID); err != nil {
+		h.fail(w, r, "can't change robot share", err)
+		return
+	}
+
+	h.publishRobotChanged(robotID, reqID, remoteAddr)
+	w.WriteHeader(http.StatusOK)
+}
+
+// UnfollowRobot отключает повторение родителя у робота пользователя.
+func (h *Handler) UnfollowRobot(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
 
+	rob, ok := h.robotToChange(w, r, robotID)
+	if !ok {
 		return
 	}
 
-	if err = h.robotStorage.ActivateRobot(robotID); err != nil {
-		h.logger.Warnw("func robotStorage return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	if !rob.IsMirror {
+		h.logger.Warnw("robot doesn't follow its parent", "robotID", robotID, "trackingID", reqID, "RealIP", remoteAddr)
+		sendError(w, "robot doesn't follow its parent", http.StatusConflict)
 
 		return
 	}
 
-	h.wsocket.Broadcast(rob.RobotID)
+	if err := h.robotStorage.Unfollow(robotID, rob.Revision); err != nil {
+		h.fail(w, r, "func robotStorage.Unfollow return with error", err)
+		return
+	}
+
+	h.publishRobotChanged(robotID, reqID, remoteAddr)
+
+	if rob.ParentRobotID != 0 {
+		h.publishRobotChanged(rob.ParentRobotID, reqID, remoteAddr)
+	}
+
 	w.WriteHeader(http.StatusOK)
 }
 
-// DeactivateRobot дективирует робота пользователя.
+// ActivateRobot запускает робота пользователя.
+func (h *Handler) ActivateRobot(w http.ResponseWriter, r *http.Request) {
+	h.applyRobotAction(w, r, robot.ActionActivate)
+}
+
+// DeactivateRobot приостанавливает робота пользователя.
 func (h *Handler) DeactivateRobot(w http.ResponseWriter, r *http.Request) {
+	h.applyRobotAction(w, r, robot.ActionDeactivate)
+}
+
+// StopRobot завершает работу робота пользователя.
+func (h *Handler) StopRobot(w http.ResponseWriter, r *http.Request) {
+	h.applyRobotAction(w, r, robot.ActionStop)
+}
+
+// applyRobotAction выполняет действие над роботом пользователя, допустимость проверяет таблица переходов.
+func (h *Handler) applyRobotAction(w http.ResponseWriter, r *http.Request, action robot.Action) {
 	reqID := middleware.GetReqID(r.Context())
 	remoteAddr := r.RemoteAddr
 	robotID := r.Context().Value(idKey{}).(int)
-	token := r.Context().Value(tokenKey{}).(string)
-	sessionToken, _ := session.DecodeToken(token)
 
-	rob, err := h.robotStorage.FindByID(robotID)
+	rob, ok := h.robotToChange(w, r, robotID)
+	if !ok {
+		return
+	}
+
+	if err := h.robotStorage.Transition(robotID, action, robot.SourceUser, rob.Revision); err != nil {
+		h.fail(w, r, "func robotStorage.Transition return with error", err)
+		return
+	}
+
+	h.logger.Infow("robot transition", "action", action, "robotID", robotID, "trackingID", reqID, "RealIP", remoteAddr)
+	h.publishRobotChanged(robotID, reqID, remoteAddr)
+	w.WriteHeader(http.StatusOK)
+}
+
+// RobotDetails возвращает json/html представление одного робота, если пользователь его видит.
+// Робота с доступом по ссылке открывает query-параметр share.
+func (h *Handler) RobotDetails(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
+
+	format, err := negotiate(w, r, applicationJSON, textHTML)
 	if err != nil {
-		if errors.Is(err, robot.ErrNotFound) {
-			h.logger.Warnw("robot not found", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "robot not found", http.StatusNotFound)
+		h.fail(w, r, "unsupported response format", err)
+		return
+	}
 
-			return
-		}
+	rob, ok := h.visibleRobot(w, r, robotID)
+	if !ok {
+		return
+	}
 
-		h.logger.Warnw("func robotStorage.FavouriteRobot return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	etag := robotETag(rob)
+	page := robotPage{Robot: rob}
 
+	if format == textHTML {
+		page.Actions = rob.AllowedActions(time.Now())
+		etag = robotPageETag(rob, page.Actions)
+	}
+
+	if notModified(w, r, etag) {
 		return
 	}
 
-	if rob.OwnerUserID != sessionToken.UserID {
-		h.logger.Warnw("user have no permission", "userID", sessionToken.UserID, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "you have no permission", http.StatusForbidden)
+	if format == textHTML {
+		w.Header().Set("Content-Type", textHTML)
+		w.WriteHeader(http.StatusOK)
+		renderTemplate(w, "robotdetail", "base", page)
 
 		return
 	}
 
-	if !rob.IsActive {
-		h.logger.Warnw("robot already deactivated", "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "robot already deactivated", http.StatusBadRequest)
+	h.writeJSON(w, http.StatusOK, rob, reqID, remoteAddr)
+}
+
+// robotPage данные HTML страницы робота. Действия вычисляются один раз для страницы и ее ETag.
+type robotPage struct {
+	*robot.Robot
+	Actions []robot.Action
+}
+
+// RobotTransitions возвращает историю переходов робота пользователя.
+func (h *Handler) RobotTransitions(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
 
+	if _, ok := h.ownRobot(w, r, robotID); !ok {
 		return
 	}
 
-	if !rob.PlanStart.Valid || !rob.PlanEnd.Valid || (time.Now().After(rob.PlanStart.Time) && time.Now().Before(rob.PlanEnd.Time)) {
-		h.logger.Warnw("time deactivated in range [plan_start:plan_end] or plan_start/plan_end is NULL", "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "time deactivated in range [plan_start:plan_end] or plan_start/plan_end is NULL", http.StatusBadRequest)
+	transitions, err := h.robotStorage.FindTransitions(robotID)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FindTransitions return with error", err)
+		return
+	}
 
+	h.writeJSON(w, http.StatusOK, transitions, reqID, remoteAddr)
+}
+
+// EditRobot меняет параметры робота пользователя. Поля, которых нет в запросе, не меняются.
+func (h *Handler) EditRobot(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
+
+	rob, ok := h.robotToChange(w, r, robotID)
+	if !ok {
 		return
 	}
 
-	if err = h.robotStorage.DeactivateRobot(robotID); err != nil {
-		h.logger.Warnw("func robotStorage.Deactivate return with error", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	var in robotInput
 
+	if err := validation.DecodeJSON(r.Body, &in); err != nil {
+		h.fail(w, r, "invalid input", err)
 		return
 	}
+	defer r.Body.Close()
 
-	h.wsocket.Broadcast(rob.RobotID)
-	w.WriteHeader(http.StatusOK)
+	if err := in.withoutOwner(); err != nil {
+		h.fail(w, r, "invalid input", err)
+		return
+	}
+
+	changes := *rob
+	in.apply(&changes)
+
+	if err := h.checkChanges(r.Context(), rob, &changes); err != nil {
+		h.fail(w, r, "invalid changes", err)
+		return
+	}
+
+	edited, err := h.robotStorage.Edit(robotID, &changes)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.Edit return with error", err)
+		return
+	}
+
+	h.logger.Infow("edit robot", "robotID", robotID, "version", edited.Version, "trackingID", reqID, "RealIP", remoteAddr)
+	h.publishRobotChanged(robotID, reqID, remoteAddr)
+	w.Header().Set("ETag", robotETag(edited))
+	h.writeJSON(w, http.StatusOK, edited, reqID, remoteAddr)
 }
 
-// RobotDetails возвращает json/html представление одного робота.
-func (h *Handler) RobotDetails(w http.ResponseWriter, r *http.Request) {
+// checkChanges проверяет новые параметры changes робота rob: расписание и тикер, если он изменился.
+func (h *Handler) checkChanges(ctx context.Context, rob, changes *robot.Robot) error {
+	if changes.Schedule != "" {
+		if _, err := calendar.ParseRecurring(changes.Schedule); err != nil {
+			return validation.Field(robot.ErrInvalidRobot, "schedule", "is not a valid schedule")
+		}
+	}
+
+	if changes.Ticker != rob.Ticker && changes.Ticker != "" {
+		if err := h.checkTicker(ctx, changes.Ticker); err != nil {
+			if errors.Is(err, robot.ErrUnknownTicker) {
+				return validation.Field(robot.ErrInvalidRobot, "ticker", "is unknown")
+			}
+
+			return fmt.Errorf("%w: %s", errPriceUnavailable, err)
+		}
+	}
+
+	return nil
+}
+
+// RobotVersions возвращает историю параметров робота пользователя.
+func (h *Handler) RobotVersions(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetReqID(r.Context())
 	remoteAddr := r.RemoteAddr
 	robotID := r.Context().Value(idKey{}).(int)
 
-	rob, err := h.robotStorage.FindByID(robotID)
-	if err != nil {
-		h.logger.Warnw("robot not found", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "robot not found", http.StatusNotFound)
+	if _, ok := h.ownRobot(w, r, robotID); !ok {
+		return
+	}
 
+	versions, err := h.robotStorage.FindVersions(robotID)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FindVersions return with error", err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	h.writeJSON(w, http.StatusOK, versions, reqID, remoteAddr)
+}
 
-	if r.Header.Get("Accept") == textHTML {
-		w.Header().Set("Content-type", "text/html")
-		renderTemplate(w, "robotdetail", "base", rob)
+// RobotDeals возвращает сделки робота пользователя с версиями параметров, по которым они совершены,
+// в JSON, CSV или NDJSON по заголовку Accept.
+func (h *Handler) RobotDeals(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
 
+	format, err := negotiate(w, r, exportFormats...)
+	if err != nil {
+		h.fail(w, r, "unsupported response format", err)
 		return
 	}
 
-	robotJSON, err := rob.MarshalJSON()
-	if err != nil {
-		h.logger.Warnw("unable to marshal robot", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	if _, ok := h.ownRobot(w, r, robotID); !ok {
+		return
+	}
 
+	deals, err := h.robotStorage.FindDeals(robotID)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FindDeals return with error", err)
 		return
 	}
 
-	if _, err = w.Write(robotJSON); err != nil {
-		h.logger.Warnw("unable to write robotJSON", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-		sendError(w, "error on server", http.StatusInternalServerError)
+	if format != applicationJSON {
+		h.writeExport(w, format, dealsExport(deals), reqID, remoteAddr)
+		return
 	}
+
+	h.writeJSON(w, http.StatusOK, deals, reqID, remoteAddr)
 }
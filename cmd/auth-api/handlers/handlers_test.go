@@ -2,23 +2,35 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
 	"gitlab.com/hitchpock/tfs-course-work/internal/session"
 	"gitlab.com/hitchpock/tfs-course-work/internal/user"
 	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
-	urlSignUp = "/api/v1/signup"
-	urlSignIn = "/api/v1/signin"
+	urlSignUp      = "/api/v1/signup"
+	urlSignIn      = "/api/v1/signin"
+	urlCreateRobot = "/api/v1/robot"
 
 	correctSignUp     = `{"first_name":"Ivan","last_name":"Ivanov","birthday":"1980-01-02","email":"e@example.com","password":"1234"}`
 	correctSignIn     = `{"email":"e@example.com","password":"1234"}`
@@ -49,7 +61,7 @@ func TestSingUp(t *testing.T) {
 		tc := tc
 		t.Run(tc.Name, func(t *testing.T) {
 			userStorage := user.CreateStorageInMemory()
-			h := NewHandler(logger, sessionStorage, userStorage, nil, nil)
+			h := NewHandler(logger, sessionStorage, userStorage, nil, nil, nil, nil)
 			handler := http.HandlerFunc(h.SignUp)
 			request := httptest.NewRequest(http.MethodPost, urlSignUp, bytes.NewBuffer(tc.In))
 			recoder := httptest.NewRecorder()
@@ -64,7 +76,7 @@ func TestSingUp(t *testing.T) {
 
 	repitSignUp := testCase{Name: "Repited SignUp", In: []byte(`{"first_name":"Ivan","last_name":"Ivanov","birthday":"1980-01-02","email":"e@example.com","password":"1234"}`), ExpectedCode: http.StatusConflict}
 	userStorage := user.CreateStorageInMemory()
-	h := NewHandler(logger, sessionStorage, userStorage, nil, nil)
+	h := NewHandler(logger, sessionStorage, userStorage, nil, nil, nil, nil)
 	handler := http.HandlerFunc(h.SignUp)
 
 	for i := 0; i < 2; i++ {
@@ -97,7 +109,7 @@ func TestSignIn(t *testing.T) {
 	logger := log.NewSugarLogger()
 	sessionStorage := session.CreateStorageInMemory()
 	userStorage := user.CreateStorageInMemory()
-	h := NewHandler(logger, sessionStorage, userStorage, nil, nil)
+	h := NewHandler(logger, sessionStorage, userStorage, nil, nil, nil, nil)
 
 	testCases := []testCase{
 		{Name: "Empty body", In: []byte(""), ExpectedCode: http.StatusBadRequest},
@@ -138,7 +150,7 @@ func TestUpdateUser(t *testing.T) {
 	logger := log.NewSugarLogger()
 	sessionStorage := session.CreateStorageInMemory()
 	userStorage := user.CreateStorageInMemory()
-	h := NewHandler(logger, sessionStorage, userStorage, nil, nil)
+	h := NewHandler(logger, sessionStorage, userStorage, nil, nil, nil, nil)
 
 	testCases := []testCase{
 		{Name: "Someone else id", Path: "/api/v1/users/12", Body: validUpdateUser, ExpectedCode: http.StatusForbidden},
@@ -173,6 +185,922 @@ func TestUpdateUser(t *testing.T) {
 	}
 }
 
+// fakeInstruments сервис котировок, который знает только заданные тикеры.
+type fakeInstruments struct {
+	fintech.TradingServiceClient
+	tickers map[string]bool
+}
+
+func (f *fakeInstruments) GetQuote(_ context.Context, in *fintech.QuoteRequest, _ ...grpc.CallOption) (*fintech.Quote, error) {
+	if !f.tickers[in.Ticker] {
+		return nil, status.Errorf(codes.NotFound, "unknown ticker %q", in.Ticker)
+	}
+
+	return &fintech.Quote{Ticker: in.Ticker, BuyPrice: 100, SellPrice: 99}, nil
+}
+
+type nopPublisher struct{}
+
+func (nopPublisher) Publish(event.Event) error { return nil }
+
+func TestCreateRobot(t *testing.T) {
+	type testCase struct {
+		Name           string
+		Body           string
+		ExpectedCode   int
+		ExpectedFields []string
+	}
+
+	assert := assert.New(t)
+	logger := log.NewSugarLogger()
+	sessionStorage := session.CreateStorageInMemory()
+	userStorage := user.CreateStorageInMemory()
+	robotStorage := robot.CreateStorageInMemory()
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true}}
+	h := NewHandler(logger, sessionStorage, userStorage, robotStorage, nil, nopPublisher{}, instruments)
+
+	setupSignUp(h, t)
+	token := setupUser(h, t, "second@example.com")
+
+	testCases := []testCase{
+		{Name: "Known ticker", Body: `{"owner_user_id":1,"ticker":"AAPL"}`, ExpectedCode: http.StatusCreated},
+		{Name: "Unknown ticker", Body: `{"owner_user_id":1,"ticker":"NOPE"}`,
+			ExpectedCode: http.StatusBadRequest, ExpectedFields: []string{"ticker"}},
+		{Name: "Someone else robot", Body: `{"owner_user_id":2,"ticker":"AAPL"}`, ExpectedCode: http.StatusForbidden},
+		{Name: "Read-only fields", Body: `{"owner_user_id":1,"is_favourite":false,"is_active":false,"ticker":"AAPL"}`,
+			ExpectedCode: http.StatusBadRequest, ExpectedFields: []string{"is_favourite", "is_active"}},
+		{Name: "Unknown field", Body: `{"owner_user_id":1,"ticker":"AAPL","buy":10}`,
+			ExpectedCode: http.StatusBadRequest, ExpectedFields: []string{"buy"}},
+		{Name: "Wrong type", Body: `{"owner_user_id":1,"ticker":"AAPL","buy_price":"10"}`,
+			ExpectedCode: http.StatusBadRequest, ExpectedFields: []string{"buy_price"}},
+		{Name: "Business rules", Body: `{"owner_user_id":1,"ticker":"AAPL","buy_price":-1,"sell_price":-2,"lots":-1,` +
+			`"plan_start":"2020-06-02T00:00:00Z","plan_end":"2020-06-01T00:00:00Z"}`,
+			ExpectedCode: http.StatusBadRequest, ExpectedFields: []string{"lots", "buy_price", "sell_price", "sell_price", "plan_end"}},
+		{Name: "Without ticker", Body: `{"owner_user_id":1}`, ExpectedCode: http.StatusBadRequest, ExpectedFields: []string{"ticker"}},
+	}
+
+	r := chi.NewRouter()
+	r.With(h.authentication).Post(urlCreateRobot, h.CreateRobot)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			auth := fmt.Sprintf("Bearer %s", token.Token)
+			recoder, code := testRequestWithAuth(t, ts, http.MethodPost, urlCreateRobot, auth, bytes.NewBuffer([]byte(tc.Body)))
+			defer recoder.Body.Close()
+
+			assert.Equal(tc.ExpectedCode, code, "Wrong http code, request: %q", tc.Body)
+
+			if tc.ExpectedFields != nil {
+				var resp apiError
+				assert.NoError(json.NewDecoder(recoder.Body).Decode(&resp))
+
+				fields := make([]string, 0, len(resp.Fields))
+				for _, f := range resp.Fields {
+					fields = append(fields, f.Field)
+				}
+
+				assert.Equal(tc.ExpectedFields, fields, "Wrong invalid fields, request: %q", tc.Body)
+			}
+		})
+	}
+
+	robots, err := robotStorage.FindActivatedByUserID(1)
+	assert.NoError(err)
+	assert.Len(robots, 1)
+}
+
+func TestRobotActions(t *testing.T) {
+	type testCase struct {
+		Name           string
+		Action         string
+		IfMatch        string
+		ExpectedCode   int
+		ExpectedStatus robot.Status
+	}
+
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, nil)
+
+	setupSignUp(h, t)
+	token := setupUser(h, t, "second@example.com")
+
+	rob := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL"}
+	assert.NoError(robotStorage.Create(rob))
+
+	testCases := []testCase{
+		{Name: "Deactivate draft", Action: "deactivate", ExpectedCode: http.StatusConflict, ExpectedStatus: robot.StatusDraft},
+		{Name: "Activate without If-Match", Action: "activate", IfMatch: "-", ExpectedCode: http.StatusPreconditionRequired,
+			ExpectedStatus: robot.StatusDraft},
+		{Name: "Activate with stale ETag", Action: "activate", IfMatch: `"r0.0"`, ExpectedCode: http.StatusPreconditionFailed,
+			ExpectedStatus: robot.StatusDraft},
+		{Name: "Activate draft", Action: "activate", ExpectedCode: http.StatusOK, ExpectedStatus: robot.StatusActive},
+		{Name: "Activate twice", Action: "activate", ExpectedCode: http.StatusConflict, ExpectedStatus: robot.StatusActive},
+		{Name: "Pause", Action: "deactivate", ExpectedCode: http.StatusOK, ExpectedStatus: robot.StatusPaused},
+		{Name: "Stop", Action: "stop", ExpectedCode: http.StatusOK, ExpectedStatus: robot.StatusStopped},
+	}
+
+	r := chi.NewRouter()
+	r.Route("/api/v1/robot/{id}", func(router chi.Router) {
+		router.Use(h.getParamID, h.authentication)
+		router.Put("/activate", h.ActivateRobot)
+		router.Put("/deactivate", h.DeactivateRobot)
+		router.Put("/stop", h.StopRobot)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			auth := fmt.Sprintf("Bearer %s", token.Token)
+			path := fmt.Sprintf("/api/v1/robot/%d/%s", rob.RobotID, tc.Action)
+
+			ifMatch := tc.IfMatch
+			switch ifMatch {
+			case "":
+				ifMatch = currentETag(t, robotStorage, rob.RobotID)
+			case "-":
+				ifMatch = ""
+			}
+
+			recoder, code := testRequestIfMatch(t, ts, http.MethodPut, path, auth, ifMatch, nil)
+			defer recoder.Body.Close()
+
+			assert.Equal(tc.ExpectedCode, code)
+
+			actual, err := robotStorage.FindByID(rob.RobotID)
+			assert.NoError(err)
+			assert.Equal(tc.ExpectedStatus, actual.Status)
+		})
+	}
+}
+
+func TestEditRobot(t *testing.T) {
+	type testCase struct {
+		Name         string
+		RobotID      int
+		Body         string
+		IfMatch      string
+		ExpectedCode int
+	}
+
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true, "SBER": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, instruments)
+
+	setupSignUp(h, t)
+	token := setupUser(h, t, "second@example.com")
+
+	waiting := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	holding := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, IsActive: true}
+	foreign := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL"}
+
+	for _, rob := range []*robot.Robot{waiting, holding, foreign} {
+		assert.NoError(robotStorage.Create(rob))
+	}
+
+	holding.Buy(9)
+	assert.NoError(robotStorage.Trade(holding, robot.Deal{Side: robot.SideBuy, Price: 9}))
+
+	testCases := []testCase{
+		{Name: "Change prices", RobotID: waiting.RobotID, Body: `{"buy_price":11,"sell_price":21}`, ExpectedCode: http.StatusOK},
+		{Name: "Change ticker", RobotID: waiting.RobotID, Body: `{"ticker":"SBER"}`, ExpectedCode: http.StatusOK},
+		{Name: "Unknown ticker", RobotID: waiting.RobotID, Body: `{"ticker":"NOPE"}`, ExpectedCode: http.StatusBadRequest},
+		{Name: "Negative price", RobotID: waiting.RobotID, Body: `{"buy_price":-1}`, ExpectedCode: http.StatusBadRequest},
+		{Name: "Invalid schedule", RobotID: waiting.RobotID, Body: `{"schedule":"sometimes"}`, ExpectedCode: http.StatusBadRequest},
+		{Name: "Price while holding", RobotID: holding.RobotID, Body: `{"sell_price":30}`, ExpectedCode: http.StatusConflict},
+		{Name: "Plan yield while holding", RobotID: holding.RobotID, Body: `{"plan_yield":5}`, ExpectedCode: http.StatusOK},
+		{Name: "Read-only field", RobotID: waiting.RobotID, Body: `{"buy_price":1,"status":"active"}`,
+			ExpectedCode: http.StatusBadRequest},
+		{Name: "Representation field", RobotID: waiting.RobotID, Body: `{"buy_price":1,"created_at":""}`,
+			ExpectedCode: http.StatusBadRequest},
+		{Name: "Owner", RobotID: waiting.RobotID, Body: `{"owner_user_id":1}`, ExpectedCode: http.StatusBadRequest},
+		{Name: "Someone else robot", RobotID: foreign.RobotID, Body: `{"buy_price":1}`, ExpectedCode: http.StatusForbidden},
+		{Name: "Stale ETag", RobotID: waiting.RobotID, Body: `{"buy_price":1}`, IfMatch: `"r1.0"`,
+			ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "Weak ETag", RobotID: waiting.RobotID, Body: `{"buy_price":1}`, IfMatch: `W/"r3.0"`,
+			ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "Without If-Match", RobotID: waiting.RobotID, Body: `{"buy_price":1}`, IfMatch: "-",
+			ExpectedCode: http.StatusPreconditionRequired},
+	}
+
+	r := chi.NewRouter()
+	r.Route("/api/v1/robot/{id}", func(router chi.Router) {
+		router.Use(h.getParamID, h.authentication)
+		router.Put("/", h.EditRobot)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			auth := fmt.Sprintf("Bearer %s", token.Token)
+			path := fmt.Sprintf("/api/v1/robot/%d", tc.RobotID)
+
+			ifMatch := tc.IfMatch
+			switch ifMatch {
+			case "":
+				ifMatch = currentETag(t, robotStorage, tc.RobotID)
+			case "-":
+				ifMatch = ""
+			}
+
+			recoder, code := testRequestIfMatch(t, ts, http.MethodPut, path, auth, ifMatch, bytes.NewBuffer([]byte(tc.Body)))
+			defer recoder.Body.Close()
+
+			assert.Equal(tc.ExpectedCode, code, "Wrong http code, request: %q", tc.Body)
+
+			if code == http.StatusOK {
+				assert.Equal(currentETag(t, robotStorage, tc.RobotID), recoder.Header.Get("ETag"))
+			}
+		})
+	}
+
+	rob, err := robotStorage.FindByID(waiting.RobotID)
+	assert.NoError(err)
+	assert.Equal("SBER", rob.Ticker)
+	assert.Equal(11.0, rob.BuyPrice)
+
+	versions, err := robotStorage.FindVersions(waiting.RobotID)
+	assert.NoError(err)
+	assert.Len(versions, 3)
+	assert.Equal(3, rob.Version)
+}
+
+func TestFollowRobot(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, instruments)
+
+	setupSignUp(h, t)
+	token := setupUser(h, t, "second@example.com")
+	auth := fmt.Sprintf("Bearer %s", token.Token)
+
+	parent := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(robotStorage.Create(parent))
+
+	r := chi.NewRouter()
+	r.Route("/api/v1/robot/{id}", func(router chi.Router) {
+		router.Use(h.getParamID, h.authentication)
+		router.Put("/favourite", h.FavouriteRobot) //nolint:misspell
+		router.Put("/unfollow", h.UnfollowRobot)
+		router.Put("/", h.EditRobot)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	request := func(robotID int, action, body string) (*http.Response, int) {
+		return testRequestIfMatch(t, ts, http.MethodPut, fmt.Sprintf("/api/v1/robot/%d/%s", robotID, action), auth,
+			currentETag(t, robotStorage, robotID), bytes.NewBuffer([]byte(body)))
+	}
+
+	edit := func(robotID int, body string) (*http.Response, int) {
+		return testRequestIfMatch(t, ts, http.MethodPut, fmt.Sprintf("/api/v1/robot/%d", robotID), auth,
+			currentETag(t, robotStorage, robotID), bytes.NewBuffer([]byte(body)))
+	}
+
+	parentPath := fmt.Sprintf("/api/v1/robot/%d", parent.RobotID)
+
+	resp, code := request(parent.RobotID, "favourite", `{"lots":-1}`) //nolint:misspell
+	resp.Body.Close()
+	assert.Equal(http.StatusBadRequest, code)
+
+	resp, code = testRequestWithAuth(t, ts, http.MethodPut, parentPath+"/favourite", auth, //nolint:misspell
+		bytes.NewBuffer([]byte(`{"mirror":true}`)))
+	resp.Body.Close()
+	assert.Equal(http.StatusPreconditionRequired, code, "the copy is made of the parent the user has seen")
+
+	resp, code = request(parent.RobotID, "favourite", `{"mirror":true,"lots":2}`) //nolint:misspell
+	assert.Equal(http.StatusOK, code)
+
+	var follower robot.Robot
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&follower))
+	resp.Body.Close()
+	assert.True(follower.IsMirror)
+	assert.Equal(2, follower.Lots)
+
+	resp, code = request(follower.RobotID, "favourite", `{"mirror":true}`) //nolint:misspell
+	resp.Body.Close()
+	assert.Equal(http.StatusConflict, code, "mirror of a mirror is not allowed")
+
+	_, err := robotStorage.Edit(parent.RobotID, &robot.Robot{Ticker: "AAPL", BuyPrice: 12, SellPrice: 22, Lots: 1,
+		Revision: parent.Revision})
+	assert.NoError(err)
+
+	synced, err := robotStorage.FindByID(follower.RobotID)
+	assert.NoError(err)
+	assert.Equal(12.0, synced.BuyPrice, "parent changes must propagate to the follower")
+	assert.Equal(2, synced.Lots)
+
+	parentRob, err := robotStorage.FindByID(parent.RobotID)
+	assert.NoError(err)
+	assert.Equal(1, parentRob.Followers)
+
+	resp, code = edit(follower.RobotID, `{"buy_price":1}`)
+	resp.Body.Close()
+	assert.Equal(http.StatusConflict, code, "follower can't change mirrored parameters")
+
+	resp, code = request(follower.RobotID, "unfollow", ``)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, code)
+
+	resp, code = request(follower.RobotID, "unfollow", ``)
+	resp.Body.Close()
+	assert.Equal(http.StatusConflict, code)
+
+	resp, code = edit(follower.RobotID, `{"buy_price":1}`)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, code, "unfollowed robot is independent")
+
+	parentRob, err = robotStorage.FindByID(parent.RobotID)
+	assert.NoError(err)
+	assert.Equal(0, parentRob.Followers)
+}
+
+func TestLeaderboard(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, &fakeInstruments{})
+
+	setupSignUp(h, t)
+	token := setupUser(h, t, "second@example.com")
+	auth := fmt.Sprintf("Bearer %s", token.Token)
+
+	trade := func(rob *robot.Robot, buy, sell float64) {
+		now := time.Now()
+
+		rob.Buy(buy)
+		assert.NoError(robotStorage.Trade(rob, robot.Deal{Side: robot.SideBuy, Price: buy, CreatedAt: now}))
+		rob.Sell(sell)
+		assert.NoError(robotStorage.Trade(rob, robot.Deal{Side: robot.SideSell, Price: sell, CreatedAt: now}))
+	}
+
+	low := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, IsActive: true}
+	high := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, IsActive: true}
+	private := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, IsActive: true, Visibility: robot.VisibilityPrivate}
+
+	for _, rob := range []*robot.Robot{low, high, private} {
+		assert.NoError(robotStorage.Create(rob))
+	}
+
+	trade(low, 10, 15)
+	trade(high, 10, 30)
+	trade(private, 10, 100)
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/robots/leaderboard", h.Leaderboard)
+	r.Route("/api/v1/robot/{id}", func(router chi.Router) {
+		router.Use(h.getParamID, h.authentication)
+		router.Get("/stats", h.RobotStats)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	testCases := []struct {
+		Name string
+		Path string
+		Code int
+	}{
+		{Name: "Unknown metric", Path: "/api/v1/robots/leaderboard?metric=luck", Code: http.StatusBadRequest},
+		{Name: "Unknown period", Path: "/api/v1/robots/leaderboard?period=decade", Code: http.StatusBadRequest},
+		{Name: "Invalid limit", Path: "/api/v1/robots/leaderboard?limit=0", Code: http.StatusBadRequest},
+		{Name: "Private robot stats", Path: fmt.Sprintf("/api/v1/robot/%d/stats", private.RobotID), Code: http.StatusNotFound},
+		{Name: "Public robot stats", Path: fmt.Sprintf("/api/v1/robot/%d/stats?period=week", high.RobotID), Code: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			resp, code := testRequestWithAuth(t, ts, http.MethodGet, tc.Path, auth, nil)
+			resp.Body.Close()
+			assert.Equal(tc.Code, code)
+		})
+	}
+
+	resp, code := testRequestWithAuth(t, ts, http.MethodGet, "/api/v1/robots/leaderboard?metric=yield&period=day", auth, nil)
+	assert.Equal(http.StatusOK, code)
+
+	var stats []robot.Stats
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&stats))
+	resp.Body.Close()
+
+	if assert.Len(stats, 2, "private robot must be excluded") {
+		assert.Equal(high.RobotID, stats[0].RobotID)
+		assert.InDelta(20.0, stats[0].Yield, 1e-9)
+		assert.Equal(low.RobotID, stats[1].RobotID)
+	}
+}
+
+func TestRobotListings(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, &fakeInstruments{})
+
+	setupSignUp(h, t)
+	token := setupUser(h, t, "second@example.com")
+	auth := fmt.Sprintf("Bearer %s", token.Token)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(robotStorage.Create(&robot.Robot{OwnerUserID: i % 2, Ticker: "AAPL", BuyPrice: float64(i)}))
+	}
+
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(router chi.Router) {
+		router.With(h.authentication).Get("/robots", h.CatalogRobots)
+		router.With(h.getParamID, h.authentication, h.authorization).Get("/users/{id}/robots", h.UserRobots)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	get := func(path string) ([]robot.Robot, string, int) {
+		resp, code := testRequestWithAuth(t, ts, http.MethodGet, path, auth, nil)
+		defer resp.Body.Close()
+
+		var robots []robot.Robot
+		if code == http.StatusOK {
+			assert.NoError(json.NewDecoder(resp.Body).Decode(&robots))
+		}
+
+		return robots, resp.Header.Get("Link"), code
+	}
+
+	_, _, code := get("/api/v1/robots?sort=password")
+	assert.Equal(http.StatusBadRequest, code)
+
+	_, _, code = get("/api/v1/robots?is_active=sometimes")
+	assert.Equal(http.StatusBadRequest, code)
+
+	robots, link, code := get("/api/v1/robots?sort=buy_price&order=desc&limit=2")
+	assert.Equal(http.StatusOK, code)
+
+	if assert.Len(robots, 2) {
+		assert.Equal(3, robots[0].RobotID)
+		assert.Equal(2, robots[1].RobotID)
+	}
+
+	next := strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+	assert.Contains(next, "cursor=")
+
+	robots, link, code = get(next)
+	assert.Equal(http.StatusOK, code)
+	assert.Empty(link, "last page has no next link")
+
+	if assert.Len(robots, 1) {
+		assert.Equal(1, robots[0].RobotID)
+	}
+
+	robots, _, code = get("/api/v1/users/1/robots?user=0")
+	assert.Equal(http.StatusOK, code)
+
+	if assert.Len(robots, 1, "user listing ignores the user filter") {
+		assert.Equal(2, robots[0].RobotID)
+	}
+}
+
+func TestRobotVisibility(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		NewWebsocket(robotStorage), nopPublisher{}, &fakeInstruments{})
+
+	owner := fmt.Sprintf("Bearer %s", setupUser(h, t, "owner@example.com").Token)
+	viewerToken := setupUser(h, t, "viewer@example.com")
+	viewer := fmt.Sprintf("Bearer %s", viewerToken.Token)
+
+	public := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL"}
+	private := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL", Visibility: robot.VisibilityPrivate}
+	users := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL", Visibility: robot.VisibilityUsers}
+	link := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL", Visibility: robot.VisibilityLink}
+
+	for _, rob := range []*robot.Robot{public, private, users, link} {
+		assert.NoError(robotStorage.Create(rob))
+	}
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	robotPath := func(rob *robot.Robot) string {
+		return fmt.Sprintf("/api/v1/robot/%d", rob.RobotID)
+	}
+
+	request := func(method, path, auth string) int {
+		resp, code := testRequestWithAuth(t, ts, method, path, auth, nil)
+		resp.Body.Close()
+
+		return code
+	}
+
+	testCases := []struct {
+		Name   string
+		Method string
+		Path   string
+		Auth   string
+		Code   int
+	}{
+		{Name: "Public details", Method: http.MethodGet, Path: robotPath(public), Auth: viewer, Code: http.StatusOK},
+		{Name: "Private details", Method: http.MethodGet, Path: robotPath(private), Auth: viewer, Code: http.StatusNotFound},
+		{Name: "Owner sees private", Method: http.MethodGet, Path: robotPath(private), Auth: owner, Code: http.StatusOK},
+		{Name: "Private favourite", Method: http.MethodPut, Path: robotPath(private) + "/favourite", Auth: viewer, Code: http.StatusNotFound}, //nolint:misspell
+		{Name: "Private stats", Method: http.MethodGet, Path: robotPath(private) + "/stats", Auth: viewer, Code: http.StatusNotFound},
+		{Name: "Link without token", Method: http.MethodGet, Path: robotPath(link), Auth: viewer, Code: http.StatusNotFound},
+		{Name: "Link with token", Method: http.MethodGet, Path: robotPath(link) + "?share=" + link.ShareToken, Auth: viewer, Code: http.StatusOK},
+		{Name: "Not shared yet", Method: http.MethodGet, Path: robotPath(users), Auth: viewer, Code: http.StatusNotFound},
+		{Name: "Share by stranger", Method: http.MethodPut, Path: robotPath(users) + "/shares/1", Auth: viewer, Code: http.StatusForbidden},
+		{Name: "Share with unknown user", Method: http.MethodPut, Path: robotPath(users) + "/shares/42", Auth: owner, Code: http.StatusNotFound},
+		{Name: "Share", Method: http.MethodPut, Path: robotPath(users) + "/shares/1", Auth: owner, Code: http.StatusOK},
+		{Name: "Shared details", Method: http.MethodGet, Path: robotPath(users), Auth: viewer, Code: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(tc.Code, request(tc.Method, tc.Path, tc.Auth))
+		})
+	}
+
+	resp, code := testRequestWithAuth(t, ts, http.MethodGet, "/api/v1/robots", viewer, nil)
+	assert.Equal(http.StatusOK, code)
+
+	var catalog []robot.Robot
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&catalog))
+	resp.Body.Close()
+
+	ids := make([]int, 0, len(catalog))
+	for _, rob := range catalog {
+		ids = append(ids, rob.RobotID)
+	}
+
+	assert.Equal([]int{public.RobotID, users.RobotID}, ids, "catalog lists only robots visible without a link")
+
+	resp, code = testRequestWithAuth(t, ts, http.MethodGet, robotPath(link)+"/shares", owner, nil)
+	assert.Equal(http.StatusOK, code)
+
+	var shares sharesView
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&shares))
+	resp.Body.Close()
+	assert.Equal(robotPath(link)+"?share="+link.ShareToken, shares.ShareLink)
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/v1/wsrobotdetail?token=" + viewerToken.Token
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var msg map[string]interface{}
+
+	assert.NoError(conn.WriteMessage(websocket.TextMessage, []byte(strconv.Itoa(private.RobotID))))
+	assert.NoError(conn.ReadJSON(&msg))
+	assert.Equal("robot not found", msg["error"], "websocket must not stream private robots")
+
+	msg = nil
+	assert.NoError(conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("%d %s", link.RobotID, link.ShareToken))))
+	assert.NoError(conn.ReadJSON(&msg))
+	assert.Equal(float64(link.RobotID), msg["robot_id"])
+
+	_, _, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/api/v1/wsrobotdetail", nil)
+	assert.Error(err, "websocket requires authentication")
+}
+
+func TestRobotTrash(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, instruments)
+
+	setupSignUp(h, t)
+	owner := fmt.Sprintf("Bearer %s", setupUser(h, t, "owner@example.com").Token)
+	stranger := fmt.Sprintf("Bearer %s", setupUser(h, t, "stranger@example.com").Token)
+
+	activated := robot.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}
+	holding := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", IsActive: true, ActivatedAt: activated}
+	unknown := &robot.Robot{OwnerUserID: 1, Ticker: "NOPE", IsActive: true, ActivatedAt: activated}
+
+	for _, rob := range []*robot.Robot{holding, unknown} {
+		assert.NoError(robotStorage.Create(rob))
+		rob.Buy(100)
+		assert.NoError(robotStorage.Trade(rob, robot.Deal{Side: robot.SideBuy, Price: 100, CreatedAt: time.Now()}))
+	}
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	request := func(method, path, auth string) int {
+		resp, code := testRequestWithAuth(t, ts, method, path, auth, nil)
+		resp.Body.Close()
+
+		return code
+	}
+
+	remove := func(robotID int, auth string) int {
+		resp, code := testRequestIfMatch(t, ts, http.MethodDelete, fmt.Sprintf("/api/v1/robot/%d", robotID), auth,
+			currentETag(t, robotStorage, robotID), nil)
+		resp.Body.Close()
+
+		return code
+	}
+
+	robotPath := fmt.Sprintf("/api/v1/robot/%d", holding.RobotID)
+
+	assert.Equal(http.StatusForbidden, remove(holding.RobotID, stranger))
+	assert.Equal(http.StatusPreconditionRequired, request(http.MethodDelete, robotPath, owner))
+	assert.Equal(http.StatusServiceUnavailable, remove(unknown.RobotID, owner), "position can't be closed without a quote")
+	assert.Equal(http.StatusOK, remove(holding.RobotID, owner))
+
+	deleted, err := robotStorage.FindDeletedByID(holding.RobotID)
+	assert.NoError(err)
+	assert.True(deleted.IsBuying, "open position must be closed")
+	assert.Equal(1, deleted.DealsCount)
+
+	deals, err := robotStorage.FindDealsByRobotIDs([]int{holding.RobotID})
+	assert.NoError(err)
+
+	if assert.Len(deals, 2) {
+		assert.Equal(robot.SideSell, deals[1].Side)
+		assert.Equal(99.0, deals[1].Price)
+	}
+
+	resp, code := testRequestWithAuth(t, ts, http.MethodGet, "/api/v1/users/1/robots/deleted", owner, nil)
+	assert.Equal(http.StatusOK, code)
+
+	var trash []robot.Robot
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&trash))
+	resp.Body.Close()
+
+	if assert.Len(trash, 1) {
+		assert.Equal(holding.RobotID, trash[0].RobotID)
+	}
+
+	assert.Equal(http.StatusForbidden, request(http.MethodPut, robotPath+"/restore", stranger))
+	assert.Equal(http.StatusOK, request(http.MethodPut, robotPath+"/restore", owner))
+	assert.Equal(http.StatusNotFound, request(http.MethodPut, robotPath+"/restore", owner), "robot isn't in trash anymore")
+
+	restored, err := robotStorage.FindByID(holding.RobotID)
+	assert.NoError(err)
+	assert.Equal(robot.StatusStopped, restored.Status)
+
+	assert.Equal(http.StatusNotFound, request(http.MethodDelete, robotPath+"/purge", owner), "only deleted robots can be purged")
+	assert.Equal(http.StatusOK, remove(holding.RobotID, owner))
+	assert.Equal(http.StatusOK, request(http.MethodDelete, robotPath+"/purge", owner))
+
+	_, err = robotStorage.FindDeletedByID(holding.RobotID)
+	assert.Equal(robot.ErrNotFound, err)
+}
+
+// traderClosingStorage закрывает позицию робота перед остановкой, как торговый процесс, который успел
+// продать между чтением робота и его остановкой.
+type traderClosingStorage struct {
+	*robot.StorageInMemory
+}
+
+func (s traderClosingStorage) Transition(robotID int, action robot.Action, source string, revision int) error {
+	if action == robot.ActionStop {
+		rob, err := s.FindByID(robotID)
+		if err != nil {
+			return err
+		}
+
+		if !rob.IsBuying {
+			rob.Sell(101)
+
+			if err = s.Trade(rob, robot.Deal{Side: robot.SideSell, Price: 101, CreatedAt: time.Now()}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.StorageInMemory.Transition(robotID, action, source, revision)
+}
+
+func TestDeleteRobotClosedByTrader(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := traderClosingStorage{robot.CreateStorageInMemory()}
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, instruments)
+
+	setupSignUp(h, t)
+	owner := fmt.Sprintf("Bearer %s", setupUser(h, t, "owner@example.com").Token)
+
+	activated := robot.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}
+	holding := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", IsActive: true, ActivatedAt: activated}
+	assert.NoError(robotStorage.Create(holding))
+	holding.Buy(100)
+	assert.NoError(robotStorage.Trade(holding, robot.Deal{Side: robot.SideBuy, Price: 100, CreatedAt: time.Now()}))
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	remove := func() int {
+		resp, code := testRequestIfMatch(t, ts, http.MethodDelete, fmt.Sprintf("/api/v1/robot/%d", holding.RobotID),
+			owner, currentETag(t, robotStorage, holding.RobotID), nil)
+		resp.Body.Close()
+
+		return code
+	}
+
+	assert.Equal(http.StatusPreconditionFailed, remove(), "the trader changed the robot after it was read")
+	assert.Equal(http.StatusOK, remove())
+
+	deals, err := robotStorage.FindDealsByRobotIDs([]int{holding.RobotID})
+	assert.NoError(err)
+
+	if assert.Len(deals, 2, "position closed by the trader must not be sold again") {
+		assert.Equal(101.0, deals[1].Price)
+	}
+}
+
+func TestBatchRobots(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, instruments)
+
+	setupSignUp(h, t)
+	auth := fmt.Sprintf("Bearer %s", setupUser(h, t, "owner@example.com").Token)
+
+	foreign := &robot.Robot{OwnerUserID: 0, Ticker: "AAPL"}
+	assert.NoError(robotStorage.Create(foreign))
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	post := func(path, body string) (batchResponse, int) {
+		resp, code := testRequestWithAuth(t, ts, http.MethodPost, path, auth, strings.NewReader(body))
+		defer resp.Body.Close()
+
+		var batch batchResponse
+		if code == http.StatusOK || code == http.StatusMultiStatus {
+			assert.NoError(json.NewDecoder(resp.Body).Decode(&batch))
+		}
+
+		return batch, code
+	}
+
+	_, code := post("/api/v1/robots/batch", `{"robots":[]}`)
+	assert.Equal(http.StatusBadRequest, code, "empty batch")
+
+	batch, code := post("/api/v1/robots/batch",
+		`{"atomic":true,"robots":[{"owner_user_id":1,"ticker":"AAPL"},{"owner_user_id":1,"ticker":"NOPE"}]}`)
+	assert.Equal(http.StatusMultiStatus, code)
+	assert.Equal([]int{http.StatusFailedDependency, http.StatusBadRequest}, batchStatuses(batch))
+
+	batch, code = post("/api/v1/robots/batch",
+		`{"robots":[{"owner_user_id":1,"ticker":"AAPL"},{"owner_user_id":1,"ticker":"AAPL"}]}`)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal([]int{http.StatusCreated, http.StatusCreated}, batchStatuses(batch))
+
+	first, second := batch.Results[0].RobotID, batch.Results[1].RobotID
+	ids := fmt.Sprintf("%d,%d,%d", first, second, foreign.RobotID)
+
+	batch, code = post("/api/v1/robots/batch/activate", `{"atomic":true,"robot_ids":[`+ids+`]}`)
+	assert.Equal(http.StatusMultiStatus, code)
+	assert.Equal([]int{http.StatusFailedDependency, http.StatusFailedDependency, http.StatusForbidden}, batchStatuses(batch))
+
+	batch, code = post("/api/v1/robots/batch/activate", `{"robot_ids":[`+ids+`]}`)
+	assert.Equal(http.StatusMultiStatus, code)
+	assert.Equal([]int{http.StatusOK, http.StatusOK, http.StatusForbidden}, batchStatuses(batch))
+	assert.Equal(2, batch.Succeeded)
+
+	batch, code = post("/api/v1/robots/batch/delete", fmt.Sprintf(`{"atomic":true,"robot_ids":[%d,%d]}`, first, second))
+	assert.Equal(http.StatusMultiStatus, code)
+	assert.Equal([]int{http.StatusConflict, http.StatusConflict}, batchStatuses(batch), "atomic batch doesn't stop robots")
+
+	batch, code = post("/api/v1/robots/batch/delete", fmt.Sprintf(`{"robot_ids":[%d,%d]}`, first, second))
+	assert.Equal(http.StatusOK, code)
+	assert.Equal([]int{http.StatusOK, http.StatusOK}, batchStatuses(batch))
+
+	deleted, err := robotStorage.FindDeleted(1)
+	assert.NoError(err)
+	assert.Len(deleted, 2)
+}
+
+func TestTemplatesAndClone(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true, "MSFT": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, instruments)
+
+	setupSignUp(h, t)
+	owner := fmt.Sprintf("Bearer %s", setupUser(h, t, "owner@example.com").Token)
+	stranger := fmt.Sprintf("Bearer %s", setupUser(h, t, "stranger@example.com").Token)
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	request := func(method, path, auth, body string, v interface{}) int {
+		resp, code := testRequestWithAuth(t, ts, method, path, auth, strings.NewReader(body))
+		defer resp.Body.Close()
+
+		if v != nil && code < http.StatusBadRequest {
+			assert.NoError(json.NewDecoder(resp.Body).Decode(v))
+		}
+
+		return code
+	}
+
+	assert.Equal(http.StatusBadRequest, request(http.MethodPost, "/api/v1/templates", owner, `{"buy_price":10}`, nil))
+
+	var tmpl robot.Template
+	code := request(http.MethodPost, "/api/v1/templates", owner,
+		`{"name":"breakout","buy_price":10,"sell_price":12,"lots":2,"visibility":"private"}`, &tmpl)
+	assert.Equal(http.StatusCreated, code)
+	assert.Equal(1, tmpl.OwnerUserID)
+
+	path := fmt.Sprintf("/api/v1/templates/%d", tmpl.ID)
+	assert.Equal(http.StatusForbidden, request(http.MethodGet, path, stranger, "", nil))
+
+	var batch batchResponse
+	code = request(http.MethodPost, path+"/robots", owner,
+		`{"robots":[{"ticker":"AAPL"},{"ticker":"MSFT","buy_price":11},{"ticker":"NOPE"},`+
+			`{"ticker":"AAPL","owner_user_id":2}]}`, &batch)
+	assert.Equal(http.StatusMultiStatus, code)
+	assert.Equal([]int{http.StatusCreated, http.StatusCreated, http.StatusBadRequest, http.StatusBadRequest},
+		batchStatuses(batch), "overrides can't change the owner")
+
+	msft, err := robotStorage.FindByID(batch.Results[1].RobotID)
+	assert.NoError(err)
+	assert.Equal(1, msft.OwnerUserID)
+	assert.Equal(11.0, msft.BuyPrice)
+	assert.Equal(12.0, msft.SellPrice)
+	assert.Equal(2, msft.Lots)
+	assert.Equal(tmpl.ID, msft.TemplateID)
+
+	clonePath := fmt.Sprintf("/api/v1/robot/%d/clone", msft.RobotID)
+	assert.Equal(http.StatusForbidden, request(http.MethodPost, clonePath, stranger, "", nil))
+
+	var clone robot.Robot
+	assert.Equal(http.StatusBadRequest,
+		request(http.MethodPost, clonePath, owner, `{"ticker":"AAPL","cloned_from_id":42}`, nil), "cloned_from_id is read-only")
+	assert.Equal(http.StatusCreated, request(http.MethodPost, clonePath, owner, `{"ticker":"AAPL"}`, &clone))
+	assert.Equal(msft.RobotID, clone.ClonedFromID)
+	assert.Zero(clone.TemplateID)
+	assert.Equal("AAPL", clone.Ticker)
+	assert.Equal(11.0, clone.BuyPrice)
+
+	assert.Equal(http.StatusCreated, request(http.MethodPost, clonePath, owner, "", &clone), "body is optional")
+	assert.Equal("MSFT", clone.Ticker)
+
+	var templates []robot.Template
+	assert.Equal(http.StatusOK, request(http.MethodGet, "/api/v1/templates", owner, "", &templates))
+	assert.Len(templates, 1)
+
+	assert.Equal(http.StatusOK, request(http.MethodDelete, path, owner, "", nil))
+	assert.Equal(http.StatusNotFound, request(http.MethodGet, path, owner, "", nil))
+}
+
+func batchStatuses(batch batchResponse) []int {
+	statuses := make([]int, 0, len(batch.Results))
+	for _, item := range batch.Results {
+		statuses = append(statuses, item.Status)
+	}
+
+	return statuses
+}
+
+// setupUser регистрирует пользователя с указанной почтой и возвращает его токен
+func setupUser(h *Handler, t *testing.T, email string) session.BearerToken {
+	assert := assert.New(t)
+	signUp := fmt.Sprintf(`{"first_name":"Petr","last_name":"Petrov","email":%q,"password":"1234"}`, email)
+	req := httptest.NewRequest(http.MethodPost, urlSignUp, bytes.NewBuffer([]byte(signUp)))
+	rec := httptest.NewRecorder()
+	http.HandlerFunc(h.SignUp).ServeHTTP(rec, req)
+	assert.Equal(http.StatusCreated, rec.Code)
+
+	signIn := fmt.Sprintf(`{"email":%q,"password":"1234"}`, email)
+	req = httptest.NewRequest(http.MethodPost, urlSignIn, bytes.NewBuffer([]byte(signIn)))
+	rec = httptest.NewRecorder()
+	http.HandlerFunc(h.SignIn).ServeHTTP(rec, req)
+	assert.Equal(http.StatusOK, rec.Code)
+
+	var token session.BearerToken
+	assert.NoError(json.Unmarshal(rec.Body.Bytes(), &token))
+
+	return token
+}
+
 // setupSignUp регистрирует пользователя
 func setupSignUp(h *Handler, t *testing.T) {
 	assert := assert.New(t)
@@ -197,6 +1125,37 @@ func setupSignIn(h *Handler, t *testing.T) []byte {
 	return rec.Body.Bytes()
 }
 
+// testRequestIfMatch отправляет запрос с заголовком If-Match, если ifMatch не пустой.
+func testRequestIfMatch(t *testing.T, ts *httptest.Server, method, path, auth, ifMatch string, body io.Reader) (*http.Response, int) {
+	req, err := http.NewRequest(method, ts.URL+path, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", auth)
+
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp, resp.StatusCode
+}
+
+// currentETag возвращает ETag робота robotID из хранилища.
+func currentETag(t *testing.T, robotStorage robot.Storage, robotID int) string {
+	rob, err := robotStorage.FindByID(robotID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return robotETag(rob)
+}
+
 // testRequestWithAuth отправляет запрос на тестовый сервер и возвращает ответ
 func testRequestWithAuth(t *testing.T, ts *httptest.Server, method, path string, header string, body io.Reader) (*http.Response, int) {
 	req, err := http.NewRequest(method, ts.URL+path, body)
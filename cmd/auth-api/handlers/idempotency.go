@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/idempotency"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	idempotencyPurgeInterval = time.Hour
+	// idempotencyLease срок резервирования ключа на время выполнения запроса. Если сервис упал, не сохранив
+	// ответ, повтор запроса занимает ключ после этого срока, а не получает 409 до конца срока хранения ответа.
+	idempotencyLease = time.Minute
+)
+
+var (
+	// errIdempotencyKeyInUse запрос с тем же ключом идемпотентности еще выполняется.
+	errIdempotencyKeyInUse = errors.New("a request with this idempotency key is in progress")
+	// errIdempotencyKeyReused ключ идемпотентности уже использован для другого запроса.
+	errIdempotencyKeyReused = errors.New("idempotency key is already used for a different request")
+)
+
+// replayedHeaders заголовки ответа, которые сохраняются вместе с телом и отправляются при повторе запроса.
+var replayedHeaders = []string{"Content-Type", "ETag", "Link", "Location", "X-Content-Type-Options"}
+
+// SetIdempotencyStorage задает хранилище ключей идемпотентности и срок, в течение которого повтор запроса
+// с тем же ключом получает сохраненный ответ. По умолчанию ключи хранятся в памяти сутки.
+func (h *Handler) SetIdempotencyStorage(storage idempotency.Storage, ttl time.Duration) {
+	h.idempotency = storage
+	h.idempotencyTTL = ttl
+}
+
+// PurgeIdempotencyKeys удаляет истекшие ключи идемпотентности, пока не отменен ctx.
+func (h *Handler) PurgeIdempotencyKeys(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := h.idempotency.DeleteExpired(time.Now())
+		if err != nil {
+			h.logger.Warnw("func idempotency.DeleteExpired return with error", "error", err)
+			continue
+		}
+
+		if n > 0 {
+			h.logger.Infow("expired idempotency keys are purged", "count", n)
+		}
+	}
+}
+
+// idempotent повторяет сохраненный ответ на запрос POST или PUT с заголовком Idempotency-Key. Первый запрос
+// с ключом выполняется, и его ответ сохраняется, а повтор с тем же методом, путем и телом получает
+// сохраненный ответ с заголовком Idempotent-Replayed. Ответы 5xx не сохраняются, такой запрос можно повторить.
+// Ключи пользователя не пересекаются с ключами других пользователей, поэтому middleware идет после authentication.
+// Анонимных клиентов не различить, поэтому запросы без сессии выполняются без ключа.
+// Ответ хранится открытым текстом, поэтому middleware не ставится на маршруты, которые отдают токены.
+func (h *Handler) idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		token, ok := r.Context().Value(tokenKey{}).(string)
+
+		if key == "" || !ok || (r.Method != http.MethodPost && r.Method != http.MethodPut) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			h.fail(w, r, "invalid idempotency key", validation.Field(validation.ErrInvalidInput, idempotencyKeyHeader,
+				fmt.Sprintf("must be at most %d characters", maxIdempotencyKeyLength)))
+
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			h.fail(w, r, "unable to read request body", err)
+			return
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		sessionToken, _ := session.DecodeToken(token)
+		now := time.Now()
+		rec := &idempotency.Record{
+			UserID:      sessionToken.UserID,
+			Key:         key,
+			Fingerprint: idempotency.Fingerprint(r.Method, r.URL.RequestURI(), body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(idempotencyLease),
+		}
+
+		existing, err := h.idempotency.Reserve(rec)
+		if err != nil {
+			h.fail(w, r, "func idempotency.Reserve return with error", err)
+			return
+		}
+
+		if existing != nil {
+			h.replay(w, r, rec, existing)
+			return
+		}
+
+		rw := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+
+		if rw.status == 0 {
+			rw.status = http.StatusOK
+		}
+
+		if rw.status >= http.StatusInternalServerError {
+			if err = h.idempotency.Release(rec.UserID, rec.Key); err != nil {
+				h.logger.Warnw("func idempotency.Release return with error", "error", err, "key", key)
+			}
+
+			return
+		}
+
+		rec.Status = rw.status
+		rec.Body = rw.body.Bytes()
+		rec.ExpiresAt = time.Now().Add(h.idempotencyTTL)
+		rec.Header = make(map[string]string)
+
+		for _, name := range replayedHeaders {
+			if v := w.Header().Get(name); v != "" {
+				rec.Header[name] = v
+			}
+		}
+
+		if err = h.idempotency.Complete(rec); err != nil {
+			h.logger.Warnw("func idempotency.Complete return with error", "error", err, "key", key)
+		}
+	})
+}
+
+// replay отправляет сохраненный ответ existing на повтор запроса rec.
+func (h *Handler) replay(w http.ResponseWriter, r *http.Request, rec, existing *idempotency.Record) {
+	switch {
+	case existing.Fingerprint != rec.Fingerprint:
+		h.fail(w, r, "idempotency key is reused", errIdempotencyKeyReused)
+	case !existing.Done():
+		h.fail(w, r, "idempotency key is in use", errIdempotencyKeyInUse)
+	default:
+		for name, v := range existing.Header {
+			w.Header().Set(name, v)
+		}
+
+		w.Header().Set(idempotentReplayedHeader, "true")
+		w.WriteHeader(existing.Status)
+
+		_, _ = w.Write(existing.Body)
+	}
+}
+
+// responseRecorder передает ответ клиенту и запоминает его статус и тело.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *responseRecorder) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseRecorder) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+
+	rw.body.Write(b)
+
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/idempotency"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	type testCase struct {
+		Name           string
+		Token          string
+		Key            string
+		Body           string
+		ExpectedCode   int
+		ExpectedRobots int
+		ExpectReplayed bool
+	}
+
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true, "SBER": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, instruments)
+
+	setupSignUp(h, t)
+	first := setupUser(h, t, "second@example.com")
+	second := setupUser(h, t, "third@example.com")
+
+	now := time.Now()
+	_, err := h.idempotency.Reserve(&idempotency.Record{UserID: 1, Key: "running", CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour), Fingerprint: idempotency.Fingerprint(http.MethodPost, urlCreateRobot, nil)})
+	assert.NoError(err)
+
+	aapl := `{"owner_user_id":1,"ticker":"AAPL"}`
+	sber := `{"owner_user_id":1,"ticker":"SBER"}`
+
+	_, err = h.idempotency.Reserve(&idempotency.Record{UserID: 1, Key: "abandoned",
+		CreatedAt: now.Add(-2 * idempotencyLease), ExpiresAt: now.Add(-idempotencyLease),
+		Fingerprint: idempotency.Fingerprint(http.MethodPost, urlCreateRobot, []byte(aapl))})
+	assert.NoError(err)
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	testCases := []testCase{
+		{Name: "First request", Token: first.Token, Key: "create-1", Body: aapl, ExpectedCode: http.StatusCreated,
+			ExpectedRobots: 1},
+		{Name: "Retry", Token: first.Token, Key: "create-1", Body: aapl, ExpectedCode: http.StatusCreated,
+			ExpectedRobots: 1, ExpectReplayed: true},
+		{Name: "Other request with the same key", Token: first.Token, Key: "create-1", Body: sber,
+			ExpectedCode: http.StatusUnprocessableEntity, ExpectedRobots: 1},
+		{Name: "New key", Token: first.Token, Key: "create-2", Body: aapl, ExpectedCode: http.StatusCreated,
+			ExpectedRobots: 2},
+		{Name: "Without key", Token: first.Token, Body: aapl, ExpectedCode: http.StatusCreated, ExpectedRobots: 3},
+		{Name: "Keys are per user", Token: second.Token, Key: "create-1", Body: `{"owner_user_id":2,"ticker":"AAPL"}`,
+			ExpectedCode: http.StatusCreated, ExpectedRobots: 4},
+		{Name: "Rejected request", Token: first.Token, Key: "create-3", Body: `{"owner_user_id":2,"ticker":"AAPL"}`,
+			ExpectedCode: http.StatusForbidden, ExpectedRobots: 4},
+		{Name: "Retry of rejected request", Token: first.Token, Key: "create-3", Body: `{"owner_user_id":2,"ticker":"AAPL"}`,
+			ExpectedCode: http.StatusForbidden, ExpectedRobots: 4, ExpectReplayed: true},
+		{Name: "Request in progress", Token: first.Token, Key: "running", ExpectedCode: http.StatusConflict,
+			ExpectedRobots: 4},
+		{Name: "Abandoned request after lease", Token: first.Token, Key: "abandoned", Body: aapl,
+			ExpectedCode: http.StatusCreated, ExpectedRobots: 5},
+		{Name: "Retry of taken over request", Token: first.Token, Key: "abandoned", Body: aapl,
+			ExpectedCode: http.StatusCreated, ExpectedRobots: 5, ExpectReplayed: true},
+		{Name: "Too long key", Token: first.Token, Key: strings.Repeat("k", maxIdempotencyKeyLength+1), Body: aapl,
+			ExpectedCode: http.StatusBadRequest, ExpectedRobots: 5},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+urlCreateRobot, bytes.NewBufferString(tc.Body))
+			assert.NoError(err)
+
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tc.Token))
+
+			if tc.Key != "" {
+				req.Header.Set(idempotencyKeyHeader, tc.Key)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(err)
+			defer resp.Body.Close()
+
+			assert.Equal(tc.ExpectedCode, resp.StatusCode)
+			assert.Equal(tc.ExpectReplayed, resp.Header.Get(idempotentReplayedHeader) == "true")
+
+			page, err := robotStorage.Find(&robot.Query{Sort: "robot_id", Limit: robot.DefaultLimit})
+			assert.NoError(err)
+			assert.Len(page.Robots, tc.ExpectedRobots)
+		})
+	}
+}
+
+func TestIdempotencyKeyReplaysETag(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, &fakeInstruments{tickers: map[string]bool{"AAPL": true}})
+
+	setupSignUp(h, t)
+	owner := "Bearer " + setupUser(h, t, "owner@example.com").Token
+
+	rob := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(robotStorage.Create(rob))
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	etag := currentETag(t, robotStorage, rob.RobotID)
+
+	edit := func() *http.Response {
+		req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v1/robot/%d", ts.URL, rob.RobotID),
+			strings.NewReader(`{"sell_price":25}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", owner)
+		req.Header.Set("If-Match", etag)
+		req.Header.Set(idempotencyKeyHeader, "edit-1")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		return resp
+	}
+
+	first := edit()
+	assert.Equal(http.StatusOK, first.StatusCode)
+	assert.Equal(currentETag(t, robotStorage, rob.RobotID), first.Header.Get("ETag"))
+
+	retry := edit()
+	assert.Equal(http.StatusOK, retry.StatusCode)
+	assert.Equal("true", retry.Header.Get(idempotentReplayedHeader))
+	assert.Equal(first.Header.Get("ETag"), retry.Header.Get("ETag"), "replayed response keeps the ETag")
+}
+
+func TestIdempotencyKeyServerError(t *testing.T) {
+	assert := assert.New(t)
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), nil,
+		nil, nil, nil)
+
+	calls := 0
+	handler := h.idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			sendError(w, "error on server", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	setupSignUp(h, t)
+	token := setupUser(h, t, "owner@example.com").Token
+
+	for _, expected := range []int{http.StatusInternalServerError, http.StatusCreated, http.StatusCreated} {
+		req := httptest.NewRequest(http.MethodPost, urlCreateRobot, bytes.NewBufferString(`{"ticker":"AAPL"}`))
+		req = req.WithContext(context.WithValue(req.Context(), tokenKey{}, token))
+		req.Header.Set(idempotencyKeyHeader, "create")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		body, err := ioutil.ReadAll(rr.Body)
+		assert.NoError(err)
+		assert.Equal(expected, rr.Code, string(body))
+	}
+
+	assert.Equal(2, calls, "server errors are not stored and the last request is replayed")
+}
+
+func TestIdempotencyKeyPublicRoutes(t *testing.T) {
+	assert := assert.New(t)
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), nil,
+		nil, nil, nil)
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	post := func(path, body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+path, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set(idempotencyKeyHeader, "same-key")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		return resp
+	}
+
+	resp := post(urlSignUp, correctSignUp)
+	assert.Equal(http.StatusCreated, resp.StatusCode)
+
+	resp = post(urlSignUp, `{"first_name":"Petr","last_name":"Petrov","email":"other@example.com","password":"1234"}`)
+	assert.Equal(http.StatusCreated, resp.StatusCode, "anonymous clients with the same key don't collide")
+	assert.Empty(resp.Header.Get(idempotentReplayedHeader))
+
+	resp = post(urlSignUp, correctSignUp)
+	assert.Equal(http.StatusConflict, resp.StatusCode, "signup isn't replayed")
+
+	resp = post(urlSignIn, correctSignIn)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Empty(resp.Header.Get(idempotentReplayedHeader))
+
+	now := time.Now()
+	existing, err := h.idempotency.Reserve(&idempotency.Record{Key: "same-key", CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour)})
+	assert.NoError(err)
+	assert.Nil(existing, "anonymous responses must not be stored")
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/gorilla/websocket"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
 	"gitlab.com/hitchpock/tfs-course-work/internal/session"
 )
 
@@ -23,18 +26,7 @@ func (h *Handler) getParamID(next http.Handler) http.Handler {
 		ID, err := strconv.Atoi(paramID)
 
 		if err != nil || ID <= 0 {
-			responseJSON := []byte(errorJSON("invalid id"))
-
-			h.logger.Warnf("invalid id: %s", paramID)
-			w.Header().Add("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-
-			if _, e := w.Write(responseJSON); e != nil {
-				h.logger.Warnf("unable to write responseJSON: %s", err)
-				sendError(w, "error on server", http.StatusInternalServerError)
-				return
-			}
-
+			h.fail(w, r, "invalid id", fmt.Errorf("%w: %q is not a positive integer", robot.ErrInvalidID, paramID))
 			return
 		}
 
@@ -43,63 +35,78 @@ func (h *Handler) getParamID(next http.Handler) http.Handler {
 	})
 }
 
-// authentication проверяет валиден ли токен аунтификации из заголовков.
-func (h *Handler) authentication(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		reqID := middleware.GetReqID(r.Context())
-		remoteAddr := r.RemoteAddr
-		value := r.Header.Get("Authorization")
+// authError отказ в аутентификации: сообщение клиенту, статус ответа HTTP и причина для лога.
+type authError struct {
+	msg    string
+	status int
+	cause  interface{}
+}
 
-		auth := strings.Split(value, " ")
-		if len(auth) < 2 { //nolint:gomnd
-			h.logger.Warnw("scheme not found", "error", value, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "scheme not found", http.StatusBadRequest)
-			return
-		}
+func (e *authError) Error() string {
+	return e.msg
+}
 
-		scheme, token := auth[0], auth[1]
+// authenticate проверяет значение заголовка Authorization и возвращает сессию из токена, SessionID сессии — сам токен.
+// Проверка общая для REST и gRPC API, при отказе возвращается *authError.
+func (h *Handler) authenticate(value string) (*session.Session, error) {
+	auth := strings.Split(value, " ")
+	if len(auth) < 2 { //nolint:gomnd
+		return nil, &authError{msg: "scheme not found", status: http.StatusBadRequest, cause: value}
+	}
 
-		if token == "" {
-			h.logger.Warnw("token not found", "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "token not found", http.StatusUnauthorized)
-			return
-		}
+	scheme, token := auth[0], auth[1]
 
-		if scheme != "Bearer" {
-			h.logger.Warnw("the authentication scheme is not supported", "error", scheme, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "the authentication scheme is not supported", http.StatusBadRequest)
-			return
-		}
+	if token == "" {
+		return nil, &authError{msg: "token not found", status: http.StatusUnauthorized}
+	}
 
-		sessionToken, err := session.DecodeToken(token)
-		if err != nil {
-			h.logger.Warnw("invalid token", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "invalid token", http.StatusBadRequest)
-			return
-		}
+	if scheme != "Bearer" {
+		return nil, &authError{msg: "the authentication scheme is not supported", status: http.StatusBadRequest, cause: scheme}
+	}
 
-		sessionToken.SessionID = token
+	sessionToken, err := session.DecodeToken(token)
+	if err != nil {
+		return nil, &authError{msg: "invalid token", status: http.StatusBadRequest, cause: err}
+	}
 
-		sessionStorage, err := h.sessionStorage.FindByUserID(sessionToken.UserID)
-		if err != nil {
-			h.logger.Warnw("session not found", "error", err, "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "session not found", http.StatusNotFound)
-			return
-		}
+	sessionToken.SessionID = token
 
-		if !sessionStorage.Equal(sessionToken) {
-			h.logger.Warnw("invalid token", "error", "session not equal", "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "invalid token", http.StatusUnauthorized)
-			return
+	sessionStorage, err := h.sessionStorage.FindByUserID(sessionToken.UserID)
+	if err != nil {
+		return nil, &authError{msg: "session not found", status: http.StatusNotFound, cause: err}
+	}
+
+	if !sessionStorage.Equal(sessionToken) {
+		return nil, &authError{msg: "invalid token", status: http.StatusUnauthorized, cause: "session not equal"}
+	}
+
+	if !(sessionToken.CreatedAt.Before(time.Now()) && sessionToken.ValidUntil.After(time.Now())) {
+		return nil, &authError{msg: "session time is over", status: http.StatusUnauthorized}
+	}
+
+	return sessionToken, nil
+}
+
+// authentication проверяет валиден ли токен аунтификации из заголовков.
+// Браузер не передает заголовки при открытии websocket, поэтому для него токен читается из query-параметра token.
+func (h *Handler) authentication(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := r.Header.Get("Authorization")
+
+		if value == "" && websocket.IsWebSocketUpgrade(r) {
+			value = "Bearer " + r.URL.Query().Get("token")
 		}
 
-		if !(sessionToken.CreatedAt.Before(time.Now()) && sessionToken.ValidUntil.After(time.Now())) {
-			h.logger.Warnw("session time is over", "trackingID", reqID, "RealIP", remoteAddr)
-			sendError(w, "session time is over", http.StatusUnauthorized)
+		sessionToken, err := h.authenticate(value)
+		if err != nil {
+			e := err.(*authError)
+			h.logger.Warnw(e.msg, "error", e.cause, "trackingID", middleware.GetReqID(r.Context()), "RealIP", r.RemoteAddr)
+			sendError(w, e.msg, e.status)
+
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), tokenKey{}, token)
+		ctx := context.WithValue(r.Context(), tokenKey{}, sessionToken.SessionID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,7 +30,7 @@ func TestGetValidSession(t *testing.T) {
 	}
 
 	assert, logger, sessionStorage, userStorage := prepare(t)
-	h := NewHandler(logger, sessionStorage, userStorage, nil, nil)
+	h := NewHandler(logger, sessionStorage, userStorage, nil, nil, nil, nil)
 	ctx := context.WithValue(context.Background(), idKey{}, 1)
 
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -55,13 +56,13 @@ func TestGetValidSession(t *testing.T) {
 	validToken := fmt.Sprintf("Bearer %s", token)
 
 	testCases := []testCase{
-		{Name: "Empty header", Header: "", ExpectedCode: http.StatusBadRequest, ExpectedMessage: errorJSON("scheme not found")},
-		{Name: "Empty token v1", Header: "Bearer", ExpectedCode: http.StatusBadRequest, ExpectedMessage: errorJSON("scheme not found")},
-		{Name: "Empty token v2", Header: "Bearer ", ExpectedCode: http.StatusUnauthorized, ExpectedMessage: errorJSON("token not found")},
-		{Name: "Not supported scheme", Header: "Base 1234", ExpectedCode: http.StatusBadRequest, ExpectedMessage: errorJSON("the authentication scheme is not supported")},
-		{Name: "Invalid token", Header: "Bearer 11111", ExpectedCode: http.StatusBadRequest, ExpectedMessage: errorJSON("invalid token")},
-		{Name: "Token from other storage", Header: fakeTokenOne, ExpectedCode: http.StatusNotFound, ExpectedMessage: errorJSON("session not found")},
-		{Name: "Token with fake time", Header: fakeTokenTwo, ExpectedCode: http.StatusUnauthorized, ExpectedMessage: errorJSON("invalid token")},
+		{Name: "Empty header", Header: "", ExpectedCode: http.StatusBadRequest, ExpectedMessage: "scheme not found"},
+		{Name: "Empty token v1", Header: "Bearer", ExpectedCode: http.StatusBadRequest, ExpectedMessage: "scheme not found"},
+		{Name: "Empty token v2", Header: "Bearer ", ExpectedCode: http.StatusUnauthorized, ExpectedMessage: "token not found"},
+		{Name: "Not supported scheme", Header: "Base 1234", ExpectedCode: http.StatusBadRequest, ExpectedMessage: "the authentication scheme is not supported"},
+		{Name: "Invalid token", Header: "Bearer 11111", ExpectedCode: http.StatusBadRequest, ExpectedMessage: "invalid token"},
+		{Name: "Token from other storage", Header: fakeTokenOne, ExpectedCode: http.StatusNotFound, ExpectedMessage: "session not found"},
+		{Name: "Token with fake time", Header: fakeTokenTwo, ExpectedCode: http.StatusUnauthorized, ExpectedMessage: "invalid token"},
 		{Name: "Valid token", Header: validToken, ExpectedCode: http.StatusOK, ExpectedMessage: "OK"},
 	}
 
@@ -77,7 +78,17 @@ func TestGetValidSession(t *testing.T) {
 			if recoder.Code != tc.ExpectedCode {
 				assert.Equal(tc.ExpectedCode, recoder.Code, "Wrong http code, response: %s, request: %q", respBody, tc.Header)
 			}
-			assert.Equal(tc.ExpectedMessage+"\n", respBody)
+
+			if recoder.Code == http.StatusOK {
+				assert.Equal(tc.ExpectedMessage+"\n", respBody)
+				return
+			}
+
+			var resp apiError
+			assert.NoError(json.Unmarshal(recoder.Body.Bytes(), &resp))
+			assert.Equal(problemJSON, recoder.Header().Get("Content-Type"))
+			assert.Equal(tc.ExpectedCode, resp.Status)
+			assert.Equal(tc.ExpectedMessage, resp.Detail)
 		})
 	}
 }
@@ -90,7 +101,7 @@ func TestGetParmID(t *testing.T) {
 	}
 
 	assert, logger, sessionStorage, userStorage := prepare(t)
-	h := NewHandler(logger, sessionStorage, userStorage, nil, nil)
+	h := NewHandler(logger, sessionStorage, userStorage, nil, nil, nil, nil)
 
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
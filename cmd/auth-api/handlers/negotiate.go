@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Форматы ответа, которые клиент выбирает заголовком Accept.
+const (
+	applicationJSON   = "application/json"
+	textCSV           = "text/csv"
+	applicationNDJSON = "application/x-ndjson"
+)
+
+// errNotAcceptable ни один из форматов ответа не подходит под заголовок Accept.
+var errNotAcceptable = errors.New("none of the response formats is acceptable")
+
+// mediaRange диапазон типов из заголовка Accept, например text/* с весом q.
+type mediaRange struct {
+	typ     string
+	subtype string
+	q       float64
+}
+
+// negotiate выбирает формат ответа из offers по заголовку Accept с учетом весов q и добавляет заголовок
+// Vary: Accept. Без заголовка выбирается первый формат, при равных весах — тот, что раньше в offers.
+// Если ни один формат не подходит, возвращается errNotAcceptable.
+func negotiate(w http.ResponseWriter, r *http.Request, offers ...string) (string, error) {
+	w.Header().Add("Vary", "Accept")
+
+	value := r.Header.Get("Accept")
+	if strings.TrimSpace(value) == "" {
+		return offers[0], nil
+	}
+
+	ranges := parseAccept(value)
+	best, bestQ := "", 0.0
+
+	for _, offer := range offers {
+		if q := acceptQuality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("%w: Accept %q, available %s", errNotAcceptable, value, strings.Join(offers, ", "))
+	}
+
+	return best, nil
+}
+
+// parseAccept читает диапазоны типов из заголовка Accept. Параметры, кроме q, не учитываются,
+// диапазоны с неверным весом пропускаются.
+func parseAccept(value string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, item := range strings.Split(value, ",") {
+		params := strings.Split(item, ";")
+
+		parts := strings.SplitN(strings.ToLower(strings.TrimSpace(params[0])), "/", 2) //nolint:gomnd
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {                       //nolint:gomnd
+			continue
+		}
+
+		mr := mediaRange{typ: parts[0], subtype: parts[1], q: 1}
+		valid := true
+
+		for _, p := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(p), "=", 2) //nolint:gomnd
+			if len(kv) != 2 || strings.ToLower(kv[0]) != "q" { //nolint:gomnd
+				continue
+			}
+
+			q, err := strconv.ParseFloat(kv[1], 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+
+			mr.q = q
+		}
+
+		if valid {
+			ranges = append(ranges, mr)
+		}
+	}
+
+	return ranges
+}
+
+// acceptQuality возвращает вес формата offer: вес самого точного подходящего диапазона, 0 — формат не подходит.
+func acceptQuality(ranges []mediaRange, offer string) float64 {
+	parts := strings.SplitN(offer, "/", 2) //nolint:gomnd
+	q, specificity := 0.0, -1
+
+	for _, mr := range ranges {
+		s := -1
+
+		switch {
+		case mr.typ == parts[0] && mr.subtype == parts[1]:
+			s = 2
+		case mr.typ == parts[0] && mr.subtype == "*":
+			s = 1
+		case mr.typ == "*" && mr.subtype == "*":
+			s = 0
+		}
+
+		if s > specificity {
+			q, specificity = mr.q, s
+		}
+	}
+
+	return q
+}
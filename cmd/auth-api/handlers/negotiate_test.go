@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+func TestNegotiate(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Accept   string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{Name: "No header", Accept: "", Expected: applicationJSON},
+		{Name: "Any", Accept: "*/*", Expected: applicationJSON},
+		{Name: "Exact", Accept: "text/csv", Expected: textCSV},
+		{Name: "Browser", Accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			Expected: textHTML},
+		{Name: "Weights", Accept: "application/json;q=0.5, application/x-ndjson", Expected: applicationNDJSON},
+		{Name: "Type range", Accept: "text/*", Expected: textHTML},
+		{Name: "Specific range wins", Accept: "text/*;q=0.2, text/csv;q=0.9, */*;q=0.1", Expected: textCSV},
+		{Name: "Excluded", Accept: "application/json;q=0, */*", Expected: textHTML},
+		{Name: "Parameters and case", Accept: "Text/CSV; charset=utf-8", Expected: textCSV},
+		{Name: "Invalid weight is skipped", Accept: "text/html;q=2, text/csv", Expected: textCSV},
+		{Name: "Not acceptable", Accept: "image/png", Expected: ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", tc.Accept)
+			rec := httptest.NewRecorder()
+
+			format, err := negotiate(rec, req, robotListFormats...)
+			assert.Equal(t, tc.Expected, format)
+			assert.Equal(t, tc.Expected == "", errors.Is(err, errNotAcceptable), "error: %v", err)
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestExports(t *testing.T) {
+	type testCase struct {
+		Name                string
+		Path                string
+		Accept              string
+		ExpectedCode        int
+		ExpectedContentType string
+		ExpectedBody        string
+	}
+
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		nil, nopPublisher{}, &fakeInstruments{})
+
+	setupSignUp(h, t)
+	owner := "Bearer " + setupUser(h, t, "owner@example.com").Token
+
+	rob := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10.5, SellPrice: 20}
+	assert.NoError(robotStorage.Create(rob))
+	assert.NoError(robotStorage.Transition(rob.RobotID, robot.ActionActivate, robot.SourceUser, 0))
+	assert.NoError(robotStorage.Trade(rob, robot.Deal{Side: "buy", Price: 10.5, Lots: 1}))
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	robotPath := fmt.Sprintf("/api/v1/robot/%d", rob.RobotID)
+
+	testCases := []testCase{
+		{Name: "Robots in JSON", Path: "/api/v1/robots", ExpectedCode: http.StatusOK,
+			ExpectedContentType: applicationJSON, ExpectedBody: `[{"robot_id":1,`},
+		{Name: "Robots in CSV", Path: "/api/v1/robots", Accept: "text/csv", ExpectedCode: http.StatusOK,
+			ExpectedContentType: "text/csv; charset=utf-8", ExpectedBody: "robot_id,owner_user_id,"},
+		{Name: "Robots in NDJSON", Path: "/api/v1/users/1/robots", Accept: applicationNDJSON,
+			ExpectedCode: http.StatusOK, ExpectedContentType: applicationNDJSON, ExpectedBody: `{"robot_id":1,`},
+		{Name: "Robots in HTML", Path: "/api/v1/robots", Accept: "text/html,*/*;q=0.8", ExpectedCode: http.StatusOK,
+			ExpectedContentType: textHTML, ExpectedBody: "<"},
+		{Name: "Robot in HTML", Path: robotPath, Accept: "text/html;q=0.9, application/json;q=0.5",
+			ExpectedCode: http.StatusOK, ExpectedContentType: textHTML, ExpectedBody: "<"},
+		{Name: "Robot in CSV", Path: robotPath, Accept: "text/csv", ExpectedCode: http.StatusNotAcceptable,
+			ExpectedContentType: problemJSON, ExpectedBody: "{"},
+		{Name: "Deals in CSV", Path: robotPath + "/deals", Accept: "text/csv", ExpectedCode: http.StatusOK,
+			ExpectedContentType: "text/csv; charset=utf-8", ExpectedBody: "id,robot_id,version,side,price,lots,created_at\n"},
+		{Name: "Deals in NDJSON", Path: robotPath + "/deals", Accept: applicationNDJSON, ExpectedCode: http.StatusOK,
+			ExpectedContentType: applicationNDJSON, ExpectedBody: `{"id":`},
+		{Name: "Deals in HTML", Path: robotPath + "/deals", Accept: textHTML, ExpectedCode: http.StatusNotAcceptable,
+			ExpectedContentType: problemJSON, ExpectedBody: "{"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, ts.URL+tc.Path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req.Header.Set("Authorization", owner)
+
+			if tc.Accept != "" {
+				req.Header.Set("Accept", tc.Accept)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(err)
+
+			assert.Equal(tc.ExpectedCode, resp.StatusCode)
+			assert.Equal(tc.ExpectedContentType, resp.Header.Get("Content-Type"))
+			assert.True(strings.HasPrefix(strings.TrimSpace(string(body)), tc.ExpectedBody), "body: %s", body)
+		})
+	}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/robots", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", owner)
+	req.Header.Set("Accept", textCSV)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	assert.NoError(err)
+
+	if assert.Len(records, 2) {
+		row := make(map[string]string)
+		for i, column := range records[0] {
+			row[column] = records[1][i]
+		}
+
+		assert.Equal("AAPL", row["ticker"])
+		assert.Equal("10.5", row["buy_price"])
+		assert.Equal("active", row["status"])
+	}
+
+	deals, err := robotStorage.FindDeals(rob.RobotID)
+	assert.NoError(err)
+
+	line, err := json.Marshal(dealsExport(deals).Item(0))
+	assert.NoError(err)
+	assert.Contains(string(line), `"side":"buy"`)
+}
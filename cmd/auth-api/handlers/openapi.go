@@ -0,0 +1,599 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+)
+
+const (
+	apiPrefix      = "/api/v1"
+	openAPIVersion = "3.0.3"
+	bearerScheme   = "bearer"
+)
+
+// openAPI документ OpenAPI 3 с описанием API сервиса. Строится функцией buildOpenAPI,
+// в нем есть только те поля спецификации, которые нужны сервису.
+type openAPI struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       openAPIInfo         `json:"info"`
+	Servers    []openAPIServer     `json:"servers"`
+	Paths      map[string]pathItem `json:"paths"`
+	Components components          `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+// pathItem операции пути по методу HTTP в нижнем регистре.
+type pathItem map[string]*operation
+
+type operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type components struct {
+	Schemas         map[string]*schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// schema схема JSON в диалекте OpenAPI 3.0. AdditionalProperties равен false у тел запросов:
+// сервис отвечает 400 на неизвестные поля.
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// apiDoc описание маршрута Routes. Тело запроса и ответов задается значением типа Go,
+// схема которого выводится отражением.
+type apiDoc struct {
+	id       string
+	summary  string
+	tag      string
+	public   bool
+	query    []parameter
+	body     interface{}
+	optional bool
+	html     bool
+	// export ответ 200 также выгружается в CSV и NDJSON.
+	export    bool
+	responses map[int]interface{}
+}
+
+// noBody ответ без тела.
+type noBody struct{}
+
+// Параметры query, общие для нескольких маршрутов.
+var (
+	limitParam  = queryParam("limit", "integer", "размер страницы")
+	periodParam = enumParam("period", "период статистики, по умолчанию all",
+		robot.PeriodDay, robot.PeriodWeek, robot.PeriodMonth, robot.PeriodYear, robot.PeriodAll)
+	robotQuery = []parameter{
+		queryParam("ticker", "string", "тикер"),
+		enumParam("sort", "поле сортировки, по умолчанию robot_id", robot.SortFields()...),
+		enumParam("order", "направление сортировки", robot.OrderAsc, robot.OrderDesc),
+		queryParam("user", "integer", "владелец робота"),
+		queryParam("parent", "integer", "родитель избранного робота"),
+		queryParam("cloned_from", "integer", "исходный робот копии"),
+		queryParam("template", "integer", "шаблон робота"),
+		queryParam("is_active", "boolean", ""),
+		queryParam("is_favourite", "boolean", ""), //nolint:misspell
+		queryParam("min_yield", "number", ""),
+		queryParam("max_yield", "number", ""),
+		queryParam("min_buy_price", "number", ""),
+		queryParam("max_buy_price", "number", ""),
+		queryParam("min_sell_price", "number", ""),
+		queryParam("max_sell_price", "number", ""),
+		queryParam("created_from", "string", "дата 2006-01-02 или время RFC 3339"),
+		queryParam("created_to", "string", "дата 2006-01-02 или время RFC 3339"),
+		limitParam,
+		queryParam("cursor", "string", "курсор следующей страницы из заголовка Link"),
+	}
+)
+
+// apiDocs описания маршрутов Routes по методу и пути без префикса /api/v1. Маршрут без описания
+// или описание без маршрута — ошибка buildOpenAPI.
+var apiDocs = map[string]apiDoc{ //nolint:gochecknoglobals
+	"GET /openapi.json": {id: "getOpenAPI", summary: "Документ OpenAPI сервиса", tag: "meta", public: true,
+		responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}}},
+
+	"POST /signup": {id: "signUp", summary: "Регистрация пользователя", tag: "users", public: true,
+		body: user.User{}, responses: map[int]interface{}{http.StatusCreated: noBody{}}},
+	"POST /signin": {id: "signIn", summary: "Вход пользователя", tag: "users", public: true,
+		body: SignInData{}, responses: map[int]interface{}{http.StatusOK: session.BearerToken{}}},
+	"GET /users/{id}": {id: "getUser", summary: "Пользователь", tag: "users", query: []parameter{ifNoneMatchParam},
+		responses: map[int]interface{}{http.StatusOK: &user.User{}, http.StatusNotModified: noBody{}}},
+	"PUT /users/{id}": {id: "updateUser", summary: "Изменение пользователя", tag: "users", query: []parameter{ifMatchParam},
+		body: user.User{}, responses: map[int]interface{}{http.StatusOK: &user.User{}}},
+	"GET /users/{id}/robots": {id: "listUserRobots", summary: "Роботы пользователя", tag: "robots",
+		query: robotQuery, html: true, export: true, responses: map[int]interface{}{http.StatusOK: []robot.Robot{}}},
+	"GET /users/{id}/robots/deleted": {id: "listDeletedRobots", summary: "Корзина пользователя", tag: "robots",
+		html: true, export: true, responses: map[int]interface{}{http.StatusOK: []robot.Robot{}}},
+
+	"GET /robots": {id: "listRobots", summary: "Каталог роботов", tag: "robots",
+		query: robotQuery, html: true, export: true, responses: map[int]interface{}{http.StatusOK: []robot.Robot{}}},
+	"GET /robots/leaderboard": {id: "leaderboard", summary: "Рейтинг публичных роботов", tag: "robots",
+		query: []parameter{
+			enumParam("metric", "метрика рейтинга, по умолчанию yield", robot.MetricYield, robot.MetricSharpe,
+				robot.MetricDeals, robot.MetricDrawdown, robot.MetricFollowers),
+			periodParam,
+			limitParam,
+		},
+		html: true, responses: map[int]interface{}{http.StatusOK: []robot.Stats{}}},
+	"POST /robot": {id: "createRobot", summary: "Создание робота", tag: "robots",
+		body: robotInput{}, responses: map[int]interface{}{http.StatusCreated: noBody{}}},
+	"POST /robots/batch": {id: "batchCreateRobots", summary: "Пакетное создание роботов", tag: "batch",
+		body: batchCreateRequest{}, responses: batchResponses(http.StatusOK)},
+	"POST /robots/batch/activate": {id: "batchActivateRobots", summary: "Пакетный запуск роботов", tag: "batch",
+		body: batchActionRequest{}, responses: batchResponses(http.StatusOK)},
+	"POST /robots/batch/deactivate": {id: "batchDeactivateRobots", summary: "Пакетная остановка роботов", tag: "batch",
+		body: batchActionRequest{}, responses: batchResponses(http.StatusOK)},
+	"POST /robots/batch/delete": {id: "batchDeleteRobots", summary: "Пакетное удаление роботов", tag: "batch",
+		body: batchActionRequest{}, responses: batchResponses(http.StatusOK)},
+
+	"POST /templates": {id: "createTemplate", summary: "Создание шаблона", tag: "templates",
+		body: robot.Template{}, responses: map[int]interface{}{http.StatusCreated: robot.Template{}}},
+	"GET /templates": {id: "listTemplates", summary: "Шаблоны пользователя", tag: "templates",
+		responses: map[int]interface{}{http.StatusOK: []robot.Template{}}},
+	"GET /templates/{id}": {id: "getTemplate", summary: "Шаблон", tag: "templates",
+		responses: map[int]interface{}{http.StatusOK: robot.Template{}}},
+	"DELETE /templates/{id}": {id: "deleteTemplate", summary: "Удаление шаблона", tag: "templates",
+		responses: map[int]interface{}{http.StatusOK: noBody{}}},
+	"POST /templates/{id}/robots": {id: "instantiateTemplate", summary: "Создание роботов из шаблона", tag: "templates",
+		body: batchCreateRequest{}, responses: batchResponses(http.StatusOK)},
+
+	"GET /robot/{id}": {id: "getRobot", summary: "Робот", tag: "robots",
+		query: []parameter{queryParam("share", "string", "токен ссылки на робота"), ifNoneMatchParam},
+		html:  true, responses: map[int]interface{}{http.StatusOK: &robot.Robot{}, http.StatusNotModified: noBody{}}},
+	"PUT /robot/{id}": {id: "editRobot", summary: "Изменение параметров робота", tag: "robots", query: []parameter{ifMatchParam},
+		body: robotInput{}, responses: map[int]interface{}{http.StatusOK: &robot.Robot{}}},
+	"DELETE /robot/{id}": {id: "deleteRobot", summary: "Удаление робота в корзину", tag: "robots", query: []parameter{ifMatchParam},
+		responses: map[int]interface{}{http.StatusOK: noBody{}}},
+	"PUT /robot/{id}/activate": {id: "activateRobot", summary: "Запуск робота", tag: "robots", query: []parameter{ifMatchParam},
+		responses: map[int]interface{}{http.StatusOK: noBody{}}},
+	"PUT /robot/{id}/deactivate": {id: "deactivateRobot", summary: "Приостановка робота", tag: "robots",
+		query: []parameter{ifMatchParam}, responses: map[int]interface{}{http.StatusOK: noBody{}}},
+	"PUT /robot/{id}/stop": {id: "stopRobot", summary: "Завершение работы робота", tag: "robots",
+		query: []parameter{ifMatchParam}, responses: map[int]interface{}{http.StatusOK: noBody{}}},
+	"PUT /robot/{id}/favourite": {id: "favouriteRobot", summary: "Добавление робота в избранное", tag: "robots", //nolint:misspell
+		query: []parameter{ifMatchParam}, body: robot.Follow{}, optional: true,
+		responses: map[int]interface{}{http.StatusOK: &robot.Robot{}}},
+	"PUT /robot/{id}/unfollow": {id: "unfollowRobot", summary: "Отключение повторения родителя", tag: "robots",
+		query: []parameter{ifMatchParam}, responses: map[int]interface{}{http.StatusOK: noBody{}}},
+	"POST /robot/{id}/clone": {id: "cloneRobot", summary: "Копия робота", tag: "robots",
+		body: robotInput{}, optional: true, responses: map[int]interface{}{http.StatusCreated: &robot.Robot{}}},
+	"PUT /robot/{id}/restore": {id: "restoreRobot", summary: "Восстановление робота из корзины", tag: "robots",
+		responses: map[int]interface{}{http.StatusOK: &robot.Robot{}}},
+	"DELETE /robot/{id}/purge": {id: "purgeRobot", summary: "Окончательное удаление робота", tag: "robots",
+		responses: map[int]interface{}{http.StatusOK: noBody{}}},
+	"GET /robot/{id}/transitions": {id: "listRobotTransitions", summary: "История переходов робота", tag: "robots",
+		responses: map[int]interface{}{http.StatusOK: []robot.Transition{}}},
+	"GET /robot/{id}/versions": {id: "listRobotVersions", summary: "История параметров робота", tag: "robots",
+		responses: map[int]interface{}{http.StatusOK: []robot.Version{}}},
+	"GET /robot/{id}/deals": {id: "listRobotDeals", summary: "Сделки робота", tag: "robots",
+		export: true, responses: map[int]interface{}{http.StatusOK: []robot.Deal{}}},
+	"GET /robot/{id}/stats": {id: "getRobotStats", summary: "Статистика робота", tag: "robots",
+		query: []parameter{periodParam}, responses: map[int]interface{}{http.StatusOK: robot.Stats{}}},
+	"GET /robot/{id}/shares": {id: "getRobotShares", summary: "Доступ к роботу", tag: "sharing",
+		responses: map[int]interface{}{http.StatusOK: sharesView{}}},
+	"PUT /robot/{id}/shares/{userID}": {id: "shareRobot", summary: "Открытие робота пользователю", tag: "sharing",
+		responses: map[int]interface{}{http.StatusOK: noBody{}}},
+	"DELETE /robot/{id}/shares/{userID}": {id: "unshareRobot", summary: "Закрытие робота от пользователя", tag: "sharing",
+		responses: map[int]interface{}{http.StatusOK: noBody{}}},
+
+	"GET /wsrobotdetail": {id: "watchRobot", summary: "Подписка на изменения робота по websocket", tag: "robots",
+		query:     []parameter{queryParam("token", "string", "токен сессии, если заголовок Authorization не передан")},
+		responses: map[int]interface{}{http.StatusSwitchingProtocols: noBody{}}},
+
+	"POST /graphql": {id: "graphql", summary: "Запрос или мутация GraphQL", tag: "graphql",
+		body: graphqlRequest{}, responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}}},
+	"GET /graphql": {id: "graphqlSubscriptions", summary: "Подписки GraphQL по websocket, протокол graphql-ws", tag: "graphql",
+		query:     []parameter{queryParam("token", "string", "токен сессии, если заголовок Authorization не передан")},
+		responses: map[int]interface{}{http.StatusSwitchingProtocols: noBody{}}},
+}
+
+// batchResponses ответы пакетной операции: success, если выполнены все элементы, иначе 207 Multi-Status.
+func batchResponses(success int) map[int]interface{} {
+	return map[int]interface{}{success: batchResponse{}, http.StatusMultiStatus: batchResponse{}}
+}
+
+// ifMatchParam и ifNoneMatchParam заголовки условных запросов с ETag объекта.
+var (
+	ifMatchParam = parameter{Name: "If-Match", In: "header", Required: true,
+		Description: "ETag, с которым объект прочитан; если объект с тех пор изменился, ответ 412",
+		Schema:      &schema{Type: "string"}}
+	ifNoneMatchParam = parameter{Name: "If-None-Match", In: "header",
+		Description: "ETag сохраненной копии; если объект не изменился, ответ 304 без тела",
+		Schema:      &schema{Type: "string"}}
+)
+
+// idempotencyKeyParam заголовок Idempotency-Key, его принимают операции POST и PUT, доступные только с сессией.
+var idempotencyKeyParam = parameter{Name: idempotencyKeyHeader, In: "header",
+	Description: "ключ идемпотентности: повтор запроса с тем же ключом получает сохраненный ответ",
+	Schema:      &schema{Type: "string"}}
+
+// apiKeyParam заголовок X-API-Key, его принимают все операции.
+var apiKeyParam = parameter{Name: apiKeyHeader, In: "header",
+	Description: "ключ API клиента из настроек сервиса: у клиента с ключом свои квоты запросов",
+	Schema:      &schema{Type: "string"}}
+
+func queryParam(name, typ, description string) parameter {
+	return parameter{Name: name, In: "query", Description: description, Schema: &schema{Type: typ}}
+}
+
+func enumParam(name, description string, values ...string) parameter {
+	return parameter{Name: name, In: "query", Description: description, Schema: &schema{Type: "string", Enum: values}}
+}
+
+// OpenAPI отправляет документ OpenAPI сервиса. Документ строится по роутеру один раз.
+func (h *Handler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	h.specOnce.Do(func() {
+		var doc *openAPI
+		if doc, h.specErr = buildOpenAPI(h.Routes()); h.specErr == nil {
+			h.spec, h.specErr = json.Marshal(doc)
+		}
+	})
+
+	if h.specErr != nil {
+		h.fail(w, r, "can't build openapi document", h.specErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(h.spec); err != nil {
+		h.logger.Warnw("unable to write openapi document", "error", err)
+	}
+}
+
+// pathParamRe параметр пути chi, например {id}.
+var pathParamRe = regexp.MustCompile(`{([^}]+)}`)
+
+// buildOpenAPI строит документ OpenAPI по маршрутам router и их описаниям из apiDocs.
+func buildOpenAPI(router chi.Routes) (*openAPI, error) {
+	doc := &openAPI{
+		OpenAPI: openAPIVersion,
+		Info:    openAPIInfo{Title: "Trading robots API", Version: "1.0.0"},
+		Servers: []openAPIServer{{URL: apiPrefix}},
+		Paths:   make(map[string]pathItem),
+		Components: components{
+			Schemas:         make(map[string]*schema),
+			SecuritySchemes: map[string]securityScheme{bearerScheme: {Type: "http", Scheme: bearerScheme}},
+		},
+	}
+	b := schemaBuilder{schemas: doc.Components.Schemas}
+	seen := make(map[string]bool)
+
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		path := routePath(route)
+		key := method + " " + path
+
+		d, ok := apiDocs[key]
+		if !ok {
+			return fmt.Errorf("route %s has no description in apiDocs", key)
+		}
+
+		seen[key] = true
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(pathItem)
+		}
+
+		op := b.operation(path, d)
+		if (method == http.MethodPost || method == http.MethodPut) && !d.public {
+			op.Parameters = append(op.Parameters, idempotencyKeyParam)
+		}
+
+		doc.Paths[path][strings.ToLower(method)] = op
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range apiDocs {
+		if !seen[key] {
+			return nil, fmt.Errorf("apiDocs describes %s, but there is no such route", key)
+		}
+	}
+
+	return doc, nil
+}
+
+// routePath возвращает путь маршрута chi без префикса /api/v1, групп и завершающего слэша.
+func routePath(route string) string {
+	path := strings.TrimPrefix(strings.ReplaceAll(route, "/*/", "/"), apiPrefix)
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	return path
+}
+
+// operation возвращает описание операции d по пути path.
+func (b *schemaBuilder) operation(path string, d apiDoc) *operation {
+	op := &operation{
+		OperationID: d.id,
+		Summary:     d.summary,
+		Tags:        []string{d.tag},
+		Responses:   make(map[string]*response),
+		Security:    []map[string][]string{},
+	}
+
+	if !d.public {
+		op.Security = append(op.Security, map[string][]string{bearerScheme: {}})
+	}
+
+	for _, m := range pathParamRe.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, parameter{Name: m[1], In: "path", Required: true,
+			Schema: &schema{Type: "integer"}})
+	}
+
+	op.Parameters = append(op.Parameters, d.query...)
+	op.Parameters = append(op.Parameters, apiKeyParam)
+
+	if d.body != nil {
+		op.RequestBody = &requestBody{Required: !d.optional, Content: map[string]mediaType{
+			"application/json": {Schema: b.of(reflect.TypeOf(d.body), true)},
+		}}
+	}
+
+	for status, body := range d.responses {
+		resp := &response{Description: http.StatusText(status)}
+
+		if _, empty := body.(noBody); !empty {
+			resp.Content = map[string]mediaType{applicationJSON: {Schema: b.of(reflect.TypeOf(body), false)}}
+
+			if d.html {
+				resp.Content[textHTML] = mediaType{Schema: &schema{Type: "string"}}
+			}
+
+			if d.export && status == http.StatusOK {
+				resp.Content[textCSV] = mediaType{Schema: &schema{Type: "string",
+					Description: "первая строка — названия колонок"}}
+				resp.Content[applicationNDJSON] = mediaType{Schema: &schema{Type: "string",
+					Description: "по объекту JSON в строке"}}
+			}
+		}
+
+		op.Responses[strconv.Itoa(status)] = resp
+	}
+
+	op.Responses["default"] = &response{Description: "Ошибка", Content: map[string]mediaType{
+		problemJSON: {Schema: b.of(reflect.TypeOf(apiError{}), false)},
+	}}
+
+	return op
+}
+
+// schemaBuilder выводит схемы из типов Go и складывает именованные схемы в schemas.
+type schemaBuilder struct {
+	schemas map[string]*schema
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	nullTimeType = reflect.TypeOf(robot.NullTime{})
+	rawJSONType  = reflect.TypeOf(json.RawMessage{})
+	robotType    = reflect.TypeOf(robot.Robot{})
+	userType     = reflect.TypeOf(user.User{})
+)
+
+// schemaNames имена схем типов, у которых имя в Go не подходит для API.
+var schemaNames = map[reflect.Type]string{ //nolint:gochecknoglobals
+	reflect.TypeOf(apiError{}):   "Problem",
+	reflect.TypeOf(sharesView{}): "Shares",
+	reflect.TypeOf(robotInput{}): "Robot",
+}
+
+// schemaEnums допустимые значения строковых типов.
+var schemaEnums = map[reflect.Type][]string{ //nolint:gochecknoglobals
+	reflect.TypeOf(robot.StatusDraft): {
+		string(robot.StatusDraft), string(robot.StatusScheduled), string(robot.StatusActive), string(robot.StatusHolding),
+		string(robot.StatusPaused), string(robot.StatusStopped), string(robot.StatusDeleted),
+	},
+	reflect.TypeOf(robot.ActionActivate): {
+		string(robot.ActionSchedule), string(robot.ActionActivate), string(robot.ActionDeactivate),
+		string(robot.ActionSuspend), string(robot.ActionStop), string(robot.ActionBuy), string(robot.ActionSell),
+		string(robot.ActionDelete), string(robot.ActionRestore),
+	},
+	reflect.TypeOf(robot.VisibilityPublic): {
+		string(robot.VisibilityPrivate), string(robot.VisibilityLink), string(robot.VisibilityUsers),
+		string(robot.VisibilityPublic),
+	},
+}
+
+// of возвращает схему типа t. Именованные структуры попадают в components и возвращаются ссылкой,
+// input — схема тела запроса: у нее нет обязательных полей, кроме заданных явно, и неизвестные поля запрещены.
+func (b *schemaBuilder) of(t reflect.Type, input bool) *schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &schema{Type: "string", Format: "date-time"}
+	case nullTimeType:
+		return &schema{Type: "string", Nullable: input, Description: "время RFC 3339, пустая строка — не задано"}
+	case rawJSONType:
+		return &schema{}
+	}
+
+	if values, ok := schemaEnums[t]; ok {
+		return &schema{Type: "string", Enum: values}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &schema{Type: "array", Items: b.of(t.Elem(), input)}
+	case reflect.Map:
+		return &schema{Type: "object"}
+	case reflect.Struct:
+		return b.ref(t, input)
+	}
+
+	return &schema{}
+}
+
+// ref добавляет схему структуры t в components и возвращает ссылку на нее. Схемы тел запросов
+// называются с суффиксом Input, если тип не описывает запрос целиком.
+func (b *schemaBuilder) ref(t reflect.Type, input bool) *schema {
+	name, ok := schemaNames[t]
+	if !ok {
+		name = strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	}
+
+	if input && !strings.HasSuffix(name, "Request") {
+		name += "Input"
+	}
+
+	if _, ok := b.schemas[name]; !ok {
+		s := &schema{}
+		b.schemas[name] = s
+		*s = *b.structSchema(t, input)
+	}
+
+	return &schema{Ref: "#/components/schemas/" + name}
+}
+
+// structSchema возвращает схему полей структуры t так, как их читает и пишет encoding/json.
+// Робот и пользователь сериализуются своими MarshalJSON, поэтому их схемы дополняются здесь.
+func (b *schemaBuilder) structSchema(t reflect.Type, input bool) *schema {
+	s := &schema{Type: "object", Properties: make(map[string]*schema)}
+	b.addFields(s, t, input)
+
+	switch {
+	case t == userType && input:
+		s.Properties["birthday"] = &schema{Type: "string", Format: "date"}
+	case t == userType:
+		s.Properties = map[string]*schema{
+			"first_name": {Type: "string"},
+			"last_name":  {Type: "string"},
+			"birthday":   {Type: "string", Format: "date"},
+			"email":      {Type: "string"},
+		}
+		s.Required = []string{"first_name", "last_name", "birthday", "email"}
+	case t == robotType && !input:
+		for _, name := range []string{"activated_at", "deactivated_at", "deleted_at"} {
+			s.Properties[name] = b.of(nullTimeType, false)
+		}
+
+		s.Properties["created_at"] = b.of(nullTimeType, false)
+		s.Properties["actions"] = &schema{Type: "array", Items: b.of(reflect.TypeOf(robot.ActionActivate), false)}
+		s.Required = append(s.Required, "created_at", "actions")
+	}
+
+	if input {
+		closed := false
+		s.AdditionalProperties = &closed
+		s.Required = nil
+
+		if t == userType {
+			s.Required = []string{"first_name", "last_name", "email", "password"}
+		}
+	}
+
+	sort.Strings(s.Required)
+
+	return s
+}
+
+// addFields добавляет в s поля структуры t, поля встроенных структур поднимаются на уровень t.
+func (b *schemaBuilder) addFields(s *schema, t reflect.Type, input bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			b.addFields(s, f.Type, input)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = b.of(f.Type, input)
+
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+func TestBuildOpenAPI(t *testing.T) {
+	assert := assert.New(t)
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), nil, nil, nil, nil)
+
+	doc, err := buildOpenAPI(h.Routes())
+	assert.NoError(err)
+
+	ids := make(map[string]bool)
+
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			assert.False(ids[op.OperationID], "operationId %s is duplicated", op.OperationID)
+			ids[op.OperationID] = true
+
+			assert.NotEmpty(op.Responses["default"], "%s %s has no error response", method, path)
+		}
+	}
+
+	assert.Len(ids, len(apiDocs))
+	assert.Equal("#/components/schemas/RobotInput", doc.Paths["/robot"]["post"].RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(doc.Components.Schemas["Robot"].Required, "actions")
+	assert.NotContains(doc.Components.Schemas["RobotInput"].Properties, "actions")
+	assert.Equal([]string{"email", "first_name", "last_name", "password"}, doc.Components.Schemas["UserInput"].Required)
+	assert.NotContains(doc.Components.Schemas["User"].Properties, "password")
+
+	undocumented := chi.NewRouter()
+	undocumented.Get(apiPrefix+"/robots", h.CatalogRobots)
+	undocumented.Get(apiPrefix+"/robots/secret", h.CatalogRobots)
+
+	_, err = buildOpenAPI(undocumented)
+	assert.Error(err, "route without description")
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	assert := assert.New(t)
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), nil, nil, nil, nil)
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	resp, code := testRequest(t, ts, http.MethodGet, "/api/v1/openapi.json", nil)
+	defer resp.Body.Close()
+
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("application/json", resp.Header.Get("Content-Type"))
+
+	var doc openAPI
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(openAPIVersion, doc.OpenAPI)
+	assert.Equal(apiPrefix, doc.Servers[0].URL)
+	assert.Contains(doc.Paths, "/robot/{id}/shares/{userID}")
+}
+
+// TestOpenAPIContract проходит по всем операциям API и проверяет каждый ответ по документу OpenAPI:
+// статус описан, тип содержимого объявлен, тело соответствует схеме без лишних полей.
+func TestOpenAPIContract(t *testing.T) {
+	assert := assert.New(t)
+	robotStorage := robot.CreateStorageInMemory()
+	instruments := &fakeInstruments{tickers: map[string]bool{"AAPL": true, "MSFT": true}}
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(), robotStorage,
+		NewWebsocket(robotStorage), nopPublisher{}, instruments)
+
+	doc, err := buildOpenAPI(h.Routes())
+	if !assert.NoError(err) {
+		return
+	}
+
+	setupSignUp(h, t)
+	owner := fmt.Sprintf("Bearer %s", setupUser(h, t, "owner@example.com").Token)
+	viewer := fmt.Sprintf("Bearer %s", setupUser(h, t, "viewer@example.com").Token)
+
+	c := &contract{t: t, doc: doc, covered: make(map[string]bool)}
+	ts := httptest.NewServer(c.middleware(h.Routes()))
+	defer ts.Close()
+
+	call := func(method, path, auth, body string, expected int, v interface{}) {
+		resp, code := testRequestWithAuth(t, ts, method, path, auth, strings.NewReader(body))
+		defer resp.Body.Close()
+
+		assert.Equal(expected, code, "%s %s", method, path)
+
+		if v != nil && code < http.StatusBadRequest {
+			assert.NoError(json.NewDecoder(resp.Body).Decode(v))
+		}
+	}
+
+	call(http.MethodGet, "/api/v1/openapi.json", "", "", http.StatusOK, nil)
+	call(http.MethodPost, "/api/v1/signup", "", `{"first_name":"Anna","last_name":"Ivanova","email":"anna@example.com","password":"1234"}`,
+		http.StatusCreated, nil)
+	call(http.MethodPost, "/api/v1/signup", "", `{"first_name":"Anna","last_name":"Ivanova","email":"anna@example.com","password":"1234"}`,
+		http.StatusConflict, nil)
+	call(http.MethodPost, "/api/v1/signup", "", `{"first_name":"Anna","email":"anna"}`, http.StatusBadRequest, nil)
+	call(http.MethodPost, "/api/v1/signin", "", `{"email":"anna@example.com","password":"1234"}`, http.StatusOK, nil)
+	call(http.MethodPost, "/api/v1/signin", "", `{"email":"anna@example.com","password":"4321"}`, http.StatusBadRequest, nil)
+
+	callIfMatch := func(method, path, auth, ifMatch, body string, expected int) {
+		resp, code := testRequestIfMatch(t, ts, method, path, auth, ifMatch, strings.NewReader(body))
+		defer resp.Body.Close()
+
+		assert.Equal(expected, code, "%s %s If-Match %s", method, path, ifMatch)
+	}
+
+	owned, err := h.userStorage.FindByID(1)
+	if !assert.NoError(err) {
+		return
+	}
+
+	userBody := `{"first_name":"Oleg","last_name":"Petrov","birthday":"1990-05-01","email":"owner@example.com","password":"1234"}`
+
+	call(http.MethodGet, "/api/v1/users/1", owner, "", http.StatusOK, nil)
+	call(http.MethodGet, "/api/v1/users/2", owner, "", http.StatusForbidden, nil)
+	call(http.MethodGet, "/api/v1/users/1", "", "", http.StatusBadRequest, nil)
+	callIfMatch(http.MethodPut, "/api/v1/users/1", owner, "", userBody, http.StatusPreconditionRequired)
+	callIfMatch(http.MethodPut, "/api/v1/users/1", owner, userETag(owned), userBody, http.StatusOK)
+	callIfMatch(http.MethodPut, "/api/v1/users/1", owner, userETag(owned), userBody, http.StatusPreconditionFailed)
+
+	call(http.MethodPost, "/api/v1/robot", owner, `{"owner_user_id":1,"ticker":"AAPL","buy_price":10,"sell_price":12}`, http.StatusCreated, nil)
+	call(http.MethodPost, "/api/v1/robot", owner, `{"owner_user_id":1,"ticker":"AAPL","buy_price":12,"sell_price":10}`, http.StatusBadRequest, nil)
+
+	var robots []robot.Robot
+	call(http.MethodGet, "/api/v1/users/1/robots", owner, "", http.StatusOK, &robots)
+	call(http.MethodGet, "/api/v1/robots?sort=ticker&limit=1", viewer, "", http.StatusOK, nil)
+	call(http.MethodGet, "/api/v1/robots?sort=nope", viewer, "", http.StatusBadRequest, nil)
+
+	if !assert.Len(robots, 1) {
+		return
+	}
+
+	path := fmt.Sprintf("/api/v1/robot/%d", robots[0].RobotID)
+
+	call(http.MethodGet, path, viewer, "", http.StatusOK, nil)
+	call(http.MethodGet, "/api/v1/robot/999", viewer, "", http.StatusNotFound, nil)
+	call(http.MethodGet, "/api/v1/robot/abc", viewer, "", http.StatusBadRequest, nil)
+	callIfMatch(http.MethodPut, path, owner, currentETag(t, robotStorage, robots[0].RobotID), `{"lots":2}`, http.StatusOK)
+	call(http.MethodPut, path, viewer, `{"lots":2}`, http.StatusForbidden, nil)
+	etag := func(robotID int) string { return currentETag(t, robotStorage, robotID) }
+
+	callIfMatch(http.MethodPut, path+"/activate", owner, "", "", http.StatusPreconditionRequired)
+	callIfMatch(http.MethodPut, path+"/activate", owner, `"r0.0"`, "", http.StatusPreconditionFailed)
+	callIfMatch(http.MethodPut, path+"/activate", owner, etag(robots[0].RobotID), "", http.StatusOK)
+	callIfMatch(http.MethodPut, path+"/activate", owner, etag(robots[0].RobotID), "", http.StatusConflict)
+	call(http.MethodGet, path+"/transitions", owner, "", http.StatusOK, nil)
+	call(http.MethodGet, path+"/versions", owner, "", http.StatusOK, nil)
+	call(http.MethodGet, path+"/deals", owner, "", http.StatusOK, nil)
+	call(http.MethodGet, path+"/stats?period=week", viewer, "", http.StatusOK, nil)
+	call(http.MethodGet, "/api/v1/robots/leaderboard?metric=yield&period=all", viewer, "", http.StatusOK, nil)
+	call(http.MethodGet, "/api/v1/robots/leaderboard?metric=nope", viewer, "", http.StatusBadRequest, nil)
+
+	var follower robot.Robot
+	callIfMatch(http.MethodPut, path+"/favourite", viewer, "", `{"mirror":true}`, //nolint:misspell
+		http.StatusPreconditionRequired)
+	callIfMatch(http.MethodPut, path+"/favourite", viewer, `"r0.0"`, `{"mirror":true}`, //nolint:misspell
+		http.StatusPreconditionFailed)
+
+	resp, code := testRequestIfMatch(t, ts, http.MethodPut, path+"/favourite", viewer, //nolint:misspell
+		etag(robots[0].RobotID), strings.NewReader(`{"mirror":true}`))
+	assert.Equal(http.StatusOK, code)
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&follower))
+	resp.Body.Close()
+
+	callIfMatch(http.MethodPut, fmt.Sprintf("/api/v1/robot/%d/unfollow", follower.RobotID), viewer, "", "",
+		http.StatusPreconditionRequired)
+	callIfMatch(http.MethodPut, fmt.Sprintf("/api/v1/robot/%d/unfollow", follower.RobotID), viewer, `"r0.0"`, "",
+		http.StatusPreconditionFailed)
+	callIfMatch(http.MethodPut, fmt.Sprintf("/api/v1/robot/%d/unfollow", follower.RobotID), viewer,
+		etag(follower.RobotID), "", http.StatusOK)
+
+	call(http.MethodGet, path+"/shares", owner, "", http.StatusOK, nil)
+	call(http.MethodPut, path+"/shares/2", owner, "", http.StatusOK, nil)
+	call(http.MethodDelete, path+"/shares/2", owner, "", http.StatusOK, nil)
+
+	callIfMatch(http.MethodPut, path+"/deactivate", owner, "", "", http.StatusPreconditionRequired)
+	callIfMatch(http.MethodPut, path+"/deactivate", owner, `"r0.0"`, "", http.StatusPreconditionFailed)
+	callIfMatch(http.MethodPut, path+"/deactivate", owner, etag(robots[0].RobotID), "", http.StatusOK)
+	callIfMatch(http.MethodPut, path+"/stop", owner, "", "", http.StatusPreconditionRequired)
+	callIfMatch(http.MethodPut, path+"/stop", owner, `"r0.0"`, "", http.StatusPreconditionFailed)
+	callIfMatch(http.MethodPut, path+"/stop", owner, etag(robots[0].RobotID), "", http.StatusOK)
+
+	var clone robot.Robot
+	call(http.MethodPost, path+"/clone", owner, `{"ticker":"MSFT"}`, http.StatusCreated, &clone)
+
+	var tmpl robot.Template
+	call(http.MethodPost, "/api/v1/templates", owner, `{"name":"breakout","buy_price":10,"sell_price":12}`,
+		http.StatusCreated, &tmpl)
+
+	tmplPath := fmt.Sprintf("/api/v1/templates/%d", tmpl.ID)
+	call(http.MethodGet, "/api/v1/templates", owner, "", http.StatusOK, nil)
+	call(http.MethodGet, tmplPath, owner, "", http.StatusOK, nil)
+	call(http.MethodPost, tmplPath+"/robots", owner, `{"robots":[{"ticker":"AAPL"},{"ticker":"NOPE"}]}`,
+		http.StatusMultiStatus, nil)
+	call(http.MethodDelete, tmplPath, owner, "", http.StatusOK, nil)
+
+	call(http.MethodPost, "/api/v1/robots/batch", owner, `{"robots":[{"owner_user_id":1,"ticker":"MSFT"}]}`, http.StatusOK, nil)
+	call(http.MethodPost, "/api/v1/robots/batch", owner, `{"robots":[]}`, http.StatusBadRequest, nil)
+
+	ids := fmt.Sprintf(`{"robot_ids":[%d]}`, clone.RobotID)
+	call(http.MethodPost, "/api/v1/robots/batch/activate", owner, ids, http.StatusOK, nil)
+	call(http.MethodPost, "/api/v1/robots/batch/deactivate", owner, ids, http.StatusOK, nil)
+	call(http.MethodPost, "/api/v1/robots/batch/delete", owner, fmt.Sprintf(`{"robot_ids":[%d,999]}`, clone.RobotID),
+		http.StatusMultiStatus, nil)
+
+	callIfMatch(http.MethodDelete, path, owner, "", "", http.StatusPreconditionRequired)
+	callIfMatch(http.MethodDelete, path, owner, `"r0.0"`, "", http.StatusPreconditionFailed)
+	callIfMatch(http.MethodDelete, path, owner, etag(robots[0].RobotID), "", http.StatusOK)
+	call(http.MethodGet, "/api/v1/users/1/robots/deleted", owner, "", http.StatusOK, nil)
+	call(http.MethodPut, path+"/restore", owner, "", http.StatusOK, nil)
+	callIfMatch(http.MethodDelete, path, owner, etag(robots[0].RobotID), "", http.StatusOK)
+	call(http.MethodDelete, path+"/purge", owner, "", http.StatusOK, nil)
+
+	call(http.MethodPost, "/api/v1/graphql", viewer, `{"query":"{ me { id } }"}`, http.StatusOK, nil)
+	call(http.MethodPost, "/api/v1/graphql", viewer, `{"query":""}`, http.StatusBadRequest, nil)
+
+	upgrade := func(path string, subprotocols ...string) {
+		dialer := websocket.Dialer{Subprotocols: subprotocols}
+
+		conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+path, http.Header{"Authorization": {owner}})
+		if !assert.NoError(err, "websocket %s", path) {
+			return
+		}
+
+		conn.Close()
+		c.checkUpgrade(resp)
+	}
+
+	upgrade("/api/v1/wsrobotdetail")
+	upgrade("/api/v1/graphql", graphqlWSProtocol)
+
+	for _, d := range apiDocs {
+		assert.True(c.covered[d.id], "operation %s is not checked by the contract test", d.id)
+	}
+}
+
+// contract проверяет ответы сервиса по документу OpenAPI и отмечает проверенные операции.
+type contract struct {
+	t       *testing.T
+	doc     *openAPI
+	mutex   sync.Mutex
+	covered map[string]bool
+}
+
+// middleware проверяет ответ next и передает его клиенту без изменений. Рукопожатие websocket требует
+// исходного соединения, поэтому оно проходит мимо проверки, а ответ сверяет checkUpgrade на стороне клиента.
+func (c *contract) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+
+		c.check(r, rec)
+
+		for key, values := range rec.Header() {
+			w.Header()[key] = values
+		}
+
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	})
+}
+
+// check сверяет ответ rec на запрос r с операцией документа.
+func (c *contract) check(r *http.Request, rec *httptest.ResponseRecorder) {
+	name := r.Method + " " + r.URL.Path
+
+	op := c.operation(r.Method, strings.TrimPrefix(r.URL.Path, apiPrefix))
+	if op == nil {
+		c.t.Errorf("%s: no operation in openapi document", name)
+		return
+	}
+
+	c.mutex.Lock()
+	c.covered[op.OperationID] = true
+	c.mutex.Unlock()
+
+	resp, ok := op.Responses[strconv.Itoa(rec.Code)]
+	if !ok && rec.Code < http.StatusBadRequest {
+		c.t.Errorf("%s: status %d is not documented", name, rec.Code)
+		return
+	} else if !ok {
+		resp = op.Responses["default"]
+	}
+
+	body := rec.Body.Bytes()
+
+	if len(resp.Content) == 0 {
+		if len(body) > 0 {
+			c.t.Errorf("%s: status %d must have no body, got %s", name, rec.Code, body)
+		}
+
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+
+	media, ok := resp.Content[contentType]
+	if !ok {
+		c.t.Errorf("%s: content type %q is not documented for status %d", name, contentType, rec.Code)
+		return
+	}
+
+	if contentType == textHTML || contentType == textCSV || contentType == applicationNDJSON {
+		return
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		c.t.Errorf("%s: invalid json %s: %s", name, body, err)
+		return
+	}
+
+	for _, err := range c.validate(media.Schema, v, "body") {
+		c.t.Errorf("%s: status %d: %s", name, rec.Code, err)
+	}
+}
+
+// checkUpgrade сверяет ответ на рукопожатие websocket с операцией документа.
+func (c *contract) checkUpgrade(resp *http.Response) {
+	name := resp.Request.Method + " " + resp.Request.URL.Path
+
+	op := c.operation(resp.Request.Method, strings.TrimPrefix(resp.Request.URL.Path, apiPrefix))
+	if op == nil {
+		c.t.Errorf("%s: no operation in openapi document", name)
+		return
+	}
+
+	c.mutex.Lock()
+	c.covered[op.OperationID] = true
+	c.mutex.Unlock()
+
+	if doc, ok := op.Responses[strconv.Itoa(resp.StatusCode)]; !ok || len(doc.Content) > 0 {
+		c.t.Errorf("%s: upgrade status is not documented without body", name)
+	}
+}
+
+// operation находит операцию документа по методу и пути запроса.
+func (c *contract) operation(method, path string) *operation {
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+
+	for pattern, item := range c.doc.Paths {
+		parts := strings.Split(pattern, "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+
+		match := true
+
+		for i, part := range parts {
+			if !strings.HasPrefix(part, "{") && part != segments[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return item[strings.ToLower(method)]
+		}
+	}
+
+	return nil
+}
+
+// validate возвращает расхождения значения v со схемой s.
+func (c *contract) validate(s *schema, v interface{}, path string) []string {
+	if s.Ref != "" {
+		s = c.doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+
+	if v == nil {
+		if s.Type == "" || s.Nullable {
+			return nil
+		}
+
+		return []string{fmt.Sprintf("%s is null, want %s", path, s.Type)}
+	}
+
+	var errs []string
+
+	switch s.Type {
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s is %T, want object", path, v)}
+		}
+
+		for _, name := range s.Required {
+			if _, ok := m[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.%s is required", path, name))
+			}
+		}
+
+		for name, value := range m {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.Properties != nil {
+					errs = append(errs, fmt.Sprintf("%s.%s is not documented", path, name))
+				}
+
+				continue
+			}
+
+			errs = append(errs, c.validate(prop, value, path+"."+name)...)
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s is %T, want array", path, v)}
+		}
+
+		for i, item := range items {
+			errs = append(errs, c.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s is %T, want string", path, v)}
+		}
+
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			errs = append(errs, fmt.Sprintf("%s is %q, want one of %v", path, str, s.Enum))
+		}
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok || (s.Type == "integer" && n != math.Trunc(n)) {
+			return []string{fmt.Sprintf("%s is %v, want %s", path, v, s.Type)}
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return []string{fmt.Sprintf("%s is %T, want boolean", path, v)}
+		}
+	}
+
+	return errs
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
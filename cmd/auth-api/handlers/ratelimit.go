@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"gitlab.com/hitchpock/tfs-course-work/internal/ratelimit"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// apiKeyHeader заголовок с ключом API клиента. В gRPC ключ передается в метаданных apiKeyMetadata.
+	apiKeyHeader   = "X-API-Key"
+	apiKeyMetadata = "x-api-key"
+	// ipRoute маршрут корзины, которую запросы с одного IP-адреса расходуют до проверки токена.
+	ipRoute = "ip"
+)
+
+var (
+	// errRateLimited клиент превысил квоту запросов маршрута.
+	errRateLimited = errors.New("too many requests, retry later")
+	// errUnknownAPIKey ключа API из запроса нет в настройках.
+	errUnknownAPIKey = errors.New("unknown API key")
+)
+
+type apiClientKey struct{}
+
+// SetRateLimits задает хранилище корзин токенов, квоты маршрутов, квоту IP-адреса и ключи API из настроек.
+// Маршрут задается как в описании API, например "GET /robots", квота ratelimit.DefaultRoute действует
+// для остальных маршрутов. По умолчанию число запросов не ограничено.
+func (h *Handler) SetRateLimits(storage ratelimit.Storage, cfg ratelimit.Config) error {
+	for route := range cfg.Routes {
+		if _, ok := apiDocs[route]; !ok && route != ratelimit.DefaultRoute {
+			return fmt.Errorf("%w: there is no route %q", ratelimit.ErrInvalidQuota, route)
+		}
+	}
+
+	for key, name := range cfg.APIKeys {
+		if key == "" || name == "" {
+			return fmt.Errorf("%w: API key and its client name can't be empty", ratelimit.ErrInvalidQuota)
+		}
+	}
+
+	h.rateLimits = storage
+	h.quotas = cfg.Routes
+	h.ipQuota = cfg.IP
+	h.apiKeys = cfg.APIKeys
+
+	return nil
+}
+
+// clientRateLimited расходует квоту IP-адреса и определяет клиента по ключу API из заголовка X-API-Key.
+// Middleware идет до authentication, поэтому запросы без токена, с неверным токеном или неизвестным ключом API
+// тоже ограничены. Запрос с неизвестным ключом API получает 401.
+func (h *Handler) clientRateLimited(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.ipQuota.Requests > 0 && !h.limit(w, r, ipClient(r.RemoteAddr), ipRoute, h.ipQuota) {
+			return
+		}
+
+		ctx, err := h.withAPIClient(r.Context(), r.Header.Get(apiKeyHeader))
+		if err != nil {
+			h.logger.Warnw("unknown API key", "trackingID", middleware.GetReqID(r.Context()), "RealIP", r.RemoteAddr)
+			sendError(w, err.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withAPIClient возвращает ctx с именем клиента ключа API key. Пустой ключ ctx не меняет.
+func (h *Handler) withAPIClient(ctx context.Context, key string) (context.Context, error) {
+	if key == "" {
+		return ctx, nil
+	}
+
+	name, ok := h.apiKeys[key]
+	if !ok {
+		return nil, errUnknownAPIKey
+	}
+
+	return context.WithValue(ctx, apiClientKey{}, name), nil
+}
+
+// rateLimited ограничивает число запросов клиента к маршруту корзиной токенов. У маршрута со своей квотой
+// своя корзина, остальные маршруты делят корзину квоты по умолчанию. Клиент определяется ключом API,
+// затем пользователем сессии, поэтому middleware идет после authentication, а для анонимных запросов — IP-адресом.
+// Ответ содержит заголовки RateLimit-*, при превышении квоты — 429 и Retry-After.
+func (h *Handler) rateLimited(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, q, ok := h.quotaFor(r)
+		if ok && !h.limit(w, r, rateLimitClient(r.Context(), r.RemoteAddr), route, q) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limit расходует токен корзины клиента client на маршруте route и отправляет заголовки RateLimit-*.
+// Если квота превышена, отправляет 429 и возвращает false.
+func (h *Handler) limit(w http.ResponseWriter, r *http.Request, client, route string, q ratelimit.Quota) bool {
+	res, ok := h.take(client, route, q)
+	if !ok {
+		return true
+	}
+
+	header := w.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", q.Requests, seconds(q.Period)))
+
+	if !res.Allowed {
+		header.Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+		h.fail(w, r, "rate limit exceeded", fmt.Errorf("%w: %s", errRateLimited, route))
+
+		return false
+	}
+
+	return true
+}
+
+// take забирает токен из корзины клиента client на маршруте route. ok равен false, если хранилище квот
+// недоступно: такой запрос пропускается, чтобы сбой хранилища не останавливал API.
+func (h *Handler) take(client, route string, q ratelimit.Quota) (ratelimit.Result, bool) {
+	res, err := h.rateLimits.Take(client+" "+route, q, time.Now())
+	if err != nil {
+		h.logger.Warnw("func rateLimits.Take return with error", "error", err, "route", route)
+		return ratelimit.Result{}, false
+	}
+
+	return res, true
+}
+
+// quotaFor возвращает маршрут запроса и его квоту. Для маршрута без своей квоты возвращается
+// ratelimit.DefaultRoute и квота по умолчанию, ok равен false, если квоты нет.
+func (h *Handler) quotaFor(r *http.Request) (string, ratelimit.Quota, bool) {
+	route := ""
+
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		match := chi.NewRouteContext()
+		if rctx.Routes.Match(match, r.Method, r.URL.Path) {
+			route = r.Method + " " + routePath(match.RoutePattern())
+		}
+	}
+
+	return h.routeQuota(route)
+}
+
+// routeQuota возвращает квоту маршрута route, а если своей квоты у него нет, — ratelimit.DefaultRoute
+// и квоту по умолчанию. ok равен false, если квоты нет.
+func (h *Handler) routeQuota(route string) (string, ratelimit.Quota, bool) {
+	if q, ok := h.quotas[route]; ok {
+		return route, q, true
+	}
+
+	q, ok := h.quotas[ratelimit.DefaultRoute]
+
+	return ratelimit.DefaultRoute, q, ok
+}
+
+// chargeRoute расходует токен корзины маршрута route, если у маршрута своя квота. Так резолверы GraphQL,
+// которые повторяют маршруты REST API, не обходят их квоты. Запрос без своей квоты уже учтен квотой POST /graphql.
+func (h *Handler) chargeRoute(ctx context.Context, route string) error {
+	q, ok := h.quotas[route]
+	if !ok {
+		return nil
+	}
+
+	res, ok := h.take(rateLimitClient(ctx, remoteAddr(ctx)), route, q)
+	if ok && !res.Allowed {
+		return fmt.Errorf("%w: %s, retry after %ds", errRateLimited, route, seconds(res.RetryAfter))
+	}
+
+	return nil
+}
+
+// grpcRoutes маршруты REST API, квоты которых расходуют методы gRPC. Метод без маршрута расходует
+// квоту по умолчанию.
+var grpcRoutes = map[string]string{
+	"/robotapi.UserService/SignUp":             "POST /signup",
+	"/robotapi.UserService/SignIn":             "POST /signin",
+	"/robotapi.UserService/GetUser":            "GET /users/{id}",
+	"/robotapi.UserService/UpdateUser":         "PUT /users/{id}",
+	"/robotapi.RobotService/ListRobots":        "GET /robots",
+	"/robotapi.RobotService/ListDeletedRobots": "GET /users/{id}/robots/deleted",
+	"/robotapi.RobotService/Leaderboard":       "GET /robots/leaderboard",
+	"/robotapi.RobotService/CreateRobot":       "POST /robot",
+	"/robotapi.RobotService/GetRobot":          "GET /robot/{id}",
+	"/robotapi.RobotService/EditRobot":         "PUT /robot/{id}",
+	"/robotapi.RobotService/DeleteRobot":       "DELETE /robot/{id}",
+	"/robotapi.RobotService/RestoreRobot":      "PUT /robot/{id}/restore",
+	"/robotapi.RobotService/PurgeRobot":        "DELETE /robot/{id}/purge",
+	"/robotapi.RobotService/ActivateRobot":     "PUT /robot/{id}/activate",
+	"/robotapi.RobotService/DeactivateRobot":   "PUT /robot/{id}/deactivate",
+	"/robotapi.RobotService/StopRobot":         "PUT /robot/{id}/stop",
+	"/robotapi.RobotService/FavouriteRobot":    "PUT /robot/{id}/favourite",
+	"/robotapi.RobotService/UnfollowRobot":     "PUT /robot/{id}/unfollow",
+	"/robotapi.RobotService/CloneRobot":        "POST /robot/{id}/clone",
+	"/robotapi.RobotService/RobotTransitions":  "GET /robot/{id}/transitions",
+	"/robotapi.RobotService/RobotVersions":     "GET /robot/{id}/versions",
+	"/robotapi.RobotService/RobotDeals":        "GET /robot/{id}/deals",
+	"/robotapi.RobotService/RobotStats":        "GET /robot/{id}/stats",
+	"/robotapi.RobotService/RobotShares":       "GET /robot/{id}/shares",
+	"/robotapi.RobotService/ShareRobot":        "PUT /robot/{id}/shares/{userID}",
+	"/robotapi.RobotService/UnshareRobot":      "DELETE /robot/{id}/shares/{userID}",
+	"/robotapi.RobotService/WatchRobot":        "GET /wsrobotdetail",
+}
+
+// unaryClientRateLimit расходует квоту IP-адреса и определяет клиента по ключу API из метаданных, как
+// clientRateLimited. Перехватчик идет до unaryAuthentication.
+func (h *Handler) unaryClientRateLimit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := h.grpcClientRateLimit(ctx, func(md metadata.MD) error {
+		return grpc.SetHeader(ctx, md)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// streamClientRateLimit расходует квоту IP-адреса и определяет клиента потока до streamAuthentication.
+func (h *Handler) streamClientRateLimit(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	ctx, err := h.grpcClientRateLimit(ss.Context(), ss.SetHeader)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// grpcClientRateLimit расходует токен корзины IP-адреса и возвращает ctx с клиентом ключа API из метаданных.
+func (h *Handler) grpcClientRateLimit(ctx context.Context, setHeader func(metadata.MD) error) (context.Context, error) {
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(apiKeyMetadata); len(values) > 0 {
+			key = values[0]
+		}
+	}
+
+	if h.ipQuota.Requests > 0 {
+		if err := h.grpcLimit(ctx, ipClient(remoteAddr(ctx)), ipRoute, h.ipQuota, setHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	clientCtx, err := h.withAPIClient(ctx, key)
+	if err != nil {
+		h.logger.Warnw("unknown API key", "RealIP", remoteAddr(ctx))
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return clientCtx, nil
+}
+
+// unaryRateLimit ограничивает вызовы gRPC теми же корзинами, что и запросы REST API. Перехватчик идет после
+// unaryAuthentication, чтобы клиентом был пользователь сессии.
+func (h *Handler) unaryRateLimit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if err := h.grpcRateLimit(ctx, info.FullMethod, func(md metadata.MD) error {
+		return grpc.SetHeader(ctx, md)
+	}); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// streamRateLimit ограничивает открытие потоков gRPC.
+func (h *Handler) streamRateLimit(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	if err := h.grpcRateLimit(ss.Context(), info.FullMethod, ss.SetHeader); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+// grpcRateLimit расходует токен маршрута метода method.
+func (h *Handler) grpcRateLimit(ctx context.Context, method string, setHeader func(metadata.MD) error) error {
+	route, q, ok := h.routeQuota(grpcRoutes[method])
+	if !ok {
+		return nil
+	}
+
+	return h.grpcLimit(ctx, rateLimitClient(ctx, remoteAddr(ctx)), route, q, setHeader)
+}
+
+// grpcLimit расходует токен корзины клиента client на маршруте route. При превышении квоты возвращает
+// ResourceExhausted и передает через setHeader метаданные retry-after, как заголовок Retry-After в REST API.
+func (h *Handler) grpcLimit(ctx context.Context, client, route string, q ratelimit.Quota,
+	setHeader func(metadata.MD) error) error {
+	res, ok := h.take(client, route, q)
+	if !ok || res.Allowed {
+		return nil
+	}
+
+	_ = setHeader(metadata.Pairs("retry-after", strconv.Itoa(seconds(res.RetryAfter))))
+
+	return h.grpcError(ctx, "rate limit exceeded", fmt.Errorf("%w: %s", errRateLimited, route))
+}
+
+// rateLimitClient возвращает ключ клиента: клиента ключа API, пользователя сессии из ctx или IP-адрес addr
+// анонимного запроса.
+func rateLimitClient(ctx context.Context, addr string) string {
+	if name, ok := ctx.Value(apiClientKey{}).(string); ok {
+		return "key:" + name
+	}
+
+	if token, ok := ctx.Value(tokenKey{}).(string); ok {
+		sessionToken, _ := session.DecodeToken(token)
+		return "user:" + strconv.Itoa(sessionToken.UserID)
+	}
+
+	return ipClient(addr)
+}
+
+// ipClient возвращает ключ клиента по адресу соединения addr. За обратным прокси все клиенты попадают
+// в одну корзину с адресом прокси. Заголовкам X-Forwarded-For и X-Real-IP ключ не доверяет: их задает сам клиент.
+func ipClient(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	return "ip:" + host
+}
+
+// seconds округляет d вверх до целых секунд, как принято в заголовках RateLimit-* и Retry-After.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
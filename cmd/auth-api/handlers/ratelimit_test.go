@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/ratelimit"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/robotapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimited(t *testing.T) {
+	type testCase struct {
+		Name              string
+		Token             string
+		Path              string
+		ExpectedCode      int
+		ExpectedRemaining string
+	}
+
+	assert := assert.New(t)
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(),
+		robot.CreateStorageInMemory(), nil, nopPublisher{}, &fakeInstruments{})
+
+	err := h.SetRateLimits(ratelimit.CreateStorageInMemory(), ratelimit.Config{Routes: map[string]ratelimit.Quota{
+		ratelimit.DefaultRoute: {Requests: 3, Period: time.Minute},
+		"GET /robots":          {Requests: 2, Period: time.Minute},
+	}})
+	if !assert.NoError(err) {
+		return
+	}
+
+	setupSignUp(h, t)
+	first := "Bearer " + setupUser(h, t, "second@example.com").Token
+	second := "Bearer " + setupUser(h, t, "third@example.com").Token
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	testCases := []testCase{
+		{Name: "Catalog", Token: first, Path: "/api/v1/robots", ExpectedCode: http.StatusOK, ExpectedRemaining: "1"},
+		{Name: "Catalog again", Token: first, Path: "/api/v1/robots", ExpectedCode: http.StatusOK,
+			ExpectedRemaining: "0"},
+		{Name: "Catalog quota exceeded", Token: first, Path: "/api/v1/robots", ExpectedCode: http.StatusTooManyRequests,
+			ExpectedRemaining: "0"},
+		{Name: "Other user has own bucket", Token: second, Path: "/api/v1/robots", ExpectedCode: http.StatusOK,
+			ExpectedRemaining: "1"},
+		{Name: "Other route uses default quota", Token: first, Path: "/api/v1/templates", ExpectedCode: http.StatusOK,
+			ExpectedRemaining: "2"},
+		{Name: "Routes without own quota share bucket", Token: first, Path: "/api/v1/users/1",
+			ExpectedCode: http.StatusOK, ExpectedRemaining: "1"},
+		{Name: "Anonymous requests are limited by IP", Path: "/api/v1/openapi.json", ExpectedCode: http.StatusOK,
+			ExpectedRemaining: "2"},
+	}
+
+	for _, tc := range testCases {
+		resp, code := testRequestWithAuth(t, ts, http.MethodGet, tc.Path, tc.Token, nil)
+		resp.Body.Close()
+
+		assert.Equal(tc.ExpectedCode, code, tc.Name)
+		assert.Equal(tc.ExpectedRemaining, resp.Header.Get("RateLimit-Remaining"), tc.Name)
+
+		if code == http.StatusTooManyRequests {
+			assert.Equal("30", resp.Header.Get("Retry-After"), tc.Name)
+			assert.Equal("2;w=60", resp.Header.Get("RateLimit-Policy"), tc.Name)
+			assert.Equal(problemJSON, resp.Header.Get("Content-Type"), tc.Name)
+		}
+	}
+
+	err = h.SetRateLimits(ratelimit.CreateStorageInMemory(), ratelimit.Config{Routes: map[string]ratelimit.Quota{
+		"GET /nope": {Requests: 1, Period: time.Second},
+	}})
+	assert.True(errors.Is(err, ratelimit.ErrInvalidQuota), "error: %v", err)
+}
+
+func TestRateLimitedBeforeAuthentication(t *testing.T) {
+	assert := assert.New(t)
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(),
+		robot.CreateStorageInMemory(), nil, nopPublisher{}, &fakeInstruments{})
+
+	err := h.SetRateLimits(ratelimit.CreateStorageInMemory(),
+		ratelimit.Config{IP: ratelimit.Quota{Requests: 2, Period: time.Minute}})
+	if !assert.NoError(err) {
+		return
+	}
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	for _, expected := range []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusTooManyRequests} {
+		resp, code := testRequestWithAuth(t, ts, http.MethodGet, "/api/v1/robots", "Bearer invalid", nil)
+		resp.Body.Close()
+
+		assert.Equal(expected, code, "requests with invalid tokens use the IP quota")
+
+		if code == http.StatusTooManyRequests {
+			assert.Equal("30", resp.Header.Get("Retry-After"))
+			assert.Equal("2;w=60", resp.Header.Get("RateLimit-Policy"))
+		}
+	}
+}
+
+func TestRateLimitedByAPIKey(t *testing.T) {
+	type testCase struct {
+		Name         string
+		Token        string
+		APIKey       string
+		ExpectedCode int
+	}
+
+	assert := assert.New(t)
+	h := NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(),
+		robot.CreateStorageInMemory(), nil, nopPublisher{}, &fakeInstruments{})
+
+	err := h.SetRateLimits(ratelimit.CreateStorageInMemory(), ratelimit.Config{
+		Routes:  map[string]ratelimit.Quota{"GET /robots": {Requests: 1, Period: time.Minute}},
+		APIKeys: map[string]string{"export-key": "export"},
+	})
+	if !assert.NoError(err) {
+		return
+	}
+
+	setupSignUp(h, t)
+	first := "Bearer " + setupUser(h, t, "second@example.com").Token
+	second := "Bearer " + setupUser(h, t, "third@example.com").Token
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	testCases := []testCase{
+		{Name: "API key", Token: first, APIKey: "export-key", ExpectedCode: http.StatusOK},
+		{Name: "User without API key has own bucket", Token: first, ExpectedCode: http.StatusOK},
+		{Name: "API key is preferred to user", Token: second, APIKey: "export-key",
+			ExpectedCode: http.StatusTooManyRequests},
+		{Name: "Unknown API key", Token: second, APIKey: "nope", ExpectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/robots", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", tc.Token)
+
+		if tc.APIKey != "" {
+			req.Header.Set(apiKeyHeader, tc.APIKey)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+		assert.Equal(tc.ExpectedCode, resp.StatusCode, tc.Name)
+	}
+
+	err = h.SetRateLimits(ratelimit.CreateStorageInMemory(), ratelimit.Config{APIKeys: map[string]string{"key": ""}})
+	assert.True(errors.Is(err, ratelimit.ErrInvalidQuota), "error: %v", err)
+}
+
+func TestGRPCRateLimitedBeforeAuthentication(t *testing.T) {
+	assert := assert.New(t)
+	h, clients := setupGRPCHandler(t)
+
+	err := h.SetRateLimits(ratelimit.CreateStorageInMemory(), ratelimit.Config{
+		IP:      ratelimit.Quota{Requests: 2, Period: time.Minute},
+		APIKeys: map[string]string{"export-key": "export"},
+	})
+	if !assert.NoError(err) {
+		return
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "nope")
+	_, err = clients.robots.ListRobots(ctx, &robotapi.ListRobotsRequest{})
+	assert.Equal(codes.Unauthenticated, status.Code(err), "unknown API key")
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), authMetadata, "Bearer invalid")
+	_, err = clients.robots.ListRobots(ctx, &robotapi.ListRobotsRequest{})
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+
+	var header metadata.MD
+
+	_, err = clients.robots.ListRobots(ctx, &robotapi.ListRobotsRequest{}, grpc.Header(&header))
+	assert.Equal(codes.ResourceExhausted, status.Code(err), "calls with invalid tokens use the IP quota")
+	assert.Equal([]string{"30"}, header.Get("retry-after"))
+}
+
+func TestGRPCRateLimited(t *testing.T) {
+	assert := assert.New(t)
+	h, clients := setupGRPCHandler(t)
+
+	err := h.SetRateLimits(ratelimit.CreateStorageInMemory(), ratelimit.Config{Routes: map[string]ratelimit.Quota{
+		ratelimit.DefaultRoute: {Requests: 100, Period: time.Minute},
+		"GET /robots":          {Requests: 2, Period: time.Minute},
+	}})
+	if !assert.NoError(err) {
+		return
+	}
+
+	ctx := grpcSignIn(t, clients, "owner@example.com")
+
+	_, err = clients.robots.ListRobots(ctx, &robotapi.ListRobotsRequest{})
+	assert.NoError(err)
+
+	ts := httptest.NewServer(h.Routes())
+	defer ts.Close()
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	resp, code := testRequestWithAuth(t, ts, http.MethodGet, "/api/v1/robots", md.Get(authMetadata)[0], nil)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, code, "REST API and gRPC share the bucket")
+	assert.Equal("0", resp.Header.Get("RateLimit-Remaining"))
+
+	var header metadata.MD
+
+	_, err = clients.robots.ListRobots(ctx, &robotapi.ListRobotsRequest{}, grpc.Header(&header))
+	assert.Equal(codes.ResourceExhausted, status.Code(err))
+	assert.Equal([]string{"30"}, header.Get("retry-after"))
+
+	apiCode, _ := problemCode(err)
+	assert.Equal("rate_limited", apiCode)
+
+	_, err = clients.users.GetUser(ctx, &robotapi.GetUserRequest{})
+	assert.NoError(err, "other methods use the default quota")
+}
+
+func TestGRPCRoutes(t *testing.T) {
+	services := robotapi.File_pkg_robotapi_robotapi_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := "/" + string(services.Get(i).FullName()) + "/" + string(methods.Get(j).Name())
+
+			route, ok := grpcRoutes[method]
+			if assert.True(t, ok, "method %s has no route", method) {
+				_, ok = apiDocs[route]
+				assert.True(t, ok, "method %s uses unknown route %s", method, route)
+			}
+		}
+	}
+}
+
+func TestGraphQLRateLimited(t *testing.T) {
+	assert := assert.New(t)
+	h, _, _, ts := setupGraphQL(t)
+	owner := setupUser(h, t, "owner@example.com")
+
+	err := h.SetRateLimits(ratelimit.CreateStorageInMemory(), ratelimit.Config{Routes: map[string]ratelimit.Quota{
+		ratelimit.DefaultRoute: {Requests: 100, Period: time.Minute},
+		"GET /robots":          {Requests: 2, Period: time.Minute},
+	}})
+	if !assert.NoError(err) {
+		return
+	}
+
+	resp := graphqlQuery(t, ts, owner.Token, `{ robots { nextCursor } }`)
+	assert.Empty(resp.Errors)
+
+	resp = graphqlQuery(t, ts, owner.Token, `{ me { robots { nextCursor } } }`)
+	assert.Empty(resp.Errors, "user robots have no own quota")
+
+	rest, code := testRequestWithAuth(t, ts, http.MethodGet, "/api/v1/robots", "Bearer "+owner.Token, nil)
+	rest.Body.Close()
+	assert.Equal(http.StatusOK, code, "REST API and GraphQL share the bucket")
+
+	resp = graphqlQuery(t, ts, owner.Token, `{ robots { nextCursor } }`)
+	assert.Equal([]interface{}{"rate_limited"}, resp.errorCodes())
+	assert.JSONEq(`null`, string(resp.Data))
+}
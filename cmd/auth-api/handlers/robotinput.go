@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+// readOnlyRobotFields поля представления робота, которые задает сервис. В запросе они возвращаются
+// как ошибки полей "is read-only", чтобы клиент отличал их от опечаток в названиях полей.
+var readOnlyRobotFields = []string{
+	"robot_id", "parent_robot_id", "cloned_from_id", "template_id", "is_favourite", //nolint:misspell
+	"is_active", "status", "fact_yield", "deals_count", "version", "is_mirror", "followers", "activated_at",
+	"deactivated_at", "created_at", "deleted_at", "actions",
+}
+
+// robotInput параметры робота, которые пользователь задает при создании и изменении. Поля, которых нет
+// в запросе, равны nil. Дату плана сбрасывает пустая строка, как в ответе сервиса.
+type robotInput struct {
+	OwnerUserID *int              `json:"owner_user_id"`
+	Ticker      *string           `json:"ticker"`
+	BuyPrice    *float64          `json:"buy_price"`
+	SellPrice   *float64          `json:"sell_price"`
+	PlanStart   *robot.NullTime   `json:"plan_start"`
+	PlanEnd     *robot.NullTime   `json:"plan_end"`
+	PlanYield   *float64          `json:"plan_yield"`
+	AutoClose   *bool             `json:"auto_close"`
+	Schedule    *string           `json:"schedule"`
+	Lots        *int              `json:"lots"`
+	Visibility  *robot.Visibility `json:"visibility"`
+}
+
+// UnmarshalJSON строго разбирает параметры робота. Поля только для чтения, неизвестные поля и значения
+// неверного типа возвращаются как *validation.Error.
+func (in *robotInput) UnmarshalJSON(b []byte) error {
+	var keys map[string]json.RawMessage
+	if err := validation.DecodeJSON(bytes.NewReader(b), &keys); err != nil {
+		return err
+	}
+
+	var f validation.Fields
+
+	for _, name := range readOnlyRobotFields {
+		if _, ok := keys[name]; ok {
+			f.Add(name, "is read-only")
+		}
+	}
+
+	if err := f.Err(validation.ErrInvalidInput); err != nil {
+		return err
+	}
+
+	type alias robotInput
+
+	return validation.DecodeJSON(bytes.NewReader(b), (*alias)(in))
+}
+
+// withoutOwner возвращает ошибку, если в запросе есть владелец. Владельца задает только создание робота,
+// у существующего робота, клона и робота из шаблона он не меняется.
+func (in *robotInput) withoutOwner() error {
+	if in.OwnerUserID != nil {
+		return validation.Field(validation.ErrInvalidInput, "owner_user_id", "is read-only")
+	}
+
+	return nil
+}
+
+// apply переносит в rob параметры, которые есть в запросе.
+func (in *robotInput) apply(rob *robot.Robot) {
+	if in.OwnerUserID != nil {
+		rob.OwnerUserID = *in.OwnerUserID
+	}
+
+	if in.Ticker != nil {
+		rob.Ticker = *in.Ticker
+	}
+
+	if in.BuyPrice != nil {
+		rob.BuyPrice = *in.BuyPrice
+	}
+
+	if in.SellPrice != nil {
+		rob.SellPrice = *in.SellPrice
+	}
+
+	if in.PlanStart != nil {
+		rob.PlanStart = *in.PlanStart
+	}
+
+	if in.PlanEnd != nil {
+		rob.PlanEnd = *in.PlanEnd
+	}
+
+	if in.PlanYield != nil {
+		rob.PlanYield = *in.PlanYield
+	}
+
+	if in.AutoClose != nil {
+		rob.AutoClose = *in.AutoClose
+	}
+
+	if in.Schedule != nil {
+		rob.Schedule = *in.Schedule
+	}
+
+	if in.Lots != nil {
+		rob.Lots = *in.Lots
+	}
+
+	if in.Visibility != nil {
+		rob.Visibility = *in.Visibility
+	}
+}
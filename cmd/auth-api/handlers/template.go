@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"gitlab.com/hitchpock/tfs-course-work/internal/calendar"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+// instantiateRequest тело запроса на создание роботов из шаблона: каждый элемент robots
+// переопределяет поля шаблона, например тикер.
+type instantiateRequest struct {
+	Atomic bool              `json:"atomic"`
+	Robots []json.RawMessage `json:"robots"`
+}
+
+// CreateTemplate сохраняет шаблон робота пользователя.
+func (h *Handler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	token := r.Context().Value(tokenKey{}).(string)
+	sessionToken, _ := session.DecodeToken(token)
+
+	var tmpl robot.Template
+	if err := validation.DecodeJSON(r.Body, &tmpl); err != nil {
+		h.fail(w, r, "can't decode template", err)
+		return
+	}
+	defer r.Body.Close()
+
+	tmpl.OwnerUserID = sessionToken.UserID
+
+	if tmpl.Schedule != "" {
+		if _, err := calendar.ParseRecurring(tmpl.Schedule); err != nil {
+			h.fail(w, r, "invalid schedule", validation.Field(robot.ErrInvalidRobot, "schedule", "is not a valid schedule"))
+			return
+		}
+	}
+
+	if err := h.robotStorage.CreateTemplate(&tmpl); err != nil {
+		h.fail(w, r, "func robotStorage.CreateTemplate return with error", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, tmpl, reqID, remoteAddr)
+}
+
+// Templates отправляет шаблоны пользователя.
+func (h *Handler) Templates(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	token := r.Context().Value(tokenKey{}).(string)
+	sessionToken, _ := session.DecodeToken(token)
+
+	templates, err := h.robotStorage.FindTemplates(sessionToken.UserID)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FindTemplates return with error", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, templates, reqID, remoteAddr)
+}
+
+// Template отправляет шаблон пользователя.
+func (h *Handler) Template(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	templateID := r.Context().Value(idKey{}).(int)
+
+	tmpl, ok := h.ownTemplate(w, r, templateID)
+	if !ok {
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tmpl, reqID, remoteAddr)
+}
+
+// DeleteTemplate удаляет шаблон пользователя, созданные из него роботы остаются.
+func (h *Handler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID := r.Context().Value(idKey{}).(int)
+
+	if _, ok := h.ownTemplate(w, r, templateID); !ok {
+		return
+	}
+
+	if err := h.robotStorage.DeleteTemplate(templateID); err != nil {
+		h.fail(w, r, "func robotStorage.DeleteTemplate return with error", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// InstantiateTemplate создает из шаблона пакет роботов, каждый со своими переопределениями полей.
+// Ответ такой же, как у пакетного создания роботов.
+func (h *Handler) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID := r.Context().Value(idKey{}).(int)
+
+	tmpl, ok := h.ownTemplate(w, r, templateID)
+	if !ok {
+		return
+	}
+
+	var req instantiateRequest
+	if err := decodeBatch(r, &req, func() int { return len(req.Robots) }); err != nil {
+		h.fail(w, r, "invalid batch", err)
+		return
+	}
+
+	robots := make([]*robot.Robot, len(req.Robots))
+	errs := make([]error, len(req.Robots))
+
+	for i, overrides := range req.Robots {
+		rob, err := h.newRobotFrom(r.Context(), tmpl.NewRobot(), overrides)
+		if err != nil {
+			rob = &robot.Robot{}
+			errs[i] = err
+		}
+
+		robots[i] = rob
+	}
+
+	h.createRobots(w, r, tmpl.OwnerUserID, req.Atomic, robots, errs)
+}
+
+// CloneRobot создает копию робота пользователя. Тело запроса, если есть, переопределяет поля копии.
+// Источник копии хранится в cloned_from_id.
+func (h *Handler) CloneRobot(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	remoteAddr := r.RemoteAddr
+	robotID := r.Context().Value(idKey{}).(int)
+
+	src, ok := h.ownRobot(w, r, robotID)
+	if !ok {
+		return
+	}
+
+	var overrides json.RawMessage
+	if err := validation.DecodeJSON(r.Body, &overrides); err != nil && !errors.Is(err, validation.ErrEmptyBody) {
+		h.fail(w, r, "can't decode overrides", err)
+		return
+	}
+	defer r.Body.Close()
+
+	clone, err := h.newRobotFrom(r.Context(), src.Clone(), overrides)
+	if err != nil {
+		h.fail(w, r, "invalid clone", err)
+		return
+	}
+
+	if err = h.robotStorage.Create(clone); err != nil {
+		h.fail(w, r, "func robotStorage.Create return with error", err)
+		return
+	}
+
+	h.logger.Infow("clone robot", "robotID", robotID, "cloneID", clone.RobotID, "trackingID", reqID, "RealIP", remoteAddr)
+	h.writeJSON(w, http.StatusCreated, clone, reqID, remoteAddr)
+}
+
+// ownTemplate находит шаблон и проверяет, что он принадлежит пользователю из токена.
+func (h *Handler) ownTemplate(w http.ResponseWriter, r *http.Request, templateID int) (*robot.Template, bool) {
+	token := r.Context().Value(tokenKey{}).(string)
+	sessionToken, _ := session.DecodeToken(token)
+
+	tmpl, err := h.robotStorage.FindTemplateByID(templateID)
+	if err != nil {
+		h.fail(w, r, "func robotStorage.FindTemplateByID return with error", err)
+		return nil, false
+	}
+
+	if tmpl.OwnerUserID != sessionToken.UserID {
+		h.fail(w, r, "user have no permission",
+			fmt.Errorf("%w: user %d doesn't own template %d", errForbidden, sessionToken.UserID, templateID))
+
+		return nil, false
+	}
+
+	return tmpl, true
+}
+
+// newRobotFrom возвращает нового робота владельца base с полями из overrides и проверяет его, как CreateRobot.
+// Связь с шаблоном или исходным роботом берется из base, а не из overrides.
+func (h *Handler) newRobotFrom(ctx context.Context, base robot.Robot, overrides json.RawMessage) (*robot.Robot, error) {
+	rob := base
+
+	if len(overrides) > 0 && string(overrides) != "null" {
+		var in robotInput
+		if err := validation.DecodeJSON(bytes.NewReader(overrides), &in); err != nil {
+			return nil, err
+		}
+
+		if err := in.withoutOwner(); err != nil {
+			return nil, err
+		}
+
+		in.apply(&rob)
+	}
+
+	if err := h.prepareNewRobot(ctx, base.OwnerUserID, &rob); err != nil {
+		return nil, err
+	}
+
+	rob.ClonedFromID = base.ClonedFromID
+	rob.TemplateID = base.TemplateID
+
+	return &rob, nil
+}
@@ -1,32 +1,44 @@
 package handlers
 
 import (
-	"io"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
 )
 
+// WSClient подписчик websocket: пользователь, робот, за которым он следит, и токен ссылки на робота.
 type WSClient struct {
-	conn    *websocket.Conn
-	robotID int
+	conn       *websocket.Conn
+	userID     int
+	robotID    int
+	shareToken string
 }
 
 type WSClients struct {
 	clients      map[int]*WSClient
+	watchers     map[int]*watcher
 	mutex        sync.Mutex
 	nextID       int
 	robotStorage robot.Storage
 }
 
+// watcher подписчик gRPC WatchRobot или подписки GraphQL robotChanged: робот и канал сигналов о его изменениях.
+type watcher struct {
+	robotID int
+	changed chan struct{}
+}
+
 func NewWebsocket(robotStorage robot.Storage) *WSClients {
 	ws := &WSClients{
 		clients:      make(map[int]*WSClient),
+		watchers:     make(map[int]*watcher),
 		mutex:        sync.Mutex{},
 		robotStorage: robotStorage,
 	}
@@ -44,26 +56,112 @@ func (c *WSClients) addClient(client *WSClient) int {
 	return c.nextID
 }
 
-func (c *WSClients) setRobot(clientID, robotID int) {
+func (c *WSClients) setRobot(clientID, robotID int, shareToken string) {
 	c.mutex.Lock()
-	c.clients[clientID].robotID = robotID
+	if client, ok := c.clients[clientID]; ok {
+		client.robotID = robotID
+		client.shareToken = shareToken
+	}
 	c.mutex.Unlock()
 }
 
-func (c *WSClients) Broadcast(robotID int) {
-	rob, err := c.robotStorage.FindByID(robotID)
-	if err != nil {
+// watch подписывает на изменения робота robotID. Канал получает сигнал после каждого Broadcast робота,
+// несколько изменений подряд могут прийти одним сигналом. Функция stop отменяет подписку.
+func (c *WSClients) watch(robotID int) (changed <-chan struct{}, stop func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.nextID++
+	id := c.nextID
+	w := &watcher{robotID: robotID, changed: make(chan struct{}, 1)}
+	c.watchers[id] = w
+
+	return w.changed, func() {
+		c.mutex.Lock()
+		delete(c.watchers, id)
+		c.mutex.Unlock()
+	}
+}
+
+// subscribe подписывает клиента на робота, если пользователь его видит.
+func (c *WSClients) subscribe(clientID, userID, robotID int, shareToken string) {
+	if _, err := c.robotStorage.FindVisible(robotID, userID, shareToken); err != nil {
+		c.send(clientID, errorMessage{Error: "robot not found"})
 		return
 	}
 
+	c.setRobot(clientID, robotID, shareToken)
+	c.Broadcast(robotID)
+}
+
+// errorMessage сообщение клиенту websocket об ошибке.
+type errorMessage struct {
+	Error string `json:"error"`
+}
+
+// send отправляет сообщение одному клиенту.
+func (c *WSClients) send(clientID int, v interface{}) {
 	c.mutex.Lock()
-	inactiveClients := make([]int, 0)
+	client, ok := c.clients[clientID]
+
+	var err error
+	if ok {
+		err = client.conn.WriteJSON(v)
+	}
+	c.mutex.Unlock()
+
+	if err != nil {
+		c.removeClients(clientID)
+	}
+}
+
+// Broadcast отправляет робота подписанным на него клиентам и сообщает об изменении подписчикам gRPC.
+// Клиенты, которые больше не видят робота, например после смены его видимости, отписываются от него.
+func (c *WSClients) Broadcast(robotID int) {
+	c.mutex.Lock()
+	for _, w := range c.watchers {
+		if w.robotID == robotID {
+			select {
+			case w.changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	subscribers := make(map[int]WSClient)
 
 	for id, client := range c.clients {
 		if client.robotID == robotID {
-			if err := client.conn.WriteJSON(rob); err != nil {
-				inactiveClients = append(inactiveClients, id)
-			}
+			subscribers[id] = *client
+		}
+	}
+	c.mutex.Unlock()
+
+	messages := make(map[int]interface{}, len(subscribers))
+
+	for id, client := range subscribers {
+		rob, err := c.robotStorage.FindVisible(robotID, client.userID, client.shareToken)
+		if err != nil {
+			c.setRobot(id, 0, "")
+			messages[id] = errorMessage{Error: "robot not found"}
+
+			continue
+		}
+
+		messages[id] = rob
+	}
+
+	c.mutex.Lock()
+	inactiveClients := make([]int, 0)
+
+	for id, msg := range messages {
+		client, ok := c.clients[id]
+		if !ok {
+			continue
+		}
+
+		if err := client.conn.WriteJSON(msg); err != nil {
+			inactiveClients = append(inactiveClients, id)
 		}
 	}
 
@@ -76,12 +174,19 @@ func (c *WSClients) removeClients(ids ...int) {
 	defer c.mutex.Unlock()
 
 	for _, id := range ids {
-		c.clients[id].conn.Close()
-		delete(c.clients, id)
+		if client, ok := c.clients[id]; ok {
+			client.conn.Close()
+			delete(c.clients, id)
+		}
 	}
 }
 
+// WSRobotDeltail отправляет пользователю изменения робота. Клиент присылает ID робота,
+// а для робота с доступом по ссылке — ID и токен ссылки через пробел.
 func (c *WSClients) WSRobotDeltail(w http.ResponseWriter, r *http.Request) {
+	token := r.Context().Value(tokenKey{}).(string)
+	sessionToken, _ := session.DecodeToken(token)
+
 	var up websocket.Upgrader
 	up.CheckOrigin = func(r *http.Request) bool {
 		return true
@@ -102,22 +207,31 @@ func (c *WSClients) WSRobotDeltail(w http.ResponseWriter, r *http.Request) {
 		return err
 	})
 
-	client := &WSClient{conn: conn}
+	client := &WSClient{conn: conn, userID: sessionToken.UserID}
 	clientID := c.addClient(client)
 
 	for {
 		_, msg, err := conn.ReadMessage()
 		if err != nil {
-			if err == io.EOF {
-				c.removeClients(clientID)
-				return
-			}
+			c.removeClients(clientID)
+			return
+		}
+
+		fields := strings.Fields(string(msg))
+		if len(fields) == 0 {
+			continue
 		}
 
-		robotID, err := strconv.Atoi(string(msg))
-		if err == nil {
-			c.setRobot(clientID, robotID)
-			c.Broadcast(robotID)
+		robotID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
 		}
+
+		var shareToken string
+		if len(fields) > 1 {
+			shareToken = fields[1]
+		}
+
+		c.subscribe(clientID, sessionToken.UserID, robotID, shareToken)
 	}
 }
@@ -1,20 +1,29 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"io"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi"
 	"gitlab.com/hitchpock/tfs-course-work/cmd/auth-api/handlers"
-	"gitlab.com/hitchpock/tfs-course-work/cmd/auth-api/trading"
+	"gitlab.com/hitchpock/tfs-course-work/cmd/auth-api/retention"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	"gitlab.com/hitchpock/tfs-course-work/internal/idempotency"
 	"gitlab.com/hitchpock/tfs-course-work/internal/postgres"
+	"gitlab.com/hitchpock/tfs-course-work/internal/ratelimit"
 	zp "gitlab.com/hitchpock/tfs-course-work/pkg/log"
 	"google.golang.org/grpc"
 )
 
 const (
-	port = ":8080"
+	port         = ":8080"
+	grpcPort     = ":9090"
+	streamerAddr = "localhost:5000"
 
 	ReadTimeoutInt  = 2
 	WriteTimeoutInt = 2
@@ -23,14 +32,28 @@ const (
 	ConnMaxLifetime = time.Minute
 	MaxOpenConns    = 10
 	MaxIdleConns    = 2
+
+	defaultTrashDays = 30
+	trashCheck       = time.Hour
 )
 
 func main() {
+	trashDays := flag.Int("trash-days", defaultTrashDays, "days to keep deleted robots before purging them")
+	grpcAddr := flag.String("grpc-addr", grpcPort, "address of the gRPC API")
+	idempotencyTTL := flag.Duration("idempotency-ttl", idempotency.DefaultTTL, "how long to replay responses by Idempotency-Key")
+	configPath := flag.String("config", "", "path to JSON config with rate limits, defaults are used without it")
+	flag.Parse()
+
 	cfgDB := configDB()
 
 	logger := zp.NewSugarLogger()
 	defer logger.Sugar.Sync() // nolint:errcheck
 
+	cfg, err := LoadConfig(*configPath)
+	if err != nil {
+		logger.Fatalf("can't load config: %s", err)
+	}
+
 	db, err := postgres.New(logger, cfgDB)
 	if err != nil {
 		logger.Fatalf("can't create db: %s", err)
@@ -63,19 +86,67 @@ func main() {
 
 	defer handleCloser(logger, "robotStorage", robotStorage)
 
-	conn, err := grpc.Dial("localhost:5000", grpc.WithInsecure())
+	idempotencyStorage, err := postgres.NewIdempotencyStorage(db)
+	if err != nil {
+		logger.Fatalf("can't create idempotency storage: %s", err)
+	}
+
+	defer handleCloser(logger, "idempotencyStorage", idempotencyStorage)
+
+	eventBus, err := postgres.NewEventBus(db, cfgDB.URL)
+	if err != nil {
+		logger.Fatalf("can't create event bus: %s", err)
+	}
+
+	defer handleCloser(logger, "eventBus", eventBus)
+
+	conn, err := grpc.Dial(streamerAddr, grpc.WithInsecure())
 	if err != nil {
 		logger.Fatalf("can't create connect to grpc server: %s", err)
 	}
 	defer conn.Close()
 
 	wsocket := handlers.NewWebsocket(robotStorage)
-	handler := handlers.NewHandler(logger, sessionStorage, userStorage, robotStorage, wsocket)
+
+	if err = eventBus.Subscribe(func(e event.Event) { wsocket.Broadcast(e.RobotID) }); err != nil {
+		logger.Fatalf("can't subscribe to robot events: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	trash := retention.NewJob(logger, robotStorage, time.Duration(*trashDays)*24*time.Hour, trashCheck)
+
+	go trash.Start(ctx)
+
+	handler := handlers.NewHandler(logger, sessionStorage, userStorage, robotStorage, wsocket, eventBus,
+		fintech.NewTradingServiceClient(conn))
+	handler.SetIdempotencyStorage(idempotencyStorage, *idempotencyTTL)
+
+	if err = handler.SetRateLimits(ratelimit.CreateStorageInMemory(), *cfg.RateLimits); err != nil {
+		logger.Fatalf("can't set rate limits: %s", err)
+	}
+
+	go handler.PurgeIdempotencyKeys(ctx)
+
 	router := routes(handler)
-	backgroundTrading := trading.NewProcess(conn, logger, robotStorage, wsocket)
 	srv := configServer(router)
 
-	go backgroundTrading.StartTrading()
+	listener, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		logger.Fatalf("can't listen gRPC address %s: %s", *grpcAddr, err)
+	}
+
+	grpcServer := handler.GRPCServer()
+	defer grpcServer.GracefulStop()
+
+	go func() {
+		logger.Infof("gRPC API is run on %s", *grpcAddr)
+
+		if err := grpcServer.Serve(listener); err != nil {
+			logger.Fatalf("gRPC server Serve: %s", err)
+		}
+	}()
 
 	logger.Infof("Application is run on port %s", port)
 
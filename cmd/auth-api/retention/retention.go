@@ -0,0 +1,56 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+// Job окончательно удаляет роботов, которые пролежали в корзине дольше срока хранения.
+type Job struct {
+	logger    log.Logger
+	storage   robot.Storage
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewJob возвращает указатель на задачу, которая раз в interval удаляет роботов старше retention.
+func NewJob(logger log.Logger, storage robot.Storage, retention, interval time.Duration) *Job {
+	return &Job{
+		logger:    logger,
+		storage:   storage,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Start очищает корзину, пока не отменен ctx.
+func (j *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.Run(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run удаляет роботов, помещенных в корзину раньше now минус срок хранения.
+func (j *Job) Run(now time.Time) {
+	n, err := j.storage.PurgeDeleted(now.Add(-j.retention))
+	if err != nil {
+		j.logger.Warnw("func robotStorage.PurgeDeleted return with error", "error", err)
+		return
+	}
+
+	if n > 0 {
+		j.logger.Infow("deleted robots are purged", "count", n)
+	}
+}
@@ -0,0 +1,38 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+	storage := robot.CreateStorageInMemory()
+	j := NewJob(log.NewSugarLogger(), storage, 30*24*time.Hour, time.Hour)
+
+	deleted := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", Lots: 1}
+	kept := &robot.Robot{OwnerUserID: 1, Ticker: "AAPL", Lots: 1}
+
+	for _, r := range []*robot.Robot{deleted, kept} {
+		assert.NoError(storage.Create(r))
+	}
+
+	assert.NoError(storage.SoftDelete(deleted.RobotID, 0))
+
+	j.Run(time.Now().Add(29 * 24 * time.Hour))
+
+	_, err := storage.FindDeletedByID(deleted.RobotID)
+	assert.NoError(err, "robot must stay in trash until retention expires")
+
+	j.Run(time.Now().Add(31 * 24 * time.Hour))
+
+	_, err = storage.FindDeletedByID(deleted.RobotID)
+	assert.Equal(robot.ErrNotFound, err, "robot must be purged after retention")
+
+	_, err = storage.FindByID(kept.RobotID)
+	assert.NoError(err, "robot that isn't deleted must be kept")
+}
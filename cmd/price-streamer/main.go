@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"net"
+
+	"gitlab.com/hitchpock/tfs-course-work/cmd/price-streamer/streamer"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	zp "gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultAddr   = ":5000"
+	defaultConfig = "cmd/price-streamer/config.json"
+)
+
+func main() {
+	addr := flag.String("addr", defaultAddr, "address of grpc server")
+	configPath := flag.String("config", defaultConfig, "path to JSON config with price models")
+	flag.Parse()
+
+	logger := zp.NewSugarLogger()
+	defer logger.Sugar.Sync() // nolint:errcheck
+
+	cfg, err := streamer.LoadConfig(*configPath)
+	if err != nil {
+		logger.Fatalf("can't load config: %s", err)
+	}
+
+	server, err := streamer.NewServer(logger, cfg)
+	if err != nil {
+		logger.Fatalf("can't create price streamer: %s", err)
+	}
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		logger.Fatalf("can't listen %s: %s", *addr, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	fintech.RegisterTradingServiceServer(grpcServer, server)
+
+	logger.Infof("Price streamer is run on %s", *addr)
+
+	if err = grpcServer.Serve(lis); err != nil {
+		logger.Fatalf("grpc server Serve: %s", err)
+	}
+}
@@ -0,0 +1,123 @@
+package streamer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// Поддерживаемые модели цен.
+const (
+	ModelRandomWalk = "random_walk"
+	ModelCSV        = "csv"
+	ModelScenario   = "scenario"
+)
+
+const defaultInterval = Duration(time.Second)
+
+var ErrUnknownModel = errors.New("unknown price model")
+
+// Config структура конфигурации стримера котировок.
+type Config struct {
+	Tickers []TickerConfig `json:"tickers"`
+}
+
+// TickerConfig описывает модель цены и частоту котировок одного тикера.
+type TickerConfig struct {
+	Ticker   string   `json:"ticker"`
+	Name     string   `json:"name"`
+	Model    string   `json:"model"`
+	Interval Duration `json:"interval"`
+
+	// Параметры random_walk.
+	Seed       int64   `json:"seed"`
+	StartPrice float64 `json:"start_price"`
+	Volatility float64 `json:"volatility"`
+	Spread     float64 `json:"spread"`
+
+	// Параметры csv: файл со столбцами buy_price,sell_price.
+	File string `json:"file"`
+	Loop bool   `json:"loop"`
+
+	// Параметры scenario.
+	Steps []Step `json:"steps"`
+}
+
+// Step шаг сценария: котировка, повторенная Repeat раз.
+type Step struct {
+	BuyPrice  float64 `json:"buy_price"`
+	SellPrice float64 `json:"sell_price"`
+	Repeat    int     `json:"repeat"`
+}
+
+// Duration длительность, которая читается из JSON в формате time.ParseDuration.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %s", err)
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %s", s, err)
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+// LoadConfig читает конфигурацию стримера из JSON файла.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("can't read config: %s", err)
+	}
+
+	if err = json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("can't unmarshal config: %s", err)
+	}
+
+	for i := range cfg.Tickers {
+		if err = cfg.Tickers[i].validate(); err != nil {
+			return cfg, fmt.Errorf("invalid ticker %q: %s", cfg.Tickers[i].Ticker, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c *TickerConfig) validate() error {
+	if c.Ticker == "" {
+		return errors.New("ticker is empty")
+	}
+
+	if c.Interval <= 0 {
+		c.Interval = defaultInterval
+	}
+
+	switch c.Model {
+	case ModelRandomWalk:
+		if c.StartPrice <= 0 {
+			return errors.New("start_price must be positive")
+		}
+	case ModelCSV:
+		if c.File == "" {
+			return errors.New("file is empty")
+		}
+	case ModelScenario:
+		if len(c.Steps) == 0 {
+			return errors.New("steps are empty")
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownModel, c.Model)
+	}
+
+	return nil
+}
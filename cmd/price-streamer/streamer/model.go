@@ -0,0 +1,156 @@
+package streamer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+)
+
+const (
+	fieldsInRecord = 2
+	minPrice       = 0.01
+)
+
+// Quote котировка тикера.
+type Quote struct {
+	BuyPrice  float64
+	SellPrice float64
+}
+
+// PriceModel генерирует последовательность котировок, ok == false означает конец потока.
+type PriceModel interface {
+	Next() (q Quote, ok bool)
+}
+
+// newModel создает модель цены, каждый вызов начинает последовательность с начала.
+func newModel(cfg *TickerConfig, records []Quote) (PriceModel, error) {
+	switch cfg.Model {
+	case ModelRandomWalk:
+		return &randomWalk{
+			rnd:        rand.New(rand.NewSource(cfg.Seed)), //nolint:gosec
+			price:      cfg.StartPrice,
+			volatility: cfg.Volatility,
+			spread:     cfg.Spread,
+		}, nil
+	case ModelCSV:
+		return &replay{records: records, loop: cfg.Loop}, nil
+	case ModelScenario:
+		return &scenario{steps: cfg.Steps}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownModel, cfg.Model)
+	}
+}
+
+// randomWalk случайное блуждание средней цены с постоянным спредом.
+type randomWalk struct {
+	rnd        *rand.Rand
+	price      float64
+	volatility float64
+	spread     float64
+}
+
+func (m *randomWalk) Next() (Quote, bool) {
+	m.price = math.Max(minPrice, m.price*(1+m.volatility*m.rnd.NormFloat64()))
+	half := m.price * m.spread / 2 //nolint:gomnd
+
+	return Quote{BuyPrice: m.price + half, SellPrice: m.price - half}, true
+}
+
+// replay проигрывает котировки, прочитанные из CSV.
+type replay struct {
+	records []Quote
+	pos     int
+	loop    bool
+}
+
+func (m *replay) Next() (Quote, bool) {
+	if m.pos == len(m.records) {
+		if !m.loop || len(m.records) == 0 {
+			return Quote{}, false
+		}
+
+		m.pos = 0
+	}
+
+	q := m.records[m.pos]
+	m.pos++
+
+	return q, true
+}
+
+// scenario проигрывает заранее заданные шаги.
+type scenario struct {
+	steps    []Step
+	step     int
+	repeated int
+}
+
+func (m *scenario) Next() (Quote, bool) {
+	for m.step < len(m.steps) {
+		s := m.steps[m.step]
+
+		repeat := s.Repeat
+		if repeat < 1 {
+			repeat = 1
+		}
+
+		if m.repeated < repeat {
+			m.repeated++
+			return Quote{BuyPrice: s.BuyPrice, SellPrice: s.SellPrice}, true
+		}
+
+		m.step++
+		m.repeated = 0
+	}
+
+	return Quote{}, false
+}
+
+// readCSV читает котировки из файла со столбцами buy_price,sell_price, нечисловой заголовок пропускается.
+func readCSV(path string) ([]Quote, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't open csv: %s", err)
+	}
+	defer f.Close()
+
+	return parseCSV(f)
+}
+
+func parseCSV(r io.Reader) ([]Quote, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = fieldsInRecord
+	reader.TrimLeadingSpace = true
+
+	var records []Quote
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("can't read csv: %s", err)
+		}
+
+		buy, errBuy := strconv.ParseFloat(record[0], 64)
+		sell, errSell := strconv.ParseFloat(record[1], 64)
+
+		if errBuy != nil || errSell != nil {
+			if line == 1 {
+				continue
+			}
+
+			return nil, fmt.Errorf("invalid prices on line %d: %v", line, record)
+		}
+
+		records = append(records, Quote{BuyPrice: buy, SellPrice: sell})
+	}
+
+	return records, nil
+}
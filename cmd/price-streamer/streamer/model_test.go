@@ -0,0 +1,96 @@
+package streamer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomWalkDeterministic(t *testing.T) {
+	assert := assert.New(t)
+	cfg := &TickerConfig{Ticker: "AAPL", Model: ModelRandomWalk, Seed: 42, StartPrice: 100, Volatility: 0.01, Spread: 0.002}
+
+	first, err := newModel(cfg, nil)
+	assert.NoError(err)
+
+	second, err := newModel(cfg, nil)
+	assert.NoError(err)
+
+	for i := 0; i < 100; i++ {
+		q1, ok1 := first.Next()
+		q2, ok2 := second.Next()
+
+		assert.True(ok1 && ok2)
+		assert.Equal(q1, q2, "step %d differs for the same seed", i)
+		assert.True(q1.BuyPrice >= q1.SellPrice, "buy price %v less than sell price %v", q1.BuyPrice, q1.SellPrice)
+	}
+}
+
+func TestScenario(t *testing.T) {
+	assert := assert.New(t)
+	cfg := &TickerConfig{Ticker: "TEST", Model: ModelScenario, Steps: []Step{
+		{BuyPrice: 10, SellPrice: 9, Repeat: 2},
+		{BuyPrice: 20, SellPrice: 19},
+	}}
+
+	model, err := newModel(cfg, nil)
+	assert.NoError(err)
+
+	expected := []Quote{{10, 9}, {10, 9}, {20, 19}}
+	for _, want := range expected {
+		q, ok := model.Next()
+		assert.True(ok)
+		assert.Equal(want, q)
+	}
+
+	_, ok := model.Next()
+	assert.False(ok)
+}
+
+func TestParseCSV(t *testing.T) {
+	type testCase struct {
+		Name     string
+		In       string
+		Expected []Quote
+		HasError bool
+	}
+
+	assert := assert.New(t)
+
+	testCases := []testCase{
+		{Name: "with header", In: "buy_price,sell_price\n10,9\n11.5,11\n", Expected: []Quote{{10, 9}, {11.5, 11}}},
+		{Name: "without header", In: "10, 9\n", Expected: []Quote{{10, 9}}},
+		{Name: "invalid price", In: "10,9\nten,9\n", HasError: true},
+		{Name: "wrong number of fields", In: "10,9,8\n", HasError: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			actual, err := parseCSV(strings.NewReader(tc.In))
+			if tc.HasError {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.Expected, actual)
+		})
+	}
+}
+
+func TestReplayLoop(t *testing.T) {
+	assert := assert.New(t)
+	cfg := &TickerConfig{Ticker: "YNDX", Model: ModelCSV, File: "yndx.csv", Loop: true}
+
+	model, err := newModel(cfg, []Quote{{1, 1}, {2, 2}})
+	assert.NoError(err)
+
+	for _, want := range []float64{1, 2, 1, 2} {
+		q, ok := model.Next()
+		assert.True(ok)
+		assert.Equal(want, q.BuyPrice)
+	}
+}
@@ -0,0 +1,202 @@
+package streamer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ fintech.TradingServiceServer = &Server{}
+
+// Server фейковый сервер котировок для разработки и тестов.
+type Server struct {
+	logger  log.Logger
+	order   []string
+	tickers map[string]*TickerConfig
+	records map[string][]Quote
+
+	mutex sync.RWMutex
+	last  map[string]Quote
+}
+
+// NewServer возвращает указатель на сервер котировок, CSV файлы читаются сразу.
+func NewServer(logger log.Logger, cfg Config) (*Server, error) {
+	s := &Server{
+		logger:  logger,
+		tickers: make(map[string]*TickerConfig),
+		records: make(map[string][]Quote),
+		last:    make(map[string]Quote),
+	}
+
+	for i := range cfg.Tickers {
+		tc := &cfg.Tickers[i]
+		if err := tc.validate(); err != nil {
+			return nil, fmt.Errorf("invalid ticker %q: %s", tc.Ticker, err)
+		}
+
+		if tc.Model == ModelCSV {
+			records, err := readCSV(tc.File)
+			if err != nil {
+				return nil, fmt.Errorf("can't load csv for %q: %s", tc.Ticker, err)
+			}
+
+			s.records[tc.Ticker] = records
+		}
+
+		s.order = append(s.order, tc.Ticker)
+		s.tickers[tc.Ticker] = tc
+	}
+
+	return s, nil
+}
+
+// Price отправляет котировки тикера с заданной в конфигурации частотой.
+func (s *Server) Price(req *fintech.PriceRequest, stream fintech.TradingService_PriceServer) error {
+	quotes, err := s.startQuotes(stream.Context(), []string{req.Ticker})
+	if err != nil {
+		return err
+	}
+
+	for q := range quotes {
+		resp := &fintech.PriceResponse{BuyPrice: q.BuyPrice, SellPrice: q.SellPrice, Ts: q.Ts}
+		if err = stream.Send(resp); err != nil {
+			s.logger.Warnw("unable to send price", "error", err, "ticker", req.Ticker)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListInstruments возвращает тикеры, для которых настроены модели цен.
+func (s *Server) ListInstruments(context.Context, *fintech.ListInstrumentsRequest) (*fintech.ListInstrumentsResponse, error) {
+	resp := &fintech.ListInstrumentsResponse{}
+
+	for _, ticker := range s.order {
+		resp.Instruments = append(resp.Instruments, &fintech.Instrument{Ticker: ticker, Name: s.tickers[ticker].Name})
+	}
+
+	return resp, nil
+}
+
+// GetQuote возвращает последнюю отправленную котировку тикера или первую котировку его модели.
+func (s *Server) GetQuote(_ context.Context, req *fintech.QuoteRequest) (*fintech.Quote, error) {
+	tc, ok := s.tickers[req.Ticker]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown ticker %q", req.Ticker)
+	}
+
+	s.mutex.RLock()
+	q, ok := s.last[req.Ticker]
+	s.mutex.RUnlock()
+
+	if !ok {
+		model, err := newModel(tc, s.records[tc.Ticker])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't create price model: %s", err)
+		}
+
+		if q, ok = model.Next(); !ok {
+			return nil, status.Errorf(codes.Unavailable, "no quotes for ticker %q", req.Ticker)
+		}
+	}
+
+	return &fintech.Quote{Ticker: req.Ticker, BuyPrice: q.BuyPrice, SellPrice: q.SellPrice, Ts: ptypes.TimestampNow()}, nil
+}
+
+// Subscribe отправляет котировки нескольких тикеров в одном потоке.
+func (s *Server) Subscribe(req *fintech.SubscribeRequest, stream fintech.TradingService_SubscribeServer) error {
+	quotes, err := s.startQuotes(stream.Context(), req.Tickers)
+	if err != nil {
+		return err
+	}
+
+	for q := range quotes {
+		if err = stream.Send(q); err != nil {
+			s.logger.Warnw("unable to send quote", "error", err, "ticker", q.Ticker)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startQuotes запускает модели тикеров и объединяет их котировки в один канал,
+// канал закрывается, когда все модели исчерпаны или отменен ctx.
+func (s *Server) startQuotes(ctx context.Context, tickers []string) (<-chan *fintech.Quote, error) {
+	if len(tickers) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "tickers are empty")
+	}
+
+	models := make(map[string]PriceModel, len(tickers))
+
+	for _, ticker := range tickers {
+		tc, ok := s.tickers[ticker]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "unknown ticker %q", ticker)
+		}
+
+		model, err := newModel(tc, s.records[ticker])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't create price model: %s", err)
+		}
+
+		models[ticker] = model
+	}
+
+	out := make(chan *fintech.Quote)
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(models))
+
+	for ticker, model := range models {
+		go s.runModel(ctx, s.tickers[ticker], model, out, &wg)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+// runModel отправляет котировки модели с частотой тикера.
+func (s *Server) runModel(ctx context.Context, tc *TickerConfig, model PriceModel, out chan<- *fintech.Quote, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(time.Duration(tc.Interval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		q, ok := model.Next()
+		if !ok {
+			s.logger.Infow("price model is exhausted", "ticker", tc.Ticker)
+			return
+		}
+
+		s.mutex.Lock()
+		s.last[tc.Ticker] = q
+		s.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case out <- &fintech.Quote{Ticker: tc.Ticker, BuyPrice: q.BuyPrice, SellPrice: q.SellPrice, Ts: ptypes.TimestampNow()}:
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Package cli команды robotctl — утилиты командной строки для управления роботами через HTTP API и websocket.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gitlab.com/hitchpock/tfs-course-work/pkg/client"
+)
+
+// ErrUsage неверный вызов команды: неизвестная команда, флаг или аргумент.
+var ErrUsage = errors.New("invalid usage")
+
+const usage = `usage: robotctl [-config path] [-server url] [-o table|json|yaml] <command> [flags] [args]
+
+commands:
+  login                     sign in and save the session to the config
+  robots list               list robots from the catalog or, with -mine, your robots
+  robots get <id>           show a robot
+  robots create             create a robot
+  robots activate <id>...   activate robots
+  robots deactivate <id>... deactivate robots
+  robots delete <id>...     move robots to the trash
+  robots favourite <id>     add a copy of a robot to favourites
+  user show                 show the current user
+  user update               update the current user
+  watch <id>                print robot updates until interrupted
+
+Run robotctl <command> -h for command flags.
+`
+
+// App утилита robotctl. Вывод команд идет в Stdout, сообщения и ошибки — в Stderr.
+type App struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// env окружение одной команды: настройки, клиент API и формат вывода.
+type env struct {
+	app        *App
+	cfg        *Config
+	configPath string
+	format     string
+	client     *client.Client
+	out        *printer
+	stdin      *bufio.Reader
+}
+
+// command команда robotctl, args — аргументы после имени команды.
+type command func(ctx context.Context, e *env, args []string) error
+
+// Run выполняет команду из аргументов args без имени программы.
+func (a *App) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("robotctl", flag.ContinueOnError)
+	fs.SetOutput(a.Stderr)
+	fs.Usage = func() { fmt.Fprint(a.Stderr, usage) }
+
+	e := &env{app: a}
+	fs.StringVar(&e.configPath, "config", DefaultConfigPath(), "path to JSON config with server and credentials")
+	server := fs.String("server", "", "service address, overrides the config")
+	fs.StringVar(&e.format, "o", FormatTable, "output format: table, json or yaml")
+
+	if err := fs.Parse(args); err != nil {
+		return usageErr(err)
+	}
+
+	cmd, args, err := lookup(fs.Args())
+	if err != nil {
+		fmt.Fprint(a.Stderr, usage)
+		return err
+	}
+
+	if e.cfg, err = LoadConfig(e.configPath); err != nil {
+		return err
+	}
+
+	if *server != "" {
+		e.cfg.Server = *server
+	}
+
+	if e.client, err = client.New(client.Config{BaseURL: e.cfg.Server}); err != nil {
+		return err
+	}
+
+	if e.cfg.Token != "" {
+		if err = e.client.SetToken(e.cfg.Token); err != nil {
+			return fmt.Errorf("config has %s, run robotctl login", err)
+		}
+	}
+
+	if e.cfg.Email != "" {
+		e.client.SetCredentials(e.cfg.Email, e.cfg.Password)
+	}
+
+	err = cmd(ctx, e, args)
+
+	// Клиент мог войти заново, сохраняем новый токен, чтобы следующая команда не входила снова.
+	if token := e.client.Token(); token != "" && token != e.cfg.Token {
+		e.cfg.Token = token
+		if saveErr := e.cfg.Save(e.configPath); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+
+	return err
+}
+
+// lookup находит команду по первым аргументам и возвращает ее аргументы.
+func lookup(args []string) (command, []string, error) {
+	if len(args) == 0 {
+		return nil, nil, ErrUsage
+	}
+
+	groups := map[string]map[string]command{
+		"robots": {
+			"list":       robotsList,
+			"get":        robotsGet,
+			"create":     robotsCreate,
+			"activate":   robotsActivate,
+			"deactivate": robotsDeactivate,
+			"delete":     robotsDelete,
+			"favourite":  robotsFavourite, //nolint:misspell
+		},
+		"user": {
+			"show":   userShow,
+			"update": userUpdate,
+		},
+	}
+
+	switch args[0] {
+	case "login":
+		return login, args[1:], nil
+	case "watch":
+		return watch, args[1:], nil
+	}
+
+	group, ok := groups[args[0]]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+	}
+
+	if len(args) < 2 {
+		return nil, nil, fmt.Errorf("%w: %s needs a subcommand", ErrUsage, args[0])
+	}
+
+	cmd, ok := group[args[1]]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unknown command %q", ErrUsage, args[0]+" "+args[1])
+	}
+
+	return cmd, args[2:], nil
+}
+
+// flags возвращает набор флагов команды name. Формат вывода можно задать и после имени команды.
+func (e *env) flags(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.app.Stderr)
+	fs.StringVar(&e.format, "o", e.format, "output format: table, json or yaml")
+	fs.Usage = func() {
+		fmt.Fprintf(e.app.Stderr, "usage: robotctl %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parse разбирает флаги команды и готовит вывод в выбранном формате.
+func (e *env) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return usageErr(err)
+	}
+
+	out, err := newPrinter(e.app.Stdout, e.format)
+	if err != nil {
+		return err
+	}
+
+	e.out = out
+
+	return nil
+}
+
+// loggedIn проверяет, что у клиента есть сессия или учетные данные для входа.
+func (e *env) loggedIn() error {
+	if e.cfg.Token == "" && e.cfg.Email == "" {
+		return errors.New("not logged in, run robotctl login")
+	}
+
+	return nil
+}
+
+// prompt спрашивает значение у пользователя.
+func (e *env) prompt(label string) (string, error) {
+	fmt.Fprintf(e.app.Stderr, "%s: ", label)
+
+	if e.stdin == nil {
+		e.stdin = bufio.NewReader(e.app.Stdin)
+	}
+
+	line, err := e.stdin.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("can't read %s: %s", strings.ToLower(label), err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+func login(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("login", "")
+	email := fs.String("email", e.cfg.Email, "user email")
+	password := fs.String("password", "", "user password, asked when empty")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	var err error
+
+	if *email == "" {
+		if *email, err = e.prompt("Email"); err != nil {
+			return err
+		}
+	}
+
+	if *password == "" {
+		if *password, err = e.prompt("Password"); err != nil {
+			return err
+		}
+	}
+
+	if err = e.client.SignIn(ctx, *email, *password); err != nil {
+		return err
+	}
+
+	e.cfg.Email, e.cfg.Password, e.cfg.Token = *email, *password, e.client.Token()
+	if err = e.cfg.Save(e.configPath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.app.Stderr, "logged in as user %d, config saved to %s\n", e.client.UserID(), e.configPath)
+
+	return nil
+}
+
+// robotIDs разбирает ID роботов из аргументов команды.
+func robotIDs(args []string, single bool) ([]int, error) {
+	if len(args) == 0 || single && len(args) > 1 {
+		return nil, fmt.Errorf("%w: expected robot id", ErrUsage)
+	}
+
+	ids := make([]int, 0, len(args))
+
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%w: invalid robot id %q", ErrUsage, arg)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func usageErr(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrUsage, err)
+}
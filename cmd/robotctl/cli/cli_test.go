@@ -0,0 +1,306 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/cmd/auth-api/handlers"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/client"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+)
+
+type fakeInstruments struct {
+	fintech.TradingServiceClient
+}
+
+func (fakeInstruments) GetQuote(_ context.Context, in *fintech.QuoteRequest, _ ...grpc.CallOption) (*fintech.Quote, error) {
+	return &fintech.Quote{Ticker: in.Ticker, BuyPrice: 100, SellPrice: 99}, nil
+}
+
+type broadcaster struct {
+	ws *handlers.WSClients
+}
+
+func (b broadcaster) Publish(e event.Event) error {
+	b.ws.Broadcast(e.RobotID)
+	return nil
+}
+
+// setupServer запускает сервис на хранилищах в памяти с зарегистрированными пользователями
+// first@example.com и second@example.com, пароль 1234.
+func setupServer(t *testing.T) string {
+	robots := robot.CreateStorageInMemory()
+	ws := handlers.NewWebsocket(robots)
+	h := handlers.NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(),
+		robots, ws, broadcaster{ws: ws}, fakeInstruments{})
+
+	srv := httptest.NewServer(h.Routes())
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(client.Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		assert.NoError(t, c.SignUp(context.Background(), client.UserParams{FirstName: "Ivan", LastName: "Ivanov",
+			Email: email, Password: "1234"}))
+	}
+
+	return srv.URL
+}
+
+// tempDir создает каталог, который удаляется после теста.
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "robotctl")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return dir
+}
+
+// run выполняет robotctl с настройками configPath и возвращает вывод.
+func run(configPath string, args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	app := &App{Stdin: strings.NewReader(""), Stdout: &stdout, Stderr: &stderr}
+	err := app.Run(context.Background(), append([]string{"-config", configPath}, args...))
+
+	return stdout.String(), stderr.String(), err
+}
+
+func setupLogin(t *testing.T) string {
+	server := setupServer(t)
+	configPath := filepath.Join(tempDir(t), "config.json")
+
+	_, _, err := run(configPath, "-server", server, "login", "-email", "second@example.com", "-password", "1234")
+	assert.NoError(t, err)
+
+	return configPath
+}
+
+func TestLogin(t *testing.T) {
+	assert := assert.New(t)
+	server := setupServer(t)
+	configPath := filepath.Join(tempDir(t), "robotctl", "config.json")
+
+	var stdout, stderr bytes.Buffer
+
+	app := &App{Stdin: strings.NewReader("second@example.com\n1234\n"), Stdout: &stdout, Stderr: &stderr}
+	err := app.Run(context.Background(), []string{"-config", configPath, "-server", server, "login"})
+	assert.NoError(err)
+	assert.Contains(stderr.String(), "logged in as user 1")
+
+	cfg, err := LoadConfig(configPath)
+	assert.NoError(err)
+	assert.Equal(server, cfg.Server)
+	assert.Equal("second@example.com", cfg.Email)
+	assert.NotEmpty(cfg.Token)
+
+	info, err := os.Stat(configPath)
+	if assert.NoError(err) {
+		assert.Equal(os.FileMode(0600), info.Mode().Perm())
+	}
+
+	_, _, err = run(configPath, "login", "-email", "second@example.com", "-password", "wrong")
+	assert.Error(err)
+}
+
+func TestRobots(t *testing.T) {
+	assert := assert.New(t)
+	configPath := setupLogin(t)
+
+	stdout, _, err := run(configPath, "-o", "json", "robots", "create", "-ticker", "AAPL", "-buy-price", "10",
+		"-sell-price", "20", "-visibility", "private")
+	assert.NoError(err)
+
+	var rob client.Robot
+	assert.NoError(json.Unmarshal([]byte(stdout), &rob))
+	assert.Equal("AAPL", rob.Ticker)
+	assert.Equal(20.0, rob.SellPrice)
+	assert.Equal("private", rob.Visibility)
+
+	id := "1"
+
+	stdout, _, err = run(configPath, "robots", "get", id)
+	assert.NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if assert.Len(lines, 2) {
+		assert.True(strings.HasPrefix(lines[0], "ID"), lines[0])
+		assert.Contains(lines[1], "AAPL")
+	}
+
+	stdout, _, err = run(configPath, "robots", "activate", "-o", "yaml", id)
+	assert.NoError(err)
+
+	var robots []map[string]interface{}
+	assert.NoError(yaml.Unmarshal([]byte(stdout), &robots))
+
+	if assert.Len(robots, 1) {
+		assert.Equal(true, robots[0]["is_active"])
+	}
+
+	stdout, _, err = run(configPath, "-o", "json", "robots", "list", "-mine", "-active")
+	assert.NoError(err)
+
+	var list []client.Robot
+	assert.NoError(json.Unmarshal([]byte(stdout), &list))
+	assert.Len(list, 1)
+
+	stdout, _, err = run(configPath, "-o", "json", "robots", "list", "-mine", "-active=false")
+	assert.NoError(err)
+	assert.Equal("[]\n", stdout)
+
+	_, _, err = run(configPath, "robots", "deactivate", id)
+	assert.NoError(err)
+
+	_, stderr, err := run(configPath, "robots", "delete", id)
+	assert.NoError(err)
+	assert.Contains(stderr, "robot 1 moved to the trash")
+
+	_, _, err = run(configPath, "robots", "get", id)
+
+	var apiErr *client.Error
+	assert.True(errors.As(err, &apiErr), "error: %v", err)
+}
+
+func TestUser(t *testing.T) {
+	assert := assert.New(t)
+	configPath := setupLogin(t)
+
+	stdout, _, err := run(configPath, "-o", "json", "user", "update", "-first-name", "Victor", "-password", "4321")
+	assert.NoError(err)
+
+	var u client.User
+	assert.NoError(json.Unmarshal([]byte(stdout), &u))
+	assert.Equal("Victor", u.FirstName)
+	assert.Equal("Ivanov", u.LastName)
+
+	cfg, err := LoadConfig(configPath)
+	assert.NoError(err)
+	assert.Equal("4321", cfg.Password)
+
+	// Токен больше не действует, клиент входит заново с новым паролем из настроек.
+	cfg.Token = ""
+	assert.NoError(cfg.Save(configPath))
+
+	stdout, _, err = run(configPath, "user", "show")
+	assert.NoError(err)
+	assert.Contains(stdout, "Victor")
+}
+
+func TestUsage(t *testing.T) {
+	type testCase struct {
+		Name string
+		Args []string
+	}
+
+	testCases := []testCase{
+		{Name: "No command", Args: []string{}},
+		{Name: "Unknown command", Args: []string{"robot"}},
+		{Name: "No subcommand", Args: []string{"robots"}},
+		{Name: "Unknown subcommand", Args: []string{"robots", "start"}},
+		{Name: "Unknown flag", Args: []string{"robots", "list", "-foo"}},
+		{Name: "Invalid robot id", Args: []string{"robots", "get", "abc"}},
+		{Name: "Several robot ids", Args: []string{"robots", "get", "1", "2"}},
+		{Name: "Invalid time", Args: []string{"robots", "create", "-plan-start", "tomorrow"}},
+	}
+
+	configPath := filepath.Join(tempDir(t), "config.json")
+	cfg := &Config{Email: "e@example.com", Password: "1234"}
+	assert.NoError(t, cfg.Save(configPath))
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			_, _, err := run(configPath, tc.Args...)
+			assert.True(t, errors.Is(err, ErrUsage), "error: %v", err)
+		})
+	}
+
+	_, _, err := run(filepath.Join(tempDir(t), "config.json"), "robots", "list")
+	assert.EqualError(t, err, "not logged in, run robotctl login")
+
+	_, _, err = run(configPath, "-o", "xml", "user", "show")
+	assert.Error(t, err)
+}
+
+func TestWatch(t *testing.T) {
+	assert := assert.New(t)
+	configPath := setupLogin(t)
+
+	_, _, err := run(configPath, "robots", "create", "-ticker", "AAPL", "-buy-price", "10", "-sell-price", "20")
+	assert.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	r, w := io.Pipe()
+	app := &App{Stdin: strings.NewReader(""), Stdout: w, Stderr: ioutil.Discard}
+	done := make(chan error, 1)
+
+	go func() {
+		done <- app.Run(ctx, []string{"-config", configPath, "watch", "1"})
+		w.Close()
+	}()
+
+	scanner := bufio.NewScanner(r)
+
+	if assert.True(scanner.Scan()) {
+		assert.True(strings.HasPrefix(scanner.Text(), "ID"), scanner.Text())
+	}
+
+	if assert.True(scanner.Scan()) {
+		assert.Contains(scanner.Text(), "draft")
+	}
+
+	_, _, err = run(configPath, "robots", "activate", "1")
+	assert.NoError(err)
+
+	if assert.True(scanner.Scan()) {
+		assert.Contains(scanner.Text(), "active")
+		assert.False(strings.HasPrefix(scanner.Text(), "ID"))
+	}
+
+	cancel()
+
+	go func() {
+		for scanner.Scan() {
+		}
+	}()
+
+	assert.NoError(<-done)
+}
+
+func TestLoadConfig(t *testing.T) {
+	assert := assert.New(t)
+	dir := tempDir(t)
+
+	cfg, err := LoadConfig(filepath.Join(dir, "missing.json"))
+	assert.NoError(err)
+	assert.Equal(&Config{Server: defaultServer}, cfg)
+
+	broken := filepath.Join(dir, "broken.json")
+	assert.NoError(ioutil.WriteFile(broken, []byte("{"), 0600))
+
+	_, err = LoadConfig(broken)
+	assert.Error(err)
+}
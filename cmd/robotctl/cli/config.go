@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// Config настройки robotctl: адрес сервиса, учетные данные и токен последней сессии.
+type Config struct {
+	Server   string `json:"server"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+// DefaultConfigPath возвращает путь к файлу настроек: $ROBOTCTL_CONFIG или robotctl/config.json
+// в каталоге настроек пользователя.
+func DefaultConfigPath() string {
+	if path := os.Getenv("ROBOTCTL_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "robotctl.json"
+	}
+
+	return filepath.Join(dir, "robotctl", "config.json")
+}
+
+// LoadConfig читает настройки из файла path. Если файла нет, возвращает настройки по умолчанию.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("can't read config %s: %s", path, err)
+	}
+
+	if err == nil {
+		if err = json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("can't parse config %s: %s", path, err)
+		}
+	}
+
+	if cfg.Server == "" {
+		cfg.Server = defaultServer
+	}
+
+	return cfg, nil
+}
+
+// Save записывает настройки в файл path. Файл содержит пароль, поэтому доступен только владельцу.
+func (c *Config) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can't marshal config: %s", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("can't create config dir: %s", err)
+	}
+
+	if err = ioutil.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("can't write config %s: %s", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gitlab.com/hitchpock/tfs-course-work/pkg/client"
+	"gopkg.in/yaml.v2"
+)
+
+// Форматы вывода.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// table таблица для вывода в формате table.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// printer выводит результаты команд в выбранном формате.
+type printer struct {
+	w      io.Writer
+	format string
+	// headed заголовок таблицы уже выведен, следующие таблицы выводятся без него.
+	headed bool
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case FormatTable, FormatJSON, FormatYAML:
+		return &printer{w: w, format: format}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format %q, must be one of %s, %s, %s", format, FormatTable, FormatJSON, FormatYAML)
+}
+
+// print выводит v в формате json или yaml, а в формате table — таблицу t.
+func (p *printer) print(v interface{}, t table) error {
+	switch p.format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("can't marshal output: %s", err)
+		}
+
+		_, err = fmt.Fprintf(p.w, "%s\n", data)
+
+		return err
+	case FormatYAML:
+		data, err := toYAML(v)
+		if err != nil {
+			return err
+		}
+
+		_, err = p.w.Write(data)
+
+		return err
+	}
+
+	return p.table(t)
+}
+
+// stream выводит очередной элемент потока: таблица выводится с заголовком один раз,
+// yaml — отдельными документами.
+func (p *printer) stream(v interface{}, t table) error {
+	if p.format == FormatYAML {
+		if _, err := io.WriteString(p.w, "---\n"); err != nil {
+			return err
+		}
+	}
+
+	if p.format == FormatTable && p.headed {
+		t.header = nil
+	}
+
+	p.headed = true
+
+	return p.print(v, t)
+}
+
+func (p *printer) table(t table) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+
+	if t.header != nil {
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	}
+
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// toYAML переводит v в yaml через JSON, чтобы имена полей совпадали с API.
+func toYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("can't marshal output: %s", err)
+	}
+
+	var tree interface{}
+	if err = yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("can't convert output to yaml: %s", err)
+	}
+
+	if data, err = yaml.Marshal(tree); err != nil {
+		return nil, fmt.Errorf("can't marshal output to yaml: %s", err)
+	}
+
+	return data, nil
+}
+
+func robotsTable(robots ...client.Robot) table {
+	t := table{header: []string{"ID", "TICKER", "STATUS", "BUY", "SELL", "PLAN YIELD", "FACT YIELD", "DEALS",
+		"VISIBILITY", "OWNER"}}
+
+	for _, r := range robots {
+		t.rows = append(t.rows, []string{
+			strconv.Itoa(r.RobotID), r.Ticker, r.Status, formatFloat(r.BuyPrice), formatFloat(r.SellPrice),
+			formatFloat(r.PlanYield), formatFloat(r.FactYield), strconv.Itoa(r.DealsCount), r.Visibility,
+			strconv.Itoa(r.OwnerUserID),
+		})
+	}
+
+	return t
+}
+
+func userTable(u *client.User) table {
+	return table{
+		header: []string{"FIRST NAME", "LAST NAME", "BIRTHDAY", "EMAIL"},
+		rows:   [][]string{{u.FirstName, u.LastName, u.Birthday, u.Email}},
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/pkg/client"
+)
+
+func robotsList(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("robots list", "")
+	mine := fs.Bool("mine", false, "list your robots instead of the catalog")
+	all := fs.Bool("all", false, "fetch all pages")
+	active := fs.Bool("active", false, "only active robots, -active=false for inactive ones")
+	q := &client.RobotQuery{}
+	fs.StringVar(&q.Ticker, "ticker", "", "filter by ticker")
+	fs.StringVar(&q.Sort, "sort", "", "sort field, for example fact_yield or created_at")
+	fs.BoolVar(&q.Desc, "desc", false, "sort in descending order")
+	fs.IntVar(&q.Limit, "limit", 0, "page size")
+	fs.StringVar(&q.Cursor, "cursor", "", "cursor of the page from the previous call")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := e.loggedIn(); err != nil {
+		return err
+	}
+
+	if isSet(fs, "active") {
+		q.IsActive = active
+	}
+
+	list := e.client.Robots
+	if *mine {
+		list = e.client.UserRobots
+	}
+
+	robots := make([]client.Robot, 0)
+
+	for {
+		page, err := list(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		robots = append(robots, page.Robots...)
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		if !*all {
+			fmt.Fprintf(e.app.Stderr, "more robots: -cursor %s\n", page.NextCursor)
+			break
+		}
+
+		q.Cursor = page.NextCursor
+	}
+
+	return e.out.print(robots, robotsTable(robots...))
+}
+
+func robotsGet(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("robots get", "<id>")
+	share := fs.String("share", "", "share token from a robot link")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	ids, err := robotIDs(fs.Args(), true)
+	if err != nil {
+		return err
+	}
+
+	if err = e.loggedIn(); err != nil {
+		return err
+	}
+
+	var rob *client.Robot
+	if *share != "" {
+		rob, err = e.client.SharedRobot(ctx, ids[0], *share)
+	} else {
+		rob, err = e.client.Robot(ctx, ids[0])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return e.out.print(rob, robotsTable(*rob))
+}
+
+func robotsCreate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("robots create", "")
+	ticker := fs.String("ticker", "", "instrument ticker")
+	buyPrice := fs.Float64("buy-price", 0, "price to buy at")
+	sellPrice := fs.Float64("sell-price", 0, "price to sell at")
+	planStart := fs.String("plan-start", "", "start of trading, RFC 3339")
+	planEnd := fs.String("plan-end", "", "end of trading, RFC 3339")
+	planYield := fs.Float64("plan-yield", 0, "planned yield")
+	autoClose := fs.Bool("auto-close", false, "close the position before the session ends")
+	schedule := fs.String("schedule", "", "trading schedule, for example \"mon-fri 10:00-18:00 Europe/Moscow\"")
+	lots := fs.Int("lots", 0, "position size in lots")
+	visibility := fs.String("visibility", "", "private, link, users or public")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := e.loggedIn(); err != nil {
+		return err
+	}
+
+	var params client.RobotParams
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "ticker":
+			params.Ticker = ticker
+		case "buy-price":
+			params.BuyPrice = buyPrice
+		case "sell-price":
+			params.SellPrice = sellPrice
+		case "plan-yield":
+			params.PlanYield = planYield
+		case "auto-close":
+			params.AutoClose = autoClose
+		case "schedule":
+			params.Schedule = schedule
+		case "lots":
+			params.Lots = lots
+		case "visibility":
+			params.Visibility = visibility
+		}
+	})
+
+	var err error
+	if params.PlanStart, err = parseTime("plan-start", *planStart); err != nil {
+		return err
+	}
+
+	if params.PlanEnd, err = parseTime("plan-end", *planEnd); err != nil {
+		return err
+	}
+
+	// Пакет из одного робота, потому что сервис возвращает созданного робота только в пакетном ответе.
+	result, err := e.client.CreateRobots(ctx, true, []client.RobotParams{params})
+	if err != nil {
+		return err
+	}
+
+	if len(result.Results) != 1 {
+		return fmt.Errorf("unexpected batch result with %d robots", len(result.Results))
+	}
+
+	item := result.Results[0]
+	if item.Error != nil {
+		return item.Error
+	}
+
+	return e.out.print(item.Robot, robotsTable(*item.Robot))
+}
+
+func robotsActivate(ctx context.Context, e *env, args []string) error {
+	return robotsAction(ctx, e, "activate", args, e.client.ActivateRobot)
+}
+
+func robotsDeactivate(ctx context.Context, e *env, args []string) error {
+	return robotsAction(ctx, e, "deactivate", args, e.client.DeactivateRobot)
+}
+
+// robotsAction выполняет действие над роботами по очереди и выводит их новое состояние.
+// Действие получает ETag только что прочитанного робота. Ошибка на одном роботе останавливает команду.
+func robotsAction(ctx context.Context, e *env, name string, args []string,
+	action func(ctx context.Context, robotID int, etag string) error) error {
+	fs := e.flags("robots "+name, "<id>...")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	ids, err := robotIDs(fs.Args(), false)
+	if err != nil {
+		return err
+	}
+
+	if err = e.loggedIn(); err != nil {
+		return err
+	}
+
+	robots := make([]client.Robot, 0, len(ids))
+
+	for _, id := range ids {
+		rob, err := e.client.Robot(ctx, id)
+		if err != nil {
+			return fmt.Errorf("robot %d: %w", id, err)
+		}
+
+		if err = action(ctx, id, rob.ETag); err != nil {
+			return fmt.Errorf("robot %d: %w", id, err)
+		}
+
+		if rob, err = e.client.Robot(ctx, id); err != nil {
+			return fmt.Errorf("robot %d: %w", id, err)
+		}
+
+		robots = append(robots, *rob)
+	}
+
+	return e.out.print(robots, robotsTable(robots...))
+}
+
+func robotsDelete(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("robots delete", "<id>...")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	ids, err := robotIDs(fs.Args(), false)
+	if err != nil {
+		return err
+	}
+
+	if err = e.loggedIn(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		rob, err := e.client.Robot(ctx, id)
+		if err != nil {
+			return fmt.Errorf("robot %d: %w", id, err)
+		}
+
+		if err = e.client.DeleteRobot(ctx, id, rob.ETag); err != nil {
+			return fmt.Errorf("robot %d: %w", id, err)
+		}
+
+		fmt.Fprintf(e.app.Stderr, "robot %d moved to the trash\n", id)
+	}
+
+	return nil
+}
+
+func robotsFavourite(ctx context.Context, e *env, args []string) error { //nolint:misspell
+	fs := e.flags("robots favourite", "<id>") //nolint:misspell
+	var follow client.Follow
+	fs.BoolVar(&follow.Mirror, "mirror", false, "repeat deals of the robot")
+	fs.IntVar(&follow.Lots, "lots", 0, "position size in lots for a mirror copy")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	ids, err := robotIDs(fs.Args(), true)
+	if err != nil {
+		return err
+	}
+
+	if err = e.loggedIn(); err != nil {
+		return err
+	}
+
+	parent, err := e.client.Robot(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+
+	rob, err := e.client.FavouriteRobot(ctx, ids[0], parent.ETag, follow) //nolint:misspell
+	if err != nil {
+		return err
+	}
+
+	return e.out.print(rob, robotsTable(*rob))
+}
+
+// isSet сообщает, задан ли флаг name явно.
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+func parseTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid -%s: %s", ErrUsage, name, err)
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"errors"
+
+	"gitlab.com/hitchpock/tfs-course-work/pkg/client"
+)
+
+func userShow(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("user show", "")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := e.loggedIn(); err != nil {
+		return err
+	}
+
+	u, err := e.client.User(ctx)
+	if err != nil {
+		return err
+	}
+
+	return e.out.print(u, userTable(u))
+}
+
+// userUpdate меняет заданные поля пользователя, остальные берутся из текущих данных.
+// Сервис требует пароль, по умолчанию это пароль из настроек.
+func userUpdate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("user update", "")
+	firstName := fs.String("first-name", "", "first name")
+	lastName := fs.String("last-name", "", "last name")
+	birthday := fs.String("birthday", "", "birthday, 2006-01-02")
+	email := fs.String("email", "", "email")
+	password := fs.String("password", "", "new password")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := e.loggedIn(); err != nil {
+		return err
+	}
+
+	u, err := e.client.User(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := client.UserParams{FirstName: u.FirstName, LastName: u.LastName, Birthday: u.Birthday, Email: u.Email,
+		Password: e.cfg.Password}
+
+	for _, f := range []struct {
+		value *string
+		field *string
+	}{
+		{firstName, &params.FirstName}, {lastName, &params.LastName}, {birthday, &params.Birthday},
+		{email, &params.Email}, {password, &params.Password},
+	} {
+		if *f.value != "" {
+			*f.field = *f.value
+		}
+	}
+
+	if params.Password == "" {
+		return errors.New("password is required, pass -password or run robotctl login")
+	}
+
+	if u, err = e.client.UpdateUser(ctx, u.ETag, params); err != nil {
+		return err
+	}
+
+	// Сохраненные учетные данные должны остаться рабочими после смены почты или пароля.
+	if e.cfg.Email != "" {
+		e.cfg.Email, e.cfg.Password = params.Email, params.Password
+		if err = e.cfg.Save(e.configPath); err != nil {
+			return err
+		}
+	}
+
+	return e.out.print(u, userTable(u))
+}
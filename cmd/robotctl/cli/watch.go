@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+)
+
+// watch выводит состояние робота при каждом изменении, пока не отменен ctx или сервис не закрыл подписку.
+func watch(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("watch", "<id>")
+	share := fs.String("share", "", "share token from a robot link")
+
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	ids, err := robotIDs(fs.Args(), true)
+	if err != nil {
+		return err
+	}
+
+	if err = e.loggedIn(); err != nil {
+		return err
+	}
+
+	w, err := e.client.WatchRobot(ctx, ids[0], *share)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	for rob := range w.Updates() {
+		rob := rob
+		if err = e.out.stream(rob, robotsTable(rob)); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return w.Err()
+}
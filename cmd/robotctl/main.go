@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gitlab.com/hitchpock/tfs-course-work/cmd/robotctl/cli"
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	app := &cli.App{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+
+	err := app.Run(ctx, os.Args[1:])
+
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, cli.ErrUsage):
+		fmt.Fprintf(os.Stderr, "robotctl: %s\n", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "robotctl: %s\n", err)
+		os.Exit(1)
+	}
+}
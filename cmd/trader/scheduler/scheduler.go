@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/calendar"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+// Scheduler запускает и останавливает роботов на границах plan_start/plan_end и их расписаний.
+type Scheduler struct {
+	logger   log.Logger
+	storage  robot.Storage
+	events   event.Publisher
+	interval time.Duration
+}
+
+// NewScheduler возвращает указатель на планировщик, который проверяет расписания раз в interval.
+func NewScheduler(logger log.Logger, storage robot.Storage, events event.Publisher, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		logger:   logger,
+		storage:  storage,
+		events:   events,
+		interval: interval,
+	}
+}
+
+// Start проверяет расписания роботов, пока не отменен ctx.
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Run(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run запускает ожидающих роботов в окне расписания и останавливает торгующих вне окна,
+// если после последней границы расписания их не запускали вручную.
+// Роботов, приостановленных пользователем, планировщик не трогает.
+func (s *Scheduler) Run(now time.Time) {
+	robots, err := s.storage.FindScheduled()
+	if err != nil {
+		s.logger.Warnw("func robotStorage.FindScheduled return with error", "error", err)
+		return
+	}
+
+	for i := range robots {
+		rob := &robots[i]
+
+		active, boundary, err := DesiredState(rob, now)
+		if err != nil {
+			s.logger.Warnw("invalid robot schedule", "error", err, "robotID", rob.RobotID)
+			continue
+		}
+
+		var action robot.Action
+
+		switch {
+		case active && rob.Status == robot.StatusScheduled:
+			action = robot.ActionActivate
+		case !active && rob.Status.IsTrading() && rob.LastChange().Before(boundary):
+			action = scheduledStop(rob, now)
+		default:
+			continue
+		}
+
+		if err = s.storage.Transition(rob.RobotID, action, robot.SourceSchedule, 0); err != nil {
+			s.logger.Warnw("func robotStorage.Transition return with error", "error", err, "robotID", rob.RobotID)
+			continue
+		}
+
+		s.logger.Infow("robot is switched by schedule", "robotID", rob.RobotID, "action", action)
+
+		if err = s.events.Publish(event.Event{Type: event.RobotChanged, RobotID: rob.RobotID}); err != nil {
+			s.logger.Warnw("func events.Publish return with error", "error", err, "robotID", rob.RobotID)
+		}
+	}
+}
+
+// scheduledStop возвращает действие для остановки робота вне окна: робот с повторяющимся расписанием
+// ждет следующего окна, а робот с завершенным планом останавливается совсем.
+func scheduledStop(rob *robot.Robot, now time.Time) robot.Action {
+	if rob.Schedule == "" || (rob.PlanEnd.Valid && !now.Before(rob.PlanEnd.Time)) {
+		return robot.ActionStop
+	}
+
+	return robot.ActionSuspend
+}
+
+// DesiredState возвращает состояние робота по плановому окну и расписанию в момент now
+// и время последней границы, после которой это состояние наступило.
+func DesiredState(rob *robot.Robot, now time.Time) (active bool, boundary time.Time, err error) {
+	active = true
+
+	if rob.PlanStart.Valid && rob.PlanEnd.Valid {
+		start, end := rob.PlanStart.Time, rob.PlanEnd.Time
+		active = !now.Before(start) && now.Before(end)
+
+		switch {
+		case !now.Before(end):
+			boundary = end
+		case !now.Before(start):
+			boundary = start
+		}
+	}
+
+	if rob.Schedule != "" {
+		var recurring *calendar.Recurring
+
+		if recurring, err = calendar.ParseRecurring(rob.Schedule); err != nil {
+			return false, time.Time{}, fmt.Errorf("can't parse schedule %q: %s", rob.Schedule, err)
+		}
+
+		active = active && recurring.Contains(now)
+
+		if last, ok := recurring.LastBoundary(now); ok && last.After(boundary) {
+			boundary = last
+		}
+	}
+
+	return active, boundary, nil
+}
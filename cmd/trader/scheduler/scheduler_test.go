@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+type fakePublisher struct {
+	events []event.Event
+}
+
+func (f *fakePublisher) Publish(e event.Event) error {
+	f.events = append(f.events, e)
+	return nil
+}
+
+func validTime(t time.Time) robot.NullTime {
+	return robot.NullTime{Time: t, Valid: true}
+}
+
+func TestDesiredState(t *testing.T) {
+	type testCase struct {
+		Name             string
+		Robot            robot.Robot
+		Now              time.Time
+		ExpectedActive   bool
+		ExpectedBoundary time.Time
+	}
+
+	assert := assert.New(t)
+	start := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2020, 6, 30, 18, 0, 0, 0, time.UTC)
+	plan := robot.Robot{PlanStart: validTime(start), PlanEnd: validTime(end)}
+	weekdays := robot.Robot{Schedule: "mon-fri 10:00-18:00"}
+	both := robot.Robot{PlanStart: validTime(start), PlanEnd: validTime(end), Schedule: "mon-fri 10:00-18:00"}
+
+	testCases := []testCase{
+		{Name: "Before plan", Robot: plan, Now: start.Add(-time.Hour), ExpectedActive: false},
+		{Name: "Inside plan", Robot: plan, Now: start.Add(time.Hour), ExpectedActive: true, ExpectedBoundary: start},
+		{Name: "After plan", Robot: plan, Now: end.Add(time.Hour), ExpectedActive: false, ExpectedBoundary: end},
+		{Name: "Weekday session", Robot: weekdays, Now: time.Date(2020, 6, 3, 12, 0, 0, 0, time.UTC),
+			ExpectedActive: true, ExpectedBoundary: time.Date(2020, 6, 3, 10, 0, 0, 0, time.UTC)},
+		{Name: "Weekend", Robot: weekdays, Now: time.Date(2020, 6, 6, 12, 0, 0, 0, time.UTC),
+			ExpectedActive: false, ExpectedBoundary: time.Date(2020, 6, 5, 18, 0, 0, 0, time.UTC)},
+		{Name: "Plan and schedule", Robot: both, Now: time.Date(2020, 6, 3, 19, 0, 0, 0, time.UTC),
+			ExpectedActive: false, ExpectedBoundary: time.Date(2020, 6, 3, 18, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			active, boundary, err := DesiredState(&tc.Robot, tc.Now)
+			assert.NoError(err)
+			assert.Equal(tc.ExpectedActive, active)
+			assert.True(tc.ExpectedBoundary.Equal(boundary), "boundary = %s, want %s", boundary, tc.ExpectedBoundary)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	storage := robot.CreateStorageInMemory()
+	publisher := &fakePublisher{}
+	s := NewScheduler(log.NewSugarLogger(), storage, publisher, time.Minute)
+
+	started := &robot.Robot{OwnerUserID: 1, PlanStart: validTime(now.Add(-time.Hour)), PlanEnd: validTime(now.Add(time.Hour))}
+	finished := &robot.Robot{OwnerUserID: 1, IsActive: true, ActivatedAt: validTime(now.Add(-3 * time.Hour)),
+		PlanStart: validTime(now.Add(-3 * time.Hour)), PlanEnd: validTime(now.Add(-time.Hour))}
+	manual := &robot.Robot{OwnerUserID: 1, IsActive: true, ActivatedAt: validTime(now.Add(-time.Minute)),
+		PlanStart: validTime(now.Add(-3 * time.Hour)), PlanEnd: validTime(now.Add(-time.Hour))}
+	paused := &robot.Robot{OwnerUserID: 1, Status: robot.StatusPaused,
+		PlanStart: validTime(now.Add(-time.Hour)), PlanEnd: validTime(now.Add(time.Hour))}
+	invalid := &robot.Robot{OwnerUserID: 1, Schedule: "sometimes"}
+
+	for _, r := range []*robot.Robot{started, finished, manual, paused, invalid} {
+		assert.NoError(storage.Create(r))
+	}
+
+	s.Run(now)
+
+	rob, _ := storage.FindByID(started.RobotID)
+	assert.True(rob.IsActive, "robot must be activated at plan_start")
+	assert.Equal(robot.StatusActive, rob.Status)
+	assert.True(rob.ActivatedAt.Valid)
+
+	rob, _ = storage.FindByID(finished.RobotID)
+	assert.False(rob.IsActive, "robot must be deactivated at plan_end")
+	assert.Equal(robot.StatusStopped, rob.Status)
+	assert.True(rob.DeactivatedAt.Valid)
+
+	rob, _ = storage.FindByID(manual.RobotID)
+	assert.True(rob.IsActive, "manual activation after plan_end must be kept")
+
+	rob, _ = storage.FindByID(paused.RobotID)
+	assert.Equal(robot.StatusPaused, rob.Status, "robot paused by user must stay paused")
+
+	transitions, err := storage.FindTransitions(started.RobotID)
+	assert.NoError(err)
+	assert.Len(transitions, 1)
+	assert.Equal(robot.SourceSchedule, transitions[0].Source)
+
+	assert.Equal([]event.Event{
+		{Type: event.RobotChanged, RobotID: started.RobotID},
+		{Type: event.RobotChanged, RobotID: finished.RobotID},
+	}, publisher.events)
+
+	s.Run(now.Add(time.Minute))
+	assert.Len(publisher.events, 2, "repeated run must not switch robots again")
+}
+
+func TestRunRecurring(t *testing.T) {
+	assert := assert.New(t)
+	storage := robot.CreateStorageInMemory()
+	s := NewScheduler(log.NewSugarLogger(), storage, &fakePublisher{}, time.Minute)
+
+	rob := &robot.Robot{OwnerUserID: 1, IsActive: true, Schedule: "mon-fri 10:00-18:00",
+		ActivatedAt: validTime(time.Date(2020, 6, 5, 10, 0, 0, 0, time.UTC))}
+	assert.NoError(storage.Create(rob))
+
+	s.Run(time.Date(2020, 6, 6, 12, 0, 0, 0, time.UTC))
+
+	actual, _ := storage.FindByID(rob.RobotID)
+	assert.Equal(robot.StatusScheduled, actual.Status, "robot must wait for the next window")
+
+	s.Run(time.Date(2020, 6, 8, 11, 0, 0, 0, time.UTC))
+
+	actual, _ = storage.FindByID(rob.RobotID)
+	assert.Equal(robot.StatusActive, actual.Status, "robot must be activated in the next window")
+
+	transitions, err := storage.FindTransitions(rob.RobotID)
+	assert.NoError(err)
+
+	if assert.Len(transitions, 2) {
+		assert.Equal(robot.ActionSuspend, transitions[0].Action)
+		assert.Equal(robot.StatusActive, transitions[0].FromStatus)
+		assert.Equal(robot.ActionActivate, transitions[1].Action)
+	}
+}
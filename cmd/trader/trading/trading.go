@@ -0,0 +1,298 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"gitlab.com/hitchpock/tfs-course-work/internal/calendar"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"google.golang.org/grpc"
+)
+
+const (
+	timeToSleep = 3
+)
+
+// Process структура фонового процесса торговли.
+type Process struct {
+	conn         *grpc.ClientConn
+	logger       log.Logger
+	robotStorage robot.Storage
+	events       event.Publisher
+	calendar     *calendar.Calendar
+	reload       chan struct{}
+}
+
+// NewProcess возвращает указатель на процесс торговли, который сообщает о сделках через events
+// и торгует только в сессии, заданные календарем cal.
+func NewProcess(conn *grpc.ClientConn, logger log.Logger, storage robot.Storage, events event.Publisher,
+	cal *calendar.Calendar) *Process {
+	process := &Process{
+		conn:         conn,
+		logger:       logger,
+		robotStorage: storage,
+		events:       events,
+		calendar:     cal,
+		reload:       make(chan struct{}, 1),
+	}
+
+	return process
+}
+
+// Reload прерывает текущий раунд торговли, чтобы заново загрузить роботов из хранилища.
+func (p *Process) Reload() {
+	select {
+	case p.reload <- struct{}{}:
+	default:
+	}
+}
+
+// HandleEvent перезагружает роботов, если API сообщило об их изменении.
+func (p *Process) HandleEvent(e event.Event) {
+	if e.Type == event.RobotChanged {
+		p.Reload()
+	}
+}
+
+// StartTrading торгует роботами раундами по timeToSleep секунд, пока не отменен parent.
+func (p *Process) StartTrading(parent context.Context) {
+	client := fintech.NewTradingServiceClient(p.conn)
+
+	for parent.Err() == nil {
+		ctx, cancel := context.WithTimeout(parent, timeToSleep*time.Second)
+
+		go func() {
+			select {
+			case <-p.reload:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		robots, err := p.robotStorage.FindToTrading()
+		if err != nil {
+			p.logger.Warnw("func robotStorage.FindToTrading return with error", "error", err)
+		}
+
+		tickerRobots := make(map[string][]robot.Robot)
+		for _, rob := range robots {
+			tickerRobots[rob.Ticker] = append(tickerRobots[rob.Ticker], rob)
+		}
+
+		tickers := make([]string, 0)
+		for key := range tickerRobots {
+			tickers = append(tickers, key)
+		}
+
+		if len(tickers) == 0 {
+			<-ctx.Done()
+			cancel()
+
+			continue
+		}
+
+		tickerChan := p.readService(ctx, client, tickers)
+
+		tickerChans := make(map[string]([]chan *fintech.Quote))
+		for ticker, value := range tickerChan {
+			tickerChans[ticker] = separateChan(value, len(tickerRobots[ticker]))
+		}
+
+		var wg sync.WaitGroup
+
+		wg.Add(len(robots))
+
+		for key, value := range tickerChans {
+			for index, ch := range value {
+				go p.Trade(tickerRobots[key][index], ch, &wg)
+			}
+		}
+
+		wg.Wait()
+		cancel()
+	}
+}
+
+// readService подписывается на котировки всех тикеров одним потоком и раскладывает их по каналам тикеров.
+func (p *Process) readService(ctx context.Context, client fintech.TradingServiceClient, tickers []string) map[string]chan *fintech.Quote {
+	outs := make(map[string]chan *fintech.Quote, len(tickers))
+	for _, ticker := range tickers {
+		outs[ticker] = make(chan *fintech.Quote)
+	}
+
+	req := fintech.SubscribeRequest{Tickers: tickers}
+
+	resp, err := client.Subscribe(ctx, &req)
+	if err != nil {
+		p.logger.Warnw("grpc func Subscribe return with error", "error", err)
+		closeTickerChans(outs)
+
+		return outs
+	}
+
+	go func() {
+		defer closeTickerChans(outs)
+
+		for {
+			quote, err := resp.Recv()
+			if err != nil {
+				if err == io.EOF {
+					p.logger.Warn("grpc channel is closed")
+					break
+				}
+
+				p.logger.Warnw("grpc resp.Recv() return with error", "error", err)
+
+				break
+			}
+
+			out, ok := outs[quote.Ticker]
+			if !ok {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+
+			case out <- quote:
+			}
+		}
+	}()
+
+	return outs
+}
+
+func separateChan(in chan *fintech.Quote, number int) []chan *fintech.Quote {
+	outs := make([]chan *fintech.Quote, 0)
+
+	for i := 0; i < number; i++ {
+		ch := make(chan *fintech.Quote)
+		outs = append(outs, ch)
+	}
+
+	go func() {
+		defer closeChan(outs)
+
+		for price := range in {
+			for _, ch := range outs {
+				ch := ch
+				ch <- price
+			}
+		}
+	}()
+
+	return outs
+}
+
+// Trade торгует роботом по котировкам его тикера: вне торговой сессии котировки пропускаются,
+// а перед концом сессии робот с AutoClose закрывает позицию и не открывает новую.
+// Если робота остановили во время раунда, оставшиеся котировки раунда только вычитываются из канала.
+func (p *Process) Trade(rob robot.Robot, in chan *fintech.Quote, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for price := range in {
+		now := quoteTime(price)
+		if !rob.Status.IsTrading() || !p.calendar.InSession(rob.Ticker, now) {
+			continue
+		}
+
+		closing := rob.AutoClose && p.calendar.ClosingSoon(rob.Ticker, now)
+
+		switch {
+		case closing && !rob.IsBuying:
+			rob.Sell(price.SellPrice)
+			p.saveTrade(&rob, rob.NewDeal(robot.SideSell, price.SellPrice, now))
+		case closing:
+		case rob.IsBuying && price.BuyPrice < rob.BuyPrice:
+			rob.Buy(price.BuyPrice)
+			p.saveTrade(&rob, rob.NewDeal(robot.SideBuy, price.BuyPrice, now))
+		case !rob.IsBuying && price.SellPrice > rob.SellPrice:
+			rob.Sell(price.SellPrice)
+			p.saveTrade(&rob, rob.NewDeal(robot.SideSell, price.SellPrice, now))
+		}
+	}
+}
+
+// saveTrade сохраняет сделку робота, сообщает о ней API и повторяет ее у подписчиков робота.
+// Сделку остановленного робота хранилище не сохраняет, тогда копия робота помечается остановленной.
+func (p *Process) saveTrade(rob *robot.Robot, deal robot.Deal) {
+	if err := p.robotStorage.Trade(rob, deal); err != nil {
+		p.logger.Warnw("func robotTorage.Trade return with error", "error", err, "robotID", rob.RobotID)
+
+		if errors.Is(err, robot.ErrNotTrading) {
+			rob.Status = robot.StatusStopped
+			return
+		}
+	}
+
+	p.publishTrade(rob.RobotID)
+
+	if !rob.IsMirror {
+		p.replicate(rob, deal)
+	}
+}
+
+// replicate повторяет сделку робота у торгующих подписчиков в их размере позиции.
+// Подписчик без позиции не продает, а подписчик с позицией не покупает повторно.
+func (p *Process) replicate(parent *robot.Robot, deal robot.Deal) {
+	followers, err := p.robotStorage.FindFollowers(parent.RobotID)
+	if err != nil {
+		p.logger.Warnw("func robotStorage.FindFollowers return with error", "error", err, "robotID", parent.RobotID)
+		return
+	}
+
+	for i := range followers {
+		follower := &followers[i]
+		if !follower.Status.IsTrading() {
+			continue
+		}
+
+		switch {
+		case deal.Side == robot.SideBuy && follower.IsBuying:
+			follower.Buy(deal.Price)
+		case deal.Side == robot.SideSell && !follower.IsBuying:
+			follower.Sell(deal.Price)
+		default:
+			continue
+		}
+
+		p.saveTrade(follower, follower.NewDeal(deal.Side, deal.Price, deal.CreatedAt))
+	}
+}
+
+// quoteTime возвращает время котировки, а если оно не задано — текущее время.
+func quoteTime(q *fintech.Quote) time.Time {
+	ts, err := ptypes.Timestamp(q.Ts)
+	if err != nil {
+		return time.Now()
+	}
+
+	return ts
+}
+
+// publishTrade сообщает API о сделке робота.
+func (p *Process) publishTrade(robotID int) {
+	if err := p.events.Publish(event.Event{Type: event.RobotTraded, RobotID: robotID}); err != nil {
+		p.logger.Warnw("func events.Publish return with error", "error", err, "robotID", robotID)
+	}
+}
+
+func closeChan(chans []chan *fintech.Quote) {
+	for _, ch := range chans {
+		close(ch)
+	}
+}
+
+func closeTickerChans(chans map[string]chan *fintech.Quote) {
+	for _, ch := range chans {
+		close(ch)
+	}
+}
@@ -0,0 +1,259 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/cmd/price-streamer/streamer"
+	"gitlab.com/hitchpock/tfs-course-work/internal/calendar"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"google.golang.org/grpc"
+)
+
+const waitTrades = 5 * time.Second
+
+type fakePublisher struct {
+	mutex  sync.Mutex
+	events []event.Event
+}
+
+func (f *fakePublisher) Publish(e event.Event) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.events = append(f.events, e)
+
+	return nil
+}
+
+func (f *fakePublisher) Events() []event.Event {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]event.Event(nil), f.events...)
+}
+
+// startStreamer запускает фейковый сервер котировок и возвращает соединение с ним.
+func startStreamer(t *testing.T, cfg streamer.Config) *grpc.ClientConn {
+	logger := log.NewSugarLogger()
+
+	server, err := streamer.NewServer(logger, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grpcServer := grpc.NewServer()
+	fintech.RegisterTradingServiceServer(grpcServer, server)
+
+	go grpcServer.Serve(lis) //nolint:errcheck
+
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestTradingEndToEnd(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := startStreamer(t, streamer.Config{Tickers: []streamer.TickerConfig{{
+		Ticker:   "TEST",
+		Model:    streamer.ModelScenario,
+		Interval: streamer.Duration(10 * time.Millisecond),
+		Steps: []streamer.Step{
+			{BuyPrice: 100, SellPrice: 99, Repeat: 3},
+			{BuyPrice: 90, SellPrice: 89},
+			{BuyPrice: 111, SellPrice: 110},
+			{BuyPrice: 100, SellPrice: 99, Repeat: 100},
+		},
+	}, {
+		Ticker:   "OTHER",
+		Model:    streamer.ModelScenario,
+		Interval: streamer.Duration(15 * time.Millisecond),
+		Steps: []streamer.Step{
+			{BuyPrice: 50, SellPrice: 49, Repeat: 2},
+			{BuyPrice: 40, SellPrice: 39},
+			{BuyPrice: 61, SellPrice: 60},
+			{BuyPrice: 50, SellPrice: 49, Repeat: 100},
+		},
+	}}})
+
+	storage := robot.CreateStorageInMemory()
+	active := &robot.Robot{OwnerUserID: 1, Ticker: "TEST", BuyPrice: 95, SellPrice: 105, IsActive: true}
+	inactive := &robot.Robot{OwnerUserID: 1, Ticker: "TEST", BuyPrice: 95, SellPrice: 105}
+	other := &robot.Robot{OwnerUserID: 2, Ticker: "OTHER", BuyPrice: 45, SellPrice: 55, IsActive: true}
+
+	assert.NoError(storage.Create(active))
+	assert.NoError(storage.Create(inactive))
+	assert.NoError(storage.Create(other))
+
+	follower, err := storage.FavouriteRobot(active.RobotID, 3, robot.Follow{Mirror: true, Lots: 2}, 0)
+	assert.NoError(err)
+	assert.NoError(storage.ActivateRobot(follower.RobotID))
+
+	publisher := &fakePublisher{}
+	process := NewProcess(conn, log.NewSugarLogger(), storage, publisher, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go process.StartTrading(ctx)
+
+	deadline := time.Now().Add(waitTrades)
+	for time.Now().Before(deadline) {
+		rob, _ := storage.FindByID(active.RobotID)
+		otherRob, _ := storage.FindByID(other.RobotID)
+
+		if rob.DealsCount > 0 && otherRob.DealsCount > 0 {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rob, err := storage.FindByID(active.RobotID)
+	assert.NoError(err)
+	assert.Equal(1, rob.DealsCount)
+	assert.InDelta(20.0, rob.FactYield, 1e-9)
+	assert.True(rob.IsBuying)
+
+	deals, err := storage.FindDeals(active.RobotID)
+	assert.NoError(err)
+
+	if assert.Len(deals, 2) {
+		assert.Equal(robot.SideBuy, deals[0].Side)
+		assert.Equal(robot.SideSell, deals[1].Side)
+		assert.Equal(1, deals[1].Version)
+	}
+
+	otherRob, err := storage.FindByID(other.RobotID)
+	assert.NoError(err)
+	assert.Equal(1, otherRob.DealsCount)
+	assert.InDelta(20.0, otherRob.FactYield, 1e-9)
+
+	idle, err := storage.FindByID(inactive.RobotID)
+	assert.NoError(err)
+	assert.Equal(0, idle.DealsCount)
+
+	mirrored, err := storage.FindByID(follower.RobotID)
+	assert.NoError(err)
+	assert.Equal(1, mirrored.DealsCount, "follower must repeat parent deals")
+	assert.InDelta(40.0, mirrored.FactYield, 1e-9, "follower yield must use its own lots")
+	assert.Equal(1, rob.Followers)
+
+	events := publisher.Events()
+	assert.True(len(events) >= 4, "expected buy and sell events for both robots, got %v", events)
+
+	for _, e := range events {
+		assert.Equal(event.RobotTraded, e.Type)
+		assert.Contains([]int{active.RobotID, other.RobotID, follower.RobotID}, e.RobotID)
+	}
+}
+
+// noonCalendar возвращает календарь, в часовом поясе которого сейчас около полудня,
+// с сессией 10:00-14:00 для тикера TEST.
+func noonCalendar(t *testing.T, holidays []string, autoCloseBefore string) *calendar.Calendar {
+	offset := 12 - time.Now().UTC().Hour()
+
+	timezone := fmt.Sprintf("Etc/GMT-%d", offset)
+	if offset < 0 {
+		timezone = fmt.Sprintf("Etc/GMT+%d", -offset)
+	}
+
+	cal, err := calendar.New(calendar.Config{
+		Exchanges: map[string]calendar.ExchangeConfig{
+			"TEST": {
+				Timezone: timezone,
+				Sessions: []calendar.SessionConfig{{Days: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}, Open: "10:00", Close: "14:00"}},
+				Holidays: holidays,
+			},
+		},
+		Instruments:     map[string]string{"TEST": "TEST"},
+		AutoCloseBefore: autoCloseBefore,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cal
+}
+
+func TestTradingCalendar(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := startStreamer(t, streamer.Config{Tickers: []streamer.TickerConfig{{
+		Ticker:   "TEST",
+		Model:    streamer.ModelScenario,
+		Interval: streamer.Duration(10 * time.Millisecond),
+		Steps: []streamer.Step{
+			{BuyPrice: 90, SellPrice: 89, Repeat: 20},
+		},
+	}}})
+
+	today := time.Now().In(time.FixedZone("", (12-time.Now().UTC().Hour())*3600)).Format("2006-01-02")
+
+	t.Run("Holiday", func(t *testing.T) {
+		storage := robot.CreateStorageInMemory()
+		rob := &robot.Robot{OwnerUserID: 1, Ticker: "TEST", BuyPrice: 95, SellPrice: 105, IsActive: true}
+		assert.NoError(storage.Create(rob))
+
+		process := NewProcess(conn, log.NewSugarLogger(), storage, &fakePublisher{}, noonCalendar(t, []string{today}, ""))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		process.StartTrading(ctx)
+
+		actual, err := storage.FindByID(rob.RobotID)
+		assert.NoError(err)
+		assert.True(actual.IsBuying, "robot must not buy on holiday")
+	})
+
+	t.Run("Auto close", func(t *testing.T) {
+		storage := robot.CreateStorageInMemory()
+		holding := &robot.Robot{OwnerUserID: 1, Ticker: "TEST", BuyPrice: 95, SellPrice: 105, IsActive: true, AutoClose: true}
+		waiting := &robot.Robot{OwnerUserID: 1, Ticker: "TEST", BuyPrice: 95, SellPrice: 105, IsActive: true, AutoClose: true}
+		assert.NoError(storage.Create(holding))
+		assert.NoError(storage.Create(waiting))
+
+		holding.Buy(100)
+		assert.NoError(storage.Trade(holding, robot.Deal{Side: robot.SideBuy, Price: 100, CreatedAt: time.Now()}))
+
+		process := NewProcess(conn, log.NewSugarLogger(), storage, &fakePublisher{}, noonCalendar(t, nil, "24h"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		process.StartTrading(ctx)
+
+		closed, err := storage.FindByID(holding.RobotID)
+		assert.NoError(err)
+		assert.True(closed.IsBuying, "position must be closed before session end")
+		assert.Equal(1, closed.DealsCount)
+		assert.InDelta(-11.0, closed.FactYield, 1e-9)
+
+		idle, err := storage.FindByID(waiting.RobotID)
+		assert.NoError(err)
+		assert.True(idle.IsBuying, "robot must not open position before session end")
+		assert.Equal(0, idle.DealsCount)
+	})
+}
@@ -6,10 +6,12 @@ require (
 	github.com/go-chi/chi v4.1.1+incompatible
 	github.com/golang/protobuf v1.4.1
 	github.com/gorilla/websocket v1.4.2
+	github.com/graph-gophers/graphql-go v0.0.0-20200309224638-dae41bde9ef9
 	github.com/lib/pq v1.5.0
 	github.com/stretchr/testify v1.5.1
 	go.uber.org/zap v1.15.0
 	golang.org/x/crypto v0.0.0-20200429183012-4b2356b1ed79
 	google.golang.org/grpc v1.29.1
 	google.golang.org/protobuf v1.22.0
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -6,7 +6,8 @@ CREATE TABLE users(
     email TEXT NOT NULL DEFAULT '',
     password TEXT NOT NULL DEFAULT '',
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    version INT NOT NULL DEFAULT 1
 );
 
 CREATE UNIQUE INDEX users_email_key ON users (email);
@@ -36,5 +37,99 @@ CREATE TABLE robots(
     deactivated_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
     deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
-    is_buying BOOLEAN NOT NULL DEFAULT true
+    is_buying BOOLEAN NOT NULL DEFAULT true,
+    auto_close BOOLEAN NOT NULL DEFAULT false,
+    schedule TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'scheduled', 'active', 'holding', 'paused', 'stopped', 'deleted')),
+    version INT NOT NULL DEFAULT 1,
+    is_mirror BOOLEAN NOT NULL DEFAULT false,
+    lots INT NOT NULL DEFAULT 1 CHECK (lots > 0),
+    visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('private', 'link', 'users', 'public')),
+    share_token TEXT NOT NULL DEFAULT '',
+    cloned_from_id BIGINT NOT NULL DEFAULT 0,
+    template_id BIGINT NOT NULL DEFAULT 0,
+    revision INT NOT NULL DEFAULT 1
 );
+
+CREATE INDEX robots_parent_robot_id_idx ON robots (parent_robot_id) WHERE is_mirror;
+CREATE INDEX robots_deleted_at_idx ON robots (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE robot_templates(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    owner_user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    ticker TEXT NOT NULL DEFAULT '',
+    buy_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    sell_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    plan_yield DOUBLE PRECISION NOT NULL DEFAULT 0,
+    auto_close BOOLEAN NOT NULL DEFAULT false,
+    schedule TEXT NOT NULL DEFAULT '',
+    lots INT NOT NULL DEFAULT 1 CHECK (lots > 0),
+    visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('private', 'link', 'users', 'public')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX robot_templates_owner_user_id_idx ON robot_templates (owner_user_id);
+
+CREATE TABLE robot_shares(
+    robot_id BIGINT NOT NULL REFERENCES robots (robot_id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (robot_id, user_id)
+);
+
+CREATE TABLE robot_transitions(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    robot_id BIGINT NOT NULL REFERENCES robots (robot_id) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX robot_transitions_robot_id_idx ON robot_transitions (robot_id);
+
+CREATE TABLE robot_versions(
+    robot_id BIGINT NOT NULL REFERENCES robots (robot_id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    ticker TEXT NOT NULL DEFAULT '',
+    buy_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    sell_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    plan_start TIMESTAMP WITH TIME ZONE,
+    plan_end TIMESTAMP WITH TIME ZONE,
+    plan_yield DOUBLE PRECISION NOT NULL DEFAULT 0,
+    auto_close BOOLEAN NOT NULL DEFAULT false,
+    schedule TEXT NOT NULL DEFAULT '',
+    lots INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (robot_id, version)
+);
+
+CREATE TABLE robot_deals(
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    robot_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    side TEXT NOT NULL CHECK (side IN ('buy', 'sell')),
+    price DOUBLE PRECISION NOT NULL,
+    lots INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    FOREIGN KEY (robot_id, version) REFERENCES robot_versions (robot_id, version) ON DELETE CASCADE
+);
+
+CREATE INDEX robot_deals_robot_id_idx ON robot_deals (robot_id, created_at);
+
+CREATE TABLE idempotency_keys(
+    user_id BIGINT NOT NULL,
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    status INT NOT NULL DEFAULT 0,
+    header JSONB NOT NULL DEFAULT '{}',
+    body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,243 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	clockLayout = "15:04"
+)
+
+var (
+	ErrUnknownExchange = errors.New("unknown exchange")
+	ErrInvalidSession  = errors.New("invalid session")
+)
+
+var weekdays = map[string]time.Weekday{ //nolint:gochecknoglobals
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Config структура файла календаря инструментов.
+type Config struct {
+	Exchanges   map[string]ExchangeConfig `json:"exchanges"`
+	Instruments map[string]string         `json:"instruments"`
+	// AutoCloseBefore за сколько до конца сессии закрываются позиции роботов с auto_close.
+	AutoCloseBefore string `json:"auto_close_before"`
+}
+
+// ExchangeConfig расписание торгов биржи в ее часовом поясе.
+type ExchangeConfig struct {
+	Timezone string          `json:"timezone"`
+	Sessions []SessionConfig `json:"sessions"`
+	Holidays []string        `json:"holidays"`
+}
+
+// SessionConfig торговая сессия в указанные дни недели, сессия не переходит через полночь.
+type SessionConfig struct {
+	Days  []string `json:"days"`
+	Open  string   `json:"open"`
+	Close string   `json:"close"`
+}
+
+type session struct {
+	days  map[time.Weekday]bool
+	open  time.Duration
+	close time.Duration
+}
+
+type exchange struct {
+	location *time.Location
+	sessions []session
+	holidays map[string]bool
+}
+
+// Calendar отвечает на вопрос, идут ли торги по тикеру в момент времени.
+// Тикеры, которых нет в календаре, торгуются круглосуточно.
+type Calendar struct {
+	exchanges       map[string]*exchange
+	instruments     map[string]string
+	autoCloseBefore time.Duration
+}
+
+// Load читает календарь из JSON файла.
+func Load(path string) (*Calendar, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read calendar: %s", err)
+	}
+
+	var cfg Config
+	if err = json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("can't unmarshal calendar: %s", err)
+	}
+
+	return New(cfg)
+}
+
+// New возвращает указатель на календарь, построенный по конфигурации.
+func New(cfg Config) (*Calendar, error) {
+	c := &Calendar{
+		exchanges:   make(map[string]*exchange, len(cfg.Exchanges)),
+		instruments: make(map[string]string, len(cfg.Instruments)),
+	}
+
+	if cfg.AutoCloseBefore != "" {
+		d, err := time.ParseDuration(cfg.AutoCloseBefore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto_close_before: %s", err)
+		}
+
+		c.autoCloseBefore = d
+	}
+
+	for name, exCfg := range cfg.Exchanges {
+		ex, err := newExchange(exCfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange %q: %s", name, err)
+		}
+
+		c.exchanges[name] = ex
+	}
+
+	for ticker, name := range cfg.Instruments {
+		if _, ok := c.exchanges[name]; !ok {
+			return nil, fmt.Errorf("%w %q for ticker %q", ErrUnknownExchange, name, ticker)
+		}
+
+		c.instruments[ticker] = name
+	}
+
+	return c, nil
+}
+
+func newExchange(cfg ExchangeConfig) (*exchange, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("can't load timezone %q: %s", cfg.Timezone, err)
+	}
+
+	ex := &exchange{location: location, holidays: make(map[string]bool, len(cfg.Holidays))}
+
+	for _, day := range cfg.Holidays {
+		if _, err = time.Parse(dateLayout, day); err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: %s", day, err)
+		}
+
+		ex.holidays[day] = true
+	}
+
+	for _, sCfg := range cfg.Sessions {
+		s, err := newSession(sCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		ex.sessions = append(ex.sessions, s)
+	}
+
+	return ex, nil
+}
+
+func newSession(cfg SessionConfig) (session, error) {
+	s := session{days: make(map[time.Weekday]bool, len(cfg.Days))}
+
+	for _, day := range cfg.Days {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return s, fmt.Errorf("%w: unknown day %q", ErrInvalidSession, day)
+		}
+
+		s.days[weekday] = true
+	}
+
+	var err error
+
+	if s.open, err = parseClock(cfg.Open); err != nil {
+		return s, err
+	}
+
+	if s.close, err = parseClock(cfg.Close); err != nil {
+		return s, err
+	}
+
+	if s.close <= s.open {
+		return s, fmt.Errorf("%w: close %s is not after open %s", ErrInvalidSession, cfg.Close, cfg.Open)
+	}
+
+	return s, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse(clockLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid time %q", ErrInvalidSession, value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// InSession сообщает, идут ли торги по тикеру в момент t.
+func (c *Calendar) InSession(ticker string, t time.Time) bool {
+	_, ok := c.SessionEnd(ticker, t)
+	return ok
+}
+
+// SessionEnd возвращает время окончания текущей сессии тикера, ok == false вне сессии.
+// Для тикеров без расписания сессия не заканчивается и возвращается нулевое время.
+func (c *Calendar) SessionEnd(ticker string, t time.Time) (end time.Time, ok bool) {
+	ex := c.exchangeOf(ticker)
+	if ex == nil {
+		return time.Time{}, true
+	}
+
+	local := t.In(ex.location)
+	if ex.holidays[local.Format(dateLayout)] {
+		return time.Time{}, false
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, ex.location)
+	sinceMidnight := local.Sub(midnight)
+
+	for _, s := range ex.sessions {
+		if s.days[local.Weekday()] && sinceMidnight >= s.open && sinceMidnight < s.close {
+			return midnight.Add(s.close), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// ClosingSoon сообщает, что до конца сессии тикера осталось меньше auto_close_before.
+func (c *Calendar) ClosingSoon(ticker string, t time.Time) bool {
+	end, ok := c.SessionEnd(ticker, t)
+	if !ok || end.IsZero() {
+		return false
+	}
+
+	return end.Sub(t) <= c.autoCloseBefore
+}
+
+func (c *Calendar) exchangeOf(ticker string) *exchange {
+	if c == nil {
+		return nil
+	}
+
+	name, ok := c.instruments[ticker]
+	if !ok {
+		return nil
+	}
+
+	return c.exchanges[name]
+}
@@ -0,0 +1,137 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCalendar(t *testing.T) *Calendar {
+	c, err := New(Config{
+		Exchanges: map[string]ExchangeConfig{
+			"MOEX": {
+				Timezone: "Europe/Moscow",
+				Sessions: []SessionConfig{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Open: "10:00", Close: "18:45"}},
+				Holidays: []string{"2020-06-12"},
+			},
+		},
+		Instruments:     map[string]string{"SBER": "MOEX"},
+		AutoCloseBefore: "10m",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestInSession(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Ticker   string
+		Time     time.Time
+		Expected bool
+	}
+
+	assert := assert.New(t)
+	c := testCalendar(t)
+
+	testCases := []testCase{
+		{Name: "Weekday in session", Ticker: "SBER", Time: time.Date(2020, 6, 10, 7, 0, 0, 0, time.UTC), Expected: true},
+		{Name: "Before open", Ticker: "SBER", Time: time.Date(2020, 6, 10, 6, 59, 0, 0, time.UTC), Expected: false},
+		{Name: "At close", Ticker: "SBER", Time: time.Date(2020, 6, 10, 15, 45, 0, 0, time.UTC), Expected: false},
+		{Name: "Weekend", Ticker: "SBER", Time: time.Date(2020, 6, 13, 12, 0, 0, 0, time.UTC), Expected: false},
+		{Name: "Holiday", Ticker: "SBER", Time: time.Date(2020, 6, 12, 12, 0, 0, 0, time.UTC), Expected: false},
+		{Name: "Ticker without schedule", Ticker: "TEST", Time: time.Date(2020, 6, 13, 3, 0, 0, 0, time.UTC), Expected: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			actual := c.InSession(tc.Ticker, tc.Time)
+			assert.Equal(tc.Expected, actual, "InSession(%q, %s) = %v, want %v", tc.Ticker, tc.Time, actual, tc.Expected)
+		})
+	}
+}
+
+func TestClosingSoon(t *testing.T) {
+	assert := assert.New(t)
+	c := testCalendar(t)
+
+	assert.False(c.ClosingSoon("SBER", time.Date(2020, 6, 10, 15, 34, 0, 0, time.UTC)))
+	assert.True(c.ClosingSoon("SBER", time.Date(2020, 6, 10, 15, 35, 0, 0, time.UTC)))
+	assert.False(c.ClosingSoon("SBER", time.Date(2020, 6, 10, 16, 0, 0, 0, time.UTC)))
+	assert.False(c.ClosingSoon("TEST", time.Date(2020, 6, 10, 15, 40, 0, 0, time.UTC)))
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := New(Config{Instruments: map[string]string{"SBER": "MOEX"}})
+	assert.Error(err)
+
+	_, err = New(Config{Exchanges: map[string]ExchangeConfig{
+		"MOEX": {Timezone: "UTC", Sessions: []SessionConfig{{Days: []string{"mon"}, Open: "18:00", Close: "10:00"}}},
+	}})
+	assert.Error(err)
+
+	_, err = New(Config{Exchanges: map[string]ExchangeConfig{
+		"MOEX": {Timezone: "UTC", Sessions: []SessionConfig{{Days: []string{"monday"}, Open: "10:00", Close: "18:00"}}},
+	}})
+	assert.Error(err)
+}
+
+func TestParseRecurring(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Spec     string
+		Time     time.Time
+		Expected bool
+		HasError bool
+	}
+
+	assert := assert.New(t)
+
+	testCases := []testCase{
+		{Name: "Weekdays range", Spec: "mon-fri 10:00-18:00", Time: time.Date(2020, 6, 5, 17, 59, 0, 0, time.UTC), Expected: true},
+		{Name: "Weekend", Spec: "mon-fri 10:00-18:00", Time: time.Date(2020, 6, 6, 12, 0, 0, 0, time.UTC), Expected: false},
+		{Name: "Days list", Spec: "mon,wed 10:00-18:00", Time: time.Date(2020, 6, 3, 12, 0, 0, 0, time.UTC), Expected: true},
+		{Name: "Daily with timezone", Spec: "daily 10:00-18:00 Europe/Moscow", Time: time.Date(2020, 6, 6, 7, 0, 0, 0, time.UTC), Expected: true},
+		{Name: "Unknown day", Spec: "weekdays 10:00-18:00", HasError: true},
+		{Name: "Invalid range", Spec: "mon-fri 18:00-10:00", HasError: true},
+		{Name: "Unknown timezone", Spec: "mon-fri 10:00-18:00 Mars/Olympus", HasError: true},
+		{Name: "Missing time", Spec: "mon-fri", HasError: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			r, err := ParseRecurring(tc.Spec)
+			if tc.HasError {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.Expected, r.Contains(tc.Time))
+		})
+	}
+}
+
+func TestLastBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	r, err := ParseRecurring("mon-fri 10:00-18:00")
+	assert.NoError(err)
+
+	last, ok := r.LastBoundary(time.Date(2020, 6, 8, 9, 0, 0, 0, time.UTC))
+	assert.True(ok)
+	assert.Equal(time.Date(2020, 6, 5, 18, 0, 0, 0, time.UTC), last.UTC())
+
+	last, ok = r.LastBoundary(time.Date(2020, 6, 8, 10, 0, 0, 0, time.UTC))
+	assert.True(ok)
+	assert.Equal(time.Date(2020, 6, 8, 10, 0, 0, 0, time.UTC), last.UTC())
+}
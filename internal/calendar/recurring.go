@@ -0,0 +1,123 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	daysInWeek    = 7
+	specMinFields = 2
+	specMaxFields = 3
+)
+
+// Recurring повторяющееся окно времени, например "mon-fri 10:00-18:00 Europe/Moscow".
+type Recurring struct {
+	location *time.Location
+	session  session
+}
+
+// ParseRecurring разбирает расписание вида "<дни> <начало>-<конец> [часовой пояс]".
+// Дни задаются списком "mon,wed,fri", диапазоном "mon-fri" или "daily", часовой пояс по умолчанию UTC.
+func ParseRecurring(spec string) (*Recurring, error) {
+	fields := strings.Fields(spec)
+	if len(fields) < specMinFields || len(fields) > specMaxFields {
+		return nil, fmt.Errorf("%w: expected \"<days> <open>-<close> [timezone]\", got %q", ErrInvalidSession, spec)
+	}
+
+	days, err := parseDays(fields[0])
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := strings.Split(fields[1], "-")
+	if len(bounds) != 2 { //nolint:gomnd
+		return nil, fmt.Errorf("%w: invalid time range %q", ErrInvalidSession, fields[1])
+	}
+
+	s, err := newSession(SessionConfig{Days: days, Open: bounds[0], Close: bounds[1]})
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+
+	if len(fields) == specMaxFields {
+		if location, err = time.LoadLocation(fields[2]); err != nil {
+			return nil, fmt.Errorf("%w: can't load timezone %q: %s", ErrInvalidSession, fields[2], err)
+		}
+	}
+
+	return &Recurring{location: location, session: s}, nil
+}
+
+func parseDays(value string) ([]string, error) {
+	order := []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+	if value == "daily" || value == "*" {
+		return order, nil
+	}
+
+	var days []string
+
+	for _, part := range strings.Split(strings.ToLower(value), ",") {
+		bounds := strings.Split(part, "-")
+		if len(bounds) == 1 {
+			days = append(days, part)
+			continue
+		}
+
+		first, last := indexOf(order, bounds[0]), indexOf(order, bounds[1])
+		if len(bounds) != 2 || first < 0 || last < 0 || last < first { //nolint:gomnd
+			return nil, fmt.Errorf("%w: invalid days range %q", ErrInvalidSession, part)
+		}
+
+		days = append(days, order[first:last+1]...)
+	}
+
+	return days, nil
+}
+
+func indexOf(values []string, value string) int {
+	for i := range values {
+		if values[i] == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Contains сообщает, попадает ли t в окно расписания.
+func (r *Recurring) Contains(t time.Time) bool {
+	local := t.In(r.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, r.location)
+	sinceMidnight := local.Sub(midnight)
+
+	return r.session.days[local.Weekday()] && sinceMidnight >= r.session.open && sinceMidnight < r.session.close
+}
+
+// LastBoundary возвращает последнее начало или конец окна, не позже t.
+func (r *Recurring) LastBoundary(t time.Time) (time.Time, bool) {
+	local := t.In(r.location)
+
+	for back := 0; back <= daysInWeek; back++ {
+		day := local.AddDate(0, 0, -back)
+		if !r.session.days[day.Weekday()] {
+			continue
+		}
+
+		midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, r.location)
+
+		if closeAt := midnight.Add(r.session.close); !closeAt.After(t) {
+			return closeAt, true
+		}
+
+		if openAt := midnight.Add(r.session.open); !openAt.After(t) {
+			return openAt, true
+		}
+	}
+
+	return time.Time{}, false
+}
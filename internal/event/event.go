@@ -0,0 +1,25 @@
+package event
+
+// Типы событий, которыми обмениваются API и торговый сервис.
+const (
+	// RobotChanged публикуется API, когда пользователь меняет робота.
+	RobotChanged = "robot_changed"
+	// RobotTraded публикуется торговым сервисом после совершения сделки.
+	RobotTraded = "robot_traded"
+)
+
+// Publisher публикует события для других сервисов.
+type Publisher interface {
+	Publish(e Event) error
+}
+
+// Subscriber доставляет опубликованные события обработчику.
+type Subscriber interface {
+	Subscribe(handler func(e Event)) error
+}
+
+// Event структура события об изменении робота.
+type Event struct {
+	Type    string `json:"type"`
+	RobotID int    `json:"robot_id"`
+}
@@ -0,0 +1,52 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// DefaultTTL сколько по умолчанию хранится ответ на запрос с ключом идемпотентности.
+const DefaultTTL = 24 * time.Hour
+
+// Storage интерфейс хранилища ключей идемпотентности. Ключ действует в пределах пользователя до ExpiresAt.
+type Storage interface {
+	// Reserve сохраняет rec без ответа и возвращает nil, если у пользователя нет действующей записи с ключом rec.Key.
+	// Иначе возвращает действующую запись, а rec не сохраняет.
+	Reserve(rec *Record) (*Record, error)
+	// Complete сохраняет ответ на запрос записи и ее новый срок ExpiresAt. Если запись уже заменена
+	// повтором, который занял ключ после истечения срока резервирования, ответ не сохраняется.
+	Complete(rec *Record) error
+	// Release удаляет запись без ответа, чтобы запрос можно было повторить.
+	Release(userID int, key string) error
+	// DeleteExpired удаляет записи, срок которых истек к now, и возвращает их число.
+	DeleteExpired(now time.Time) (int, error)
+}
+
+// Record запрос с ключом идемпотентности и ответ на него. Пока запрос выполняется, Status равен нулю,
+// а ExpiresAt — короткий срок резервирования, после которого ключ может занять повтор запроса.
+type Record struct {
+	UserID      int
+	Key         string
+	Fingerprint string
+	Status      int
+	Header      map[string]string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Done сообщает, сохранен ли ответ на запрос.
+func (r *Record) Done() bool {
+	return r.Status != 0
+}
+
+// Fingerprint возвращает отпечаток запроса: повтор с тем же ключом должен совпадать с исходным запросом
+// по методу, адресу с параметрами запроса и телу.
+func Fingerprint(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + uri + "\n"))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
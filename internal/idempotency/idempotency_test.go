@@ -0,0 +1,92 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageInMemory(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+	now := time.Now()
+
+	rec := &Record{UserID: 1, Key: "key", Fingerprint: Fingerprint(http.MethodPost, "/robot", []byte("{}")),
+		CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	existing, err := storage.Reserve(rec)
+	assert.NoError(err)
+	assert.Nil(existing)
+
+	existing, err = storage.Reserve(rec)
+	assert.NoError(err)
+	assert.False(existing.Done(), "request is in progress")
+
+	assert.NoError(storage.Release(1, "key"))
+
+	existing, err = storage.Reserve(rec)
+	assert.NoError(err)
+	assert.Nil(existing, "released key can be used again")
+
+	rec.Status, rec.Body = http.StatusCreated, []byte("{}")
+	assert.NoError(storage.Complete(rec))
+	assert.NoError(storage.Release(1, "key"), "completed record stays")
+
+	existing, err = storage.Reserve(rec)
+	assert.NoError(err)
+	assert.True(existing.Done())
+	assert.Equal(rec.Body, existing.Body)
+
+	other := *rec
+	other.UserID = 2
+	existing, err = storage.Reserve(&other)
+	assert.NoError(err)
+	assert.Nil(existing, "keys are per user")
+
+	later := *rec
+	later.CreatedAt, later.ExpiresAt = rec.ExpiresAt, rec.ExpiresAt.Add(2*time.Hour)
+	existing, err = storage.Reserve(&later)
+	assert.NoError(err)
+	assert.Nil(existing, "expired record is replaced")
+
+	n, err := storage.DeleteExpired(now.Add(2 * time.Hour))
+	assert.NoError(err)
+	assert.Equal(1, n, "only the replaced record of user 1 is left")
+
+	assert.NotEqual(Fingerprint(http.MethodPost, "/robot", nil), Fingerprint(http.MethodPut, "/robot", nil))
+	assert.NotEqual(Fingerprint(http.MethodPost, "/robots/batch?atomic=true", nil),
+		Fingerprint(http.MethodPost, "/robots/batch", nil))
+}
+
+func TestStorageInMemoryTakeover(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+	now := time.Now()
+
+	abandoned := &Record{UserID: 1, Key: "key", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	existing, err := storage.Reserve(abandoned)
+	assert.NoError(err)
+	assert.Nil(existing)
+
+	retry := &Record{UserID: 1, Key: "key", CreatedAt: now.Add(2 * time.Minute), ExpiresAt: now.Add(3 * time.Minute)}
+	existing, err = storage.Reserve(retry)
+	assert.NoError(err)
+	assert.Nil(existing, "expired reservation is taken over")
+
+	abandoned.Status, abandoned.ExpiresAt = http.StatusCreated, now.Add(24*time.Hour)
+	assert.NoError(storage.Complete(abandoned))
+
+	existing, err = storage.Reserve(&Record{UserID: 1, Key: "key", CreatedAt: now.Add(2 * time.Minute)})
+	assert.NoError(err)
+	assert.False(existing.Done(), "late response must not overwrite the retry")
+
+	retry.Status, retry.ExpiresAt = http.StatusOK, now.Add(24*time.Hour)
+	assert.NoError(storage.Complete(retry))
+
+	existing, err = storage.Reserve(&Record{UserID: 1, Key: "key", CreatedAt: now.Add(time.Hour)})
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, existing.Status)
+	assert.Equal(retry.ExpiresAt, existing.ExpiresAt, "completed record is kept for the ttl")
+}
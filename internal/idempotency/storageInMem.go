@@ -0,0 +1,84 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+var _ Storage = &StorageInMemory{}
+
+type recordKey struct {
+	userID int
+	key    string
+}
+
+// StorageInMemory структура хранилища ключей идемпотентности в памяти.
+type StorageInMemory struct {
+	storage map[recordKey]Record
+	mutex   sync.Mutex
+}
+
+// CreateStorageInMemory возвращает указатель на хранилище ключей идемпотентности in-memory.
+func CreateStorageInMemory() *StorageInMemory {
+	return &StorageInMemory{storage: make(map[recordKey]Record)}
+}
+
+// Reserve сохраняет rec, если у пользователя нет действующей записи с тем же ключом, иначе возвращает ее.
+func (s *StorageInMemory) Reserve(rec *Record) (*Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	k := recordKey{userID: rec.UserID, key: rec.Key}
+	if existing, ok := s.storage[k]; ok && existing.ExpiresAt.After(rec.CreatedAt) {
+		return &existing, nil
+	}
+
+	reserved := *rec
+	reserved.Status, reserved.Header, reserved.Body = 0, nil, nil
+	s.storage[k] = reserved
+
+	return nil, nil
+}
+
+// Complete сохраняет ответ на запрос записи, если ее не заменил повтор запроса.
+func (s *StorageInMemory) Complete(rec *Record) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	k := recordKey{userID: rec.UserID, key: rec.Key}
+	if existing, ok := s.storage[k]; ok && !existing.Done() && existing.CreatedAt.Equal(rec.CreatedAt) {
+		s.storage[k] = *rec
+	}
+
+	return nil
+}
+
+// Release удаляет запись без ответа.
+func (s *StorageInMemory) Release(userID int, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	k := recordKey{userID: userID, key: key}
+	if rec, ok := s.storage[k]; ok && !rec.Done() {
+		delete(s.storage, k)
+	}
+
+	return nil
+}
+
+// DeleteExpired удаляет записи, срок которых истек к now.
+func (s *StorageInMemory) DeleteExpired(now time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	n := 0
+
+	for k, rec := range s.storage {
+		if !rec.ExpiresAt.After(now) {
+			delete(s.storage, k)
+			n++
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+)
+
+var (
+	_ event.Publisher  = &EventBus{}
+	_ event.Subscriber = &EventBus{}
+)
+
+const (
+	eventsChannel = "robot_events"
+
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+	listenerPingInterval = 90 * time.Second
+)
+
+// EventBus передает события между сервисами через LISTEN/NOTIFY.
+type EventBus struct {
+	statementStorage
+
+	url      string
+	listener *pq.Listener
+	handlers []func(e event.Event)
+	mutex    sync.Mutex
+
+	publishStmt *sql.Stmt
+}
+
+// NewEventBus возвращает указатель на шину событий поверх postgres.
+func NewEventBus(db *DB, url string) (*EventBus, error) {
+	b := &EventBus{statementStorage: newStatementStorage(db), url: url}
+
+	stmts := []stmt{
+		{Query: publishEventQuery, Dst: &b.publishStmt},
+	}
+
+	if err := b.initStatements(stmts); err != nil {
+		return nil, fmt.Errorf("can't init statements: %s", err)
+	}
+
+	return b, nil
+}
+
+const publishEventQuery = `SELECT pg_notify($1, $2)`
+
+// Publish отправляет событие всем подписанным сервисам.
+func (b *EventBus) Publish(e event.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("can't marshal event: %s", err)
+	}
+
+	if _, err = b.publishStmt.Exec(eventsChannel, string(payload)); err != nil {
+		return fmt.Errorf("can't notify %q: %s", eventsChannel, err)
+	}
+
+	return nil
+}
+
+// Subscribe добавляет обработчик событий, при первом вызове начинает слушать канал.
+func (b *EventBus) Subscribe(handler func(e event.Event)) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+
+	if b.listener != nil {
+		return nil
+	}
+
+	listener := pq.NewListener(b.url, minReconnectInterval, maxReconnectInterval, b.reportProblem)
+	if err := listener.Listen(eventsChannel); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("can't listen %q: %s", eventsChannel, err)
+	}
+
+	b.listener = listener
+
+	go b.listen()
+
+	return nil
+}
+
+// listen читает уведомления и передает их обработчикам.
+func (b *EventBus) listen() {
+	for {
+		select {
+		case n, ok := <-b.listener.Notify:
+			if !ok {
+				return
+			}
+
+			if n == nil {
+				b.db.Logger.Info("listener connection is re-established")
+				continue
+			}
+
+			var e event.Event
+			if err := json.Unmarshal([]byte(n.Extra), &e); err != nil {
+				b.db.Logger.Warnw("can't unmarshal event", "error", err, "payload", n.Extra)
+				continue
+			}
+
+			b.mutex.Lock()
+			handlers := b.handlers
+			b.mutex.Unlock()
+
+			for _, handler := range handlers {
+				handler(e)
+			}
+
+		case <-time.After(listenerPingInterval):
+			if err := b.listener.Ping(); err != nil {
+				b.db.Logger.Warnw("listener ping return with error", "error", err)
+			}
+		}
+	}
+}
+
+func (b *EventBus) reportProblem(ev pq.ListenerEventType, err error) {
+	if err != nil {
+		b.db.Logger.Warnw("listener return with error", "event", ev, "error", err)
+	}
+}
+
+// Close прекращает прослушивание и закрывает подготовленные запросы.
+func (b *EventBus) Close() error {
+	b.mutex.Lock()
+	listener := b.listener
+	b.mutex.Unlock()
+
+	if listener != nil {
+		if err := listener.Close(); err != nil {
+			return fmt.Errorf("can't close listener: %s", err)
+		}
+	}
+
+	return b.statementStorage.Close()
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/idempotency"
+)
+
+var _ idempotency.Storage = &IdempotencyStorage{}
+
+// IdempotencyStorage хранилище ключей идемпотентности в postgres.
+type IdempotencyStorage struct {
+	statementStorage
+
+	reserveStmt       *sql.Stmt
+	findStmt          *sql.Stmt
+	completeStmt      *sql.Stmt
+	releaseStmt       *sql.Stmt
+	deleteExpiredStmt *sql.Stmt
+}
+
+// NewIdempotencyStorage возвращает указатель на хранилище ключей идемпотентности.
+func NewIdempotencyStorage(db *DB) (*IdempotencyStorage, error) {
+	s := &IdempotencyStorage{statementStorage: newStatementStorage(db)}
+
+	stmts := []stmt{
+		{Query: reserveIdempotencyKeyQuery, Dst: &s.reserveStmt},
+		{Query: findIdempotencyKeyQuery, Dst: &s.findStmt},
+		{Query: completeIdempotencyKeyQuery, Dst: &s.completeStmt},
+		{Query: releaseIdempotencyKeyQuery, Dst: &s.releaseStmt},
+		{Query: deleteExpiredIdempotencyKeysQuery, Dst: &s.deleteExpiredStmt},
+	}
+
+	if err := s.initStatements(stmts); err != nil {
+		return nil, fmt.Errorf("can't init statements: %s", err)
+	}
+
+	return s, nil
+}
+
+const idempotencyFields = `user_id, key, fingerprint, status, header, body, created_at, expires_at`
+
+func scanIdempotencyRecord(scanner sqlScanner, rec *idempotency.Record) error {
+	var header []byte
+
+	if err := scanner.Scan(&rec.UserID, &rec.Key, &rec.Fingerprint, &rec.Status, &header, &rec.Body,
+		&rec.CreatedAt, &rec.ExpiresAt); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(header, &rec.Header); err != nil {
+		return fmt.Errorf("can't unmarshal header: %s", err)
+	}
+
+	return nil
+}
+
+// Истекшая запись с тем же ключом заменяется новой в том же запросе.
+const reserveIdempotencyKeyQuery = `INSERT INTO idempotency_keys(user_id, key, fingerprint, created_at, expires_at) ` +
+	`VALUES ($1, $2, $3, $4, $5) ` +
+	`ON CONFLICT (user_id, key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, status = 0, header = '{}', ` +
+	`body = NULL, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at ` +
+	`WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`
+
+const findIdempotencyKeyQuery = `SELECT ` + idempotencyFields + ` FROM idempotency_keys WHERE user_id = $1 AND key = $2`
+
+// reserveAttempts сколько раз Reserve пробует занять ключ, если запись удаляют между вставкой и чтением.
+const reserveAttempts = 3
+
+// Reserve сохраняет rec, если у пользователя нет действующей записи с тем же ключом, иначе возвращает ее.
+// Запись, которую не удалось вставить, может удалить Release запроса, завершившегося ошибкой, до того как
+// ее прочитает Reserve; тогда ключ свободен и вставка повторяется.
+func (s *IdempotencyStorage) Reserve(rec *idempotency.Record) (*idempotency.Record, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := s.reserveStmt.Exec(rec.UserID, rec.Key, rec.Fingerprint, rec.CreatedAt, rec.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("can't insert idempotency key in database: %s", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("can't get affected rows: %s", err)
+		}
+
+		if n > 0 {
+			return nil, nil
+		}
+
+		var existing idempotency.Record
+
+		err = scanIdempotencyRecord(s.findStmt.QueryRow(rec.UserID, rec.Key), &existing)
+		if errors.Is(err, sql.ErrNoRows) && attempt < reserveAttempts {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("can't scan idempotency key: %s", err)
+		}
+
+		return &existing, nil
+	}
+}
+
+// Запись, которую после истечения резервирования занял повтор запроса, отличается временем создания.
+const completeIdempotencyKeyQuery = `UPDATE idempotency_keys ` +
+	`SET status = $3, header = $4, body = $5, expires_at = $6 ` +
+	`WHERE user_id = $1 AND key = $2 AND status = 0 AND created_at = $7`
+
+// Complete сохраняет ответ на запрос записи, если ее не заменил повтор запроса.
+func (s *IdempotencyStorage) Complete(rec *idempotency.Record) error {
+	header, err := json.Marshal(rec.Header)
+	if err != nil {
+		return fmt.Errorf("can't marshal header: %s", err)
+	}
+
+	if _, err = s.completeStmt.Exec(rec.UserID, rec.Key, rec.Status, string(header), rec.Body, rec.ExpiresAt,
+		rec.CreatedAt); err != nil {
+		return fmt.Errorf("can't update idempotency key in database: %s", err)
+	}
+
+	return nil
+}
+
+const releaseIdempotencyKeyQuery = `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND status = 0`
+
+// Release удаляет запись без ответа.
+func (s *IdempotencyStorage) Release(userID int, key string) error {
+	if _, err := s.releaseStmt.Exec(userID, key); err != nil {
+		return fmt.Errorf("can't delete idempotency key from database: %s", err)
+	}
+
+	return nil
+}
+
+const deleteExpiredIdempotencyKeysQuery = `DELETE FROM idempotency_keys WHERE expires_at <= $1`
+
+// DeleteExpired удаляет записи, срок которых истек к now.
+func (s *IdempotencyStorage) DeleteExpired(now time.Time) (int, error) {
+	res, err := s.deleteExpiredStmt.Exec(now)
+	if err != nil {
+		return 0, fmt.Errorf("can't delete expired idempotency keys: %s", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("can't get affected rows: %s", err)
+	}
+
+	return int(n), nil
+}
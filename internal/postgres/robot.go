@@ -4,9 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
 )
 
@@ -21,11 +22,37 @@ type RobotStorage struct {
 	findActivatedByTicker       *sql.Stmt
 	findActivatedStmt           *sql.Stmt
 	findActivatedByTickerUserID *sql.Stmt
-	activateRobotStmt           *sql.Stmt
-	deactivateRobotStmt         *sql.Stmt
+	findForUpdateStmt           *sql.Stmt
+	applyTransitionStmt         *sql.Stmt
 	findToTradingStmt           *sql.Stmt
+	findScheduledStmt           *sql.Stmt
+	createTransitionStmt        *sql.Stmt
+	findTransitionsStmt         *sql.Stmt
+	editStmt                    *sql.Stmt
+	createVersionStmt           *sql.Stmt
+	findVersionsStmt            *sql.Stmt
 	tradeStmt                   *sql.Stmt
-	softDeleteStmt              *sql.Stmt
+	closePositionStmt           *sql.Stmt
+	createDealStmt              *sql.Stmt
+	findDealsStmt               *sql.Stmt
+	findDealsByRobotIDsStmt     *sql.Stmt
+	findFollowersStmt           *sql.Stmt
+	findFollowersForUpdateStmt  *sql.Stmt
+	unfollowStmt                *sql.Stmt
+	findVisibleStmt             *sql.Stmt
+	findVisibleByIDsStmt        *sql.Stmt
+	shareStmt                   *sql.Stmt
+	unshareStmt                 *sql.Stmt
+	findSharesStmt              *sql.Stmt
+	findDeletedStmt             *sql.Stmt
+	findDeletedByIDStmt         *sql.Stmt
+	findDeletedForUpdateStmt    *sql.Stmt
+	purgeStmt                   *sql.Stmt
+	purgeDeletedStmt            *sql.Stmt
+	createTemplateStmt          *sql.Stmt
+	findTemplatesStmt           *sql.Stmt
+	findTemplateByIDStmt        *sql.Stmt
+	deleteTemplateStmt          *sql.Stmt
 }
 
 // NewRobotStorage возвращает указатель на хранилище робтов.
@@ -35,15 +62,41 @@ func NewRobotStorage(db *DB) (*RobotStorage, error) {
 	stmts := []stmt{
 		{Query: createRobotQuery, Dst: &s.createStmt},
 		{Query: findByIDQuery, Dst: &s.findByIDStmt},
-		{Query: softDeleteQuery, Dst: &s.softDeleteStmt},
 		{Query: findActivatedByUserIDQuery, Dst: &s.findActivatedByUserIDStmt},
 		{Query: findActivatedByTickerQuery, Dst: &s.findActivatedByTicker},
 		{Query: findActivatedByTickerUserIDQuery, Dst: &s.findActivatedByTickerUserID},
-		{Query: activateRobotQuery, Dst: &s.activateRobotStmt},
-		{Query: deactivateRobotQuery, Dst: &s.deactivateRobotStmt},
+		{Query: findForUpdateQuery, Dst: &s.findForUpdateStmt},
+		{Query: applyTransitionQuery, Dst: &s.applyTransitionStmt},
 		{Query: findActivatedQuery, Dst: &s.findActivatedStmt},
 		{Query: tradeQuery, Dst: &s.tradeStmt},
+		{Query: closePositionQuery, Dst: &s.closePositionStmt},
 		{Query: findToTradingQuery, Dst: &s.findToTradingStmt},
+		{Query: findScheduledQuery, Dst: &s.findScheduledStmt},
+		{Query: createTransitionQuery, Dst: &s.createTransitionStmt},
+		{Query: findTransitionsQuery, Dst: &s.findTransitionsStmt},
+		{Query: editQuery, Dst: &s.editStmt},
+		{Query: createVersionQuery, Dst: &s.createVersionStmt},
+		{Query: findVersionsQuery, Dst: &s.findVersionsStmt},
+		{Query: createDealQuery, Dst: &s.createDealStmt},
+		{Query: findDealsQuery, Dst: &s.findDealsStmt},
+		{Query: findDealsByRobotIDsQuery, Dst: &s.findDealsByRobotIDsStmt},
+		{Query: findFollowersQuery, Dst: &s.findFollowersStmt},
+		{Query: findFollowersForUpdateQuery, Dst: &s.findFollowersForUpdateStmt},
+		{Query: unfollowQuery, Dst: &s.unfollowStmt},
+		{Query: findVisibleQuery, Dst: &s.findVisibleStmt},
+		{Query: findVisibleByIDsQuery, Dst: &s.findVisibleByIDsStmt},
+		{Query: shareQuery, Dst: &s.shareStmt},
+		{Query: unshareQuery, Dst: &s.unshareStmt},
+		{Query: findSharesQuery, Dst: &s.findSharesStmt},
+		{Query: findDeletedQuery, Dst: &s.findDeletedStmt},
+		{Query: findDeletedByIDQuery, Dst: &s.findDeletedByIDStmt},
+		{Query: findDeletedForUpdateQuery, Dst: &s.findDeletedForUpdateStmt},
+		{Query: purgeQuery, Dst: &s.purgeStmt},
+		{Query: purgeDeletedQuery, Dst: &s.purgeDeletedStmt},
+		{Query: createTemplateQuery, Dst: &s.createTemplateStmt},
+		{Query: findTemplatesQuery, Dst: &s.findTemplatesStmt},
+		{Query: findTemplateByIDQuery, Dst: &s.findTemplateByIDStmt},
+		{Query: deleteTemplateQuery, Dst: &s.deleteTemplateStmt},
 	}
 
 	if err := s.initStatements(stmts); err != nil {
@@ -55,20 +108,30 @@ func NewRobotStorage(db *DB) (*RobotStorage, error) {
 
 const robotFieldsInsert = `owner_user_id, parent_robot_id, is_favourite, is_active, ticker, buy_price, ` + //nolint:misspell
 	`sell_price, plan_start, plan_end, plan_yield, fact_yield, deals_count, activated_at, deactivated_at, ` +
-	`created_at, deleted_at, is_buying`
+	`created_at, deleted_at, is_buying, auto_close, schedule, status, version, is_mirror, lots, visibility, share_token, ` +
+	`cloned_from_id, template_id`
 
-const robotFieldsSelect = `robot_id, ` + robotFieldsInsert
+// followersColumn число неудаленных подписчиков, повторяющих робота.
+const followersColumn = `(SELECT count(*) FROM robots f ` +
+	`WHERE f.parent_robot_id = robots.robot_id AND f.is_mirror AND f.deleted_at IS NULL)`
+
+// robotFieldsSelect дополняет поля робота числом подписчиков, повторяющих его.
+const robotFieldsSelect = `robot_id, ` + robotFieldsInsert + `, revision, ` + followersColumn + ` AS followers`
+
+// robotFieldsLocked поля робота для запросов с FOR UPDATE, число подписчиков в них не считается.
+const robotFieldsLocked = `robot_id, ` + robotFieldsInsert + `, revision, 0 AS followers`
 
 // scnaRobot сканирует робота из курсора базы данных.
 func scanRobot(scanner sqlScanner, r *robot.Robot) error {
 	return scanner.Scan(&r.RobotID, &r.OwnerUserID, &r.ParentRobotID, &r.IsFavourite, &r.IsActive, &r.Ticker,
 		&r.BuyPrice, &r.SellPrice, &r.PlanStart, &r.PlanEnd, &r.PlanYield, &r.FactYield, &r.DealsCount,
-		&r.ActivatedAt, &r.DeactivatedAt, &r.CreatedAt, &r.DeletedAt, &r.IsBuying)
+		&r.ActivatedAt, &r.DeactivatedAt, &r.CreatedAt, &r.DeletedAt, &r.IsBuying, &r.AutoClose, &r.Schedule, &r.Status, &r.Version, &r.IsMirror, &r.Lots, &r.Visibility, &r.ShareToken,
+		&r.ClonedFromID, &r.TemplateID, &r.Revision, &r.Followers)
 }
 
 // scanRobots возвращает список роботов из базы данных.
 func scanRobots(rows *sql.Rows) ([]robot.Robot, error) {
-	var robots []robot.Robot
+	robots := make([]robot.Robot, 0)
 
 	var err error
 
@@ -89,30 +152,127 @@ func scanRobots(rows *sql.Rows) ([]robot.Robot, error) {
 }
 
 const createRobotQuery = `INSERT INTO robots(` + robotFieldsInsert + `) VALUES ($1, $2, $3, $4, $5, $6, ` +
-	`$7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`
+	`$7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27) RETURNING robot_id`
 
 // Create дабавляет робота в хранилище.
 func (s *RobotStorage) Create(r *robot.Robot) error {
-	r.IsBuying = true
-	r.CreatedAt.Valid = true
-	r.CreatedAt.Time = time.Now()
+	if err := prepareCreate(r); err != nil {
+		return err
+	}
 
 	tx, err := s.db.Session.Begin()
 	if err != nil {
 		return fmt.Errorf("can't start a transaction: %s", err)
 	}
 
-	_, err = tx.Stmt(s.createStmt).Exec(r.OwnerUserID, r.ParentRobotID, r.IsFavourite, r.IsActive, r.Ticker, r.BuyPrice,
-		r.SellPrice, r.PlanStart, r.PlanEnd, r.PlanYield, r.FactYield, r.DealsCount, r.ActivatedAt, r.DeactivatedAt,
-		r.CreatedAt, r.DeletedAt, r.IsBuying)
+	if err = s.create(tx, r); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("can't commit in robotStorage: %s", err)
+	}
+
+	return nil
+}
 
+// CreateBatch создает роботов пакетом и возвращает ошибку для каждого из них.
+// В атомарном режиме роботы создаются в одной транзакции, которая откатывается при любой ошибке.
+func (s *RobotStorage) CreateBatch(robots []*robot.Robot, atomic bool) ([]error, error) {
+	errs := make([]error, len(robots))
+
+	if !atomic {
+		for i, r := range robots {
+			errs[i] = s.Create(r)
+		}
+
+		return errs, nil
+	}
+
+	tx, err := s.db.Session.Begin()
 	if err != nil {
+		return nil, fmt.Errorf("can't start a transaction: %s", err)
+	}
+
+	for i, r := range robots {
+		r := r
+
+		if errs[i] = prepareCreate(r); errs[i] == nil {
+			errs[i] = savepoint(tx, func() error { return s.create(tx, r) })
+		}
+	}
+
+	if robot.AbortBatch(errs) {
 		_ = tx.Rollback()
-		return fmt.Errorf("can't create robot: %s", err)
+
+		for _, r := range robots {
+			r.RobotID = 0
+		}
+
+		return errs, nil
 	}
 
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("can't commit in robotStorage: %s", err)
+		return nil, fmt.Errorf("can't commit in robotStorage: %s", err)
+	}
+
+	return errs, nil
+}
+
+// prepareCreate заполняет поля нового робота значениями по умолчанию.
+func prepareCreate(r *robot.Robot) error {
+	if err := r.SetVisibility(r.Visibility); err != nil {
+		return err
+	}
+
+	r.IsBuying = true
+	r.CreatedAt.Valid = true
+	r.CreatedAt.Time = time.Now()
+	r.Version = 1
+	r.Revision = 1
+
+	if r.Lots < 1 {
+		r.Lots = 1
+	}
+
+	if r.Status == "" {
+		r.Status = r.InitialStatus()
+	}
+
+	return nil
+}
+
+// create сохраняет робота и первую версию его параметров в транзакции tx.
+func (s *RobotStorage) create(tx *sql.Tx, r *robot.Robot) error {
+	err := tx.Stmt(s.createStmt).QueryRow(r.OwnerUserID, r.ParentRobotID, r.IsFavourite, r.IsActive, r.Ticker, r.BuyPrice,
+		r.SellPrice, r.PlanStart, r.PlanEnd, r.PlanYield, r.FactYield, r.DealsCount, r.ActivatedAt, r.DeactivatedAt,
+		r.CreatedAt, r.DeletedAt, r.IsBuying, r.AutoClose, r.Schedule, r.Status, r.Version, r.IsMirror, r.Lots, r.Visibility, r.ShareToken,
+		r.ClonedFromID, r.TemplateID).Scan(&r.RobotID)
+	if err != nil {
+		return fmt.Errorf("can't create robot: %s", err)
+	}
+
+	return s.createVersion(tx, r.CurrentVersion(r.CreatedAt.Time))
+}
+
+// savepoint выполняет fn внутри точки сохранения транзакции tx. При ошибке транзакция откатывается
+// к точке сохранения и остается пригодной для следующих операций пакета.
+func savepoint(tx *sql.Tx, fn func() error) error {
+	if _, err := tx.Exec(`SAVEPOINT batch_item`); err != nil {
+		return fmt.Errorf("can't create savepoint: %s", err)
+	}
+
+	if err := fn(); err != nil {
+		if _, e := tx.Exec(`ROLLBACK TO SAVEPOINT batch_item`); e != nil {
+			return fmt.Errorf("%w (can't rollback to savepoint: %s)", err, e)
+		}
+
+		return err
+	}
+
+	if _, err := tx.Exec(`RELEASE SAVEPOINT batch_item`); err != nil {
+		return fmt.Errorf("can't release savepoint: %s", err)
 	}
 
 	return nil
@@ -136,25 +296,9 @@ func (s *RobotStorage) FindByID(id int) (*robot.Robot, error) {
 	return &r, nil
 }
 
-const softDeleteQuery = `UPDATE robots SET deleted_at = $1 WHERE robot_id = $2`
-
-// SoftDelete реализует 'soft delete', проставляя дату удаления, но не удаляя запись.
-func (s *RobotStorage) SoftDelete(id int) error {
-	tx, err := s.db.Session.Begin()
-	if err != nil {
-		return fmt.Errorf("can't start a transaction: %s", err)
-	}
-
-	if _, err = tx.Stmt(s.softDeleteStmt).Exec(time.Now(), id); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("can't 'soft delete' robot: %s", err)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("can't commit in robotStorage: %s", err)
-	}
-
-	return nil
+// SoftDelete переводит робота в состояние deleted, проставляя дату удаления, но не удаляя запись.
+func (s *RobotStorage) SoftDelete(id, revision int) error {
+	return s.Transition(id, robot.ActionDelete, robot.SourceUser, revision)
 }
 
 const findActivatedByUserIDQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE owner_user_id = $1 AND deleted_at IS NULL ORDER BY robot_id`
@@ -229,62 +373,387 @@ func (s *RobotStorage) FindActivatedByTickerUserID(ticker string, userID int) ([
 	return robots, nil
 }
 
-// Filter выбирает какой запрос необходимо обработать.
-func (s *RobotStorage) Filter(ticker, userID string) ([]robot.Robot, error) {
-	if userID == "" {
-		if ticker == "" {
-			return s.FindActivated()
+// sortColumns выражения полей сортировки роботов. Строки сравниваются побайтно, как в robot.Query,
+// а пустое время — как нулевое.
+var sortColumns = map[string]string{ //nolint:gochecknoglobals
+	"robot_id":        "robot_id",
+	"owner_user_id":   "owner_user_id",
+	"parent_robot_id": "parent_robot_id",
+	"cloned_from_id":  "cloned_from_id",
+	"template_id":     "template_id",
+	"is_favourite":    "is_favourite", //nolint:misspell
+	"is_active":       "is_active",
+	"status":          `status COLLATE "C"`,
+	"ticker":          `ticker COLLATE "C"`,
+	"buy_price":       "buy_price",
+	"sell_price":      "sell_price",
+	"plan_start":      `COALESCE(plan_start, '0001-01-01 00:00:00+00')`,
+	"plan_end":        `COALESCE(plan_end, '0001-01-01 00:00:00+00')`,
+	"plan_yield":      "plan_yield",
+	"fact_yield":      "fact_yield",
+	"deals_count":     "deals_count",
+	"auto_close":      "auto_close",
+	"schedule":        `schedule COLLATE "C"`,
+	"version":         "version",
+	"is_mirror":       "is_mirror",
+	"lots":            "lots",
+	"followers":       followersColumn,
+	"visibility":      `visibility COLLATE "C"`,
+	"created_at":      "created_at",
+}
+
+// findQuery собирает запрос выборки роботов и его аргументы. Страница начинается после курсора
+// и содержит на одного робота больше лимита, чтобы узнать, есть ли следующая.
+func findQuery(q *robot.Query) (string, []interface{}) {
+	var (
+		where = []string{"deleted_at IS NULL"}
+		args  []interface{}
+	)
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+
+	if q.Ticker != "" {
+		add("ticker = $%d", q.Ticker)
+	}
+
+	if q.OwnerUserID != nil {
+		add("owner_user_id = $%d", *q.OwnerUserID)
+	}
+
+	if q.ParentRobotID != nil {
+		add("parent_robot_id = $%d", *q.ParentRobotID)
+	}
+
+	if q.ClonedFromID != nil {
+		add("cloned_from_id = $%d", *q.ClonedFromID)
+	}
+
+	if q.TemplateID != nil {
+		add("template_id = $%d", *q.TemplateID)
+	}
+
+	if q.IsActive != nil {
+		add("is_active = $%d", *q.IsActive)
+	}
+
+	if q.IsFavourite != nil {
+		add("is_favourite = $%d", *q.IsFavourite) //nolint:misspell
+	}
+
+	ranges := []struct {
+		cond  string
+		value *float64
+	}{
+		{"fact_yield >= $%d", q.MinYield},
+		{"fact_yield <= $%d", q.MaxYield},
+		{"buy_price >= $%d", q.MinBuyPrice},
+		{"buy_price <= $%d", q.MaxBuyPrice},
+		{"sell_price >= $%d", q.MinSellPrice},
+		{"sell_price <= $%d", q.MaxSellPrice},
+	}
+
+	for _, r := range ranges {
+		if r.value != nil {
+			add(r.cond, *r.value)
 		}
+	}
+
+	if q.CreatedFrom.Valid {
+		add("created_at >= $%d", q.CreatedFrom.Time)
+	}
+
+	if q.CreatedTo.Valid {
+		add("created_at < $%d", q.CreatedTo.Time)
+	}
+
+	if q.ViewerID != nil {
+		add("(owner_user_id = $%[1]d OR "+visibleWithoutLink+")", *q.ViewerID)
+	}
+
+	column := sortColumns[q.Sort]
+	order, cmp := "ASC", ">"
 
-		return s.FindActivatedByTicker(ticker)
+	if q.Desc {
+		order, cmp = "DESC", "<"
 	}
 
-	id, err := strconv.Atoi(userID)
+	if q.After != nil {
+		args = append(args, q.After.Value, q.After.RobotID)
+		where = append(where, fmt.Sprintf("(%s, robot_id) %s ($%d, $%d)", column, cmp, len(args)-1, len(args)))
+	}
+
+	args = append(args, q.Limit+1)
+
+	return fmt.Sprintf(`SELECT %s FROM robots WHERE %s ORDER BY %s %s, robot_id %s LIMIT $%d`,
+		robotFieldsSelect, strings.Join(where, " AND "), column, order, order, len(args)), args
+}
+
+// Find возвращает страницу неудаленных роботов, удовлетворяющих выборке.
+func (s *RobotStorage) Find(q *robot.Query) (*robot.Page, error) {
+	query, args := findQuery(q)
+
+	rows, err := s.db.Session.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	robots, err := scanRobots(rows)
 	if err != nil {
-		return nil, robot.ErrInvalidID
+		return nil, fmt.Errorf("can't scan robots: %s", err)
+	}
+
+	return q.NewPage(robots), nil
+}
+
+// visibleWithoutLink шаблон условия видимости робота без ссылки, %[1]d — номер параметра с ID пользователя.
+const visibleWithoutLink = `visibility = 'public' OR (visibility = 'users' AND EXISTS (SELECT 1 FROM robot_shares sh ` +
+	`WHERE sh.robot_id = robots.robot_id AND sh.user_id = $%[1]d))`
+
+const findVisibleQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE robot_id = $1 AND deleted_at IS NULL ` +
+	`AND (owner_user_id = $2 OR (visibility = 'link' AND share_token <> '' AND share_token = $3) OR ` +
+	`visibility = 'public' OR (visibility = 'users' AND EXISTS (SELECT 1 FROM robot_shares sh ` +
+	`WHERE sh.robot_id = robots.robot_id AND sh.user_id = $2)))`
+
+// FindVisible находит неудаленного робота, если пользователь userID его видит.
+func (s *RobotStorage) FindVisible(robotID, userID int, shareToken string) (*robot.Robot, error) {
+	var r robot.Robot
+
+	err := scanRobot(s.findVisibleStmt.QueryRow(robotID, userID, shareToken), &r)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, robot.ErrNotFound
 	}
 
-	if ticker == "" {
-		return s.FindActivatedByUserID(id)
+	if err != nil {
+		return nil, fmt.Errorf("can't scan robot: %s", err)
 	}
 
-	return s.FindActivatedByTickerUserID(ticker, id)
+	return &r, nil
 }
 
-// FavouriteRobot добавляет в базу данных нового избранного робота.
-func (s *RobotStorage) FavouriteRobot(parentRobotID, userID int) error {
-	r, err := s.FindByID(parentRobotID)
+const findVisibleByIDsQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE robot_id = ANY($1) ` +
+	`AND deleted_at IS NULL AND (owner_user_id = $2 OR visibility = 'public' OR (visibility = 'users' AND EXISTS ` +
+	`(SELECT 1 FROM robot_shares sh WHERE sh.robot_id = robots.robot_id AND sh.user_id = $2))) ORDER BY robot_id`
+
+// FindVisibleByIDs возвращает неудаленных роботов из robotIDs, которых пользователь userID видит без ссылки.
+func (s *RobotStorage) FindVisibleByIDs(robotIDs []int, userID int) ([]robot.Robot, error) {
+	ids := make([]int64, len(robotIDs))
+	for i, id := range robotIDs {
+		ids[i] = int64(id)
+	}
+
+	rows, err := s.findVisibleByIDsStmt.Query(pq.Array(ids), userID)
 	if err != nil {
-		return fmt.Errorf("%w: robotStorage.FindByID return with error: %s", robot.ErrNotFound, err)
+		return nil, fmt.Errorf("can't exec query: %s", err)
 	}
+	defer rows.Close()
 
-	r.OwnerUserID = userID
-	r.ParentRobotID = parentRobotID
-	r.IsFavourite = true
-	r.IsActive = false
-	r.DeletedAt.Valid = false
-	r.DealsCount = 0
-	r.FactYield = 0.0
+	robots, err := scanRobots(rows)
+	if err != nil {
+		return nil, fmt.Errorf("can't scan robots: %s", err)
+	}
 
-	if err = s.Create(r); err != nil {
-		return fmt.Errorf("robotStorage.Create return with error: %s", err)
+	return robots, nil
+}
+
+const shareQuery = `INSERT INTO robot_shares(robot_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+// Share открывает робота пользователю userID.
+func (s *RobotStorage) Share(robotID, userID int) error {
+	if _, err := s.FindByID(robotID); err != nil {
+		return err
+	}
+
+	if _, err := s.shareStmt.Exec(robotID, userID); err != nil {
+		return fmt.Errorf("can't exec query: %s", err)
 	}
 
 	return nil
 }
 
-const activateRobotQuery = `UPDATE robots SET is_active = $1, activated_at = $2 WHERE robot_id = $3`
+const unshareQuery = `DELETE FROM robot_shares WHERE robot_id = $1 AND user_id = $2`
+
+// Unshare закрывает робота от пользователя userID.
+func (s *RobotStorage) Unshare(robotID, userID int) error {
+	res, err := s.unshareStmt.Exec(robotID, userID)
+	if err != nil {
+		return fmt.Errorf("can't exec query: %s", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return robot.ErrNotFound
+	}
+
+	return nil
+}
+
+const findSharesQuery = `SELECT user_id FROM robot_shares WHERE robot_id = $1 ORDER BY user_id`
+
+// FindShares возвращает ID пользователей, которым открыт робот.
+func (s *RobotStorage) FindShares(robotID int) ([]int, error) {
+	rows, err := s.findSharesStmt.Query(robotID)
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	users := make([]int, 0)
+
+	for rows.Next() {
+		var userID int
+		if err = rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("can't scan user id: %s", err)
+		}
+
+		users = append(users, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows return error: %s", err)
+	}
+
+	return users, nil
+}
+
+// FavouriteRobot добавляет в базу данных нового избранного робота. Родитель блокируется до конца транзакции,
+// поэтому копия создается с параметрами той ревизии, которую проверил revision.
+func (s *RobotStorage) FavouriteRobot(parentRobotID, userID int, follow robot.Follow, revision int) (*robot.Robot, error) {
+	tx, err := s.db.Session.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("can't start a transaction: %s", err)
+	}
+
+	r, err := s.favourite(tx, parentRobotID, userID, follow, revision)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("can't commit in robotStorage: %s", err)
+	}
+
+	return r, nil
+}
+
+// favourite создает копию заблокированного родителя в транзакции tx.
+func (s *RobotStorage) favourite(tx *sql.Tx, parentRobotID, userID int, follow robot.Follow,
+	revision int) (*robot.Robot, error) {
+	var parent robot.Robot
+	if err := scanRobot(tx.Stmt(s.findForUpdateStmt).QueryRow(parentRobotID), &parent); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: can't scan robot: %s", robot.ErrNotFound, err)
+		}
+
+		return nil, fmt.Errorf("can't scan robot: %s", err)
+	}
+
+	if err := parent.CheckRevision(revision); err != nil {
+		return nil, err
+	}
+
+	r, err := robot.NewFollower(&parent, userID, follow)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = prepareCreate(r); err != nil {
+		return nil, err
+	}
+
+	if err = s.create(tx, r); err != nil {
+		return nil, fmt.Errorf("robotStorage.create return with error: %s", err)
+	}
+
+	return r, nil
+}
+
+const findFollowersQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE parent_robot_id = $1 AND is_mirror ` +
+	`AND deleted_at IS NULL ORDER BY robot_id`
+
+// FindFollowers находит подписчиков, повторяющих робота.
+func (s *RobotStorage) FindFollowers(parentRobotID int) ([]robot.Robot, error) {
+	rows, err := s.findFollowersStmt.Query(parentRobotID)
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	robots, err := scanRobots(rows)
+	if err != nil {
+		return nil, fmt.Errorf("can't scan robots: %s", err)
+	}
+
+	return robots, nil
+}
+
+// Ревизия проверяется в том же запросе, который меняет строку, поэтому проверку не обгонит другое изменение.
+const unfollowQuery = `UPDATE robots SET is_mirror = false, revision = revision + 1 ` +
+	`WHERE robot_id = $1 AND deleted_at IS NULL AND ($2 = 0 OR revision = $2)`
+
+// Unfollow отключает повторение родителя, после чего робот становится независимой копией.
+func (s *RobotStorage) Unfollow(robotID, revision int) error {
+	res, err := s.unfollowStmt.Exec(robotID, revision)
+	if err != nil {
+		return fmt.Errorf("can't unfollow robot: %s", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("can't get affected rows: %s", err)
+	}
+
+	if affected > 0 {
+		return nil
+	}
+
+	// Строка не изменилась: робота нет или его ревизия другая.
+	r, err := s.FindByID(robotID)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: revision %d, expected %d", robot.ErrConflict, r.Revision, revision)
+}
 
-// ActivateRobot активирует робота.
+// ActivateRobot активирует робота по запросу пользователя.
 func (s *RobotStorage) ActivateRobot(robotID int) error {
+	return s.Transition(robotID, robot.ActionActivate, robot.SourceUser, 0)
+}
+
+// DeactivateRobot приостанавливает робота по запросу пользователя.
+func (s *RobotStorage) DeactivateRobot(robotID int) error {
+	return s.Transition(robotID, robot.ActionDeactivate, robot.SourceUser, 0)
+}
+
+const findForUpdateQuery = `SELECT ` + robotFieldsLocked + ` FROM robots WHERE robot_id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+const applyTransitionQuery = `UPDATE robots SET status = $1, is_active = $2, activated_at = $3, deactivated_at = $4, ` +
+	`deleted_at = $5, revision = revision + 1 WHERE robot_id = $6`
+
+const createTransitionQuery = `INSERT INTO robot_transitions(robot_id, action, from_status, to_status, source, created_at) ` +
+	`VALUES ($1, $2, $3, $4, $5, $6)`
+
+// Transition выполняет действие над роботом по таблице переходов и записывает переход в историю.
+// Робот блокируется до конца транзакции, поэтому параллельные переходы не перетирают друг друга,
+// а ревизия revision проверяется у заблокированной строки.
+func (s *RobotStorage) Transition(robotID int, action robot.Action, source string, revision int) error {
+	return s.transition(s.findForUpdateStmt, robotID, action, source, revision)
+}
+
+// transition выполняет действие над роботом, которого блокирует запрос find.
+func (s *RobotStorage) transition(find *sql.Stmt, robotID int, action robot.Action, source string, revision int) error {
 	tx, err := s.db.Session.Begin()
 	if err != nil {
 		return fmt.Errorf("can't start a transaction: %s", err)
 	}
 
-	if _, err = tx.Stmt(s.activateRobotStmt).Exec(true, time.Now(), robotID); err != nil {
+	if err = s.applyTransition(tx, find, robotID, action, source, revision, time.Now()); err != nil {
 		_ = tx.Rollback()
-		return fmt.Errorf("can't activate robot: %s", err)
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -294,31 +763,290 @@ func (s *RobotStorage) ActivateRobot(robotID int) error {
 	return nil
 }
 
-const deactivateRobotQuery = `UPDATE robots SET is_active = $1, deactivated_at = $2 WHERE robot_id = $3`
+// TransitionBatch выполняет действие над роботами пакетом и возвращает ошибку для каждого из них.
+// В атомарном режиме переходы выполняются в одной транзакции, которая откатывается при любой ошибке.
+func (s *RobotStorage) TransitionBatch(robotIDs []int, action robot.Action, source string, atomic bool) ([]error, error) {
+	errs := make([]error, len(robotIDs))
+
+	if !atomic {
+		for i, id := range robotIDs {
+			errs[i] = s.Transition(id, action, source, 0)
+		}
+
+		return errs, nil
+	}
+
+	now := time.Now()
 
-// DeactivateRobot активирует робота.
-func (s *RobotStorage) DeactivateRobot(robotID int) error {
 	tx, err := s.db.Session.Begin()
 	if err != nil {
-		return fmt.Errorf("can't start a transaction: %s", err)
+		return nil, fmt.Errorf("can't start a transaction: %s", err)
+	}
+
+	for i, id := range robotIDs {
+		id := id
+		errs[i] = savepoint(tx, func() error {
+			return s.applyTransition(tx, s.findForUpdateStmt, id, action, source, 0, now)
+		})
 	}
 
-	if _, err = tx.Stmt(s.deactivateRobotStmt).Exec(false, time.Now(), robotID); err != nil {
+	if robot.AbortBatch(errs) {
 		_ = tx.Rollback()
-		return fmt.Errorf("can't activate robot: %s", err)
+		return errs, nil
 	}
 
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("can't commit in robotStorage: %s", err)
+		return nil, fmt.Errorf("can't commit in robotStorage: %s", err)
+	}
+
+	return errs, nil
+}
+
+// applyTransition блокирует робота запросом find, выполняет над ним действие и записывает переход в транзакции tx.
+func (s *RobotStorage) applyTransition(tx *sql.Tx, find *sql.Stmt, robotID int, action robot.Action, source string,
+	revision int, now time.Time) error {
+	var r robot.Robot
+	if err := scanRobot(tx.Stmt(find).QueryRow(robotID), &r); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: can't scan robot: %s", robot.ErrNotFound, err)
+		}
+
+		return fmt.Errorf("can't scan robot: %s", err)
+	}
+
+	if err := r.CheckRevision(revision); err != nil {
+		return err
+	}
+
+	from, err := r.Apply(action, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Stmt(s.applyTransitionStmt).Exec(r.Status, r.IsActive, r.ActivatedAt, r.DeactivatedAt, r.DeletedAt, robotID)
+	if err != nil {
+		return fmt.Errorf("can't change robot status: %s", err)
+	}
+
+	if _, err = tx.Stmt(s.createTransitionStmt).Exec(robotID, action, from, r.Status, source, now); err != nil {
+		return fmt.Errorf("can't insert robot transition: %s", err)
+	}
+
+	return nil
+}
+
+const findTransitionsQuery = `SELECT id, robot_id, action, from_status, to_status, source, created_at FROM robot_transitions ` +
+	`WHERE robot_id = $1 ORDER BY created_at, id`
+
+// FindTransitions возвращает историю переходов робота.
+func (s *RobotStorage) FindTransitions(robotID int) ([]robot.Transition, error) {
+	rows, err := s.findTransitionsStmt.Query(robotID)
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	transitions := make([]robot.Transition, 0)
+
+	for rows.Next() {
+		var t robot.Transition
+		if err = rows.Scan(&t.ID, &t.RobotID, &t.Action, &t.FromStatus, &t.ToStatus, &t.Source, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("can't scan transition: %s", err)
+		}
+
+		transitions = append(transitions, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows return error: %s", err)
+	}
+
+	return transitions, nil
+}
+
+const editQuery = `UPDATE robots SET ticker = $1, buy_price = $2, sell_price = $3, plan_start = $4, plan_end = $5, ` +
+	`plan_yield = $6, auto_close = $7, schedule = $8, status = $9, version = $10, lots = $11, visibility = $12, share_token = $13, ` +
+	`revision = revision + 1 WHERE robot_id = $14`
+
+const findFollowersForUpdateQuery = `SELECT ` + robotFieldsLocked + ` FROM robots WHERE parent_robot_id = $1 ` +
+	`AND is_mirror AND deleted_at IS NULL ORDER BY robot_id FOR UPDATE`
+
+// Edit меняет параметры робота, сохраняет новую версию параметров и переносит их подписчикам.
+func (s *RobotStorage) Edit(robotID int, changes *robot.Robot) (*robot.Robot, error) {
+	now := time.Now()
+
+	tx, err := s.db.Session.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("can't start a transaction: %s", err)
+	}
+
+	var r robot.Robot
+	if err = scanRobot(tx.Stmt(s.findForUpdateStmt).QueryRow(robotID), &r); err != nil {
+		_ = tx.Rollback()
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: can't scan robot: %s", robot.ErrNotFound, err)
+		}
+
+		return nil, fmt.Errorf("can't scan robot: %s", err)
+	}
+
+	from := r.Status
+
+	if err = r.Edit(changes, now); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err = s.saveEdit(tx, &r, from, now); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if r.Followers, err = s.syncFollowers(tx, &r, now); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("can't commit in robotStorage: %s", err)
+	}
+
+	return &r, nil
+}
+
+// syncFollowers переносит параметры робота его подписчикам и возвращает их число.
+func (s *RobotStorage) syncFollowers(tx *sql.Tx, parent *robot.Robot, now time.Time) (int, error) {
+	rows, err := tx.Stmt(s.findFollowersForUpdateStmt).Query(parent.RobotID)
+	if err != nil {
+		return 0, fmt.Errorf("can't exec query: %s", err)
+	}
+
+	followers, err := scanRobots(rows)
+	rows.Close()
+
+	if err != nil {
+		return 0, fmt.Errorf("can't scan followers: %s", err)
+	}
+
+	for i := range followers {
+		from := followers[i].Status
+		if !followers[i].SyncWith(parent, now) {
+			continue
+		}
+
+		if err = s.saveEdit(tx, &followers[i], from, now); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(followers), nil
+}
+
+// syncClosedMirror переносит подписчику, который закрыл позицию, тикер и цены родителя, отложенные SyncWith.
+// Родитель читается без блокировки: Edit блокирует родителя раньше подписчиков, и обратный порядок привел бы к взаимной блокировке.
+func (s *RobotStorage) syncClosedMirror(tx *sql.Tx, robotID int, now time.Time) error {
+	var r, parent robot.Robot
+
+	if err := scanRobot(tx.Stmt(s.findForUpdateStmt).QueryRow(robotID), &r); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("can't scan robot: %s", err)
+	}
+
+	if !r.IsMirror || !r.IsBuying {
+		return nil
+	}
+
+	if err := scanRobot(tx.Stmt(s.findByIDStmt).QueryRow(r.ParentRobotID), &parent); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("can't scan parent robot: %s", err)
+	}
+
+	from := r.Status
+	if !r.SyncWith(&parent, now) {
+		return nil
+	}
+
+	return s.saveEdit(tx, &r, from, now)
+}
+
+// saveEdit сохраняет измененного робота, его версию и переход, если состояние изменилось.
+func (s *RobotStorage) saveEdit(tx *sql.Tx, r *robot.Robot, from robot.Status, now time.Time) error {
+	_, err := tx.Stmt(s.editStmt).Exec(r.Ticker, r.BuyPrice, r.SellPrice, r.PlanStart, r.PlanEnd, r.PlanYield,
+		r.AutoClose, r.Schedule, r.Status, r.Version, r.Lots, r.Visibility, r.ShareToken, r.RobotID)
+	if err != nil {
+		return fmt.Errorf("can't edit robot: %s", err)
+	}
+
+	r.Revision++
+
+	if err = s.createVersion(tx, r.CurrentVersion(now)); err != nil {
+		return err
+	}
+
+	if r.Status != from {
+		_, err = tx.Stmt(s.createTransitionStmt).Exec(r.RobotID, robot.ActionSchedule, from, r.Status, robot.SourceUser, now)
+		if err != nil {
+			return fmt.Errorf("can't insert robot transition: %s", err)
+		}
 	}
 
 	return nil
 }
 
-const findToTradingQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE deleted_at IS NULL AND ` +
-	`((plan_start IS NOT NULL AND plan_end IS NOT NULL AND plan_start < now() AND plan_end > now()) OR is_active = true)`
+const createVersionQuery = `INSERT INTO robot_versions(robot_id, version, ticker, buy_price, sell_price, plan_start, ` +
+	`plan_end, plan_yield, auto_close, schedule, lots, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
-// FindToTrading находит роботов которых можно запустить на торговлю
+// createVersion сохраняет версию параметров робота в транзакции tx.
+func (s *RobotStorage) createVersion(tx *sql.Tx, v robot.Version) error {
+	_, err := tx.Stmt(s.createVersionStmt).Exec(v.RobotID, v.Version, v.Ticker, v.BuyPrice, v.SellPrice, v.PlanStart,
+		v.PlanEnd, v.PlanYield, v.AutoClose, v.Schedule, v.Lots, v.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("can't insert robot version: %s", err)
+	}
+
+	return nil
+}
+
+const findVersionsQuery = `SELECT robot_id, version, ticker, buy_price, sell_price, plan_start, plan_end, plan_yield, ` +
+	`auto_close, schedule, lots, created_at FROM robot_versions WHERE robot_id = $1 ORDER BY version`
+
+// FindVersions возвращает историю параметров робота.
+func (s *RobotStorage) FindVersions(robotID int) ([]robot.Version, error) {
+	rows, err := s.findVersionsStmt.Query(robotID)
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	versions := make([]robot.Version, 0)
+
+	for rows.Next() {
+		var v robot.Version
+		if err = rows.Scan(&v.RobotID, &v.Version, &v.Ticker, &v.BuyPrice, &v.SellPrice, &v.PlanStart, &v.PlanEnd,
+			&v.PlanYield, &v.AutoClose, &v.Schedule, &v.Lots, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("can't scan version: %s", err)
+		}
+
+		versions = append(versions, v)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows return error: %s", err)
+	}
+
+	return versions, nil
+}
+
+const findToTradingQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE deleted_at IS NULL AND status IN ('active', 'holding') AND NOT is_mirror`
+
+// FindToTrading находит торгующих роботов, подписчики торгуют вслед за родителем и не попадают в список
 func (s *RobotStorage) FindToTrading() ([]robot.Robot, error) {
 	rows, err := s.findToTradingStmt.Query()
 	if err != nil {
@@ -334,23 +1062,221 @@ func (s *RobotStorage) FindToTrading() ([]robot.Robot, error) {
 	return robots, nil
 }
 
-const tradeQuery = `UPDATE robots SET is_buying = $1, deals_count = $2, fact_yield = $3 WHERE robot_id = $4`
+const findScheduledQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE deleted_at IS NULL AND ` +
+	`status IN ('scheduled', 'active', 'holding') AND ((plan_start IS NOT NULL AND plan_end IS NOT NULL) OR schedule <> '') ORDER BY robot_id`
+
+// FindScheduled находит ожидающих или торгующих роботов с плановым окном или повторяющимся расписанием.
+func (s *RobotStorage) FindScheduled() ([]robot.Robot, error) {
+	rows, err := s.findScheduledStmt.Query()
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	robots, err := scanRobots(rows)
+	if err != nil {
+		return nil, fmt.Errorf("can't scan robots: %s", err)
+	}
+
+	return robots, nil
+}
+
+// tradeQuery сохраняет сделку только торгующего робота: робота могли остановить, пока торговый процесс
+// торговал по его копии.
+const tradeQuery = `UPDATE robots SET is_buying = $1, deals_count = $2, fact_yield = $3, status = $4, ` +
+	`revision = revision + 1 WHERE robot_id = $5 AND deleted_at IS NULL AND status IN ('active', 'holding')`
+
+// closePositionQuery сохраняет продажу остановленного робота, если его позицию еще никто не закрыл.
+const closePositionQuery = `UPDATE robots SET is_buying = $1, deals_count = $2, fact_yield = $3, ` +
+	`revision = revision + 1 WHERE robot_id = $4 AND deleted_at IS NULL AND status NOT IN ('active', 'holding') ` +
+	`AND is_buying = false`
+
+const createDealQuery = `INSERT INTO robot_deals(robot_id, version, side, price, lots, created_at) ` +
+	`VALUES ($1, $2, $3, $4, $5, $6)`
+
+// Trade Пишет в базу изменения рбота после транзакции и саму сделку с версией параметров робота.
+func (s *RobotStorage) Trade(rob *robot.Robot, deal robot.Deal) error {
+	return s.trade(rob, deal, robot.ErrNotTrading, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.Stmt(s.tradeStmt).Exec(rob.IsBuying, rob.DealsCount, rob.FactYield, rob.Status, rob.RobotID)
+	})
+}
+
+// ClosePosition пишет в базу продажу, которой закрывается позиция остановленного робота.
+func (s *RobotStorage) ClosePosition(rob *robot.Robot, deal robot.Deal) error {
+	return s.trade(rob, deal, robot.ErrConflict, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.Stmt(s.closePositionStmt).Exec(rob.IsBuying, rob.DealsCount, rob.FactYield, rob.RobotID)
+	})
+}
 
-// Trade Пишет в базу изменения рбота после транзакции
-func (s *RobotStorage) Trade(rob *robot.Robot) error {
+// trade меняет робота запросом update и записывает сделку. Если update не изменил робота, сделка
+// не записывается и возвращается rejected.
+func (s *RobotStorage) trade(rob *robot.Robot, deal robot.Deal, rejected error,
+	update func(tx *sql.Tx) (sql.Result, error)) error {
 	tx, err := s.db.Session.Begin()
 	if err != nil {
 		return fmt.Errorf("can't start a transaction: %s", err)
 	}
 
-	if _, err = tx.Stmt(s.tradeStmt).Exec(rob.IsBuying, rob.DealsCount, rob.FactYield, rob.RobotID); err != nil {
+	res, err := update(tx)
+	if err != nil {
 		_ = tx.Rollback()
 		return fmt.Errorf("can't execute trade: %s", err)
 	}
 
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		_ = tx.Rollback()
+		return rejected
+	}
+
+	if _, err = tx.Stmt(s.createDealStmt).Exec(rob.RobotID, rob.Version, deal.Side, deal.Price, deal.Lots, deal.CreatedAt); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("can't insert deal: %s", err)
+	}
+
+	if rob.IsMirror && rob.IsBuying {
+		if err = s.syncClosedMirror(tx, rob.RobotID, time.Now()); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("can't commit in robotStorage: %s", err)
 	}
 
 	return nil
 }
+
+const findDealsQuery = `SELECT id, robot_id, version, side, price, lots, created_at FROM robot_deals ` +
+	`WHERE robot_id = $1 ORDER BY created_at, id`
+
+// FindDeals возвращает сделки робота.
+func (s *RobotStorage) FindDeals(robotID int) ([]robot.Deal, error) {
+	rows, err := s.findDealsStmt.Query(robotID)
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	return scanDeals(rows)
+}
+
+const findDealsByRobotIDsQuery = `SELECT id, robot_id, version, side, price, lots, created_at FROM robot_deals ` +
+	`WHERE robot_id = ANY($1) ORDER BY robot_id, created_at, id`
+
+// FindDealsByRobotIDs возвращает сделки роботов, отсортированные по роботу и времени.
+func (s *RobotStorage) FindDealsByRobotIDs(robotIDs []int) ([]robot.Deal, error) {
+	ids := make([]int64, len(robotIDs))
+	for i, id := range robotIDs {
+		ids[i] = int64(id)
+	}
+
+	rows, err := s.findDealsByRobotIDsStmt.Query(pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	return scanDeals(rows)
+}
+
+// scanDeals возвращает список сделок из базы данных.
+func scanDeals(rows *sql.Rows) ([]robot.Deal, error) {
+	deals := make([]robot.Deal, 0)
+
+	var err error
+
+	for rows.Next() {
+		var d robot.Deal
+		if err = rows.Scan(&d.ID, &d.RobotID, &d.Version, &d.Side, &d.Price, &d.Lots, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("can't scan deal: %s", err)
+		}
+
+		deals = append(deals, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows return error: %s", err)
+	}
+
+	return deals, nil
+}
+
+const findDeletedQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE owner_user_id = $1 AND deleted_at IS NOT NULL ` +
+	`ORDER BY deleted_at DESC, robot_id DESC`
+
+// FindDeleted возвращает удаленных роботов пользователя, недавно удаленные в начале.
+func (s *RobotStorage) FindDeleted(userID int) ([]robot.Robot, error) {
+	rows, err := s.findDeletedStmt.Query(userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	robots, err := scanRobots(rows)
+	if err != nil {
+		return nil, fmt.Errorf("can't scan robots: %s", err)
+	}
+
+	return robots, nil
+}
+
+const findDeletedByIDQuery = `SELECT ` + robotFieldsSelect + ` FROM robots WHERE robot_id = $1 AND deleted_at IS NOT NULL`
+
+// FindDeletedByID находит удаленного робота по его ID.
+func (s *RobotStorage) FindDeletedByID(robotID int) (*robot.Robot, error) {
+	var r robot.Robot
+
+	err := scanRobot(s.findDeletedByIDStmt.QueryRow(robotID), &r)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, robot.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can't scan robot: %s", err)
+	}
+
+	return &r, nil
+}
+
+const findDeletedForUpdateQuery = `SELECT ` + robotFieldsLocked + ` FROM robots WHERE robot_id = $1 ` +
+	`AND deleted_at IS NOT NULL FOR UPDATE`
+
+// Restore возвращает удаленного робота из корзины.
+func (s *RobotStorage) Restore(robotID int) error {
+	return s.transition(s.findDeletedForUpdateStmt, robotID, robot.ActionRestore, robot.SourceUser, 0)
+}
+
+// История, версии, сделки и доступы робота удаляются каскадно.
+const purgeQuery = `DELETE FROM robots WHERE robot_id = $1 AND deleted_at IS NOT NULL`
+
+// Purge окончательно удаляет робота из корзины вместе с его историей, сделками и доступами.
+func (s *RobotStorage) Purge(robotID int) error {
+	res, err := s.purgeStmt.Exec(robotID)
+	if err != nil {
+		return fmt.Errorf("can't exec query: %s", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return robot.ErrNotFound
+	}
+
+	return nil
+}
+
+const purgeDeletedQuery = `DELETE FROM robots WHERE deleted_at < $1`
+
+// PurgeDeleted окончательно удаляет роботов, удаленных раньше before, и возвращает их число.
+func (s *RobotStorage) PurgeDeleted(before time.Time) (int, error) {
+	res, err := s.purgeDeletedStmt.Exec(before)
+	if err != nil {
+		return 0, fmt.Errorf("can't exec query: %s", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("can't get affected rows: %s", err)
+	}
+
+	return int(n), nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+)
+
+// testDB возвращает базу из TEST_DATABASE_URL со схемой init.sql, без нее тест пропускается.
+func testDB(t *testing.T) *DB {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := New(log.NewSugarLogger(), Config{URL: url, MaxOpenConns: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+// tradeLots торгует роботом в хранилище s и возвращает размеры сохраненных сделок.
+func tradeLots(t *testing.T, s robot.Storage, ownerID int) []int {
+	assert := assert.New(t)
+
+	rob := &robot.Robot{OwnerUserID: ownerID, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, Lots: 3}
+	if err := s.Create(rob); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(s.Transition(rob.RobotID, robot.ActionActivate, robot.SourceUser, 0))
+
+	trading, err := s.FindByID(rob.RobotID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	trading.Buy(10)
+	assert.NoError(s.Trade(trading, trading.NewDeal(robot.SideBuy, 10, now)))
+
+	trading.Sell(20)
+	scaled := trading.NewDeal(robot.SideSell, 20, now.Add(time.Second))
+	scaled.Lots = 2
+	assert.NoError(s.Trade(trading, scaled))
+
+	assert.NoError(s.Transition(rob.RobotID, robot.ActionStop, robot.SourceUser, 0))
+
+	trading.Buy(10)
+	assert.True(errors.Is(s.Trade(trading, trading.NewDeal(robot.SideBuy, 10, now.Add(2*time.Second))),
+		robot.ErrNotTrading))
+
+	deals, err := s.FindDeals(rob.RobotID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lots := make([]int, 0, len(deals))
+	for _, d := range deals {
+		lots = append(lots, d.Lots)
+	}
+
+	return lots
+}
+
+func TestTradeParity(t *testing.T) {
+	expected := tradeLots(t, robot.CreateStorageInMemory(), 1)
+	assert.Equal(t, []int{3, 2}, expected, "deals are stored with their own lots")
+
+	db := testDB(t)
+
+	users, err := NewUserStorage(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := &user.User{FirstName: "Ivan", LastName: "Ivanov", Password: "1234",
+		Email: fmt.Sprintf("parity-%d@example.com", time.Now().UnixNano())}
+	if err = users.Create(owner); err != nil {
+		t.Fatal(err)
+	}
+
+	robots, err := NewRobotStorage(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, expected, tradeLots(t, robots, owner.ID))
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+)
+
+const templateFields = `owner_user_id, name, ticker, buy_price, sell_price, plan_yield, auto_close, schedule, lots, ` +
+	`visibility, created_at`
+
+const createTemplateQuery = `INSERT INTO robot_templates(` + templateFields + `) ` +
+	`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+
+// CreateTemplate сохраняет шаблон робота.
+func (s *RobotStorage) CreateTemplate(t *robot.Template) error {
+	if err := t.Normalize(); err != nil {
+		return err
+	}
+
+	t.CreatedAt = time.Now()
+
+	err := s.createTemplateStmt.QueryRow(t.OwnerUserID, t.Name, t.Ticker, t.BuyPrice, t.SellPrice, t.PlanYield,
+		t.AutoClose, t.Schedule, t.Lots, t.Visibility, t.CreatedAt).Scan(&t.ID)
+	if err != nil {
+		return fmt.Errorf("can't create template: %s", err)
+	}
+
+	return nil
+}
+
+const findTemplatesQuery = `SELECT id, ` + templateFields + ` FROM robot_templates WHERE owner_user_id = $1 ORDER BY id`
+
+// FindTemplates возвращает шаблоны пользователя в порядке создания.
+func (s *RobotStorage) FindTemplates(userID int) ([]robot.Template, error) {
+	rows, err := s.findTemplatesStmt.Query(userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	templates := make([]robot.Template, 0)
+
+	for rows.Next() {
+		var t robot.Template
+		if err = scanTemplate(rows, &t); err != nil {
+			return nil, fmt.Errorf("can't scan template: %s", err)
+		}
+
+		templates = append(templates, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows return error: %s", err)
+	}
+
+	return templates, nil
+}
+
+const findTemplateByIDQuery = `SELECT id, ` + templateFields + ` FROM robot_templates WHERE id = $1`
+
+// FindTemplateByID находит шаблон по его ID.
+func (s *RobotStorage) FindTemplateByID(id int) (*robot.Template, error) {
+	var t robot.Template
+
+	err := scanTemplate(s.findTemplateByIDStmt.QueryRow(id), &t)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, robot.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can't scan template: %s", err)
+	}
+
+	return &t, nil
+}
+
+const deleteTemplateQuery = `DELETE FROM robot_templates WHERE id = $1`
+
+// DeleteTemplate удаляет шаблон, созданные из него роботы остаются.
+func (s *RobotStorage) DeleteTemplate(id int) error {
+	res, err := s.deleteTemplateStmt.Exec(id)
+	if err != nil {
+		return fmt.Errorf("can't exec query: %s", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return robot.ErrNotFound
+	}
+
+	return nil
+}
+
+// scanTemplate сканирует шаблон робота из курсора базы данных.
+func scanTemplate(scanner sqlScanner, t *robot.Template) error {
+	return scanner.Scan(&t.ID, &t.OwnerUserID, &t.Name, &t.Ticker, &t.BuyPrice, &t.SellPrice, &t.PlanYield,
+		&t.AutoClose, &t.Schedule, &t.Lots, &t.Visibility, &t.CreatedAt)
+}
@@ -2,12 +2,17 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"gitlab.com/hitchpock/tfs-course-work/internal/user"
 )
 
+// uniqueViolation код ошибки postgres при нарушении уникального индекса.
+const uniqueViolation = "23505"
+
 var _ user.Storage = &UserStorage{}
 
 type UserStorage struct {
@@ -15,6 +20,7 @@ type UserStorage struct {
 
 	createStmt      *sql.Stmt
 	findByIDStmt    *sql.Stmt
+	findByIDsStmt   *sql.Stmt
 	findByEmailStmt *sql.Stmt
 	updateStmt      *sql.Stmt
 }
@@ -25,6 +31,7 @@ func NewUserStorage(db *DB) (*UserStorage, error) {
 	stmts := []stmt{
 		{Query: createUserQuery, Dst: &s.createStmt},
 		{Query: findUserByIDQuery, Dst: &s.findByIDStmt},
+		{Query: findUsersByIDsQuery, Dst: &s.findByIDsStmt},
 		{Query: findUserByEmailQuery, Dst: &s.findByEmailStmt},
 		{Query: updateUserQuery, Dst: &s.updateStmt},
 	}
@@ -37,11 +44,11 @@ func NewUserStorage(db *DB) (*UserStorage, error) {
 }
 
 const userFields = `id, first_name, last_name, birthday, email, password, ` +
-	`created_at, updated_at`
+	`created_at, updated_at, version`
 
 func scanUser(scanner sqlScanner, u *user.User) error {
 	return scanner.Scan(&u.ID, &u.FirstName, &u.LastName, &u.Birthday, &u.Email, &u.Password,
-		&u.CreatedAt, &u.UpdatedAt)
+		&u.CreatedAt, &u.UpdatedAt, &u.Version)
 }
 
 const createUserQuery = `INSERT INTO users(first_name, last_name, birthday, email, password, created_at, updated_at) ` +
@@ -50,6 +57,7 @@ const createUserQuery = `INSERT INTO users(first_name, last_name, birthday, emai
 func (s *UserStorage) Create(u *user.User) error {
 	u.CreatedAt = time.Now()
 	u.UpdatedAt = time.Now()
+	u.Version = 1
 
 	tx, err := s.db.Session.Begin()
 	if err != nil {
@@ -58,7 +66,13 @@ func (s *UserStorage) Create(u *user.User) error {
 
 	if _, err = tx.Stmt(s.createStmt).Exec(u.FirstName, u.LastName, u.Birthday, u.Email, u.Password, u.CreatedAt, u.UpdatedAt); err != nil {
 		_ = tx.Rollback()
-		return fmt.Errorf("user %s is already registered", u.Email)
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return fmt.Errorf("%w: %s", user.ErrAlreadyExists, u.Email)
+		}
+
+		return fmt.Errorf("can't create user: %s", err)
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -75,12 +89,49 @@ func (s *UserStorage) FindByID(id int) (*user.User, error) {
 
 	row := s.findByIDStmt.QueryRow(id)
 	if err := scanUser(row, &u); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: can't scan user: %s", user.ErrNotFound, err)
+		}
+
 		return nil, fmt.Errorf("can't scan user: %s", err)
 	}
 
 	return &u, nil
 }
 
+const findUsersByIDsQuery = `SELECT ` + userFields + ` FROM users WHERE id = ANY($1) ORDER BY id`
+
+// FindByIDs возвращает найденных пользователей из ids, отсутствующие пропускаются.
+func (s *UserStorage) FindByIDs(ids []int) ([]user.User, error) {
+	arg := make([]int64, len(ids))
+	for i, id := range ids {
+		arg[i] = int64(id)
+	}
+
+	rows, err := s.findByIDsStmt.Query(pq.Array(arg))
+	if err != nil {
+		return nil, fmt.Errorf("can't exec query: %s", err)
+	}
+	defer rows.Close()
+
+	users := make([]user.User, 0, len(ids))
+
+	for rows.Next() {
+		var u user.User
+		if err = scanUser(rows, &u); err != nil {
+			return nil, fmt.Errorf("can't scan user: %s", err)
+		}
+
+		users = append(users, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows return error: %s", err)
+	}
+
+	return users, nil
+}
+
 const findUserByEmailQuery = `SELECT ` + userFields + ` FROM users WHERE email = $1`
 
 func (s *UserStorage) FindByEmail(email string) (*user.User, error) {
@@ -88,6 +139,10 @@ func (s *UserStorage) FindByEmail(email string) (*user.User, error) {
 
 	row := s.findByEmailStmt.QueryRow(email)
 	if err := scanUser(row, &u); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: can't scan user: %s", user.ErrNotFound, err)
+		}
+
 		return nil, fmt.Errorf("can't scan user: %s", err)
 	}
 
@@ -95,9 +150,10 @@ func (s *UserStorage) FindByEmail(email string) (*user.User, error) {
 }
 
 const updateUserQuery = `UPDATE users ` +
-	`SET first_name = $1, last_name = $2, birthday = $3, email = $4, password = $5, updated_at = $6 ` +
-	`WHERE id = $7`
+	`SET first_name = $1, last_name = $2, birthday = $3, email = $4, password = $5, updated_at = $6, ` +
+	`version = version + 1 WHERE id = $7 AND version = $8`
 
+// Update сохраняет пользователя, если с тех пор, как его прочитали, версия не изменилась, и увеличивает версию.
 func (s *UserStorage) Update(u *user.User) error {
 	u.UpdatedAt = time.Now()
 
@@ -106,14 +162,29 @@ func (s *UserStorage) Update(u *user.User) error {
 		return fmt.Errorf("unable to start a transaction: %s", err)
 	}
 
-	if _, err = tx.Stmt(s.updateStmt).Exec(u.FirstName, u.LastName, u.Birthday, u.Email, u.Password, u.UpdatedAt, u.ID); err != nil {
+	res, err := tx.Stmt(s.updateStmt).Exec(u.FirstName, u.LastName, u.Birthday, u.Email, u.Password, u.UpdatedAt,
+		u.ID, u.Version)
+	if err != nil {
 		_ = tx.Rollback()
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return fmt.Errorf("%w: %s", user.ErrAlreadyExists, u.Email)
+		}
+
 		return fmt.Errorf("can't update user: %s", err)
 	}
 
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		_ = tx.Rollback()
+		return fmt.Errorf("%w: version %d is outdated", user.ErrConflict, u.Version)
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("can't commit in userStorage: %s", err)
 	}
 
+	u.Version++
+
 	return nil
 }
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRoute ключ квоты для маршрутов, у которых нет своей квоты.
+const DefaultRoute = "*"
+
+// ErrInvalidQuota квота в настройках задана неверно.
+var ErrInvalidQuota = errors.New("invalid rate limit quota")
+
+// Storage интерфейс хранилища корзин токенов. Корзина определяется ключом, например пользователем и маршрутом.
+type Storage interface {
+	// Take забирает токен из корзины key с квотой q в момент now. Если токенов нет, запрос не разрешается,
+	// а корзина не меняется.
+	Take(key string, q Quota, now time.Time) (Result, error)
+}
+
+// Quota квота: не больше Requests запросов за Period. Корзина вмещает Requests токенов и пополняется
+// равномерно, поэтому после паузы клиент может отправить сразу Requests запросов.
+type Quota struct {
+	Requests int
+	Period   time.Duration
+}
+
+// interval время, за которое в корзине появляется один токен.
+func (q Quota) interval() time.Duration {
+	return q.Period / time.Duration(q.Requests)
+}
+
+// Result результат Take. Limit — размер корзины, Remaining — сколько токенов осталось, Reset — через сколько
+// корзина снова будет полной, RetryAfter — через сколько появится токен, если запрос не разрешен.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Config настройки ограничения запросов в файле настроек сервиса.
+type Config struct {
+	// Routes квоты маршрутов клиента, маршрут DefaultRoute задает квоту остальных маршрутов.
+	Routes map[string]Quota `json:"routes"`
+	// IP квота всех запросов с одного IP-адреса, которую запрос расходует до проверки токена.
+	// Нулевая квота запросы не ограничивает.
+	IP Quota `json:"ip"`
+	// APIKeys имена клиентов по ключам API. Клиент с ключом получает свои корзины, а не корзины пользователя.
+	APIKeys map[string]string `json:"api_keys"`
+}
+
+// UnmarshalJSON читает квоту из строки вида "60/1m": число запросов и период.
+func (q *Quota) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("%w: %s is not a string", ErrInvalidQuota, b)
+	}
+
+	parsed, err := parseQuota(s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidQuota, err)
+	}
+
+	*q = parsed
+
+	return nil
+}
+
+// parseQuota читает квоту вида 60/1m.
+func parseQuota(s string) (Quota, error) {
+	parts := strings.SplitN(s, "/", 2) //nolint:gomnd
+	if len(parts) != 2 {               //nolint:gomnd
+		return Quota{}, fmt.Errorf("%q is not requests/period", s)
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests <= 0 {
+		return Quota{}, fmt.Errorf("requests %q is not a positive integer", parts[0])
+	}
+
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return Quota{}, fmt.Errorf("period %q is not a positive duration", parts[1])
+	}
+
+	return Quota{Requests: requests, Period: period}, nil
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageInMemory(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+	q := Quota{Requests: 2, Period: 2 * time.Second}
+	now := time.Now()
+
+	res, err := storage.Take("user:1", q, now)
+	assert.NoError(err)
+	assert.Equal(Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, res)
+
+	res, _ = storage.Take("user:1", q, now)
+	assert.True(res.Allowed)
+	assert.Equal(0, res.Remaining)
+	assert.Equal(2*time.Second, res.Reset)
+
+	res, _ = storage.Take("user:1", q, now.Add(500*time.Millisecond))
+	assert.False(res.Allowed, "bucket is empty")
+	assert.Equal(500*time.Millisecond, res.RetryAfter)
+
+	res, _ = storage.Take("user:2", q, now)
+	assert.True(res.Allowed, "buckets are per key")
+
+	res, _ = storage.Take("user:1", q, now.Add(time.Second))
+	assert.True(res.Allowed, "a token is added every second")
+	assert.Equal(0, res.Remaining)
+
+	res, _ = storage.Take("user:1", q, now.Add(time.Hour))
+	assert.True(res.Allowed)
+	assert.Equal(1, res.Remaining, "bucket holds no more than Requests tokens")
+
+	res, _ = storage.Take("user:1", Quota{Requests: 5, Period: time.Minute}, now.Add(time.Hour))
+	assert.Equal(4, res.Remaining, "changed quota starts a new bucket")
+
+	_, _ = storage.Take("user:3", q, now.Add(2*time.Hour))
+	assert.Len(storage.buckets, 1, "full buckets are swept")
+}
+
+func TestConfig(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Value    string
+		Expected Config
+		Invalid  bool
+	}
+
+	testCases := []testCase{
+		{Name: "Empty", Value: `{}`, Expected: Config{}},
+		{Name: "Routes", Value: `{"routes":{"*":"600/1m","GET /robots":"60/1m"},"ip":"1200/1m","api_keys":{"k1":"export"}}`,
+			Expected: Config{
+				Routes: map[string]Quota{
+					DefaultRoute:  {Requests: 600, Period: time.Minute},
+					"GET /robots": {Requests: 60, Period: time.Minute},
+				},
+				IP:      Quota{Requests: 1200, Period: time.Minute},
+				APIKeys: map[string]string{"k1": "export"},
+			}},
+		{Name: "Not a string", Value: `{"ip":60}`, Invalid: true},
+		{Name: "No period", Value: `{"routes":{"*":"60"}}`, Invalid: true},
+		{Name: "Zero requests", Value: `{"routes":{"*":"0/1m"}}`, Invalid: true},
+		{Name: "Invalid period", Value: `{"routes":{"*":"60/minute"}}`, Invalid: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			var cfg Config
+
+			err := json.Unmarshal([]byte(tc.Value), &cfg)
+			if tc.Invalid {
+				assert.True(t, errors.Is(err, ErrInvalidQuota), "error: %v", err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, cfg)
+		})
+	}
+}
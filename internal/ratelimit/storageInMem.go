@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+var _ Storage = &StorageInMemory{}
+
+// sweepInterval как часто хранилище удаляет полные корзины: они не отличаются от новых.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	quota   Quota
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// StorageInMemory структура хранилища корзин токенов в памяти. Подходит, когда сервис запущен в одном экземпляре.
+type StorageInMemory struct {
+	buckets map[string]*bucket
+	swept   time.Time
+	mutex   sync.Mutex
+}
+
+// CreateStorageInMemory возвращает указатель на хранилище корзин токенов in-memory.
+func CreateStorageInMemory() *StorageInMemory {
+	return &StorageInMemory{buckets: make(map[string]*bucket)}
+}
+
+// Take забирает токен из корзины key. Если квота корзины изменилась, корзина начинается заново.
+func (s *StorageInMemory) Take(key string, q Quota, now time.Time) (Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok || b.quota != q {
+		b = &bucket{quota: q, tokens: float64(q.Requests), updated: now}
+		s.buckets[key] = b
+	}
+
+	interval := q.interval()
+
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens += float64(elapsed) / float64(interval)
+		if b.tokens > float64(q.Requests) {
+			b.tokens = float64(q.Requests)
+		}
+
+		b.updated = now
+	}
+
+	res := Result{Limit: q.Requests}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+
+	res.Remaining = int(b.tokens)
+	res.Reset = time.Duration((float64(q.Requests) - b.tokens) * float64(interval))
+	b.full = now.Add(res.Reset)
+
+	return res, nil
+}
+
+// sweep удаляет корзины, которые к now снова полные.
+func (s *StorageInMemory) sweep(now time.Time) {
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+
+	s.swept = now
+
+	for key, b := range s.buckets {
+		if !b.full.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
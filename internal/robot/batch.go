@@ -0,0 +1,34 @@
+package robot
+
+import "errors"
+
+// MaxBatch наибольшее число роботов в одном пакетном запросе.
+const MaxBatch = 100
+
+// ErrBatchAborted операция над роботом отменена, потому что в атомарном пакете не прошла другая операция.
+var ErrBatchAborted = errors.New("batch is aborted")
+
+// AbortBatch проверяет, есть ли в пакете ошибки, и отмечает успешные операции как отмененные.
+// Используется в атомарном режиме, где пакет применяется целиком или не применяется совсем.
+func AbortBatch(errs []error) bool {
+	failed := false
+
+	for _, err := range errs {
+		if err != nil {
+			failed = true
+			break
+		}
+	}
+
+	if !failed {
+		return false
+	}
+
+	for i := range errs {
+		if errs[i] == nil {
+			errs[i] = ErrBatchAborted
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,80 @@
+package robot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitionBatch(t *testing.T) {
+	type testCase struct {
+		Name             string
+		Atomic           bool
+		ExpectedErrors   []error
+		ExpectedStatuses []Status
+	}
+
+	assert := assert.New(t)
+
+	testCases := []testCase{
+		{Name: "Partial", Atomic: false, ExpectedErrors: []error{nil, ErrTransition, ErrNotFound},
+			ExpectedStatuses: []Status{StatusActive, StatusActive}},
+		{Name: "Atomic", Atomic: true, ExpectedErrors: []error{ErrBatchAborted, ErrTransition, ErrNotFound},
+			ExpectedStatuses: []Status{StatusDraft, StatusActive}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			storage := CreateStorageInMemory()
+			draft := &Robot{OwnerUserID: 1, Ticker: "AAPL"}
+			active := &Robot{OwnerUserID: 1, Ticker: "AAPL", IsActive: true}
+
+			for _, r := range []*Robot{draft, active} {
+				assert.NoError(storage.Create(r))
+			}
+
+			errs, err := storage.TransitionBatch([]int{draft.RobotID, active.RobotID, 42}, ActionActivate, SourceUser, tc.Atomic)
+			assert.NoError(err)
+
+			if assert.Len(errs, len(tc.ExpectedErrors)) {
+				for i, expected := range tc.ExpectedErrors {
+					if expected == nil {
+						assert.NoError(errs[i])
+					} else {
+						assert.True(errors.Is(errs[i], expected))
+					}
+				}
+			}
+
+			for i, r := range []*Robot{draft, active} {
+				actual, err := storage.FindByID(r.RobotID)
+				assert.NoError(err)
+				assert.Equal(tc.ExpectedStatuses[i], actual.Status)
+			}
+		})
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+
+	robots := []*Robot{{OwnerUserID: 1, Ticker: "AAPL"}, {OwnerUserID: 1, Ticker: "AAPL", Visibility: "secret"}}
+
+	errs, err := storage.CreateBatch(robots, true)
+	assert.NoError(err)
+	assert.True(errors.Is(errs[0], ErrBatchAborted))
+	assert.True(errors.Is(errs[1], ErrInvalidRobot))
+	assert.Zero(robots[0].RobotID, "atomic batch with an invalid robot creates nothing")
+
+	errs, err = storage.CreateBatch(robots, false)
+	assert.NoError(err)
+	assert.NoError(errs[0])
+	assert.True(errors.Is(errs[1], ErrInvalidRobot))
+
+	_, err = storage.FindByID(robots[0].RobotID)
+	assert.NoError(err)
+}
@@ -0,0 +1,99 @@
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrMirror = errors.New("robot mirrors its parent")
+
+// Follow параметры добавления робота в избранное.
+// Копия с Mirror повторяет изменения параметров и сделки родителя в размере Lots лотов,
+// копия без Mirror независима от родителя.
+type Follow struct {
+	Mirror bool `json:"mirror"`
+	Lots   int  `json:"lots"`
+}
+
+// NewFollower возвращает копию робота parent для пользователя userID.
+func NewFollower(parent *Robot, userID int, follow Follow) (*Robot, error) {
+	if follow.Mirror && parent.IsMirror {
+		return nil, fmt.Errorf("%w: can't follow a robot that mirrors another one", ErrMirror)
+	}
+
+	if follow.Lots < 0 {
+		return nil, fmt.Errorf("%w: lots can't be negative", ErrInvalidRobot)
+	}
+
+	r := *parent
+	r.RobotID = 0
+	r.OwnerUserID = userID
+	r.ParentRobotID = parent.RobotID
+	r.ClonedFromID = 0
+	r.TemplateID = 0
+	r.IsFavourite = true
+	r.IsActive = false
+	r.IsMirror = follow.Mirror
+	r.Lots = follow.Lots
+	r.Status = ""
+	r.DealsCount = 0
+	r.FactYield = 0.0
+	r.Followers = 0
+	r.Visibility = VisibilityPrivate
+	r.ShareToken = ""
+	r.ActivatedAt = NullTime{}
+	r.DeactivatedAt = NullTime{}
+	r.DeletedAt = NullTime{}
+
+	return &r, nil
+}
+
+// SyncWith переносит в робота-подписчика параметры родителя и увеличивает версию. Пока у подписчика открыта
+// позиция, тикер и цены не меняются, как и в Edit: их переносит повторный SyncWith после закрытия позиции.
+// Возвращает false, если переносить нечего, тогда робот не меняется.
+func (r *Robot) SyncWith(parent *Robot, now time.Time) bool {
+	synced := *r
+	synced.setParams(parent)
+
+	if !r.IsBuying {
+		synced.Ticker, synced.BuyPrice, synced.SellPrice = r.Ticker, r.BuyPrice, r.SellPrice
+	}
+
+	if synced.sameParams(r) {
+		return false
+	}
+
+	*r = synced
+
+	if r.Can(ActionSchedule, now) == nil {
+		_, _ = r.Apply(ActionSchedule, now)
+	}
+
+	r.Version++
+
+	return true
+}
+
+// setParams копирует торговые параметры из src.
+func (r *Robot) setParams(src *Robot) {
+	r.Ticker = src.Ticker
+	r.BuyPrice = src.BuyPrice
+	r.SellPrice = src.SellPrice
+	r.PlanStart = src.PlanStart
+	r.PlanEnd = src.PlanEnd
+	r.PlanYield = src.PlanYield
+	r.AutoClose = src.AutoClose
+	r.Schedule = src.Schedule
+}
+
+// sameParams сообщает, что торговые параметры роботов совпадают.
+func (r *Robot) sameParams(other *Robot) bool {
+	return r.Ticker == other.Ticker && r.BuyPrice == other.BuyPrice && r.SellPrice == other.SellPrice &&
+		sameTime(r.PlanStart, other.PlanStart) && sameTime(r.PlanEnd, other.PlanEnd) &&
+		r.PlanYield == other.PlanYield && r.AutoClose == other.AutoClose && r.Schedule == other.Schedule
+}
+
+func sameTime(a, b NullTime) bool {
+	return a.Valid == b.Valid && a.Time.Equal(b.Time)
+}
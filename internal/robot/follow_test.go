@@ -0,0 +1,100 @@
+package robot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncWith(t *testing.T) {
+	type testCase struct {
+		Name           string
+		Follower       Robot
+		ExpectedSynced bool
+		ExpectedTicker string
+		ExpectedYield  float64
+	}
+
+	now := time.Now()
+	parent := Robot{Ticker: "SBER", BuyPrice: 100, SellPrice: 120, PlanYield: 5}
+	waiting := Robot{Status: StatusActive, IsBuying: true, IsMirror: true, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20,
+		Lots: 1, Version: 1}
+	holding := waiting
+	holding.IsBuying = false
+	holding.Status = StatusHolding
+
+	testCases := []testCase{
+		{Name: "Waiting follower", Follower: waiting, ExpectedSynced: true, ExpectedTicker: "SBER", ExpectedYield: 5},
+		{Name: "Holding follower keeps ticker and prices", Follower: holding, ExpectedSynced: true,
+			ExpectedTicker: "AAPL", ExpectedYield: 5},
+		{Name: "Nothing to sync", Follower: Robot{IsBuying: true, Ticker: "SBER", BuyPrice: 100, SellPrice: 120,
+			PlanYield: 5, Version: 1}, ExpectedSynced: false, ExpectedTicker: "SBER", ExpectedYield: 5},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			before := tc.Follower
+
+			assert.Equal(t, tc.ExpectedSynced, tc.Follower.SyncWith(&parent, now))
+			assert.Equal(t, tc.ExpectedTicker, tc.Follower.Ticker)
+			assert.Equal(t, tc.ExpectedYield, tc.Follower.PlanYield)
+			assert.Equal(t, before.Lots, tc.Follower.Lots, "follower keeps its lots")
+
+			if tc.ExpectedSynced {
+				assert.Equal(t, before.Version+1, tc.Follower.Version)
+			} else {
+				assert.Equal(t, before, tc.Follower)
+			}
+		})
+	}
+}
+
+func TestSyncHoldingFollower(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+
+	parent := &Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(storage.Create(parent))
+
+	follower, err := storage.FavouriteRobot(parent.RobotID, 2, Follow{Mirror: true, Lots: 2}, 0)
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.NoError(storage.Transition(follower.RobotID, ActionActivate, SourceUser, 0))
+
+	follower.IsBuying = false
+	assert.NoError(storage.Trade(follower, Deal{Side: "buy", Price: 10}))
+
+	changes, err := storage.FindByID(parent.RobotID)
+	assert.NoError(err)
+
+	changes.Ticker, changes.BuyPrice, changes.SellPrice, changes.PlanYield = "SBER", 100, 120, 5
+	_, err = storage.Edit(parent.RobotID, changes)
+	assert.NoError(err)
+
+	holding, err := storage.FindByID(follower.RobotID)
+	assert.NoError(err)
+	assert.Equal("AAPL", holding.Ticker, "holding follower keeps its ticker")
+	assert.Equal(10.0, holding.BuyPrice)
+	assert.Equal(5.0, holding.PlanYield, "plan is synced while holding")
+	assert.Equal(2, holding.Lots)
+
+	holding.IsBuying = true
+	assert.NoError(storage.Trade(holding, Deal{Side: "sell", Price: 20}))
+
+	closed, err := storage.FindByID(follower.RobotID)
+	assert.NoError(err)
+	assert.Equal("SBER", closed.Ticker, "deferred parameters are synced after the position is closed")
+	assert.Equal(100.0, closed.BuyPrice)
+	assert.Equal(120.0, closed.SellPrice)
+
+	deals, err := storage.FindDeals(follower.RobotID)
+	assert.NoError(err)
+
+	if assert.Len(deals, 2) {
+		assert.Equal(holding.Version, deals[1].Version, "closing deal is recorded with the version it was made by")
+	}
+}
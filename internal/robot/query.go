@@ -0,0 +1,443 @@
+package robot
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Размер страницы списка роботов.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Направления сортировки.
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+const dateLayout = "2006-01-02"
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Query параметры выборки неудаленных роботов: фильтры, сортировка и страница.
+// Фильтры со значением nil не учитываются. Если задан ViewerID, выбираются только роботы,
+// которые пользователь видит без ссылки.
+type Query struct {
+	Ticker        string
+	OwnerUserID   *int
+	ParentRobotID *int
+	ClonedFromID  *int
+	TemplateID    *int
+	IsActive      *bool
+	IsFavourite   *bool
+	MinYield      *float64
+	MaxYield      *float64
+	MinBuyPrice   *float64
+	MaxBuyPrice   *float64
+	MinSellPrice  *float64
+	MaxSellPrice  *float64
+	CreatedFrom   NullTime
+	CreatedTo     NullTime
+	Sort          string
+	Desc          bool
+	ViewerID      *int
+	After         *Cursor
+	Limit         int
+}
+
+// Cursor позиция последнего робота страницы: значение поля сортировки и ID робота.
+type Cursor struct {
+	Value   interface{}
+	RobotID int
+}
+
+// Page страница списка роботов. NextCursor пуст на последней странице.
+type Page struct {
+	Robots     []Robot `json:"robots"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type fieldKind int
+
+const (
+	kindInt fieldKind = iota
+	kindFloat
+	kindString
+	kindBool
+	kindTime
+)
+
+// sortField поле, по которому можно сортировать роботов. Значение пустого времени — нулевое время.
+type sortField struct {
+	kind  fieldKind
+	value func(r *Robot) interface{}
+}
+
+// sortFields поля сортировки по их имени в json.
+var sortFields = map[string]sortField{ //nolint:gochecknoglobals
+	"robot_id":        {kindInt, func(r *Robot) interface{} { return r.RobotID }},
+	"owner_user_id":   {kindInt, func(r *Robot) interface{} { return r.OwnerUserID }},
+	"parent_robot_id": {kindInt, func(r *Robot) interface{} { return r.ParentRobotID }},
+	"cloned_from_id":  {kindInt, func(r *Robot) interface{} { return r.ClonedFromID }},
+	"template_id":     {kindInt, func(r *Robot) interface{} { return r.TemplateID }},
+	"is_favourite":    {kindBool, func(r *Robot) interface{} { return r.IsFavourite }}, //nolint:misspell
+	"is_active":       {kindBool, func(r *Robot) interface{} { return r.IsActive }},
+	"status":          {kindString, func(r *Robot) interface{} { return string(r.Status) }},
+	"ticker":          {kindString, func(r *Robot) interface{} { return r.Ticker }},
+	"buy_price":       {kindFloat, func(r *Robot) interface{} { return r.BuyPrice }},
+	"sell_price":      {kindFloat, func(r *Robot) interface{} { return r.SellPrice }},
+	"plan_start":      {kindTime, func(r *Robot) interface{} { return r.PlanStart.Time }},
+	"plan_end":        {kindTime, func(r *Robot) interface{} { return r.PlanEnd.Time }},
+	"plan_yield":      {kindFloat, func(r *Robot) interface{} { return r.PlanYield }},
+	"fact_yield":      {kindFloat, func(r *Robot) interface{} { return r.FactYield }},
+	"deals_count":     {kindInt, func(r *Robot) interface{} { return r.DealsCount }},
+	"auto_close":      {kindBool, func(r *Robot) interface{} { return r.AutoClose }},
+	"schedule":        {kindString, func(r *Robot) interface{} { return r.Schedule }},
+	"version":         {kindInt, func(r *Robot) interface{} { return r.Version }},
+	"is_mirror":       {kindBool, func(r *Robot) interface{} { return r.IsMirror }},
+	"lots":            {kindInt, func(r *Robot) interface{} { return r.Lots }},
+	"followers":       {kindInt, func(r *Robot) interface{} { return r.Followers }},
+	"visibility":      {kindString, func(r *Robot) interface{} { return string(r.Visibility) }},
+	"created_at":      {kindTime, func(r *Robot) interface{} { return r.CreatedAt.Time }},
+}
+
+// SortFields возвращает имена полей, по которым можно сортировать роботов.
+func SortFields() []string {
+	names := make([]string, 0, len(sortFields))
+	for name := range sortFields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseQuery разбирает и проверяет query-параметры списка роботов.
+func ParseQuery(values url.Values) (*Query, error) {
+	q := &Query{Ticker: values.Get("ticker"), Sort: values.Get("sort"), Limit: DefaultLimit}
+
+	if q.Sort == "" {
+		q.Sort = "robot_id"
+	}
+
+	if _, ok := sortFields[q.Sort]; !ok {
+		return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, q.Sort)
+	}
+
+	switch values.Get("order") {
+	case OrderAsc, "":
+	case OrderDesc:
+		q.Desc = true
+	default:
+		return nil, fmt.Errorf("%w: order must be %q or %q", ErrInvalidQuery, OrderAsc, OrderDesc)
+	}
+
+	p := queryParser{values: values}
+	q.OwnerUserID = p.int("user")
+	q.ParentRobotID = p.int("parent")
+	q.ClonedFromID = p.int("cloned_from")
+	q.TemplateID = p.int("template")
+	q.IsActive = p.bool("is_active")
+	q.IsFavourite = p.bool("is_favourite") //nolint:misspell
+	q.MinYield = p.float("min_yield")
+	q.MaxYield = p.float("max_yield")
+	q.MinBuyPrice = p.float("min_buy_price")
+	q.MaxBuyPrice = p.float("max_buy_price")
+	q.MinSellPrice = p.float("min_sell_price")
+	q.MaxSellPrice = p.float("max_sell_price")
+	q.CreatedFrom = p.time("created_from")
+	q.CreatedTo = p.time("created_to")
+
+	if limit := p.int("limit"); limit != nil {
+		q.Limit = *limit
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	if q.Limit < 1 || q.Limit > MaxLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxLimit)
+	}
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		after, err := q.decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		q.After = after
+	}
+
+	return q, nil
+}
+
+// queryParser разбирает query-параметры и запоминает первую ошибку.
+type queryParser struct {
+	values url.Values
+	err    error
+}
+
+func (p *queryParser) get(name string) (string, bool) {
+	value := p.values.Get(name)
+	return value, value != "" && p.err == nil
+}
+
+func (p *queryParser) fail(name, value string) {
+	p.err = fmt.Errorf("%w: invalid %s %q", ErrInvalidQuery, name, value)
+}
+
+func (p *queryParser) int(name string) *int {
+	value, ok := p.get(name)
+	if !ok {
+		return nil
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(name, value)
+		return nil
+	}
+
+	return &v
+}
+
+func (p *queryParser) float(name string) *float64 {
+	value, ok := p.get(name)
+	if !ok {
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.fail(name, value)
+		return nil
+	}
+
+	return &v
+}
+
+func (p *queryParser) bool(name string) *bool {
+	value, ok := p.get(name)
+	if !ok {
+		return nil
+	}
+
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		p.fail(name, value)
+		return nil
+	}
+
+	return &v
+}
+
+// time принимает дату 2006-01-02 или время в RFC 3339.
+func (p *queryParser) time(name string) NullTime {
+	value, ok := p.get(name)
+	if !ok {
+		return NullTime{}
+	}
+
+	for _, layout := range []string{time.RFC3339, dateLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return NullTime{Time: t, Valid: true}
+		}
+	}
+
+	p.fail(name, value)
+
+	return NullTime{}
+}
+
+// Match проверяет, что робот удовлетворяет фильтрам выборки.
+func (q *Query) Match(r *Robot) bool {
+	switch {
+	case q.Ticker != "" && r.Ticker != q.Ticker,
+		q.OwnerUserID != nil && r.OwnerUserID != *q.OwnerUserID,
+		q.ParentRobotID != nil && r.ParentRobotID != *q.ParentRobotID,
+		q.ClonedFromID != nil && r.ClonedFromID != *q.ClonedFromID,
+		q.TemplateID != nil && r.TemplateID != *q.TemplateID,
+		q.IsActive != nil && r.IsActive != *q.IsActive,
+		q.IsFavourite != nil && r.IsFavourite != *q.IsFavourite,
+		q.MinYield != nil && r.FactYield < *q.MinYield,
+		q.MaxYield != nil && r.FactYield > *q.MaxYield,
+		q.MinBuyPrice != nil && r.BuyPrice < *q.MinBuyPrice,
+		q.MaxBuyPrice != nil && r.BuyPrice > *q.MaxBuyPrice,
+		q.MinSellPrice != nil && r.SellPrice < *q.MinSellPrice,
+		q.MaxSellPrice != nil && r.SellPrice > *q.MaxSellPrice,
+		q.CreatedFrom.Valid && r.CreatedAt.Time.Before(q.CreatedFrom.Time),
+		q.CreatedTo.Valid && !r.CreatedAt.Time.Before(q.CreatedTo.Time):
+		return false
+	}
+
+	return true
+}
+
+// compare сравнивает роботов в порядке выборки: по полю сортировки, затем по ID.
+func (q *Query) compare(a *Robot, value interface{}, robotID int) int {
+	c := compareValues(sortFields[q.Sort].value(a), value)
+	if c == 0 {
+		c = compareValues(a.RobotID, robotID)
+	}
+
+	if q.Desc {
+		return -c
+	}
+
+	return c
+}
+
+// SortRobots сортирует роботов в порядке выборки.
+func (q *Query) SortRobots(robots []Robot) {
+	field := sortFields[q.Sort]
+
+	sort.Slice(robots, func(i, j int) bool {
+		return q.compare(&robots[i], field.value(&robots[j]), robots[j].RobotID) < 0
+	})
+}
+
+// IsAfterCursor проверяет, что робот идет после курсора выборки.
+func (q *Query) IsAfterCursor(r *Robot) bool {
+	return q.After == nil || q.compare(r, q.After.Value, q.After.RobotID) > 0
+}
+
+// NewPage собирает страницу из отсортированных роботов после курсора.
+// Хранилище передает до Limit+1 роботов, лишний робот означает, что есть следующая страница.
+func (q *Query) NewPage(robots []Robot) *Page {
+	if len(robots) <= q.Limit {
+		return &Page{Robots: robots}
+	}
+
+	robots = robots[:q.Limit]
+	last := &robots[len(robots)-1]
+
+	return &Page{Robots: robots, NextCursor: q.encodeCursor(last)}
+}
+
+// cursorJSON представление курсора; курсор привязан к сортировке, по которой получен.
+type cursorJSON struct {
+	Sort    string `json:"s"`
+	Desc    bool   `json:"d,omitempty"`
+	Value   string `json:"v"`
+	RobotID int    `json:"id"`
+}
+
+func (q *Query) encodeCursor(r *Robot) string {
+	var value string
+
+	switch v := sortFields[q.Sort].value(r).(type) {
+	case int:
+		value = strconv.Itoa(v)
+	case float64:
+		value = strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		value = v
+	case bool:
+		value = strconv.FormatBool(v)
+	case time.Time:
+		value = v.UTC().Format(time.RFC3339Nano)
+	}
+
+	b, _ := json.Marshal(cursorJSON{Sort: q.Sort, Desc: q.Desc, Value: value, RobotID: r.RobotID})
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (q *Query) decodeCursor(cursor string) (*Cursor, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var c cursorJSON
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, invalid
+	}
+
+	if c.Sort != q.Sort || c.Desc != q.Desc {
+		return nil, fmt.Errorf("%w: cursor belongs to another sort order", ErrInvalidQuery)
+	}
+
+	var value interface{}
+
+	switch sortFields[q.Sort].kind {
+	case kindInt:
+		value, err = strconv.Atoi(c.Value)
+	case kindFloat:
+		value, err = strconv.ParseFloat(c.Value, 64)
+	case kindString:
+		value = c.Value
+	case kindBool:
+		value, err = strconv.ParseBool(c.Value)
+	case kindTime:
+		value, err = time.Parse(time.RFC3339Nano, c.Value)
+	}
+
+	if err != nil {
+		return nil, invalid
+	}
+
+	return &Cursor{Value: value, RobotID: c.RobotID}, nil
+}
+
+func compareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case int:
+		return compareFloat(float64(a), float64(b.(int)))
+	case float64:
+		return compareFloat(a, b.(float64))
+	case string:
+		b := b.(string)
+
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case bool:
+		return compareFloat(boolToFloat(a), boolToFloat(b.(bool)))
+	case time.Time:
+		b := b.(time.Time)
+
+		switch {
+		case a.Before(b):
+			return -1
+		case a.After(b):
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
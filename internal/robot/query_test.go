@@ -0,0 +1,111 @@
+package robot
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	type testCase struct {
+		Name  string
+		Query string
+		Err   bool
+	}
+
+	testCases := []testCase{
+		{Name: "Empty", Query: ""},
+		{Name: "All filters", Query: "ticker=AAPL&user=1&parent=2&is_active=true&is_favourite=false&min_yield=-5" +
+			"&max_yield=10.5&min_buy_price=1&max_buy_price=2&min_sell_price=3&max_sell_price=4" +
+			"&created_from=2020-01-01&created_to=2020-02-01T10:00:00Z&sort=fact_yield&order=desc&limit=100"},
+		{Name: "Unknown sort", Query: "sort=password", Err: true},
+		{Name: "Unknown order", Query: "order=up", Err: true},
+		{Name: "Invalid user", Query: "user=first", Err: true},
+		{Name: "Invalid bool", Query: "is_active=maybe", Err: true},
+		{Name: "Invalid price", Query: "min_buy_price=cheap", Err: true},
+		{Name: "Invalid date", Query: "created_from=yesterday", Err: true},
+		{Name: "Limit too big", Query: "limit=101", Err: true},
+		{Name: "Invalid cursor", Query: "cursor=abc", Err: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.Query)
+			assert.NoError(t, err)
+
+			_, err = ParseQuery(values)
+			if tc.Err {
+				assert.True(t, errors.Is(err, ErrInvalidQuery), "got %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFindPages(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+
+	yields := []float64{5, 20, 5, 10, 20, -3, 5}
+	for i, y := range yields {
+		assert.NoError(storage.Create(&Robot{OwnerUserID: i % 2, Ticker: "AAPL", BuyPrice: float64(i), FactYield: y}))
+	}
+
+	assert.NoError(storage.Create(&Robot{OwnerUserID: 0, Ticker: "SBER", FactYield: 100}))
+
+	var (
+		ids    []int
+		cursor string
+		first  string
+	)
+
+	for pages := 0; pages < len(yields); pages++ {
+		values := url.Values{"ticker": {"AAPL"}, "sort": {"fact_yield"}, "order": {"desc"}, "limit": {"3"}}
+		if cursor != "" {
+			values.Set("cursor", cursor)
+		}
+
+		q, err := ParseQuery(values)
+		assert.NoError(err)
+
+		page, err := storage.Find(q)
+		assert.NoError(err)
+
+		for _, r := range page.Robots {
+			ids = append(ids, r.RobotID)
+		}
+
+		cursor = page.NextCursor
+		if first == "" {
+			first = cursor
+		}
+
+		if cursor == "" {
+			break
+		}
+	}
+
+	assert.Equal([]int{5, 2, 4, 7, 3, 1, 6}, ids, "ties must be ordered by robot_id in the same direction")
+
+	q, err := ParseQuery(url.Values{"user": {"1"}, "min_buy_price": {"2"}, "min_yield": {"0"}, "max_yield": {"10"}})
+	assert.NoError(err)
+
+	page, err := storage.Find(q)
+	assert.NoError(err)
+
+	if assert.Len(page.Robots, 1) {
+		assert.Equal(4, page.Robots[0].RobotID)
+	}
+
+	assert.Empty(page.NextCursor)
+
+	_, err = ParseQuery(url.Values{"sort": {"ticker"}, "order": {"desc"}, "cursor": {first}})
+	assert.True(errors.Is(err, ErrInvalidQuery), "cursor can't be reused with another sort field")
+
+	_, err = ParseQuery(url.Values{"sort": {"fact_yield"}, "cursor": {first}})
+	assert.True(errors.Is(err, ErrInvalidQuery), "cursor can't be reused with another order")
+}
@@ -3,49 +3,104 @@ package robot
 import (
 	"encoding/json"
 	"errors"
+	"time"
+)
+
+// Источники переходов робота между состояниями.
+const (
+	SourceUser     = "user"
+	SourceSchedule = "schedule"
 )
 
 var (
-	ErrNotFound  = errors.New("not found object")
-	ErrInvalidID = errors.New("invalid id")
+	ErrNotFound      = errors.New("not found object")
+	ErrInvalidID     = errors.New("invalid id")
+	ErrUnknownTicker = errors.New("unknown ticker")
+	// ErrConflict робота изменил другой запрос после того, как его прочитали.
+	ErrConflict = errors.New("robot was changed by another request")
+	// ErrNotTrading сделку торгового процесса не сохранить: робота остановили, пока он торговал.
+	ErrNotTrading = errors.New("robot is not trading")
 )
 
 type Storage interface {
 	Create(robot *Robot) error
+	CreateBatch(robots []*Robot, atomic bool) ([]error, error)
 	FindByID(id int) (*Robot, error)
 	FindActivatedByUserID(userID int) ([]Robot, error)
 	FindActivatedByTicker(ticker string) ([]Robot, error)
 	FindActivated() ([]Robot, error)
 	FindActivatedByTickerUserID(ticker string, id int) ([]Robot, error)
-	Filter(ticker, userID string) ([]Robot, error)
-	FavouriteRobot(parentRobotID, userID int) error
+	Find(q *Query) (*Page, error)
+	FindVisible(robotID, userID int, shareToken string) (*Robot, error)
+	FindVisibleByIDs(robotIDs []int, userID int) ([]Robot, error)
+	Share(robotID, userID int) error
+	Unshare(robotID, userID int) error
+	FindShares(robotID int) ([]int, error)
+	// Методы, которые принимают revision, меняют робота, только если его ревизия равна revision,
+	// иначе возвращают ErrConflict. Нулевая revision означает изменение без проверки.
+	FavouriteRobot(parentRobotID, userID int, follow Follow, revision int) (*Robot, error)
+	FindFollowers(parentRobotID int) ([]Robot, error)
+	Unfollow(robotID, revision int) error
 	ActivateRobot(robotID int) error
 	DeactivateRobot(robotID int) error
 	FindToTrading() ([]Robot, error)
-	Trade(robot *Robot) error
-	SoftDelete(id int) error
+	FindScheduled() ([]Robot, error)
+	Transition(robotID int, action Action, source string, revision int) error
+	TransitionBatch(robotIDs []int, action Action, source string, atomic bool) ([]error, error)
+	FindTransitions(robotID int) ([]Transition, error)
+	Edit(robotID int, changes *Robot) (*Robot, error)
+	FindVersions(robotID int) ([]Version, error)
+	// Trade сохраняет сделку только торгующего робота, иначе возвращает ErrNotTrading.
+	Trade(robot *Robot, deal Deal) error
+	// ClosePosition сохраняет продажу, которой закрывается открытая позиция остановленного робота.
+	// Если робот торгует или позиция уже закрыта, возвращает ErrConflict.
+	ClosePosition(robot *Robot, deal Deal) error
+	FindDeals(robotID int) ([]Deal, error)
+	FindDealsByRobotIDs(robotIDs []int) ([]Deal, error)
+	SoftDelete(id, revision int) error
+	FindDeleted(userID int) ([]Robot, error)
+	FindDeletedByID(robotID int) (*Robot, error)
+	Restore(robotID int) error
+	Purge(robotID int) error
+	PurgeDeleted(before time.Time) (int, error)
+	CreateTemplate(t *Template) error
+	FindTemplates(userID int) ([]Template, error)
+	FindTemplateByID(id int) (*Template, error)
+	DeleteTemplate(id int) error
 }
 
 // Robot структура торгового робота
 type Robot struct {
-	RobotID       int      `json:"robot_id"`
-	OwnerUserID   int      `json:"owner_user_id"`
-	ParentRobotID int      `json:"parent_robot_id"`
-	IsFavourite   bool     `json:"is_favourite"` //nolint:misspell
-	IsActive      bool     `json:"is_active"`
-	Ticker        string   `json:"ticker"`
-	BuyPrice      float64  `json:"buy_price"`
-	SellPrice     float64  `json:"sell_price"`
-	PlanStart     NullTime `json:"plan_start"`
-	PlanEnd       NullTime `json:"plan_end"`
-	PlanYield     float64  `json:"plan_yield"`
-	FactYield     float64  `json:"fact_yield"`
-	DealsCount    int      `json:"deals_count"`
-	ActivatedAt   NullTime `json:"-"`
-	DeactivatedAt NullTime `json:"-"`
-	CreatedAt     NullTime `json:"-"`
-	DeletedAt     NullTime `json:"-"`
-	IsBuying      bool     `json:"-"`
+	RobotID       int        `json:"robot_id"`
+	OwnerUserID   int        `json:"owner_user_id"`
+	ParentRobotID int        `json:"parent_robot_id"`
+	ClonedFromID  int        `json:"cloned_from_id"`
+	TemplateID    int        `json:"template_id"`
+	IsFavourite   bool       `json:"is_favourite"` //nolint:misspell
+	IsActive      bool       `json:"is_active"`
+	Status        Status     `json:"status"`
+	Ticker        string     `json:"ticker"`
+	BuyPrice      float64    `json:"buy_price"`
+	SellPrice     float64    `json:"sell_price"`
+	PlanStart     NullTime   `json:"plan_start"`
+	PlanEnd       NullTime   `json:"plan_end"`
+	PlanYield     float64    `json:"plan_yield"`
+	FactYield     float64    `json:"fact_yield"`
+	DealsCount    int        `json:"deals_count"`
+	AutoClose     bool       `json:"auto_close"`
+	Schedule      string     `json:"schedule"`
+	Version       int        `json:"version"`
+	Revision      int        `json:"-"`
+	IsMirror      bool       `json:"is_mirror"`
+	Lots          int        `json:"lots"`
+	Followers     int        `json:"followers"`
+	Visibility    Visibility `json:"visibility"`
+	ShareToken    string     `json:"-"`
+	ActivatedAt   NullTime   `json:"-"`
+	DeactivatedAt NullTime   `json:"-"`
+	CreatedAt     NullTime   `json:"-"`
+	DeletedAt     NullTime   `json:"-"`
+	IsBuying      bool       `json:"-"`
 }
 
 func (r *Robot) MarshalJSON() ([]byte, error) {
@@ -57,22 +112,55 @@ func (r *Robot) MarshalJSON() ([]byte, error) {
 		DeactivatedAt NullTime `json:"deactivated_at,omitempty"`
 		CreatedAt     NullTime `json:"created_at"`
 		DeletedAt     NullTime `json:"deleted_at,omitempty"`
+		Actions       []Action `json:"actions"`
 	}{
 		Alias:         (*Alias)(r),
 		ActivatedAt:   r.ActivatedAt,
 		DeactivatedAt: r.DeactivatedAt,
 		CreatedAt:     r.CreatedAt,
 		DeletedAt:     r.DeletedAt,
+		Actions:       r.AllowedActions(time.Now()),
 	})
 }
 
+// LastChange возвращает время последней активации или деактивации робота.
+func (r *Robot) LastChange() time.Time {
+	if r.DeactivatedAt.Valid && (!r.ActivatedAt.Valid || r.DeactivatedAt.Time.After(r.ActivatedAt.Time)) {
+		return r.DeactivatedAt.Time
+	}
+
+	if r.ActivatedAt.Valid {
+		return r.ActivatedAt.Time
+	}
+
+	return time.Time{}
+}
+
 func (r *Robot) Buy(buyPrice float64) {
-	r.FactYield -= buyPrice
+	r.FactYield -= buyPrice * float64(r.lots())
 	r.IsBuying = false
+	_, _ = r.Apply(ActionBuy, time.Now())
 }
 
 func (r *Robot) Sell(sellPrice float64) {
-	r.FactYield += sellPrice
+	r.FactYield += sellPrice * float64(r.lots())
 	r.DealsCount++
 	r.IsBuying = true
+	_, _ = r.Apply(ActionSell, time.Now())
+}
+
+// lots возвращает размер позиции робота в лотах.
+func (r *Robot) lots() int {
+	return lotsOf(r.Lots)
+}
+
+// Transition запись о переходе робота между состояниями.
+type Transition struct {
+	ID         int       `json:"id"`
+	RobotID    int       `json:"robot_id"`
+	Action     Action    `json:"action"`
+	FromStatus Status    `json:"from_status"`
+	ToStatus   Status    `json:"to_status"`
+	Source     string    `json:"source"`
+	CreatedAt  time.Time `json:"created_at"`
 }
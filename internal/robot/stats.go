@@ -0,0 +1,185 @@
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Метрики, по которым строится рейтинг роботов.
+const (
+	MetricYield     = "yield"
+	MetricSharpe    = "sharpe"
+	MetricDeals     = "deals"
+	MetricDrawdown  = "drawdown"
+	MetricFollowers = "followers"
+)
+
+// Периоды, за которые считается статистика роботов.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+	PeriodAll   = "all"
+)
+
+var (
+	ErrUnknownMetric = errors.New("unknown metric")
+	ErrUnknownPeriod = errors.New("unknown period")
+)
+
+// Stats результаты робота за период по закрытым сделкам.
+type Stats struct {
+	RobotID     int     `json:"robot_id"`
+	OwnerUserID int     `json:"owner_user_id"`
+	Ticker      string  `json:"ticker"`
+	Yield       float64 `json:"yield"`
+	Sharpe      float64 `json:"sharpe"`
+	Deals       int     `json:"deals"`
+	Drawdown    float64 `json:"drawdown"`
+	Followers   int     `json:"followers"`
+}
+
+// PeriodStart возвращает начало периода, который заканчивается в now; для PeriodAll — нулевое время.
+func PeriodStart(period string, now time.Time) (time.Time, error) {
+	switch period {
+	case PeriodDay:
+		return now.AddDate(0, 0, -1), nil
+	case PeriodWeek:
+		return now.AddDate(0, 0, -7), nil
+	case PeriodMonth:
+		return now.AddDate(0, -1, 0), nil
+	case PeriodYear:
+		return now.AddDate(-1, 0, 0), nil
+	case PeriodAll, "":
+		return time.Time{}, nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", ErrUnknownPeriod, period)
+}
+
+// ComputeStats считает статистику робота по его сделкам, отсортированным по времени.
+// Сделка считается закрытой при продаже и учитывается, если продажа была не раньше since.
+// Sharpe — отношение средней доходности закрытой сделки к ее стандартному отклонению,
+// Drawdown — наибольшее падение накопленной прибыли от максимума.
+func ComputeStats(r *Robot, deals []Deal, since time.Time) Stats {
+	s := Stats{RobotID: r.RobotID, OwnerUserID: r.OwnerUserID, Ticker: r.Ticker, Followers: r.Followers}
+
+	var (
+		returns          []float64
+		open             *Deal
+		cumulative, peak float64
+	)
+
+	for i := range deals {
+		d := deals[i]
+
+		if d.Side == SideBuy {
+			open = &d
+			continue
+		}
+
+		if open == nil || d.CreatedAt.Before(since) {
+			open = nil
+			continue
+		}
+
+		profit := (d.Price - open.Price) * float64(lotsOf(open.Lots))
+		s.Yield += profit
+		s.Deals++
+
+		if open.Price != 0 {
+			returns = append(returns, (d.Price-open.Price)/open.Price)
+		}
+
+		cumulative += profit
+		peak = math.Max(peak, cumulative)
+		s.Drawdown = math.Max(s.Drawdown, peak-cumulative)
+		open = nil
+	}
+
+	s.Sharpe = sharpe(returns)
+
+	return s
+}
+
+// StatsByRobot считает статистику каждого робота по сделкам, отсортированным по роботу и времени.
+func StatsByRobot(robots []Robot, deals []Deal, since time.Time) []Stats {
+	byRobot := make(map[int][]Deal, len(robots))
+	for _, d := range deals {
+		byRobot[d.RobotID] = append(byRobot[d.RobotID], d)
+	}
+
+	stats := make([]Stats, 0, len(robots))
+	for i := range robots {
+		stats = append(stats, ComputeStats(&robots[i], byRobot[robots[i].RobotID], since))
+	}
+
+	return stats
+}
+
+// lotsOf возвращает размер позиции, у старых роботов и сделок он не задан и равен одному лоту.
+func lotsOf(lots int) int {
+	if lots < 1 {
+		return 1
+	}
+
+	return lots
+}
+
+func sharpe(returns []float64) float64 {
+	if len(returns) < 2 { //nolint:gomnd
+		return 0
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	if std == 0 {
+		return 0
+	}
+
+	return mean / std
+}
+
+// Leaderboard сортирует статистику роботов по метрике, лучшие в начале, и оставляет limit первых.
+// Для просадки лучше меньшее значение, для остальных метрик — большее.
+func Leaderboard(stats []Stats, metric string, limit int) ([]Stats, error) {
+	var less func(a, b *Stats) bool
+
+	switch metric {
+	case MetricYield, "":
+		less = func(a, b *Stats) bool { return a.Yield > b.Yield }
+	case MetricSharpe:
+		less = func(a, b *Stats) bool { return a.Sharpe > b.Sharpe }
+	case MetricDeals:
+		less = func(a, b *Stats) bool { return a.Deals > b.Deals }
+	case MetricDrawdown:
+		less = func(a, b *Stats) bool { return a.Drawdown < b.Drawdown }
+	case MetricFollowers:
+		less = func(a, b *Stats) bool { return a.Followers > b.Followers }
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMetric, metric)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool { return less(&stats[i], &stats[j]) })
+
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+
+	return stats, nil
+}
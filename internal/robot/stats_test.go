@@ -0,0 +1,99 @@
+package robot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeStats(t *testing.T) {
+	now := time.Date(2020, 5, 20, 12, 0, 0, 0, time.UTC)
+	rob := &Robot{RobotID: 1, OwnerUserID: 2, Ticker: "AAPL", Followers: 3}
+
+	deal := func(side string, price float64, lots int, ago time.Duration) Deal {
+		return Deal{RobotID: 1, Side: side, Price: price, Lots: lots, CreatedAt: now.Add(-ago)}
+	}
+
+	deals := []Deal{
+		deal(SideBuy, 100, 1, 72*time.Hour),
+		deal(SideSell, 110, 1, 71*time.Hour),
+		deal(SideBuy, 100, 2, 3*time.Hour),
+		deal(SideSell, 90, 2, 2*time.Hour),
+		deal(SideBuy, 90, 1, time.Hour),
+		deal(SideSell, 120, 1, 30*time.Minute),
+		deal(SideBuy, 120, 1, 10*time.Minute),
+	}
+
+	type testCase struct {
+		Name     string
+		Since    time.Time
+		Yield    float64
+		Deals    int
+		Drawdown float64
+	}
+
+	testCases := []testCase{
+		{Name: "All", Since: time.Time{}, Yield: 20, Deals: 3, Drawdown: 20},
+		{Name: "Day", Since: now.AddDate(0, 0, -1), Yield: 10, Deals: 2, Drawdown: 20},
+		{Name: "Future", Since: now.Add(time.Hour), Yield: 0, Deals: 0, Drawdown: 0},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			s := ComputeStats(rob, deals, tc.Since)
+			assert.Equal(t, 1, s.RobotID)
+			assert.Equal(t, 3, s.Followers)
+			assert.InDelta(t, tc.Yield, s.Yield, 1e-9)
+			assert.Equal(t, tc.Deals, s.Deals)
+			assert.InDelta(t, tc.Drawdown, s.Drawdown, 1e-9)
+		})
+	}
+
+	assert.NotZero(t, ComputeStats(rob, deals, time.Time{}).Sharpe)
+}
+
+func TestLeaderboard(t *testing.T) {
+	stats := []Stats{
+		{RobotID: 1, Yield: 10, Deals: 5, Drawdown: 3, Followers: 0},
+		{RobotID: 2, Yield: 30, Deals: 1, Drawdown: 7, Followers: 2},
+		{RobotID: 3, Yield: 20, Deals: 3, Drawdown: 1, Followers: 1},
+	}
+
+	type testCase struct {
+		Name   string
+		Metric string
+		Limit  int
+		IDs    []int
+	}
+
+	testCases := []testCase{
+		{Name: "Default", Metric: "", IDs: []int{2, 3, 1}},
+		{Name: "Deals", Metric: MetricDeals, IDs: []int{1, 3, 2}},
+		{Name: "Drawdown", Metric: MetricDrawdown, IDs: []int{3, 1, 2}},
+		{Name: "Followers with limit", Metric: MetricFollowers, Limit: 2, IDs: []int{2, 3}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			board, err := Leaderboard(append([]Stats(nil), stats...), tc.Metric, tc.Limit)
+			assert.NoError(t, err)
+
+			ids := make([]int, 0, len(board))
+			for _, s := range board {
+				ids = append(ids, s.RobotID)
+			}
+
+			assert.Equal(t, tc.IDs, ids)
+		})
+	}
+
+	_, err := Leaderboard(stats, "luck", 0)
+	assert.True(t, errors.Is(err, ErrUnknownMetric))
+
+	_, err = PeriodStart("decade", time.Now())
+	assert.True(t, errors.Is(err, ErrUnknownPeriod))
+}
@@ -0,0 +1,233 @@
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Status состояние жизненного цикла робота.
+type Status string
+
+const (
+	// StatusDraft робот создан, но не запускался и не имеет расписания.
+	StatusDraft Status = "draft"
+	// StatusScheduled робот ждет начала планового окна или расписания.
+	StatusScheduled Status = "scheduled"
+	// StatusActive робот торгует и ждет покупки.
+	StatusActive Status = "active"
+	// StatusHolding робот торгует и держит открытую позицию.
+	StatusHolding Status = "holding"
+	// StatusPaused робот остановлен пользователем и может быть запущен снова.
+	StatusPaused Status = "paused"
+	// StatusStopped робот завершил работу.
+	StatusStopped Status = "stopped"
+	// StatusDeleted робот удален.
+	StatusDeleted Status = "deleted"
+)
+
+// Action действие, переводящее робота из одного состояния в другое.
+type Action string
+
+const (
+	ActionSchedule   Action = "schedule"
+	ActionActivate   Action = "activate"
+	ActionDeactivate Action = "deactivate"
+	ActionSuspend    Action = "suspend"
+	ActionStop       Action = "stop"
+	ActionBuy        Action = "buy"
+	ActionSell       Action = "sell"
+	ActionDelete     Action = "delete"
+	ActionRestore    Action = "restore"
+)
+
+var (
+	ErrTransition    = errors.New("transition is not allowed")
+	ErrUnknownAction = errors.New("unknown action")
+)
+
+// transition описывает допустимые исходные состояния действия, целевое состояние и дополнительное условие.
+type transition struct {
+	from  []Status
+	to    func(r *Robot) Status
+	guard func(r *Robot, now time.Time) error
+}
+
+// transitions таблица переходов робота.
+var transitions = map[Action]transition{ //nolint:gochecknoglobals
+	ActionSchedule: {
+		from:  []Status{StatusDraft},
+		to:    constStatus(StatusScheduled),
+		guard: hasSchedule,
+	},
+	ActionActivate: {
+		from:  []Status{StatusDraft, StatusScheduled, StatusPaused, StatusStopped},
+		to:    tradingStatus,
+		guard: planNotOver,
+	},
+	ActionDeactivate: {
+		from: []Status{StatusActive, StatusHolding},
+		to:   constStatus(StatusPaused),
+	},
+	ActionSuspend: {
+		from:  []Status{StatusActive, StatusHolding},
+		to:    constStatus(StatusScheduled),
+		guard: hasRecurringSchedule,
+	},
+	ActionStop: {
+		from: []Status{StatusScheduled, StatusActive, StatusHolding, StatusPaused},
+		to:   constStatus(StatusStopped),
+	},
+	ActionBuy: {
+		from: []Status{StatusActive},
+		to:   constStatus(StatusHolding),
+	},
+	ActionSell: {
+		from: []Status{StatusHolding},
+		to:   constStatus(StatusActive),
+	},
+	ActionDelete: {
+		from: []Status{StatusDraft, StatusScheduled, StatusPaused, StatusStopped},
+		to:   constStatus(StatusDeleted),
+	},
+	ActionRestore: {
+		from: []Status{StatusDeleted},
+		to:   restoredStatus,
+	},
+}
+
+// userActions действия, которые пользователь выполняет через API.
+// Торгующего робота API удаляет после остановки, поэтому удаление доступно и там, где доступна остановка.
+var userActions = []Action{ActionActivate, ActionDeactivate, ActionStop, ActionDelete} //nolint:gochecknoglobals
+
+func constStatus(s Status) func(r *Robot) Status {
+	return func(*Robot) Status { return s }
+}
+
+// tradingStatus возвращает holding, если у робота осталась открытая позиция.
+func tradingStatus(r *Robot) Status {
+	if !r.IsBuying {
+		return StatusHolding
+	}
+
+	return StatusActive
+}
+
+// restoredStatus возвращает состояние восстановленного робота: запускавшийся робот остановлен, остальные — черновики.
+func restoredStatus(r *Robot) Status {
+	if r.ActivatedAt.Valid {
+		return StatusStopped
+	}
+
+	return StatusDraft
+}
+
+func hasSchedule(r *Robot, _ time.Time) error {
+	if (r.PlanStart.Valid && r.PlanEnd.Valid) || r.Schedule != "" {
+		return nil
+	}
+
+	return errors.New("robot has no plan or schedule")
+}
+
+func hasRecurringSchedule(r *Robot, _ time.Time) error {
+	if r.Schedule != "" {
+		return nil
+	}
+
+	return errors.New("robot has no recurring schedule")
+}
+
+func planNotOver(r *Robot, now time.Time) error {
+	if r.PlanEnd.Valid && !now.Before(r.PlanEnd.Time) {
+		return errors.New("plan_end has passed")
+	}
+
+	return nil
+}
+
+// IsTrading сообщает, что робот в состоянии торговли.
+func (s Status) IsTrading() bool {
+	return s == StatusActive || s == StatusHolding
+}
+
+// InitialStatus возвращает состояние нового робота.
+func (r *Robot) InitialStatus() Status {
+	switch {
+	case r.IsActive:
+		return tradingStatus(r)
+	case (r.PlanStart.Valid && r.PlanEnd.Valid) || r.Schedule != "":
+		return StatusScheduled
+	default:
+		return StatusDraft
+	}
+}
+
+// Can проверяет, можно ли выполнить действие над роботом в момент now.
+func (r *Robot) Can(a Action, now time.Time) error {
+	t, ok := transitions[a]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownAction, a)
+	}
+
+	allowed := false
+
+	for _, from := range t.from {
+		if r.Status == from {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		return fmt.Errorf("%w: can't %s robot in status %q", ErrTransition, a, r.Status)
+	}
+
+	if t.guard != nil {
+		if err := t.guard(r, now); err != nil {
+			return fmt.Errorf("%w: can't %s robot: %s", ErrTransition, a, err)
+		}
+	}
+
+	return nil
+}
+
+// Apply выполняет действие над роботом и возвращает его прежнее состояние.
+func (r *Robot) Apply(a Action, now time.Time) (Status, error) {
+	if err := r.Can(a, now); err != nil {
+		return r.Status, err
+	}
+
+	from := r.Status
+	r.Status = transitions[a].to(r)
+	r.IsActive = r.Status.IsTrading()
+
+	switch {
+	case r.IsActive && !from.IsTrading():
+		r.ActivatedAt = NullTime{Time: now, Valid: true}
+	case !r.IsActive && from.IsTrading():
+		r.DeactivatedAt = NullTime{Time: now, Valid: true}
+	}
+
+	switch {
+	case r.Status == StatusDeleted:
+		r.DeletedAt = NullTime{Time: now, Valid: true}
+	case from == StatusDeleted:
+		r.DeletedAt = NullTime{}
+	}
+
+	return from, nil
+}
+
+// AllowedActions возвращает действия, которые пользователь может выполнить над роботом сейчас.
+func (r *Robot) AllowedActions(now time.Time) []Action {
+	actions := make([]Action, 0, len(userActions))
+
+	for _, a := range userActions {
+		if r.Can(a, now) == nil || (a == ActionDelete && r.Status.IsTrading()) {
+			actions = append(actions, a)
+		}
+	}
+
+	return actions
+}
@@ -0,0 +1,221 @@
+package robot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Robot    Robot
+		Action   Action
+		Expected Status
+		HasError bool
+	}
+
+	assert := assert.New(t)
+	now := time.Date(2020, 6, 10, 12, 0, 0, 0, time.UTC)
+	ended := NullTime{Time: now.Add(-time.Hour), Valid: true}
+
+	testCases := []testCase{
+		{Name: "Activate draft", Robot: Robot{Status: StatusDraft, IsBuying: true}, Action: ActionActivate, Expected: StatusActive},
+		{Name: "Resume with open position", Robot: Robot{Status: StatusPaused}, Action: ActionActivate, Expected: StatusHolding},
+		{Name: "Activate after plan_end", Robot: Robot{Status: StatusStopped, PlanStart: ended, PlanEnd: ended},
+			Action: ActionActivate, HasError: true},
+		{Name: "Pause holding", Robot: Robot{Status: StatusHolding}, Action: ActionDeactivate, Expected: StatusPaused},
+		{Name: "Pause draft", Robot: Robot{Status: StatusDraft}, Action: ActionDeactivate, HasError: true},
+		{Name: "Suspend without schedule", Robot: Robot{Status: StatusActive}, Action: ActionSuspend, HasError: true},
+		{Name: "Schedule draft without plan", Robot: Robot{Status: StatusDraft}, Action: ActionSchedule, HasError: true},
+		{Name: "Buy", Robot: Robot{Status: StatusActive}, Action: ActionBuy, Expected: StatusHolding},
+		{Name: "Delete deleted", Robot: Robot{Status: StatusDeleted}, Action: ActionDelete, HasError: true},
+		{Name: "Delete active", Robot: Robot{Status: StatusActive}, Action: ActionDelete, HasError: true},
+		{Name: "Restore started", Robot: Robot{Status: StatusDeleted, ActivatedAt: ended}, Action: ActionRestore,
+			Expected: StatusStopped},
+		{Name: "Restore draft", Robot: Robot{Status: StatusDeleted}, Action: ActionRestore, Expected: StatusDraft},
+		{Name: "Restore stopped", Robot: Robot{Status: StatusStopped}, Action: ActionRestore, HasError: true},
+		{Name: "Unknown action", Robot: Robot{Status: StatusDraft}, Action: "fly", HasError: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			from := tc.Robot.Status
+
+			_, err := tc.Robot.Apply(tc.Action, now)
+			if tc.HasError {
+				assert.Error(err)
+				assert.Equal(from, tc.Robot.Status, "status must not change on error")
+
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.Expected, tc.Robot.Status)
+			assert.Equal(tc.Expected.IsTrading(), tc.Robot.IsActive)
+		})
+	}
+}
+
+func TestAllowedActions(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	assert.Equal([]Action{ActionActivate, ActionDelete}, (&Robot{Status: StatusDraft}).AllowedActions(now))
+	assert.Equal([]Action{ActionDeactivate, ActionStop, ActionDelete}, (&Robot{Status: StatusHolding}).AllowedActions(now))
+	assert.Empty((&Robot{Status: StatusDeleted}).AllowedActions(now))
+
+	err := (&Robot{Status: StatusDraft}).Can(ActionStop, now)
+	assert.True(errors.Is(err, ErrTransition))
+}
+
+func TestTrash(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+
+	first := &Robot{OwnerUserID: 1, Ticker: "AAPL", Lots: 1}
+	second := &Robot{OwnerUserID: 1, Ticker: "AAPL", Lots: 1}
+	active := &Robot{OwnerUserID: 1, Ticker: "AAPL", Lots: 1, IsActive: true}
+
+	for _, r := range []*Robot{first, second, active} {
+		assert.NoError(storage.Create(r))
+	}
+
+	assert.NoError(storage.SoftDelete(first.RobotID, 0))
+	assert.NoError(storage.SoftDelete(second.RobotID, 0))
+	assert.True(errors.Is(storage.SoftDelete(active.RobotID, 0), ErrTransition), "trading robot must be stopped first")
+
+	deleted, err := storage.FindDeleted(1)
+	assert.NoError(err)
+
+	if assert.Len(deleted, 2) {
+		assert.Equal(second.RobotID, deleted[0].RobotID, "recently deleted robots go first")
+	}
+
+	assert.NoError(storage.Restore(first.RobotID))
+
+	rob, err := storage.FindByID(first.RobotID)
+	assert.NoError(err)
+	assert.Equal(StatusDraft, rob.Status)
+	assert.False(rob.DeletedAt.Valid)
+
+	assert.Equal(ErrNotFound, storage.Purge(first.RobotID), "only deleted robots can be purged")
+	assert.NoError(storage.Purge(second.RobotID))
+
+	_, err = storage.FindDeletedByID(second.RobotID)
+	assert.Equal(ErrNotFound, err)
+
+	transitions, err := storage.FindTransitions(second.RobotID)
+	assert.NoError(err)
+	assert.Empty(transitions)
+
+	assert.NoError(storage.Transition(first.RobotID, ActionActivate, SourceUser, 0))
+	assert.NoError(storage.Trade(active, Deal{Side: SideBuy, Price: 10}))
+	assert.NoError(storage.Trade(first, Deal{Side: SideBuy, Price: 10}))
+	assert.NoError(storage.Transition(first.RobotID, ActionStop, SourceUser, 0))
+	assert.NoError(storage.SoftDelete(first.RobotID, 0))
+
+	transitions, err = storage.FindTransitions(first.RobotID)
+	assert.NoError(err)
+
+	ids := make(map[int]bool)
+	for _, tr := range transitions {
+		assert.False(ids[tr.ID], "transition id %d is reused after purge", tr.ID)
+		ids[tr.ID] = true
+	}
+
+	assert.NoError(storage.Purge(first.RobotID))
+	assert.NoError(storage.Trade(active, Deal{Side: SideSell, Price: 12}))
+
+	deals, err := storage.FindDeals(active.RobotID)
+	assert.NoError(err)
+
+	if assert.Len(deals, 2) {
+		assert.Equal(3, deals[1].ID, "deal ids are not reused after purge")
+	}
+}
+
+func TestStaleRevision(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+
+	parent := &Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(storage.Create(parent))
+
+	read, err := storage.FindByID(parent.RobotID)
+	assert.NoError(err)
+
+	assert.NoError(storage.Transition(parent.RobotID, ActionActivate, SourceUser, read.Revision))
+	assert.True(errors.Is(storage.Transition(parent.RobotID, ActionDeactivate, SourceUser, read.Revision), ErrConflict))
+
+	_, err = storage.FavouriteRobot(parent.RobotID, 2, Follow{Mirror: true}, read.Revision)
+	assert.True(errors.Is(err, ErrConflict), "error: %v", err)
+
+	active, err := storage.FindByID(parent.RobotID)
+	assert.NoError(err)
+	assert.Equal(StatusActive, active.Status)
+	assert.Equal(0, active.Followers, "follower of a stale parent isn't created")
+
+	follower, err := storage.FavouriteRobot(parent.RobotID, 2, Follow{Mirror: true}, active.Revision)
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.NoError(storage.Unfollow(follower.RobotID, follower.Revision))
+	assert.True(errors.Is(storage.Unfollow(follower.RobotID, follower.Revision), ErrConflict))
+	assert.True(errors.Is(storage.SoftDelete(follower.RobotID, follower.Revision), ErrConflict))
+
+	unfollowed, err := storage.FindByID(follower.RobotID)
+	if assert.NoError(err) {
+		assert.False(unfollowed.IsMirror)
+		assert.NoError(storage.SoftDelete(follower.RobotID, unfollowed.Revision))
+	}
+}
+
+func TestTradeAfterStop(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+
+	rob := &Robot{OwnerUserID: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}
+	assert.NoError(storage.Create(rob))
+	assert.NoError(storage.Transition(rob.RobotID, ActionActivate, SourceUser, 0))
+
+	trader, err := storage.FindByID(rob.RobotID)
+	assert.NoError(err)
+
+	trader.Buy(10)
+	assert.NoError(storage.Trade(trader, Deal{Side: SideBuy, Price: 10}))
+	assert.NoError(storage.Transition(rob.RobotID, ActionStop, SourceUser, 0))
+
+	stopped, err := storage.FindByID(rob.RobotID)
+	assert.NoError(err)
+
+	trader.Sell(20)
+	assert.True(errors.Is(storage.Trade(trader, Deal{Side: SideSell, Price: 20}), ErrNotTrading))
+
+	deals, err := storage.FindDeals(rob.RobotID)
+	assert.NoError(err)
+	assert.Len(deals, 1, "the trader's copy doesn't trade after the robot is stopped")
+
+	current, err := storage.FindByID(rob.RobotID)
+	assert.NoError(err)
+	assert.Equal(stopped, current)
+
+	stopped.Sell(19)
+	assert.NoError(storage.ClosePosition(stopped, Deal{Side: SideSell, Price: 19}))
+	assert.True(errors.Is(storage.ClosePosition(stopped, Deal{Side: SideSell, Price: 19}), ErrConflict),
+		"the position is closed once")
+
+	closed, err := storage.FindByID(rob.RobotID)
+	assert.NoError(err)
+	assert.Equal(StatusStopped, closed.Status)
+	assert.True(closed.IsBuying)
+
+	deals, err = storage.FindDeals(rob.RobotID)
+	assert.NoError(err)
+	assert.Len(deals, 2)
+}
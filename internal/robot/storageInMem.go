@@ -0,0 +1,821 @@
+package robot
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ Storage = &StorageInMemory{}
+
+// StorageInMemory структура хранилища роботов в памяти.
+type StorageInMemory struct {
+	storage     map[int]Robot
+	transitions []Transition
+	versions    []Version
+	deals       []Deal
+	shares      map[int]map[int]bool
+	templates   map[int]Template
+	nextID      int
+	nextTmplID  int
+	// Сделки и переходы удаляются вместе с роботом, поэтому их номера не выводятся из длины срезов.
+	nextDealID       int
+	nextTransitionID int
+	mutex            sync.RWMutex
+}
+
+// CreateStorageInMemory возвращает указатель на хранилище роботов in-memory.
+func CreateStorageInMemory() *StorageInMemory {
+	return &StorageInMemory{
+		storage:   make(map[int]Robot),
+		shares:    make(map[int]map[int]bool),
+		templates: make(map[int]Template),
+	}
+}
+
+// Create добавляет робота в хранилище.
+func (s *StorageInMemory) Create(r *Robot) error {
+	if err := r.SetVisibility(r.Visibility); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.create(r)
+
+	return nil
+}
+
+// CreateBatch создает роботов пакетом и возвращает ошибку для каждого из них.
+// В атомарном режиме роботы создаются, только если ошибок нет ни у одного.
+func (s *StorageInMemory) CreateBatch(robots []*Robot, atomic bool) ([]error, error) {
+	errs := make([]error, len(robots))
+
+	for i, r := range robots {
+		errs[i] = r.SetVisibility(r.Visibility)
+	}
+
+	if atomic && AbortBatch(errs) {
+		return errs, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, r := range robots {
+		if errs[i] == nil {
+			s.create(r)
+		}
+	}
+
+	return errs, nil
+}
+
+// create сохраняет нового робота; вызывается под мьютексом.
+func (s *StorageInMemory) create(r *Robot) {
+	s.nextID++
+	r.RobotID = s.nextID
+	r.IsBuying = true
+	r.CreatedAt = NullTime{Time: time.Now(), Valid: true}
+
+	r.Version = 1
+
+	if r.Lots < 1 {
+		r.Lots = 1
+	}
+
+	if r.Status == "" {
+		r.Status = r.InitialStatus()
+	}
+
+	r.Revision = 1
+	s.storage[r.RobotID] = *r
+	s.versions = append(s.versions, r.CurrentVersion(r.CreatedAt.Time))
+}
+
+// FindByID находит неудаленного робота по его ID.
+func (s *StorageInMemory) FindByID(id int) (*Robot, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	r, ok := s.storage[id]
+	if !ok || r.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	r.Followers = s.followers(id)
+
+	return &r, nil
+}
+
+// FindActivatedByUserID находит неудаленных роботов пользователя.
+func (s *StorageInMemory) FindActivatedByUserID(userID int) ([]Robot, error) {
+	return s.filter(func(r *Robot) bool { return r.OwnerUserID == userID }), nil
+}
+
+// FindActivatedByTicker находит неудаленных роботов по тикеру.
+func (s *StorageInMemory) FindActivatedByTicker(ticker string) ([]Robot, error) {
+	return s.filter(func(r *Robot) bool { return r.Ticker == ticker }), nil
+}
+
+// FindActivated возвращает список всех неудаленных роботов.
+func (s *StorageInMemory) FindActivated() ([]Robot, error) {
+	return s.filter(func(r *Robot) bool { return true }), nil
+}
+
+// FindActivatedByTickerUserID возвращает неудаленных роботов пользователя по тикеру.
+func (s *StorageInMemory) FindActivatedByTickerUserID(ticker string, userID int) ([]Robot, error) {
+	return s.filter(func(r *Robot) bool { return r.Ticker == ticker && r.OwnerUserID == userID }), nil
+}
+
+// Find возвращает страницу неудаленных роботов, удовлетворяющих выборке.
+func (s *StorageInMemory) Find(q *Query) (*Page, error) {
+	robots := s.filter(func(r *Robot) bool {
+		return q.Match(r) && (q.ViewerID == nil || r.VisibleTo(*q.ViewerID, "", s.shares[r.RobotID][*q.ViewerID]))
+	})
+	q.SortRobots(robots)
+
+	page := make([]Robot, 0, q.Limit+1)
+
+	for i := range robots {
+		if len(page) > q.Limit {
+			break
+		}
+
+		if q.IsAfterCursor(&robots[i]) {
+			page = append(page, robots[i])
+		}
+	}
+
+	return q.NewPage(page), nil
+}
+
+// FindVisible находит неудаленного робота, если пользователь userID его видит.
+func (s *StorageInMemory) FindVisible(robotID, userID int, shareToken string) (*Robot, error) {
+	r, err := s.FindByID(robotID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	shared := s.shares[robotID][userID]
+	s.mutex.RUnlock()
+
+	if !r.VisibleTo(userID, shareToken, shared) {
+		return nil, ErrNotFound
+	}
+
+	return r, nil
+}
+
+// FindVisibleByIDs возвращает неудаленных роботов из robotIDs, которых пользователь userID видит без ссылки.
+func (s *StorageInMemory) FindVisibleByIDs(robotIDs []int, userID int) ([]Robot, error) {
+	ids := make(map[int]bool, len(robotIDs))
+	for _, id := range robotIDs {
+		ids[id] = true
+	}
+
+	return s.filter(func(r *Robot) bool {
+		return ids[r.RobotID] && r.VisibleTo(userID, "", s.shares[r.RobotID][userID])
+	}), nil
+}
+
+// Share открывает робота пользователю userID.
+func (s *StorageInMemory) Share(robotID, userID int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r, ok := s.storage[robotID]; !ok || r.DeletedAt.Valid {
+		return ErrNotFound
+	}
+
+	if s.shares[robotID] == nil {
+		s.shares[robotID] = make(map[int]bool)
+	}
+
+	s.shares[robotID][userID] = true
+
+	return nil
+}
+
+// Unshare закрывает робота от пользователя userID.
+func (s *StorageInMemory) Unshare(robotID, userID int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.shares[robotID][userID] {
+		return ErrNotFound
+	}
+
+	delete(s.shares[robotID], userID)
+
+	return nil
+}
+
+// FindShares возвращает отсортированные ID пользователей, которым открыт робот.
+func (s *StorageInMemory) FindShares(robotID int) ([]int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	users := make([]int, 0, len(s.shares[robotID]))
+	for userID := range s.shares[robotID] {
+		users = append(users, userID)
+	}
+
+	sort.Ints(users)
+
+	return users, nil
+}
+
+// FavouriteRobot добавляет копию робота в избранное пользователя.
+func (s *StorageInMemory) FavouriteRobot(parentRobotID, userID int, follow Follow, revision int) (*Robot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	parent, ok := s.storage[parentRobotID]
+	if !ok || parent.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	if err := parent.CheckRevision(revision); err != nil {
+		return nil, err
+	}
+
+	r, err := NewFollower(&parent, userID, follow)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.SetVisibility(r.Visibility); err != nil {
+		return nil, err
+	}
+
+	s.create(r)
+
+	return r, nil
+}
+
+// FindFollowers находит подписчиков, повторяющих робота.
+func (s *StorageInMemory) FindFollowers(parentRobotID int) ([]Robot, error) {
+	return s.filter(func(r *Robot) bool { return r.IsMirror && r.ParentRobotID == parentRobotID }), nil
+}
+
+// Unfollow отключает повторение родителя, после чего робот становится независимой копией.
+func (s *StorageInMemory) Unfollow(robotID, revision int) error {
+	return s.update(robotID, revision, func(r *Robot) {
+		r.IsMirror = false
+	})
+}
+
+// ActivateRobot активирует робота по запросу пользователя.
+func (s *StorageInMemory) ActivateRobot(robotID int) error {
+	return s.Transition(robotID, ActionActivate, SourceUser, 0)
+}
+
+// DeactivateRobot приостанавливает робота по запросу пользователя.
+func (s *StorageInMemory) DeactivateRobot(robotID int) error {
+	return s.Transition(robotID, ActionDeactivate, SourceUser, 0)
+}
+
+// Transition выполняет действие над роботом по таблице переходов и записывает переход в историю.
+func (s *StorageInMemory) Transition(robotID int, action Action, source string, revision int) error {
+	return s.transition(robotID, action, source, false, revision)
+}
+
+// transition выполняет действие над неудаленным роботом или, если deleted, над удаленным.
+func (s *StorageInMemory) transition(robotID int, action Action, source string, deleted bool, revision int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r, ok := s.storage[robotID]
+	if !ok || r.DeletedAt.Valid != deleted {
+		return ErrNotFound
+	}
+
+	if err := r.CheckRevision(revision); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	from, err := r.Apply(action, now)
+	if err != nil {
+		return err
+	}
+
+	r.Revision++
+	s.storage[robotID] = r
+	s.nextTransitionID++
+	s.transitions = append(s.transitions, Transition{
+		ID:         s.nextTransitionID,
+		RobotID:    robotID,
+		Action:     action,
+		FromStatus: from,
+		ToStatus:   r.Status,
+		Source:     source,
+		CreatedAt:  now,
+	})
+
+	return nil
+}
+
+// TransitionBatch выполняет действие над роботами пакетом и возвращает ошибку для каждого из них.
+// В атомарном режиме переходы применяются, только если ошибок нет ни у одного робота.
+func (s *StorageInMemory) TransitionBatch(robotIDs []int, action Action, source string, atomic bool) ([]error, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	errs := make([]error, len(robotIDs))
+	changed := make(map[int]Robot, len(robotIDs))
+	transitions := make([]Transition, 0, len(robotIDs))
+
+	for i, id := range robotIDs {
+		r, ok := changed[id]
+		if !ok {
+			r, ok = s.storage[id]
+		}
+
+		if !ok || r.DeletedAt.Valid {
+			errs[i] = ErrNotFound
+			continue
+		}
+
+		from, err := r.Apply(action, now)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		changed[id] = r
+		transitions = append(transitions, Transition{
+			RobotID:    id,
+			Action:     action,
+			FromStatus: from,
+			ToStatus:   r.Status,
+			Source:     source,
+			CreatedAt:  now,
+		})
+	}
+
+	if atomic && AbortBatch(errs) {
+		return errs, nil
+	}
+
+	for id, r := range changed {
+		r.Revision++
+		s.storage[id] = r
+	}
+
+	for _, t := range transitions {
+		s.nextTransitionID++
+		t.ID = s.nextTransitionID
+		s.transitions = append(s.transitions, t)
+	}
+
+	return errs, nil
+}
+
+// FindTransitions возвращает историю переходов робота.
+func (s *StorageInMemory) FindTransitions(robotID int) ([]Transition, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	transitions := make([]Transition, 0)
+
+	for _, t := range s.transitions {
+		if t.RobotID == robotID {
+			transitions = append(transitions, t)
+		}
+	}
+
+	return transitions, nil
+}
+
+// Edit меняет параметры робота и сохраняет новую версию параметров.
+func (s *StorageInMemory) Edit(robotID int, changes *Robot) (*Robot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r, ok := s.storage[robotID]
+	if !ok || r.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	now := time.Now()
+	from := r.Status
+
+	if err := r.Edit(changes, now); err != nil {
+		return nil, err
+	}
+
+	s.saveEdit(&r, from, now)
+
+	for id, f := range s.storage {
+		if f.IsMirror && f.ParentRobotID == robotID && !f.DeletedAt.Valid {
+			followerFrom := f.Status
+			if f.SyncWith(&r, now) {
+				s.saveEdit(&f, followerFrom, now)
+				s.storage[id] = f
+			}
+		}
+	}
+
+	r.Followers = s.followers(robotID)
+
+	return &r, nil
+}
+
+// saveEdit сохраняет измененного робота, его версию и переход, если состояние изменилось.
+func (s *StorageInMemory) saveEdit(r *Robot, from Status, now time.Time) {
+	r.Revision++
+	s.storage[r.RobotID] = *r
+	s.versions = append(s.versions, r.CurrentVersion(now))
+
+	if r.Status != from {
+		s.nextTransitionID++
+		s.transitions = append(s.transitions, Transition{
+			ID:         s.nextTransitionID,
+			RobotID:    r.RobotID,
+			Action:     ActionSchedule,
+			FromStatus: from,
+			ToStatus:   r.Status,
+			Source:     SourceUser,
+			CreatedAt:  now,
+		})
+	}
+}
+
+// FindVersions возвращает историю параметров робота.
+func (s *StorageInMemory) FindVersions(robotID int) ([]Version, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	versions := make([]Version, 0)
+
+	for _, v := range s.versions {
+		if v.RobotID == robotID {
+			versions = append(versions, v)
+		}
+	}
+
+	return versions, nil
+}
+
+// FindToTrading находит торгующих роботов, подписчики торгуют вслед за родителем и не попадают в список.
+func (s *StorageInMemory) FindToTrading() ([]Robot, error) {
+	return s.filter(func(r *Robot) bool { return r.Status.IsTrading() && !r.IsMirror }), nil
+}
+
+// FindScheduled находит ожидающих или торгующих роботов с плановым окном или повторяющимся расписанием.
+func (s *StorageInMemory) FindScheduled() ([]Robot, error) {
+	return s.filter(func(r *Robot) bool {
+		return (r.Status == StatusScheduled || r.Status.IsTrading()) &&
+			((r.PlanStart.Valid && r.PlanEnd.Valid) || r.Schedule != "")
+	}), nil
+}
+
+// Trade сохраняет результат сделки робота и саму сделку с версией параметров робота.
+func (s *StorageInMemory) Trade(rob *Robot, deal Deal) error {
+	return s.trade(rob, deal, func(r *Robot) error {
+		if !r.Status.IsTrading() {
+			return ErrNotTrading
+		}
+
+		return nil
+	})
+}
+
+// ClosePosition сохраняет продажу, которой закрывается позиция остановленного робота.
+func (s *StorageInMemory) ClosePosition(rob *Robot, deal Deal) error {
+	return s.trade(rob, deal, func(r *Robot) error {
+		if r.Status.IsTrading() || r.IsBuying {
+			return ErrConflict
+		}
+
+		return nil
+	})
+}
+
+// trade сохраняет сделку, если check разрешает ее для текущего состояния робота.
+func (s *StorageInMemory) trade(rob *Robot, deal Deal, check func(r *Robot) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r, ok := s.storage[rob.RobotID]
+	if !ok || r.DeletedAt.Valid {
+		return ErrNotFound
+	}
+
+	if err := check(&r); err != nil {
+		return err
+	}
+
+	r.IsBuying = rob.IsBuying
+	r.DealsCount = rob.DealsCount
+	r.FactYield = rob.FactYield
+	r.Revision++
+
+	if r.Status.IsTrading() {
+		r.Status = tradingStatus(&r)
+	}
+
+	s.storage[r.RobotID] = r
+
+	s.nextDealID++
+	deal.ID = s.nextDealID
+	deal.RobotID = rob.RobotID
+	deal.Version = rob.Version
+	s.deals = append(s.deals, deal)
+
+	s.syncClosedMirror(rob.RobotID, time.Now())
+
+	return nil
+}
+
+// syncClosedMirror переносит подписчику, который закрыл позицию, тикер и цены родителя, отложенные SyncWith.
+func (s *StorageInMemory) syncClosedMirror(robotID int, now time.Time) {
+	r, ok := s.storage[robotID]
+	if !ok || !r.IsMirror || !r.IsBuying || r.DeletedAt.Valid {
+		return
+	}
+
+	parent, ok := s.storage[r.ParentRobotID]
+	if !ok || parent.DeletedAt.Valid {
+		return
+	}
+
+	from := r.Status
+	if r.SyncWith(&parent, now) {
+		s.saveEdit(&r, from, now)
+	}
+}
+
+// FindDealsByRobotIDs возвращает сделки роботов, отсортированные по роботу и времени.
+func (s *StorageInMemory) FindDealsByRobotIDs(robotIDs []int) ([]Deal, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ids := make(map[int]bool, len(robotIDs))
+	for _, id := range robotIDs {
+		ids[id] = true
+	}
+
+	deals := make([]Deal, 0)
+
+	for _, d := range s.deals {
+		if ids[d.RobotID] {
+			deals = append(deals, d)
+		}
+	}
+
+	sort.SliceStable(deals, func(i, j int) bool { return deals[i].RobotID < deals[j].RobotID })
+
+	return deals, nil
+}
+
+// FindDeals возвращает сделки робота.
+func (s *StorageInMemory) FindDeals(robotID int) ([]Deal, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	deals := make([]Deal, 0)
+
+	for _, d := range s.deals {
+		if d.RobotID == robotID {
+			deals = append(deals, d)
+		}
+	}
+
+	return deals, nil
+}
+
+// SoftDelete переводит робота в состояние deleted.
+func (s *StorageInMemory) SoftDelete(id, revision int) error {
+	return s.Transition(id, ActionDelete, SourceUser, revision)
+}
+
+// FindDeleted возвращает удаленных роботов пользователя, недавно удаленные в начале.
+func (s *StorageInMemory) FindDeleted(userID int) ([]Robot, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	robots := make([]Robot, 0)
+
+	for _, r := range s.storage {
+		if r.DeletedAt.Valid && r.OwnerUserID == userID {
+			robots = append(robots, r)
+		}
+	}
+
+	sort.Slice(robots, func(i, j int) bool {
+		if !robots[i].DeletedAt.Time.Equal(robots[j].DeletedAt.Time) {
+			return robots[i].DeletedAt.Time.After(robots[j].DeletedAt.Time)
+		}
+
+		return robots[i].RobotID > robots[j].RobotID
+	})
+
+	return robots, nil
+}
+
+// FindDeletedByID находит удаленного робота по его ID.
+func (s *StorageInMemory) FindDeletedByID(robotID int) (*Robot, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	r, ok := s.storage[robotID]
+	if !ok || !r.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	return &r, nil
+}
+
+// Restore возвращает удаленного робота из корзины.
+func (s *StorageInMemory) Restore(robotID int) error {
+	return s.transition(robotID, ActionRestore, SourceUser, true, 0)
+}
+
+// Purge окончательно удаляет робота из корзины вместе с его историей, сделками и доступами.
+func (s *StorageInMemory) Purge(robotID int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r, ok := s.storage[robotID]
+	if !ok || !r.DeletedAt.Valid {
+		return ErrNotFound
+	}
+
+	s.purge(robotID)
+
+	return nil
+}
+
+// PurgeDeleted окончательно удаляет роботов, удаленных раньше before, и возвращает их число.
+func (s *StorageInMemory) PurgeDeleted(before time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	purged := 0
+
+	for id, r := range s.storage {
+		if r.DeletedAt.Valid && r.DeletedAt.Time.Before(before) {
+			s.purge(id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// purge удаляет робота и связанные с ним записи; вызывается под мьютексом.
+func (s *StorageInMemory) purge(robotID int) {
+	delete(s.storage, robotID)
+	delete(s.shares, robotID)
+
+	transitions := s.transitions[:0]
+
+	for _, t := range s.transitions {
+		if t.RobotID != robotID {
+			transitions = append(transitions, t)
+		}
+	}
+
+	s.transitions = transitions
+
+	versions := s.versions[:0]
+
+	for _, v := range s.versions {
+		if v.RobotID != robotID {
+			versions = append(versions, v)
+		}
+	}
+
+	s.versions = versions
+
+	deals := s.deals[:0]
+
+	for _, d := range s.deals {
+		if d.RobotID != robotID {
+			deals = append(deals, d)
+		}
+	}
+
+	s.deals = deals
+}
+
+// filter возвращает отсортированных по ID неудаленных роботов, удовлетворяющих условию.
+func (s *StorageInMemory) filter(match func(r *Robot) bool) []Robot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	robots := make([]Robot, 0)
+
+	for id := 1; id <= s.nextID; id++ {
+		r, ok := s.storage[id]
+		if ok && !r.DeletedAt.Valid && match(&r) {
+			r.Followers = s.followers(id)
+			robots = append(robots, r)
+		}
+	}
+
+	return robots
+}
+
+// followers считает неудаленных подписчиков, повторяющих робота; вызывается под мьютексом.
+func (s *StorageInMemory) followers(robotID int) int {
+	count := 0
+
+	for _, r := range s.storage {
+		if r.IsMirror && r.ParentRobotID == robotID && !r.DeletedAt.Valid {
+			count++
+		}
+	}
+
+	return count
+}
+
+// update применяет изменение к неудаленному роботу, если его ревизия равна revision или revision нулевая.
+func (s *StorageInMemory) update(id, revision int, change func(r *Robot)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r, ok := s.storage[id]
+	if !ok || r.DeletedAt.Valid {
+		return ErrNotFound
+	}
+
+	if err := r.CheckRevision(revision); err != nil {
+		return err
+	}
+
+	change(&r)
+	r.Revision++
+	s.storage[id] = r
+
+	return nil
+}
+
+// CreateTemplate сохраняет шаблон робота.
+func (s *StorageInMemory) CreateTemplate(t *Template) error {
+	if err := t.Normalize(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nextTmplID++
+	t.ID = s.nextTmplID
+	t.CreatedAt = time.Now()
+	s.templates[t.ID] = *t
+
+	return nil
+}
+
+// FindTemplates возвращает шаблоны пользователя в порядке создания.
+func (s *StorageInMemory) FindTemplates(userID int) ([]Template, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	templates := make([]Template, 0)
+
+	for _, t := range s.templates {
+		if t.OwnerUserID == userID {
+			templates = append(templates, t)
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+	return templates, nil
+}
+
+// FindTemplateByID находит шаблон по его ID.
+func (s *StorageInMemory) FindTemplateByID(id int) (*Template, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	t, ok := s.templates[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &t, nil
+}
+
+// DeleteTemplate удаляет шаблон, созданные из него роботы остаются.
+func (s *StorageInMemory) DeleteTemplate(id int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.templates[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.templates, id)
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package robot
+
+import (
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+// Template сохраненная конфигурация робота, из которой пользователь создает роботов на разных тикерах.
+type Template struct {
+	ID          int        `json:"id"`
+	OwnerUserID int        `json:"owner_user_id"`
+	Name        string     `json:"name"`
+	Ticker      string     `json:"ticker"`
+	BuyPrice    float64    `json:"buy_price"`
+	SellPrice   float64    `json:"sell_price"`
+	PlanYield   float64    `json:"plan_yield"`
+	AutoClose   bool       `json:"auto_close"`
+	Schedule    string     `json:"schedule"`
+	Lots        int        `json:"lots"`
+	Visibility  Visibility `json:"visibility"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Normalize заполняет незаданные поля шаблона значениями по умолчанию и проверяет его.
+func (t *Template) Normalize() error {
+	if t.Lots == 0 {
+		t.Lots = 1
+	}
+
+	if t.Visibility == "" {
+		t.Visibility = VisibilityPublic
+	}
+
+	var f validation.Fields
+
+	if t.Name == "" {
+		f.Add("name", "is required")
+	}
+
+	if t.Lots < 1 {
+		f.Add("lots", "must be positive")
+	}
+
+	validatePrices(&f, t.BuyPrice, t.SellPrice, t.PlanYield)
+
+	t.Visibility.check(&f)
+
+	return f.Err(ErrInvalidRobot)
+}
+
+// NewRobot возвращает робота владельца шаблона с параметрами шаблона.
+func (t *Template) NewRobot() Robot {
+	return Robot{
+		OwnerUserID: t.OwnerUserID,
+		TemplateID:  t.ID,
+		Ticker:      t.Ticker,
+		BuyPrice:    t.BuyPrice,
+		SellPrice:   t.SellPrice,
+		PlanYield:   t.PlanYield,
+		AutoClose:   t.AutoClose,
+		Schedule:    t.Schedule,
+		Lots:        t.Lots,
+		Visibility:  t.Visibility,
+	}
+}
+
+// Clone возвращает новый робот того же владельца с параметрами r. Источник копии хранится в ClonedFromID.
+func (r *Robot) Clone() Robot {
+	c := Robot{
+		OwnerUserID:  r.OwnerUserID,
+		ClonedFromID: r.RobotID,
+		Lots:         r.Lots,
+		Visibility:   r.Visibility,
+	}
+	c.setParams(r)
+
+	return c
+}
@@ -0,0 +1,54 @@
+package robot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTemplate(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Template Template
+		HasError bool
+	}
+
+	assert := assert.New(t)
+
+	testCases := []testCase{
+		{Name: "Defaults", Template: Template{Name: "breakout"}},
+		{Name: "Without name", Template: Template{Ticker: "AAPL"}, HasError: true},
+		{Name: "Negative price", Template: Template{Name: "breakout", BuyPrice: -1}, HasError: true},
+		{Name: "Negative lots", Template: Template{Name: "breakout", Lots: -1}, HasError: true},
+		{Name: "Unknown visibility", Template: Template{Name: "breakout", Visibility: "secret"}, HasError: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Template.Normalize()
+			if tc.HasError {
+				assert.True(errors.Is(err, ErrInvalidRobot))
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(1, tc.Template.Lots)
+			assert.Equal(VisibilityPublic, tc.Template.Visibility)
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	assert := assert.New(t)
+
+	src := Robot{RobotID: 7, OwnerUserID: 1, ParentRobotID: 3, IsFavourite: true, Ticker: "AAPL", BuyPrice: 10,
+		SellPrice: 12, Lots: 2, Status: StatusHolding, IsActive: true, DealsCount: 5, FactYield: 3,
+		Visibility: VisibilityLink, ShareToken: "token"}
+
+	clone := src.Clone()
+	assert.Equal(Robot{OwnerUserID: 1, ClonedFromID: 7, Ticker: "AAPL", BuyPrice: 10, SellPrice: 12, Lots: 2,
+		Visibility: VisibilityLink}, clone, "clone keeps parameters, but not state, lineage or share link")
+}
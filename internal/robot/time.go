@@ -15,8 +15,11 @@ const (
 type NullTime sql.NullTime
 
 func (t *NullTime) UnmarshalJSON(b []byte) error {
-	if b == nil {
+	if b == nil || string(b) == "null" || string(b) == `""` {
 		t.Valid = false
+		t.Time = time.Time{}
+
+		return nil
 	}
 
 	t.Valid = true
@@ -0,0 +1,168 @@
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+// Стороны сделки робота.
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+var (
+	ErrInvalidRobot = errors.New("invalid robot")
+	ErrPositionOpen = errors.New("robot holds a position")
+)
+
+// Version параметры робота, действовавшие начиная с CreatedAt.
+type Version struct {
+	RobotID   int       `json:"robot_id"`
+	Version   int       `json:"version"`
+	Ticker    string    `json:"ticker"`
+	BuyPrice  float64   `json:"buy_price"`
+	SellPrice float64   `json:"sell_price"`
+	PlanStart NullTime  `json:"plan_start"`
+	PlanEnd   NullTime  `json:"plan_end"`
+	PlanYield float64   `json:"plan_yield"`
+	AutoClose bool      `json:"auto_close"`
+	Schedule  string    `json:"schedule"`
+	Lots      int       `json:"lots"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Deal сделка робота с версией параметров, по которым она совершена.
+type Deal struct {
+	ID        int       `json:"id"`
+	RobotID   int       `json:"robot_id"`
+	Version   int       `json:"version"`
+	Side      string    `json:"side"`
+	Price     float64   `json:"price"`
+	Lots      int       `json:"lots"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewDeal возвращает сделку робота в размере его позиции. Хранилища сохраняют сделку с ее собственными Lots.
+func (r *Robot) NewDeal(side string, price float64, at time.Time) Deal {
+	return Deal{RobotID: r.RobotID, Side: side, Price: price, Lots: r.lots(), CreatedAt: at}
+}
+
+// CurrentVersion возвращает текущие параметры робота.
+func (r *Robot) CurrentVersion(now time.Time) Version {
+	return Version{
+		RobotID:   r.RobotID,
+		Version:   r.Version,
+		Ticker:    r.Ticker,
+		BuyPrice:  r.BuyPrice,
+		SellPrice: r.SellPrice,
+		PlanStart: r.PlanStart,
+		PlanEnd:   r.PlanEnd,
+		PlanYield: r.PlanYield,
+		AutoClose: r.AutoClose,
+		Schedule:  r.Schedule,
+		Lots:      r.Lots,
+		CreatedAt: now,
+	}
+}
+
+// Validate проверяет параметры робота и возвращает ошибки всех неверных полей.
+func (r *Robot) Validate() error {
+	var f validation.Fields
+
+	if r.Ticker == "" {
+		f.Add("ticker", "is required")
+	}
+
+	if r.Lots < 1 {
+		f.Add("lots", "must be positive")
+	}
+
+	validatePrices(&f, r.BuyPrice, r.SellPrice, r.PlanYield)
+
+	if r.Visibility != "" {
+		r.Visibility.check(&f)
+	}
+
+	switch {
+	case r.PlanStart.Valid != r.PlanEnd.Valid:
+		f.Add("plan_end", "must be set together with plan_start")
+	case r.PlanStart.Valid && !r.PlanStart.Time.Before(r.PlanEnd.Time):
+		f.Add("plan_end", "must be after plan_start")
+	}
+
+	return f.Err(ErrInvalidRobot)
+}
+
+// validatePrices проверяет цены и плановую доходность робота или шаблона.
+func validatePrices(f *validation.Fields, buyPrice, sellPrice, planYield float64) {
+	if buyPrice < 0 {
+		f.Add("buy_price", "can't be negative")
+	}
+
+	if sellPrice < 0 {
+		f.Add("sell_price", "can't be negative")
+	}
+
+	if planYield < 0 {
+		f.Add("plan_yield", "can't be negative")
+	}
+
+	if sellPrice < buyPrice {
+		f.Add("sell_price", "can't be less than buy_price")
+	}
+}
+
+// CheckRevision возвращает ErrConflict, если ожидаемая ревизия revision задана и робот после чтения
+// изменил другой запрос. Нулевая revision означает изменение без проверки.
+func (r *Robot) CheckRevision(revision int) error {
+	if revision != 0 && revision != r.Revision {
+		return fmt.Errorf("%w: revision %d, expected %d", ErrConflict, r.Revision, revision)
+	}
+
+	return nil
+}
+
+// Edit переносит в робота редактируемые параметры из changes и увеличивает версию.
+// Пока у робота открыта позиция, тикер, цены и размер позиции менять нельзя, а у подписчика,
+// повторяющего родителя, меняется только размер позиции. Черновик, которому задали
+// плановое окно или расписание, переходит в состояние scheduled. Если после чтения changes робота
+// изменил другой запрос и ревизии не совпадают, возвращается ErrConflict.
+func (r *Robot) Edit(changes *Robot, now time.Time) error {
+	if changes.Revision != r.Revision {
+		return fmt.Errorf("%w: revision %d, expected %d", ErrConflict, r.Revision, changes.Revision)
+	}
+
+	if !r.IsBuying && (changes.Ticker != r.Ticker || changes.BuyPrice != r.BuyPrice ||
+		changes.SellPrice != r.SellPrice || changes.Lots != r.Lots) {
+		return fmt.Errorf("%w: ticker, buy_price, sell_price and lots can't be changed", ErrPositionOpen)
+	}
+
+	if r.IsMirror && !r.sameParams(changes) {
+		return fmt.Errorf("%w: unfollow the parent robot to change its parameters", ErrMirror)
+	}
+
+	edited := *r
+	edited.setParams(changes)
+	edited.Lots = changes.Lots
+
+	if err := edited.SetVisibility(changes.Visibility); err != nil {
+		return err
+	}
+
+	if err := edited.Validate(); err != nil {
+		return err
+	}
+
+	if edited.Can(ActionSchedule, now) == nil {
+		_, _ = edited.Apply(ActionSchedule, now)
+	}
+
+	edited.Version++
+	*r = edited
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package robot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+func TestEdit(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Robot    Robot
+		Changes  Robot
+		Expected error
+	}
+
+	assert := assert.New(t)
+	now := time.Date(2020, 6, 10, 12, 0, 0, 0, time.UTC)
+	start := NullTime{Time: now, Valid: true}
+	end := NullTime{Time: now.Add(time.Hour), Valid: true}
+	waiting := Robot{Status: StatusActive, IsBuying: true, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, Lots: 1, Version: 1}
+	mirror := Robot{Status: StatusActive, IsBuying: true, IsMirror: true, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, Lots: 1, Version: 1}
+	holding := Robot{Status: StatusHolding, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, Lots: 1, Version: 1}
+
+	testCases := []testCase{
+		{Name: "Change prices", Robot: waiting, Changes: Robot{Lots: 1, Ticker: "AAPL", BuyPrice: 11, SellPrice: 21}},
+		{Name: "Change ticker while holding", Robot: holding, Changes: Robot{Lots: 1, Ticker: "SBER", BuyPrice: 10, SellPrice: 20},
+			Expected: ErrPositionOpen},
+		{Name: "Change plan while holding", Robot: holding,
+			Changes: Robot{Lots: 1, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20, PlanStart: start, PlanEnd: end}},
+		{Name: "Lots while holding", Robot: holding, Changes: Robot{Lots: 2, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20},
+			Expected: ErrPositionOpen},
+		{Name: "Mirror parameters", Robot: mirror, Changes: Robot{Lots: 1, Ticker: "AAPL", BuyPrice: 11, SellPrice: 20},
+			Expected: ErrMirror},
+		{Name: "Mirror lots", Robot: mirror, Changes: Robot{Lots: 3, Ticker: "AAPL", BuyPrice: 10, SellPrice: 20}},
+		{Name: "Empty ticker", Robot: waiting, Changes: Robot{Lots: 1, BuyPrice: 10, SellPrice: 20}, Expected: ErrInvalidRobot},
+		{Name: "Negative price", Robot: waiting, Changes: Robot{Lots: 1, Ticker: "AAPL", BuyPrice: -1}, Expected: ErrInvalidRobot},
+		{Name: "Plan without end", Robot: waiting, Changes: Robot{Lots: 1, Ticker: "AAPL", PlanStart: start}, Expected: ErrInvalidRobot},
+		{Name: "Reversed plan", Robot: waiting, Changes: Robot{Lots: 1, Ticker: "AAPL", PlanStart: end, PlanEnd: start},
+			Expected: ErrInvalidRobot},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			before := tc.Robot
+
+			err := tc.Robot.Edit(&tc.Changes, now)
+			if tc.Expected != nil {
+				assert.True(errors.Is(err, tc.Expected), "error = %v, want %v", err, tc.Expected)
+				assert.Equal(before, tc.Robot, "robot must not change on error")
+
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(before.Version+1, tc.Robot.Version)
+			assert.Equal(tc.Changes.BuyPrice, tc.Robot.BuyPrice)
+		})
+	}
+}
+
+func TestEditSchedulesDraft(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	r := Robot{Status: StatusDraft, IsBuying: true, Ticker: "AAPL", Lots: 1, Version: 1}
+
+	assert.NoError(r.Edit(&Robot{Ticker: "AAPL", Lots: 1, Schedule: "mon-fri 10:00-18:00"}, now))
+	assert.Equal(StatusScheduled, r.Status)
+	assert.Equal(2, r.Version)
+}
+
+func TestValidate(t *testing.T) {
+	type testCase struct {
+		Name           string
+		Robot          Robot
+		ExpectedFields []string
+	}
+
+	assert := assert.New(t)
+	now := time.Date(2020, 6, 10, 12, 0, 0, 0, time.UTC)
+	start := NullTime{Time: now, Valid: true}
+	end := NullTime{Time: now.Add(time.Hour), Valid: true}
+
+	testCases := []testCase{
+		{Name: "Valid", Robot: Robot{Ticker: "AAPL", Lots: 1, BuyPrice: 10, SellPrice: 20, PlanStart: start, PlanEnd: end}},
+		{Name: "Equal prices", Robot: Robot{Ticker: "AAPL", Lots: 1, BuyPrice: 10, SellPrice: 10}},
+		{Name: "Sell below buy", Robot: Robot{Ticker: "AAPL", Lots: 1, BuyPrice: 20, SellPrice: 10},
+			ExpectedFields: []string{"sell_price"}},
+		{Name: "All errors at once", Robot: Robot{BuyPrice: -1, PlanYield: -1, Visibility: "friends", PlanStart: end, PlanEnd: start},
+			ExpectedFields: []string{"ticker", "lots", "buy_price", "plan_yield", "visibility", "plan_end"}},
+		{Name: "Plan without start", Robot: Robot{Ticker: "AAPL", Lots: 1, PlanEnd: end}, ExpectedFields: []string{"plan_end"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Robot.Validate()
+			if tc.ExpectedFields == nil {
+				assert.NoError(err)
+				return
+			}
+
+			assert.True(errors.Is(err, ErrInvalidRobot))
+
+			var verr *validation.Error
+			if assert.True(errors.As(err, &verr)) {
+				fields := make([]string, 0, len(verr.Fields))
+				for _, f := range verr.Fields {
+					fields = append(fields, f.Field)
+				}
+
+				assert.Equal(tc.ExpectedFields, fields)
+			}
+		})
+	}
+}
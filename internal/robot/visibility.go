@@ -0,0 +1,87 @@
+package robot
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
+)
+
+// Visibility определяет, кому кроме владельца виден робот.
+type Visibility string
+
+const (
+	// VisibilityPrivate робот виден только владельцу.
+	VisibilityPrivate Visibility = "private"
+	// VisibilityLink робот виден тем, у кого есть ссылка с токеном, но не попадает в каталог.
+	VisibilityLink Visibility = "link"
+	// VisibilityUsers робот виден пользователям, которым владелец его открыл.
+	VisibilityUsers Visibility = "users"
+	// VisibilityPublic робот виден всем и попадает в каталог и рейтинг.
+	VisibilityPublic Visibility = "public"
+)
+
+const shareTokenBytes = 16
+
+// SetVisibility меняет видимость робота, пустая видимость означает публичного робота.
+// Для доступа по ссылке создается токен, при смене видимости старая ссылка перестает работать.
+func (r *Robot) SetVisibility(v Visibility) error {
+	if v == "" {
+		v = VisibilityPublic
+	}
+
+	var f validation.Fields
+	if v.check(&f); len(f) > 0 {
+		return f.Err(ErrInvalidRobot)
+	}
+
+	r.Visibility = v
+
+	if v != VisibilityLink {
+		r.ShareToken = ""
+		return nil
+	}
+
+	if r.ShareToken == "" {
+		b := make([]byte, shareTokenBytes)
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("can't generate share token: %s", err)
+		}
+
+		r.ShareToken = hex.EncodeToString(b)
+	}
+
+	return nil
+}
+
+// check добавляет в f ошибку поля visibility, если видимость неизвестна.
+func (v Visibility) check(f *validation.Fields) {
+	switch v {
+	case VisibilityPrivate, VisibilityLink, VisibilityUsers, VisibilityPublic:
+		return
+	}
+
+	f.Add("visibility", "must be one of %q, %q, %q or %q",
+		VisibilityPrivate, VisibilityLink, VisibilityUsers, VisibilityPublic)
+}
+
+// VisibleTo проверяет, что пользователь userID видит робота. shareToken — токен из ссылки,
+// shared сообщает, что владелец открыл робота этому пользователю.
+func (r *Robot) VisibleTo(userID int, shareToken string, shared bool) bool {
+	switch r.Visibility {
+	case VisibilityPublic:
+		return true
+	case VisibilityLink:
+		if r.ShareToken != "" && subtle.ConstantTimeCompare([]byte(r.ShareToken), []byte(shareToken)) == 1 {
+			return true
+		}
+	case VisibilityUsers:
+		if shared {
+			return true
+		}
+	}
+
+	return r.OwnerUserID == userID
+}
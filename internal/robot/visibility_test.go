@@ -0,0 +1,106 @@
+package robot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisibleTo(t *testing.T) {
+	type testCase struct {
+		Name       string
+		Visibility Visibility
+		UserID     int
+		ShareToken string
+		Shared     bool
+		Visible    bool
+	}
+
+	testCases := []testCase{
+		{Name: "Owner sees private", Visibility: VisibilityPrivate, UserID: 1, Visible: true},
+		{Name: "Private", Visibility: VisibilityPrivate, UserID: 2, Shared: true},
+		{Name: "Public", Visibility: VisibilityPublic, UserID: 2, Visible: true},
+		{Name: "Link with token", Visibility: VisibilityLink, UserID: 2, ShareToken: "token", Visible: true},
+		{Name: "Link with wrong token", Visibility: VisibilityLink, UserID: 2, ShareToken: "guess"},
+		{Name: "Link without token", Visibility: VisibilityLink, UserID: 2},
+		{Name: "Shared with user", Visibility: VisibilityUsers, UserID: 2, Shared: true, Visible: true},
+		{Name: "Not shared with user", Visibility: VisibilityUsers, UserID: 2, ShareToken: "token"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			r := &Robot{OwnerUserID: 1, Visibility: tc.Visibility}
+			if tc.Visibility == VisibilityLink {
+				r.ShareToken = "token"
+			}
+
+			assert.Equal(t, tc.Visible, r.VisibleTo(tc.UserID, tc.ShareToken, tc.Shared))
+		})
+	}
+}
+
+func TestSetVisibility(t *testing.T) {
+	assert := assert.New(t)
+	r := &Robot{}
+
+	assert.NoError(r.SetVisibility(""))
+	assert.Equal(VisibilityPublic, r.Visibility)
+
+	assert.True(errors.Is(r.SetVisibility("friends"), ErrInvalidRobot))
+
+	assert.NoError(r.SetVisibility(VisibilityLink))
+	assert.NotEmpty(r.ShareToken)
+
+	token := r.ShareToken
+	assert.NoError(r.SetVisibility(VisibilityLink))
+	assert.Equal(token, r.ShareToken, "link stays the same while visibility is unchanged")
+
+	assert.NoError(r.SetVisibility(VisibilityPrivate))
+	assert.Empty(r.ShareToken, "changing visibility revokes the link")
+}
+
+func TestFindVisible(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+
+	public := &Robot{OwnerUserID: 1, Ticker: "AAPL"}
+	shared := &Robot{OwnerUserID: 1, Ticker: "AAPL", Visibility: VisibilityUsers}
+	link := &Robot{OwnerUserID: 1, Ticker: "AAPL", Visibility: VisibilityLink}
+
+	for _, r := range []*Robot{public, shared, link} {
+		assert.NoError(storage.Create(r))
+	}
+
+	assert.NoError(storage.Share(shared.RobotID, 3))
+
+	_, err := storage.FindVisible(shared.RobotID, 2, "")
+	assert.True(errors.Is(err, ErrNotFound))
+
+	_, err = storage.FindVisible(shared.RobotID, 3, "")
+	assert.NoError(err)
+
+	_, err = storage.FindVisible(link.RobotID, 2, link.ShareToken)
+	assert.NoError(err)
+
+	viewer := 3
+	page, err := storage.Find(&Query{Sort: "robot_id", Limit: DefaultLimit, ViewerID: &viewer})
+	assert.NoError(err)
+	assert.Len(page.Robots, 2, "link robots are not listed")
+
+	robots, err := storage.FindVisibleByIDs([]int{public.RobotID, shared.RobotID, link.RobotID, 100}, viewer)
+	assert.NoError(err)
+	assert.Len(robots, 2, "link and missing robots are skipped")
+
+	robots, err = storage.FindVisibleByIDs([]int{link.RobotID}, link.OwnerUserID)
+	assert.NoError(err)
+	assert.Len(robots, 1, "owner sees own link robot")
+
+	assert.NoError(storage.Unshare(shared.RobotID, 3))
+	assert.True(errors.Is(storage.Unshare(shared.RobotID, 3), ErrNotFound))
+
+	users, err := storage.FindShares(shared.RobotID)
+	assert.NoError(err)
+	assert.Empty(users)
+}
@@ -2,12 +2,15 @@ package user
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 )
 
 // Структура хранилища в памяти.
 type StorageInMemory struct {
 	storage map[string]User
+	mutex   sync.RWMutex
 }
 
 func CreateStorageInMemory() *StorageInMemory {
@@ -16,13 +19,17 @@ func CreateStorageInMemory() *StorageInMemory {
 
 // Добавление пользователя в хранилище.
 func (s *StorageInMemory) Create(u *User) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	if _, ok := s.storage[u.Email]; ok {
-		return fmt.Errorf("user %s is already registered", u.Email)
+		return fmt.Errorf("%w: %s", ErrAlreadyExists, u.Email)
 	}
 
 	u.ID = len(s.storage)
 	u.CreatedAt = time.Now()
 	u.UpdatedAt = time.Now()
+	u.Version = 1
 
 	s.storage[u.Email] = *u
 
@@ -30,15 +37,26 @@ func (s *StorageInMemory) Create(u *User) error {
 }
 
 func (s *StorageInMemory) FindByEmail(email string) (*User, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	user, ok := s.storage[email]
 	if !ok {
-		return nil, fmt.Errorf("user %s not found", email)
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, email)
 	}
 
 	return &user, nil
 }
 
 func (s *StorageInMemory) FindByID(id int) (*User, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.findByID(id)
+}
+
+// findByID ищет пользователя по id, вызывающий держит блокировку.
+func (s *StorageInMemory) findByID(id int) (*User, error) {
 	users := s.storage
 	for _, user := range users {
 		if user.ID == id {
@@ -46,10 +64,55 @@ func (s *StorageInMemory) FindByID(id int) (*User, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("user %d not found", id)
+	return nil, fmt.Errorf("%w: %d", ErrNotFound, id)
 }
 
+// FindByIDs возвращает найденных пользователей из ids, отсутствующие пропускаются.
+func (s *StorageInMemory) FindByIDs(ids []int) ([]User, error) {
+	found := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		found[id] = true
+	}
+
+	users := make([]User, 0, len(ids))
+
+	s.mutex.RLock()
+	for _, u := range s.storage {
+		if found[u.ID] {
+			users = append(users, u)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	return users, nil
+}
+
+// Update сохраняет пользователя, если с тех пор, как его прочитали, версия не изменилась, и увеличивает версию.
+// Проверка версии и свободной почты и запись выполняются под одной блокировкой, поэтому из двух изменений
+// одной версии пройдет одно, а новая почта не займет чужую учетную запись.
 func (s *StorageInMemory) Update(user *User) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, err := s.findByID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	if stored.Version != user.Version {
+		return fmt.Errorf("%w: version %d, expected %d", ErrConflict, stored.Version, user.Version)
+	}
+
+	if _, ok := s.storage[user.Email]; ok && user.Email != stored.Email {
+		return fmt.Errorf("%w: %s", ErrAlreadyExists, user.Email)
+	}
+
+	user.Version++
+
+	delete(s.storage, stored.Email)
 	s.storage[user.Email] = *user
+
 	return nil
 }
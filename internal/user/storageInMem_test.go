@@ -0,0 +1,34 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTakenEmail(t *testing.T) {
+	assert := assert.New(t)
+	storage := CreateStorageInMemory()
+
+	first := &User{FirstName: "Ivan", LastName: "Ivanov", Email: "first@example.com", Password: "1234"}
+	second := &User{FirstName: "Petr", LastName: "Petrov", Email: "second@example.com", Password: "1234"}
+	assert.NoError(storage.Create(first))
+	assert.NoError(storage.Create(second))
+
+	changed := *second
+	changed.Email = first.Email
+	assert.True(errors.Is(storage.Update(&changed), ErrAlreadyExists))
+
+	u, err := storage.FindByEmail(first.Email)
+	if assert.NoError(err) {
+		assert.Equal(first.ID, u.ID, "the other user's account is kept")
+	}
+
+	changed = *second
+	changed.Email = "renamed@example.com"
+	assert.NoError(storage.Update(&changed))
+
+	_, err = storage.FindByEmail(second.Email)
+	assert.True(errors.Is(err, ErrNotFound), "the old email is released")
+}
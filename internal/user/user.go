@@ -1,34 +1,54 @@
 package user
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/mail"
 	"time"
 
+	"gitlab.com/hitchpock/tfs-course-work/internal/validation"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// BirthdayLayout формат даты рождения в API.
+const BirthdayLayout = "2006-01-02"
+
+var (
+	// ErrInvalidUser данные пользователя не прошли проверку.
+	ErrInvalidUser = errors.New("invalid user")
+	// ErrNotFound пользователь не найден.
+	ErrNotFound = errors.New("user not found")
+	// ErrAlreadyExists пользователь с такой почтой уже зарегистрирован.
+	ErrAlreadyExists = errors.New("user is already registered")
+	// ErrConflict пользователя изменил другой запрос после того, как его прочитали.
+	ErrConflict = errors.New("user was changed by another request")
+)
+
 type Storage interface {
 	Create(user *User) error
 	FindByID(ID int) (*User, error)
+	FindByIDs(IDs []int) ([]User, error)
 	FindByEmail(email string) (*User, error)
 	Update(user *User) error
 }
 
 // Структура пользователя.
 type User struct {
-	ID        int
+	ID        int       `json:"-"`
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	Birthday  time.Time `json:"birthday,omitempty"`
 	Email     string    `json:"email"`
 	Password  string    `json:"password"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+	Version   int       `json:"-"`
 }
 
-// Кастомный Unmarshal пользователя с временем.
+// Кастомный Unmarshal пользователя с временем. Неизвестные поля и неверные значения
+// возвращаются как *validation.Error.
 func (u *User) UnmarshalJSON(b []byte) error {
 	type Alias User
 
@@ -38,24 +58,61 @@ func (u *User) UnmarshalJSON(b []byte) error {
 	}{
 		Alias: (*Alias)(u),
 	}
-	if err := json.Unmarshal(b, &aux); err != nil {
+	if err := validation.DecodeJSON(bytes.NewReader(b), aux); err != nil {
 		return err
 	}
 
-	if aux.Birthday == "" {
-		aux.Birthday = "0001-01-01"
+	t, err := ParseBirthday(aux.Birthday)
+	if err != nil {
+		return err
 	}
 
-	aux.Birthday += "T00:00:00Z"
-	t, err := time.Parse(time.RFC3339, aux.Birthday)
+	u.Birthday = t
+
+	return nil
+}
+
+// ParseBirthday разбирает дату рождения в формате BirthdayLayout, пустая строка означает незаданную дату.
+func ParseBirthday(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
 
+	t, err := time.Parse(BirthdayLayout, value)
 	if err != nil {
-		return fmt.Errorf("unable to parse time: %s", err)
+		return time.Time{}, validation.Field(ErrInvalidUser, "birthday", "must be a date in %s format", BirthdayLayout)
 	}
 
-	u.Birthday = t
+	return t, nil
+}
 
-	return nil
+// Validate проверяет данные пользователя и возвращает ошибки всех неверных полей.
+func (u *User) Validate() error {
+	var f validation.Fields
+
+	if u.FirstName == "" {
+		f.Add("first_name", "is required")
+	}
+
+	if u.LastName == "" {
+		f.Add("last_name", "is required")
+	}
+
+	if u.Email == "" {
+		f.Add("email", "is required")
+	} else if addr, err := mail.ParseAddress(u.Email); err != nil || addr.Address != u.Email {
+		f.Add("email", "must be a valid email address")
+	}
+
+	if u.Password == "" {
+		f.Add("password", "is required")
+	}
+
+	if u.Birthday.After(time.Now()) {
+		f.Add("birthday", "can't be in the future")
+	}
+
+	return f.Err(ErrInvalidUser)
 }
 
 func (u *User) MarshalJSON() ([]byte, error) {
@@ -67,20 +124,20 @@ func (u *User) MarshalJSON() ([]byte, error) {
 	}{
 		FirstName: u.FirstName,
 		LastName:  u.LastName,
-		Birthday:  u.Birthday.Format("2006-01-02"),
+		Birthday:  u.Birthday.Format(BirthdayLayout),
 		Email:     u.Email,
 	})
 }
 
-// CreateFromJSON формирует объект пользователя из слайса байт.
+// CreateFromJSON формирует объект пользователя из слайса байт. Ошибки разбора и проверки
+// полей возвращаются как *validation.Error.
 func (u *User) CreateFromJSON(body []byte) error {
-	err := json.Unmarshal(body, &u)
-	if err != nil {
-		return fmt.Errorf("invalid input: %s", err)
+	if err := validation.DecodeJSON(bytes.NewReader(body), u); err != nil {
+		return err
 	}
 
-	if u.FirstName == "" || u.LastName == "" || u.Email == "" || u.Password == "" {
-		return errors.New("invalid input")
+	if err := u.Validate(); err != nil {
+		return err
 	}
 
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const bodyField = "body"
+
+var (
+	// ErrInvalidInput тело запроса не удалось разобрать.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrEmptyBody тело запроса пустое.
+	ErrEmptyBody = fmt.Errorf("%w: request body is empty", ErrInvalidInput)
+)
+
+// FieldError ошибка значения одного поля запроса.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Fields собирает ошибки полей при проверке.
+type Fields []FieldError
+
+// Add добавляет ошибку поля field.
+func (f *Fields) Add(field, format string, args ...interface{}) {
+	*f = append(*f, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err возвращает ошибку вида kind с собранными ошибками полей или nil, если ошибок нет.
+func (f Fields) Err(kind error) error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	return &Error{Kind: kind, Fields: f}
+}
+
+// Error ошибки проверки полей, уточняющие ошибку предметной области Kind.
+type Error struct {
+	Kind   error
+	Fields []FieldError
+}
+
+// Field возвращает ошибку вида kind для одного поля.
+func Field(kind error, field, format string, args ...interface{}) error {
+	var f Fields
+	f.Add(field, format, args...)
+
+	return f.Err(kind)
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Field+" "+f.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Kind, strings.Join(msgs, "; "))
+}
+
+func (e *Error) Unwrap() error { return e.Kind }
+
+// DecodeJSON строго читает из r один JSON-объект в v: неизвестные поля, неверные типы и лишние данные
+// после объекта возвращаются как ошибки полей вида ErrInvalidInput.
+func DecodeJSON(r io.Reader, v interface{}) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err)
+	}
+
+	if dec.More() {
+		return Field(ErrInvalidInput, bodyField, "must contain a single JSON object")
+	}
+
+	return nil
+}
+
+// decodeError переводит ошибку encoding/json в ошибку поля.
+func decodeError(err error) error {
+	var (
+		verr      *Error
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+
+	switch {
+	case errors.As(err, &verr):
+		return err
+	case errors.Is(err, io.EOF):
+		return Field(ErrEmptyBody, bodyField, "is required")
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &syntaxErr):
+		return Field(ErrInvalidInput, bodyField, "is not valid JSON")
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = bodyField
+		}
+
+		return Field(ErrInvalidInput, field, "must be %s", jsonType(typeErr.Type.Kind().String()))
+	}
+
+	if field := strings.TrimPrefix(err.Error(), "json: unknown field "); field != err.Error() {
+		return Field(ErrInvalidInput, strings.Trim(field, `"`), "is unknown")
+	}
+
+	return Field(ErrInvalidInput, bodyField, "%s", err)
+}
+
+// jsonType возвращает название типа JSON для вида значения Go.
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "a number"
+	case kind == "bool":
+		return "a boolean"
+	case kind == "string":
+		return "a string"
+	case kind == "slice", kind == "array":
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type payload struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+		Tags  []int   `json:"tags"`
+	}
+
+	type testCase struct {
+		Name          string
+		Body          string
+		ExpectedField string
+		ExpectedKind  error
+	}
+
+	assert := assert.New(t)
+
+	testCases := []testCase{
+		{Name: "Valid", Body: `{"name":"a","price":1.5,"tags":[1]}`},
+		{Name: "Empty body", Body: ``, ExpectedField: "body", ExpectedKind: ErrEmptyBody},
+		{Name: "Malformed", Body: `{"name":`, ExpectedField: "body", ExpectedKind: ErrInvalidInput},
+		{Name: "Unknown field", Body: `{"name":"a","colour":"red"}`, ExpectedField: "colour", ExpectedKind: ErrInvalidInput},
+		{Name: "Wrong type", Body: `{"price":"cheap"}`, ExpectedField: "price", ExpectedKind: ErrInvalidInput},
+		{Name: "Trailing data", Body: `{"name":"a"} {"name":"b"}`, ExpectedField: "body", ExpectedKind: ErrInvalidInput},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			var p payload
+
+			err := DecodeJSON(strings.NewReader(tc.Body), &p)
+			if tc.ExpectedKind == nil {
+				assert.NoError(err)
+				return
+			}
+
+			var verr *Error
+			if assert.True(errors.As(err, &verr), "error must carry field errors: %v", err) {
+				assert.Equal(tc.ExpectedField, verr.Fields[0].Field)
+			}
+
+			assert.True(errors.Is(err, tc.ExpectedKind))
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	assert := assert.New(t)
+	kind := errors.New("invalid robot")
+
+	var f Fields
+	assert.NoError(f.Err(kind))
+
+	f.Add("lots", "must be positive")
+	f.Add("ticker", "is required")
+
+	err := f.Err(kind)
+	assert.True(errors.Is(err, kind))
+	assert.Equal("invalid robot: lots must be positive; ticker is required", err.Error())
+}
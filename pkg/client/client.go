@@ -0,0 +1,434 @@
+// Package client клиент API торговых роботов: вход пользователя, обновление токена сессии,
+// повтор запросов при временных ошибках, разбор ошибок сервиса и подписка на изменения робота.
+package client
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiPath = "/api/v1"
+
+	defaultMaxRetries = 3
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 2 * time.Second
+	// defaultMaxRetryAfter самая долгая пауза из Retry-After, которую клиент ждет перед повтором.
+	defaultMaxRetryAfter = time.Minute
+
+	// refreshBefore за сколько до истечения сессии клиент входит заново.
+	refreshBefore = time.Minute
+)
+
+var errNoCredentials = errors.New("client has no credentials to refresh the session, call SignIn")
+
+// Config настройки клиента. Незаданные поля принимают значения по умолчанию.
+type Config struct {
+	// BaseURL адрес сервиса, например http://localhost:8080.
+	BaseURL    string
+	HTTPClient *http.Client
+	// MaxRetries сколько раз повторять запрос при сетевой ошибке или ответах 429, 502, 503 и 504.
+	// Повторяются только запросы GET, PUT и DELETE. Запрос PUT отправляется с заголовком Idempotency-Key,
+	// поэтому повтор уже выполненного запроса получает его первый ответ. Отрицательное значение отключает повторы.
+	MaxRetries int
+	// MinBackoff и MaxBackoff пределы паузы между повторами, пауза растет экспоненциально.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxRetryAfter самая долгая пауза из заголовка Retry-After. Если сервис просит ждать дольше,
+	// запрос не повторяется и возвращается ошибка.
+	MaxRetryAfter time.Duration
+}
+
+// Client клиент API. Безопасен для одновременного использования из нескольких горутин.
+type Client struct {
+	cfg  Config
+	base *url.URL
+
+	mutex      sync.Mutex
+	token      string
+	session    tokenSession
+	email      string
+	password   string
+	refreshing sync.Mutex
+}
+
+// tokenSession сессия, закодированная в токене сервиса.
+type tokenSession struct {
+	UserID     int       `json:"user_id"`
+	ValidUntil time.Time `json:"valid_until"`
+}
+
+// call запрос к API. Путь задается без префикса /api/v1.
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	out    interface{}
+	public bool
+	// ifMatch ETag объекта для заголовка If-Match.
+	ifMatch string
+	// idempotencyKey ключ для заголовка Idempotency-Key, общий для всех повторов запроса.
+	idempotencyKey string
+}
+
+// New возвращает клиент сервиса с адресом cfg.BaseURL.
+func New(cfg Config) (*Client, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/"))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q", cfg.BaseURL)
+	}
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	} else if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+
+	if cfg.MaxRetryAfter <= 0 {
+		cfg.MaxRetryAfter = defaultMaxRetryAfter
+	}
+
+	return &Client{cfg: cfg, base: base}, nil
+}
+
+// SetToken задает токен сессии, полученный не через SignIn. Когда сессия истечет, клиент
+// не сможет войти заново и вернет ошибку ErrUnauthorized.
+func (c *Client) SetToken(token string) error {
+	s, err := decodeToken(token)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.token, c.session = token, s
+	c.email, c.password = "", ""
+	c.mutex.Unlock()
+
+	return nil
+}
+
+// SetCredentials задает почту и пароль, с которыми клиент входит заново, когда сессия истекает,
+// например вместе с токеном, сохраненным после прошлого SignIn. Без токена клиент войдет при первом запросе.
+func (c *Client) SetCredentials(email, password string) {
+	c.mutex.Lock()
+	c.email, c.password = email, password
+	c.mutex.Unlock()
+}
+
+// Token возвращает текущий токен сессии.
+func (c *Client) Token() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.token
+}
+
+// UserID возвращает ID пользователя текущей сессии.
+func (c *Client) UserID() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.session.UserID
+}
+
+// decodeToken читает сессию из токена сервиса: это JSON сессии в base32.
+func decodeToken(token string) (tokenSession, error) {
+	var s tokenSession
+
+	b, err := base32.StdEncoding.DecodeString(token)
+	if err != nil {
+		return s, fmt.Errorf("invalid session token: %s", err)
+	}
+
+	if err = json.Unmarshal(b, &s); err != nil {
+		return s, fmt.Errorf("invalid session token: %s", err)
+	}
+
+	return s, nil
+}
+
+// authToken возвращает токен для запроса. Если сессия скоро истечет, клиент сначала входит заново.
+func (c *Client) authToken(ctx context.Context) (string, error) {
+	c.mutex.Lock()
+	token, validUntil, canRefresh := c.token, c.session.ValidUntil, c.email != ""
+	c.mutex.Unlock()
+
+	if canRefresh && time.Until(validUntil) < refreshBefore {
+		if err := c.refresh(ctx, token); err != nil {
+			return "", err
+		}
+
+		return c.Token(), nil
+	}
+
+	return token, nil
+}
+
+// canRefresh сообщает, может ли клиент войти заново: он знает почту и пароль пользователя.
+func (c *Client) canRefresh() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.email != ""
+}
+
+// refresh входит заново, если токен stale еще не обновил другой запрос. Вход заменяет сессию пользователя
+// на сервисе, поэтому одновременно выполняется только один вход.
+func (c *Client) refresh(ctx context.Context, stale string) error {
+	c.refreshing.Lock()
+	defer c.refreshing.Unlock()
+
+	c.mutex.Lock()
+	token, email, password := c.token, c.email, c.password
+	c.mutex.Unlock()
+
+	if token != stale {
+		return nil
+	}
+
+	if email == "" {
+		return errNoCredentials
+	}
+
+	return c.SignIn(ctx, email, password)
+}
+
+// do выполняет запрос r и читает ответ в r.out. Ответ 401 на авторизованный запрос обновляет сессию
+// и повторяет запрос один раз, временные ошибки повторяются по Config.MaxRetries. Возвращает заголовки ответа.
+func (c *Client) do(ctx context.Context, r call) (http.Header, error) {
+	var body []byte
+
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return nil, fmt.Errorf("can't marshal request: %s", err)
+		}
+	}
+
+	retries := 0
+	if idempotent(r.method) {
+		retries = c.cfg.MaxRetries
+	}
+
+	// PUT меняет робота: повтор после успешной, но не дошедшей до клиента попытки создал бы еще одну копию
+	// или вернул бы 409, поэтому все попытки отправляются с одним ключом идемпотентности.
+	if retries > 0 && r.method == http.MethodPut && r.idempotencyKey == "" {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+
+		r.idempotencyKey = key
+	}
+
+	refreshed := false
+
+	for attempt := 0; ; attempt++ {
+		resp, token, err := c.send(ctx, r, body)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= retries {
+				return nil, err
+			}
+
+			if err = sleep(ctx, c.backoff(attempt)); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && !r.public && !refreshed && c.canRefresh() {
+			closeBody(resp)
+
+			refreshed = true
+			attempt--
+
+			if err = c.refresh(ctx, token); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if retryable(resp.StatusCode) && attempt < retries {
+			if delay, ok := c.retryDelay(resp, attempt); ok {
+				closeBody(resp)
+
+				if err = sleep(ctx, delay); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+		}
+
+		return resp.Header, c.read(resp, r.out)
+	}
+}
+
+// send отправляет запрос один раз и возвращает ответ и токен, с которым он отправлен.
+func (c *Client) send(ctx context.Context, r call, body []byte) (*http.Response, string, error) {
+	u := *c.base
+	u.Path += apiPath + r.path
+	u.RawQuery = r.query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("can't create request: %s", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if r.ifMatch != "" {
+		req.Header.Set("If-Match", r.ifMatch)
+	}
+
+	if r.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", r.idempotencyKey)
+	}
+
+	var token string
+
+	if !r.public {
+		if token, err = c.authToken(ctx); err != nil {
+			return nil, "", err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, token, fmt.Errorf("%s %s: %s", r.method, r.path, err)
+	}
+
+	return resp, token, nil
+}
+
+// read читает ответ в out или возвращает ошибку сервиса.
+func (c *Client) read(resp *http.Response, out interface{}) error {
+	defer closeBody(resp)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("can't decode response: %s", err)
+	}
+
+	return nil
+}
+
+// backoff возвращает паузу перед повтором attempt: экспоненциальный рост от MinBackoff до MaxBackoff
+// со случайной добавкой, чтобы клиенты не повторяли запросы одновременно.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.cfg.MaxBackoff
+	if attempt < 30 && c.cfg.MinBackoff<<uint(attempt) < d {
+		d = c.cfg.MinBackoff << uint(attempt)
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) //nolint:gosec
+}
+
+// retryDelay возвращает паузу из заголовка Retry-After, если он есть, иначе backoff. Если Retry-After
+// больше Config.MaxRetryAfter, ok равен false и запрос не повторяется.
+func (c *Client) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		if time.Duration(seconds) > c.cfg.MaxRetryAfter/time.Second {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	return c.backoff(attempt), true
+}
+
+// newIdempotencyKey возвращает случайный ключ идемпотентности.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16) //nolint:gomnd
+	if _, err := crand.Read(b); err != nil {
+		return "", fmt.Errorf("can't generate idempotency key: %s", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+
+	return false
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// sleep ждет d или отмены ctx.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// closeBody дочитывает и закрывает тело ответа, чтобы соединение вернулось в пул.
+func closeBody(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,547 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/hitchpock/tfs-course-work/cmd/auth-api/handlers"
+	"gitlab.com/hitchpock/tfs-course-work/internal/event"
+	"gitlab.com/hitchpock/tfs-course-work/internal/fintech"
+	"gitlab.com/hitchpock/tfs-course-work/internal/robot"
+	"gitlab.com/hitchpock/tfs-course-work/internal/session"
+	"gitlab.com/hitchpock/tfs-course-work/internal/user"
+	"gitlab.com/hitchpock/tfs-course-work/pkg/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeInstruments сервис котировок, который знает только AAPL и SBER.
+type fakeInstruments struct {
+	fintech.TradingServiceClient
+}
+
+func (fakeInstruments) GetQuote(_ context.Context, in *fintech.QuoteRequest, _ ...grpc.CallOption) (*fintech.Quote, error) {
+	if in.Ticker != "AAPL" && in.Ticker != "SBER" {
+		return nil, status.Errorf(codes.NotFound, "unknown ticker %q", in.Ticker)
+	}
+
+	return &fintech.Quote{Ticker: in.Ticker, BuyPrice: 100, SellPrice: 99}, nil
+}
+
+// broadcaster рассылает события роботов подписчикам websocket, как шина событий в main.go.
+type broadcaster struct {
+	ws *handlers.WSClients
+}
+
+func (b broadcaster) Publish(e event.Event) error {
+	b.ws.Broadcast(e.RobotID)
+	return nil
+}
+
+// setupServer запускает сервис на хранилищах в памяти и регистрирует пользователя,
+// чтобы пользователи тестов получили ID больше нуля.
+func setupServer(t *testing.T) *httptest.Server {
+	robots := robot.CreateStorageInMemory()
+	ws := handlers.NewWebsocket(robots)
+	h := handlers.NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(),
+		robots, ws, broadcaster{ws: ws}, fakeInstruments{})
+
+	srv := httptest.NewServer(h.Routes())
+	t.Cleanup(srv.Close)
+
+	c := setupClient(t, srv.URL)
+	assert.NoError(t, c.SignUp(context.Background(), userParams("first@example.com")))
+
+	return srv
+}
+
+func setupClient(t *testing.T, baseURL string) *Client {
+	c, err := New(Config{BaseURL: baseURL, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+	assert.NoError(t, err)
+
+	return c
+}
+
+// setupUser регистрирует пользователя email и возвращает клиент, вошедший под ним.
+func setupUser(t *testing.T, baseURL, email string) *Client {
+	ctx := context.Background()
+	c := setupClient(t, baseURL)
+
+	assert.NoError(t, c.SignUp(ctx, userParams(email)))
+	assert.NoError(t, c.SignIn(ctx, email, "1234"))
+
+	return c
+}
+
+func userParams(email string) UserParams {
+	return UserParams{FirstName: "Ivan", LastName: "Ivanov", Birthday: "1980-01-02", Email: email, Password: "1234"}
+}
+
+func robotParams(ticker string) RobotParams {
+	return RobotParams{Ticker: String(ticker), BuyPrice: Float(10), SellPrice: Float(20)}
+}
+
+func TestNew(t *testing.T) {
+	type testCase struct {
+		Name    string
+		BaseURL string
+		IsErr   bool
+	}
+
+	testCases := []testCase{
+		{Name: "Valid url", BaseURL: "http://localhost:8080"},
+		{Name: "Trailing slash", BaseURL: "http://localhost:8080/"},
+		{Name: "Without scheme", BaseURL: "localhost:8080", IsErr: true},
+		{Name: "Empty", BaseURL: "", IsErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := New(Config{BaseURL: tc.BaseURL})
+			assert.Equal(t, tc.IsErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestUsers(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv := setupServer(t)
+
+	c := setupUser(t, srv.URL, "second@example.com")
+	assert.Equal(1, c.UserID())
+
+	u, err := c.User(ctx)
+	assert.NoError(err)
+	assert.Equal("second@example.com", u.Email)
+
+	params := userParams("second@example.com")
+	params.FirstName = "Victor"
+	updated, err := c.UpdateUser(ctx, u.ETag, params)
+	assert.NoError(err)
+	assert.Equal("Victor", updated.FirstName)
+	assert.NotEqual(u.ETag, updated.ETag)
+
+	_, err = c.UpdateUser(ctx, u.ETag, params)
+	assert.True(errors.Is(err, ErrPreconditionFailed), "error: %v", err)
+
+	err = c.SignUp(ctx, userParams("second@example.com"))
+	assert.True(errors.Is(err, ErrUserExists), "error: %v", err)
+
+	err = setupClient(t, srv.URL).SignIn(ctx, "second@example.com", "wrong")
+	var apiErr *Error
+	assert.True(errors.As(err, &apiErr), "error: %v", err)
+
+	doc, err := c.OpenAPI(ctx)
+	assert.NoError(err)
+	assert.Contains(string(doc), `"openapi"`)
+}
+
+func TestRobots(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv := setupServer(t)
+
+	c := setupUser(t, srv.URL, "second@example.com")
+	other := setupUser(t, srv.URL, "third@example.com")
+
+	params := robotParams("AAPL")
+	params.Visibility = String("users")
+	assert.NoError(c.CreateRobot(ctx, params))
+
+	err := c.CreateRobot(ctx, robotParams("NOPE"))
+	assert.True(errors.Is(err, ErrInvalidRobot), "error: %v", err)
+
+	var apiErr *Error
+	if assert.True(errors.As(err, &apiErr)) {
+		assert.Equal(http.StatusBadRequest, apiErr.Status)
+		assert.NotEmpty(apiErr.RequestID)
+
+		if assert.Len(apiErr.Fields, 1) {
+			assert.Equal("ticker", apiErr.Fields[0].Field)
+		}
+	}
+
+	page, err := c.UserRobots(ctx, nil)
+	assert.NoError(err)
+
+	if !assert.Len(page.Robots, 1) {
+		return
+	}
+
+	robotID := page.Robots[0].RobotID
+	assert.True(page.Robots[0].Can("activate"))
+
+	rob, err := c.Robot(ctx, robotID)
+	assert.NoError(err)
+	assert.NotEmpty(rob.ETag)
+
+	edited, err := c.EditRobot(ctx, robotID, rob.ETag, RobotParams{SellPrice: Float(30)})
+	assert.NoError(err)
+	assert.Equal(30.0, edited.SellPrice)
+	assert.Equal("AAPL", edited.Ticker)
+
+	_, err = c.EditRobot(ctx, robotID, rob.ETag, RobotParams{SellPrice: Float(31)})
+	assert.True(errors.Is(err, ErrPreconditionFailed), "error: %v", err)
+
+	err = c.ActivateRobot(ctx, robotID, rob.ETag)
+	assert.True(errors.Is(err, ErrPreconditionFailed), "error: %v", err)
+
+	assert.NoError(c.ActivateRobot(ctx, robotID, edited.ETag))
+
+	rob, err = c.Robot(ctx, robotID)
+	assert.NoError(err)
+	assert.True(rob.IsActive)
+
+	err = c.ActivateRobot(ctx, robotID, rob.ETag)
+	assert.True(errors.Is(err, ErrTransition), "error: %v", err)
+
+	transitions, err := c.RobotTransitions(ctx, robotID)
+	assert.NoError(err)
+	assert.NotEmpty(transitions)
+
+	versions, err := c.RobotVersions(ctx, robotID)
+	assert.NoError(err)
+	assert.Len(versions, 2)
+
+	deals, err := c.RobotDeals(ctx, robotID)
+	assert.NoError(err)
+	assert.Empty(deals)
+
+	_, err = other.Robot(ctx, robotID)
+	assert.True(errors.Is(err, ErrNotFound), "error: %v", err)
+
+	err = other.ActivateRobot(ctx, robotID, rob.ETag)
+	assert.True(errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden), "error: %v", err)
+
+	assert.NoError(c.ShareRobot(ctx, robotID, other.UserID()))
+
+	shares, err := c.RobotShares(ctx, robotID)
+	assert.NoError(err)
+	assert.Equal([]int{other.UserID()}, shares.Users)
+
+	_, err = other.Robot(ctx, robotID)
+	assert.NoError(err)
+
+	_, err = other.CloneRobot(ctx, robotID, nil)
+	assert.True(errors.Is(err, ErrForbidden), "error: %v", err)
+
+	clone, err := c.CloneRobot(ctx, robotID, &RobotParams{Ticker: String("SBER")})
+	if assert.NoError(err) {
+		assert.Equal("SBER", clone.Ticker)
+		assert.Equal(robotID, clone.ClonedFromID)
+	}
+
+	rob, err = c.Robot(ctx, robotID)
+	if assert.NoError(err) {
+		assert.NoError(c.DeleteRobot(ctx, robotID, rob.ETag))
+	}
+
+	deleted, err := c.DeletedRobots(ctx)
+	assert.NoError(err)
+	assert.Len(deleted, 1)
+
+	rob, err = c.RestoreRobot(ctx, robotID)
+	assert.NoError(err)
+	assert.Equal(robotID, rob.RobotID)
+
+	_, err = c.Robot(ctx, 100)
+	assert.True(errors.Is(err, ErrNotFound), "error: %v", err)
+}
+
+func TestRobotsPagination(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv := setupServer(t)
+
+	c := setupUser(t, srv.URL, "second@example.com")
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(c.CreateRobot(ctx, robotParams("AAPL")))
+	}
+
+	ids := make([]int, 0)
+	q := &RobotQuery{Limit: 2}
+
+	for pages := 0; pages < 10; pages++ {
+		page, err := c.UserRobots(ctx, q)
+		if !assert.NoError(err) {
+			return
+		}
+
+		for _, rob := range page.Robots {
+			ids = append(ids, rob.RobotID)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		q.Cursor = page.NextCursor
+	}
+
+	assert.Len(ids, 5)
+}
+
+func TestBatch(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv := setupServer(t)
+
+	c := setupUser(t, srv.URL, "second@example.com")
+
+	result, err := c.CreateRobots(ctx, false, []RobotParams{robotParams("AAPL"), robotParams("NOPE")})
+	if !assert.NoError(err) || !assert.Len(result.Results, 2) {
+		return
+	}
+
+	assert.Equal(1, result.Succeeded)
+	assert.Equal(1, result.Failed)
+	assert.Nil(result.Results[0].Error)
+	assert.NotNil(result.Results[0].Robot)
+
+	if assert.NotNil(result.Results[1].Error) {
+		assert.True(errors.Is(result.Results[1].Error, ErrInvalidRobot), "error: %v", result.Results[1].Error)
+	}
+
+	robotID := result.Results[0].RobotID
+
+	result, err = c.ActivateRobots(ctx, true, []int{robotID, 100})
+	assert.NoError(err)
+	assert.Equal(0, result.Succeeded)
+
+	rob, err := c.Robot(ctx, robotID)
+	assert.NoError(err)
+	assert.False(rob.IsActive)
+
+	tmpl, err := c.CreateTemplate(ctx, TemplateParams{Name: "aapl", Ticker: "AAPL", BuyPrice: 10, SellPrice: 20})
+	assert.NoError(err)
+
+	result, err = c.InstantiateTemplate(ctx, tmpl.ID, false, []RobotParams{{Lots: Int(2)}})
+	assert.NoError(err)
+	assert.Equal(1, result.Succeeded)
+
+	if assert.Len(result.Results, 1) && assert.NotNil(result.Results[0].Robot) {
+		assert.Equal(2, result.Results[0].Robot.Lots)
+		assert.Equal(tmpl.ID, result.Results[0].Robot.TemplateID)
+	}
+}
+
+func TestRefreshSession(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv := setupServer(t)
+
+	c := setupUser(t, srv.URL, "second@example.com")
+	token := c.Token()
+
+	// Вход с другого устройства заменяет сессию пользователя, токен клиента становится недействительным.
+	other := setupClient(t, srv.URL)
+	assert.NoError(other.SignIn(ctx, "second@example.com", "1234"))
+
+	_, err := c.User(ctx)
+	assert.NoError(err)
+	assert.NotEqual(token, c.Token())
+
+	// Без почты и пароля клиент не может войти заново.
+	assert.NoError(other.SetToken(token))
+
+	_, err = other.User(ctx)
+	assert.True(errors.Is(err, ErrUnauthorized), "error: %v", err)
+}
+
+func TestRetry(t *testing.T) {
+	type testCase struct {
+		Name          string
+		Method        string
+		Failures      int32
+		Status        int
+		RetryAfter    string
+		MaxRetries    int
+		ExpectedCalls int32
+		ExpectedKey   bool
+		IsErr         bool
+	}
+
+	testCases := []testCase{
+		{Name: "Unavailable then ok", Method: http.MethodGet, Failures: 2, Status: http.StatusServiceUnavailable,
+			ExpectedCalls: 3},
+		{Name: "Too many requests with Retry-After", Method: http.MethodGet, Failures: 1, Status: http.StatusTooManyRequests,
+			RetryAfter: "0", ExpectedCalls: 2},
+		{Name: "Retries exhausted", Method: http.MethodGet, Failures: 10, Status: http.StatusBadGateway,
+			MaxRetries: 2, ExpectedCalls: 3, IsErr: true},
+		{Name: "Retries disabled", Method: http.MethodGet, Failures: 1, Status: http.StatusServiceUnavailable,
+			MaxRetries: -1, ExpectedCalls: 1, IsErr: true},
+		{Name: "Post is not retried", Method: http.MethodPost, Failures: 1, Status: http.StatusServiceUnavailable,
+			ExpectedCalls: 1, IsErr: true},
+		{Name: "Client error is not retried", Method: http.MethodGet, Failures: 1, Status: http.StatusBadRequest,
+			ExpectedCalls: 1, IsErr: true},
+		{Name: "Retry-After above limit is not retried", Method: http.MethodGet, Failures: 1,
+			Status: http.StatusTooManyRequests, RetryAfter: "3600", ExpectedCalls: 1, IsErr: true},
+		{Name: "Put is retried with one idempotency key", Method: http.MethodPut, Failures: 2,
+			Status: http.StatusServiceUnavailable, ExpectedCalls: 3, ExpectedKey: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			var (
+				calls int32
+				mutex sync.Mutex
+				keys  = make(map[string]bool)
+			)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				keys[r.Header.Get("Idempotency-Key")] = true
+				mutex.Unlock()
+
+				if atomic.AddInt32(&calls, 1) <= tc.Failures {
+					if tc.RetryAfter != "" {
+						w.Header().Set("Retry-After", tc.RetryAfter)
+					}
+
+					w.WriteHeader(tc.Status)
+
+					return
+				}
+
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			c, err := New(Config{BaseURL: srv.URL, MaxRetries: tc.MaxRetries, MinBackoff: time.Millisecond,
+				MaxBackoff: 2 * time.Millisecond})
+			assert.NoError(t, err)
+
+			_, err = c.do(context.Background(), call{method: tc.Method, path: "/signup", public: true})
+			assert.Equal(t, tc.IsErr, err != nil, "error: %v", err)
+			assert.Equal(t, tc.ExpectedCalls, atomic.LoadInt32(&calls))
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			assert.Len(t, keys, 1, "all attempts are sent with the same key")
+			assert.Equal(t, tc.ExpectedKey, !keys[""], "keys: %v", keys)
+		})
+	}
+}
+
+// TestRetryLostResponse проверяет, что повтор изменения, ответ на которое потерялся, не выполняет его второй раз.
+func TestRetryLostResponse(t *testing.T) {
+	assert := assert.New(t)
+	robots := robot.CreateStorageInMemory()
+	ws := handlers.NewWebsocket(robots)
+	h := handlers.NewHandler(log.NewSugarLogger(), session.CreateStorageInMemory(), user.CreateStorageInMemory(),
+		robots, ws, broadcaster{ws: ws}, fakeInstruments{})
+	routes := h.Routes()
+
+	var lost int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && atomic.AddInt32(&lost, 1) == 1 {
+			routes.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		routes.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	// первый пользователь хранилища в памяти получает id 0, как в setupServer
+	assert.NoError(setupClient(t, srv.URL).SignUp(ctx, userParams("first@example.com")))
+
+	owner := setupUser(t, srv.URL, "second@example.com")
+	c := setupUser(t, srv.URL, "third@example.com")
+
+	assert.NoError(owner.CreateRobot(ctx, robotParams("AAPL")))
+
+	page, err := owner.UserRobots(ctx, nil)
+	if !assert.NoError(err) || !assert.Len(page.Robots, 1) {
+		return
+	}
+
+	parent, err := c.Robot(ctx, page.Robots[0].RobotID)
+	if !assert.NoError(err) {
+		return
+	}
+
+	_, err = c.FavouriteRobot(ctx, parent.RobotID, parent.ETag, Follow{}) //nolint:misspell
+	assert.NoError(err)
+
+	own, err := c.UserRobots(ctx, nil)
+	assert.NoError(err)
+	assert.Len(own.Robots, 1, "retry must not copy the robot twice")
+}
+
+func TestWatchRobot(t *testing.T) {
+	assert := assert.New(t)
+	srv := setupServer(t)
+	c := setupUser(t, srv.URL, "second@example.com")
+	other := setupUser(t, srv.URL, "third@example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	params := robotParams("AAPL")
+	params.Visibility = String("private")
+	assert.NoError(c.CreateRobot(ctx, params))
+
+	page, err := c.UserRobots(ctx, nil)
+	if !assert.NoError(err) || !assert.Len(page.Robots, 1) {
+		return
+	}
+
+	robotID := page.Robots[0].RobotID
+
+	w, err := c.WatchRobot(ctx, robotID, "")
+	if !assert.NoError(err) {
+		return
+	}
+	defer w.Close()
+
+	rob := <-w.Updates()
+	assert.Equal(robotID, rob.RobotID)
+	assert.False(rob.IsActive)
+
+	current, err := c.Robot(ctx, robotID)
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.NoError(c.ActivateRobot(ctx, robotID, current.ETag))
+
+	rob = <-w.Updates()
+	assert.True(rob.IsActive)
+
+	assert.NoError(w.Close())
+
+	_, ok := <-w.Updates()
+	assert.False(ok)
+	assert.NoError(w.Err())
+
+	hidden, err := other.WatchRobot(ctx, robotID, "")
+	if !assert.NoError(err) {
+		return
+	}
+	defer hidden.Close()
+
+	_, ok = <-hidden.Updates()
+	assert.False(ok)
+	assert.True(errors.Is(hidden.Err(), ErrNotFound), "error: %v", hidden.Err())
+
+	_, err = setupClient(t, srv.URL).WatchRobot(ctx, robotID, "")
+	assert.Error(err)
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Коды ошибок сервиса, по которым удобно сравнивать ошибки через errors.Is.
+var (
+	ErrNotFound     = &Error{Code: "not_found"}
+	ErrUserNotFound = &Error{Code: "user_not_found"}
+	ErrForbidden    = &Error{Code: "forbidden"}
+	ErrUserExists   = &Error{Code: "user_exists"}
+	ErrTransition   = &Error{Code: "transition_not_allowed"}
+	ErrInvalidRobot = &Error{Code: "invalid_robot"}
+	ErrUnauthorized = &Error{Code: "unauthorized"}
+	// ErrPreconditionFailed объект изменился после чтения, его нужно прочитать заново.
+	ErrPreconditionFailed = &Error{Code: "precondition_failed"}
+)
+
+// FieldError ошибка одного поля запроса.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error ошибка, которую вернул сервис в формате application/problem+json. Code — машиночитаемый код ошибки,
+// RequestID — идентификатор запроса в логах сервиса.
+type Error struct {
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Title     string       `json:"title"`
+	Detail    string       `json:"detail"`
+	Fields    []FieldError `json:"fields"`
+	RequestID string       `json:"request_id"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("robot api: %d %s", e.Status, e.Code)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+
+	for _, f := range e.Fields {
+		msg += fmt.Sprintf("; %s %s", f.Field, f.Message)
+	}
+
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request %s)", e.RequestID)
+	}
+
+	return msg
+}
+
+// Is сравнивает ошибки по коду, например errors.Is(err, client.ErrNotFound).
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// decodeError читает ошибку из ответа сервиса. Если тело не в формате problem+json,
+// код ошибки соответствует статусу, а тело становится ее описанием.
+func decodeError(resp *http.Response) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("can't read error response: %s", err)
+	}
+
+	e := &Error{}
+	if json.Unmarshal(body, e) != nil || e.Code == "" {
+		e = &Error{Detail: strings.TrimSpace(string(body))}
+	}
+
+	if e.Status == 0 {
+		e.Status = resp.StatusCode
+	}
+
+	if e.Code == "" {
+		e.Code = strings.ReplaceAll(strings.ToLower(http.StatusText(resp.StatusCode)), " ", "_")
+	}
+
+	if e.RequestID == "" {
+		e.RequestID = resp.Header.Get("X-Request-Id")
+	}
+
+	return e
+}
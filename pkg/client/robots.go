@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// nextLinkRe ссылка на следующую страницу в заголовке Link.
+var nextLinkRe = regexp.MustCompile(`<([^>]*)>;\s*rel="next"`)
+
+// Robots возвращает страницу каталога роботов, которых видит пользователь.
+func (c *Client) Robots(ctx context.Context, q *RobotQuery) (*RobotPage, error) {
+	return c.robotPage(ctx, "/robots", q)
+}
+
+// UserRobots возвращает страницу роботов пользователя текущей сессии.
+func (c *Client) UserRobots(ctx context.Context, q *RobotQuery) (*RobotPage, error) {
+	path, err := c.userPath(ctx, "/robots")
+	if err != nil {
+		return nil, err
+	}
+
+	return c.robotPage(ctx, path, q)
+}
+
+// robotPage запрашивает страницу роботов и берет курсор следующей страницы из заголовка Link.
+func (c *Client) robotPage(ctx context.Context, path string, q *RobotQuery) (*RobotPage, error) {
+	page := &RobotPage{}
+
+	header, err := c.do(ctx, call{method: http.MethodGet, path: path, query: q.values(), out: &page.Robots})
+	if err != nil {
+		return nil, err
+	}
+
+	if m := nextLinkRe.FindStringSubmatch(header.Get("Link")); m != nil {
+		if next, err := url.Parse(m[1]); err == nil {
+			page.NextCursor = next.Query().Get("cursor")
+		}
+	}
+
+	return page, nil
+}
+
+// DeletedRobots возвращает корзину пользователя текущей сессии.
+func (c *Client) DeletedRobots(ctx context.Context) ([]Robot, error) {
+	path, err := c.userPath(ctx, "/robots/deleted")
+	if err != nil {
+		return nil, err
+	}
+
+	var robots []Robot
+	_, err = c.do(ctx, call{method: http.MethodGet, path: path, out: &robots})
+
+	return robots, err
+}
+
+// Leaderboard возвращает рейтинг публичных роботов по метрике metric за период period.
+// Пустые значения и limit 0 означают значения по умолчанию сервиса.
+func (c *Client) Leaderboard(ctx context.Context, metric, period string, limit int) ([]Stats, error) {
+	q := url.Values{}
+	setString(q, "metric", metric)
+	setString(q, "period", period)
+
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+
+	var stats []Stats
+	_, err := c.do(ctx, call{method: http.MethodGet, path: "/robots/leaderboard", query: q, out: &stats})
+
+	return stats, err
+}
+
+// CreateRobot создает робота пользователя текущей сессии. Сервис не возвращает созданного робота,
+// если он нужен, используйте CreateRobots.
+func (c *Client) CreateRobot(ctx context.Context, params RobotParams) error {
+	userID, err := c.sessionUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, call{method: http.MethodPost, path: "/robot", body: ownRobot(userID, params)})
+
+	return err
+}
+
+// ownRobot возвращает тело запроса на создание робота пользователя userID.
+func ownRobot(userID int, params RobotParams) interface{} {
+	return struct {
+		OwnerUserID int `json:"owner_user_id"`
+		RobotParams
+	}{OwnerUserID: userID, RobotParams: params}
+}
+
+// Robot возвращает робота, которого видит пользователь.
+func (c *Client) Robot(ctx context.Context, robotID int) (*Robot, error) {
+	return c.robot(ctx, http.MethodGet, robotPath(robotID, ""), nil, nil)
+}
+
+// SharedRobot возвращает робота с доступом по ссылке, shareToken — токен из ссылки.
+func (c *Client) SharedRobot(ctx context.Context, robotID int, shareToken string) (*Robot, error) {
+	return c.robot(ctx, http.MethodGet, robotPath(robotID, ""), url.Values{"share": {shareToken}}, nil)
+}
+
+// EditRobot меняет параметры робота, поля nil остаются прежними. etag — Robot.ETag прочитанного робота:
+// если робота с тех пор изменили, возвращается ErrPreconditionFailed. Возвращает новую версию робота.
+func (c *Client) EditRobot(ctx context.Context, robotID int, etag string, params RobotParams) (*Robot, error) {
+	return c.robotCall(ctx, call{method: http.MethodPut, path: robotPath(robotID, ""), body: params, ifMatch: etag})
+}
+
+// Методы, которые меняют состояние робота, принимают etag — Robot.ETag прочитанного робота, как EditRobot:
+// если робота с тех пор изменили, возвращается ErrPreconditionFailed.
+
+// DeleteRobot помещает робота в корзину. Торгующий робот сначала останавливается и закрывает позицию.
+func (c *Client) DeleteRobot(ctx context.Context, robotID int, etag string) error {
+	return c.robotAction(ctx, http.MethodDelete, robotID, "", etag)
+}
+
+// ActivateRobot запускает робота.
+func (c *Client) ActivateRobot(ctx context.Context, robotID int, etag string) error {
+	return c.robotAction(ctx, http.MethodPut, robotID, "/activate", etag)
+}
+
+// DeactivateRobot приостанавливает робота.
+func (c *Client) DeactivateRobot(ctx context.Context, robotID int, etag string) error {
+	return c.robotAction(ctx, http.MethodPut, robotID, "/deactivate", etag)
+}
+
+// StopRobot завершает работу робота.
+func (c *Client) StopRobot(ctx context.Context, robotID int, etag string) error {
+	return c.robotAction(ctx, http.MethodPut, robotID, "/stop", etag)
+}
+
+// FavouriteRobot добавляет копию робота в избранное пользователя и возвращает копию. etag — ETag родителя.
+func (c *Client) FavouriteRobot(ctx context.Context, robotID int, etag string, follow Follow) (*Robot, error) { //nolint:misspell
+	return c.robotCall(ctx, call{method: http.MethodPut, path: robotPath(robotID, "/favourite"), //nolint:misspell
+		body: follow, ifMatch: etag})
+}
+
+// UnfollowRobot отключает повторение родителя у робота.
+func (c *Client) UnfollowRobot(ctx context.Context, robotID int, etag string) error {
+	return c.robotAction(ctx, http.MethodPut, robotID, "/unfollow", etag)
+}
+
+// CloneRobot создает копию робота. Поля params, кроме nil, переопределяют поля копии.
+func (c *Client) CloneRobot(ctx context.Context, robotID int, params *RobotParams) (*Robot, error) {
+	var body interface{}
+	if params != nil {
+		body = params
+	}
+
+	return c.robot(ctx, http.MethodPost, robotPath(robotID, "/clone"), nil, body)
+}
+
+// RestoreRobot возвращает робота из корзины.
+func (c *Client) RestoreRobot(ctx context.Context, robotID int) (*Robot, error) {
+	return c.robot(ctx, http.MethodPut, robotPath(robotID, "/restore"), nil, nil)
+}
+
+// PurgeRobot окончательно удаляет робота из корзины.
+func (c *Client) PurgeRobot(ctx context.Context, robotID int) error {
+	return c.robotAction(ctx, http.MethodDelete, robotID, "/purge", "")
+}
+
+// RobotTransitions возвращает историю переходов робота.
+func (c *Client) RobotTransitions(ctx context.Context, robotID int) ([]Transition, error) {
+	var transitions []Transition
+	_, err := c.do(ctx, call{method: http.MethodGet, path: robotPath(robotID, "/transitions"), out: &transitions})
+
+	return transitions, err
+}
+
+// RobotVersions возвращает историю параметров робота.
+func (c *Client) RobotVersions(ctx context.Context, robotID int) ([]Version, error) {
+	var versions []Version
+	_, err := c.do(ctx, call{method: http.MethodGet, path: robotPath(robotID, "/versions"), out: &versions})
+
+	return versions, err
+}
+
+// RobotDeals возвращает сделки робота.
+func (c *Client) RobotDeals(ctx context.Context, robotID int) ([]Deal, error) {
+	var deals []Deal
+	_, err := c.do(ctx, call{method: http.MethodGet, path: robotPath(robotID, "/deals"), out: &deals})
+
+	return deals, err
+}
+
+// RobotStats возвращает статистику робота за период period, пустой период — за все время.
+func (c *Client) RobotStats(ctx context.Context, robotID int, period string) (*Stats, error) {
+	q := url.Values{}
+	setString(q, "period", period)
+
+	var stats Stats
+	if _, err := c.do(ctx, call{method: http.MethodGet, path: robotPath(robotID, "/stats"), query: q, out: &stats}); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// RobotShares возвращает видимость робота, ссылку для доступа и пользователей, которым робот открыт.
+func (c *Client) RobotShares(ctx context.Context, robotID int) (*Shares, error) {
+	var shares Shares
+	if _, err := c.do(ctx, call{method: http.MethodGet, path: robotPath(robotID, "/shares"), out: &shares}); err != nil {
+		return nil, err
+	}
+
+	return &shares, nil
+}
+
+// ShareRobot открывает робота пользователю userID.
+func (c *Client) ShareRobot(ctx context.Context, robotID, userID int) error {
+	return c.robotAction(ctx, http.MethodPut, robotID, fmt.Sprintf("/shares/%d", userID), "")
+}
+
+// UnshareRobot закрывает робота от пользователя userID.
+func (c *Client) UnshareRobot(ctx context.Context, robotID, userID int) error {
+	return c.robotAction(ctx, http.MethodDelete, robotID, fmt.Sprintf("/shares/%d", userID), "")
+}
+
+// robot выполняет запрос, ответ на который — робот.
+func (c *Client) robot(ctx context.Context, method, path string, query url.Values, body interface{}) (*Robot, error) {
+	return c.robotCall(ctx, call{method: method, path: path, query: query, body: body})
+}
+
+// robotCall выполняет запрос r и читает робота вместе с ETag из ответа.
+func (c *Client) robotCall(ctx context.Context, r call) (*Robot, error) {
+	var rob Robot
+
+	r.out = &rob
+
+	header, err := c.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	rob.ETag = header.Get("ETag")
+
+	return &rob, nil
+}
+
+// robotAction выполняет запрос к роботу без тела ответа, etag передается в If-Match, если он не пустой.
+func (c *Client) robotAction(ctx context.Context, method string, robotID int, suffix, etag string) error {
+	_, err := c.do(ctx, call{method: method, path: robotPath(robotID, suffix), ifMatch: etag})
+	return err
+}
+
+func robotPath(robotID int, suffix string) string {
+	return fmt.Sprintf("/robot/%d%s", robotID, suffix)
+}
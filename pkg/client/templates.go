@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CreateRobots создает роботов пользователя текущей сессии пакетом. В атомарном пакете ошибка одного робота
+// отменяет создание всех.
+func (c *Client) CreateRobots(ctx context.Context, atomic bool, robots []RobotParams) (*BatchResult, error) {
+	userID, err := c.sessionUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := struct {
+		Atomic bool          `json:"atomic"`
+		Robots []interface{} `json:"robots"`
+	}{Atomic: atomic, Robots: make([]interface{}, 0, len(robots))}
+
+	for _, params := range robots {
+		body.Robots = append(body.Robots, ownRobot(userID, params))
+	}
+
+	return c.batch(ctx, "/robots/batch", body)
+}
+
+// ActivateRobots запускает роботов пакетом.
+func (c *Client) ActivateRobots(ctx context.Context, atomic bool, robotIDs []int) (*BatchResult, error) {
+	return c.batchAction(ctx, "/robots/batch/activate", atomic, robotIDs)
+}
+
+// DeactivateRobots приостанавливает роботов пакетом.
+func (c *Client) DeactivateRobots(ctx context.Context, atomic bool, robotIDs []int) (*BatchResult, error) {
+	return c.batchAction(ctx, "/robots/batch/deactivate", atomic, robotIDs)
+}
+
+// DeleteRobots помещает роботов в корзину пакетом.
+func (c *Client) DeleteRobots(ctx context.Context, atomic bool, robotIDs []int) (*BatchResult, error) {
+	return c.batchAction(ctx, "/robots/batch/delete", atomic, robotIDs)
+}
+
+func (c *Client) batchAction(ctx context.Context, path string, atomic bool, robotIDs []int) (*BatchResult, error) {
+	body := struct {
+		Atomic   bool  `json:"atomic"`
+		RobotIDs []int `json:"robot_ids"`
+	}{Atomic: atomic, RobotIDs: robotIDs}
+
+	return c.batch(ctx, path, body)
+}
+
+// batch выполняет пакетный запрос. Ответ 207 Multi-Status не считается ошибкой:
+// ошибки отдельных роботов лежат в BatchItem.Error.
+func (c *Client) batch(ctx context.Context, path string, body interface{}) (*BatchResult, error) {
+	var result BatchResult
+	if _, err := c.do(ctx, call{method: http.MethodPost, path: path, body: body, out: &result}); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreateTemplate сохраняет шаблон робота.
+func (c *Client) CreateTemplate(ctx context.Context, params TemplateParams) (*Template, error) {
+	var tmpl Template
+	if _, err := c.do(ctx, call{method: http.MethodPost, path: "/templates", body: params, out: &tmpl}); err != nil {
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
+
+// Templates возвращает шаблоны пользователя текущей сессии.
+func (c *Client) Templates(ctx context.Context) ([]Template, error) {
+	var templates []Template
+	_, err := c.do(ctx, call{method: http.MethodGet, path: "/templates", out: &templates})
+
+	return templates, err
+}
+
+// Template возвращает шаблон пользователя.
+func (c *Client) Template(ctx context.Context, templateID int) (*Template, error) {
+	var tmpl Template
+	if _, err := c.do(ctx, call{method: http.MethodGet, path: templatePath(templateID, ""), out: &tmpl}); err != nil {
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
+
+// DeleteTemplate удаляет шаблон, созданные из него роботы остаются.
+func (c *Client) DeleteTemplate(ctx context.Context, templateID int) error {
+	_, err := c.do(ctx, call{method: http.MethodDelete, path: templatePath(templateID, "")})
+	return err
+}
+
+// InstantiateTemplate создает роботов из шаблона, каждый элемент robots переопределяет поля шаблона.
+func (c *Client) InstantiateTemplate(ctx context.Context, templateID int, atomic bool,
+	robots []RobotParams) (*BatchResult, error) {
+	body := struct {
+		Atomic bool          `json:"atomic"`
+		Robots []RobotParams `json:"robots"`
+	}{Atomic: atomic, Robots: robots}
+
+	return c.batch(ctx, templatePath(templateID, "/robots"), body)
+}
+
+func templatePath(templateID int, suffix string) string {
+	return fmt.Sprintf("/templates/%d%s", templateID, suffix)
+}
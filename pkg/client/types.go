@@ -0,0 +1,337 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Time время в ответах сервиса. Сервис отправляет незаданное время пустой строкой, тогда Time нулевое.
+type Time struct {
+	time.Time
+}
+
+func (t *Time) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte(`""`)) || bytes.Equal(b, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	return json.Unmarshal(b, &t.Time)
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+
+	return json.Marshal(t.Time)
+}
+
+// Robot торговый робот.
+type Robot struct {
+	RobotID       int      `json:"robot_id"`
+	OwnerUserID   int      `json:"owner_user_id"`
+	ParentRobotID int      `json:"parent_robot_id"`
+	ClonedFromID  int      `json:"cloned_from_id"`
+	TemplateID    int      `json:"template_id"`
+	IsFavourite   bool     `json:"is_favourite"` //nolint:misspell
+	IsActive      bool     `json:"is_active"`
+	Status        string   `json:"status"`
+	Ticker        string   `json:"ticker"`
+	BuyPrice      float64  `json:"buy_price"`
+	SellPrice     float64  `json:"sell_price"`
+	PlanStart     Time     `json:"plan_start"`
+	PlanEnd       Time     `json:"plan_end"`
+	PlanYield     float64  `json:"plan_yield"`
+	FactYield     float64  `json:"fact_yield"`
+	DealsCount    int      `json:"deals_count"`
+	AutoClose     bool     `json:"auto_close"`
+	Schedule      string   `json:"schedule"`
+	Version       int      `json:"version"`
+	IsMirror      bool     `json:"is_mirror"`
+	Lots          int      `json:"lots"`
+	Followers     int      `json:"followers"`
+	Visibility    string   `json:"visibility"`
+	ActivatedAt   Time     `json:"activated_at"`
+	DeactivatedAt Time     `json:"deactivated_at"`
+	CreatedAt     Time     `json:"created_at"`
+	DeletedAt     Time     `json:"deleted_at"`
+	Actions       []string `json:"actions"`
+	// ETag версия робота для EditRobot. Заполняется у ответов Robot, SharedRobot и EditRobot, в списках пустая.
+	ETag string `json:"-"`
+}
+
+// Can сообщает, разрешено ли сейчас действие action над роботом, например activate.
+func (r *Robot) Can(action string) bool {
+	for _, a := range r.Actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RobotParams параметры робота в запросах создания и изменения. Поля nil не передаются:
+// при изменении робота они остаются прежними, при создании принимают значения по умолчанию.
+type RobotParams struct {
+	Ticker     *string    `json:"ticker,omitempty"`
+	BuyPrice   *float64   `json:"buy_price,omitempty"`
+	SellPrice  *float64   `json:"sell_price,omitempty"`
+	PlanStart  *time.Time `json:"plan_start,omitempty"`
+	PlanEnd    *time.Time `json:"plan_end,omitempty"`
+	PlanYield  *float64   `json:"plan_yield,omitempty"`
+	AutoClose  *bool      `json:"auto_close,omitempty"`
+	Schedule   *string    `json:"schedule,omitempty"`
+	Lots       *int       `json:"lots,omitempty"`
+	Visibility *string    `json:"visibility,omitempty"`
+}
+
+// String возвращает указатель на s для полей параметров.
+func String(s string) *string { return &s }
+
+// Float возвращает указатель на f для полей параметров.
+func Float(f float64) *float64 { return &f }
+
+// Int возвращает указатель на i для полей параметров.
+func Int(i int) *int { return &i }
+
+// Bool возвращает указатель на b для полей параметров.
+func Bool(b bool) *bool { return &b }
+
+// Follow параметры добавления робота в избранное. Если Mirror, копия повторяет сделки родителя
+// позицией в Lots лотов.
+type Follow struct {
+	Mirror bool `json:"mirror"`
+	Lots   int  `json:"lots,omitempty"`
+}
+
+// User пользователь сервиса.
+type User struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Birthday  string `json:"birthday"`
+	Email     string `json:"email"`
+	// ETag версия данных пользователя для UpdateUser.
+	ETag string `json:"-"`
+}
+
+// UserParams данные пользователя при регистрации и изменении. Birthday — дата в формате 2006-01-02.
+type UserParams struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Birthday  string `json:"birthday,omitempty"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+}
+
+// Template шаблон робота.
+type Template struct {
+	ID          int       `json:"id"`
+	OwnerUserID int       `json:"owner_user_id"`
+	Name        string    `json:"name"`
+	Ticker      string    `json:"ticker"`
+	BuyPrice    float64   `json:"buy_price"`
+	SellPrice   float64   `json:"sell_price"`
+	PlanYield   float64   `json:"plan_yield"`
+	AutoClose   bool      `json:"auto_close"`
+	Schedule    string    `json:"schedule"`
+	Lots        int       `json:"lots"`
+	Visibility  string    `json:"visibility"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TemplateParams параметры нового шаблона.
+type TemplateParams struct {
+	Name       string  `json:"name"`
+	Ticker     string  `json:"ticker,omitempty"`
+	BuyPrice   float64 `json:"buy_price,omitempty"`
+	SellPrice  float64 `json:"sell_price,omitempty"`
+	PlanYield  float64 `json:"plan_yield,omitempty"`
+	AutoClose  bool    `json:"auto_close,omitempty"`
+	Schedule   string  `json:"schedule,omitempty"`
+	Lots       int     `json:"lots,omitempty"`
+	Visibility string  `json:"visibility,omitempty"`
+}
+
+// Transition переход робота между состояниями.
+type Transition struct {
+	ID         int       `json:"id"`
+	RobotID    int       `json:"robot_id"`
+	Action     string    `json:"action"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Source     string    `json:"source"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Version версия параметров робота.
+type Version struct {
+	RobotID   int       `json:"robot_id"`
+	Version   int       `json:"version"`
+	Ticker    string    `json:"ticker"`
+	BuyPrice  float64   `json:"buy_price"`
+	SellPrice float64   `json:"sell_price"`
+	PlanStart Time      `json:"plan_start"`
+	PlanEnd   Time      `json:"plan_end"`
+	PlanYield float64   `json:"plan_yield"`
+	AutoClose bool      `json:"auto_close"`
+	Schedule  string    `json:"schedule"`
+	Lots      int       `json:"lots"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Deal сделка робота.
+type Deal struct {
+	ID        int       `json:"id"`
+	RobotID   int       `json:"robot_id"`
+	Version   int       `json:"version"`
+	Side      string    `json:"side"`
+	Price     float64   `json:"price"`
+	Lots      int       `json:"lots"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Stats статистика робота за период.
+type Stats struct {
+	RobotID     int     `json:"robot_id"`
+	OwnerUserID int     `json:"owner_user_id"`
+	Ticker      string  `json:"ticker"`
+	Yield       float64 `json:"yield"`
+	Sharpe      float64 `json:"sharpe"`
+	Deals       int     `json:"deals"`
+	Drawdown    float64 `json:"drawdown"`
+	Followers   int     `json:"followers"`
+}
+
+// Shares настройки доступа к роботу.
+type Shares struct {
+	Visibility string `json:"visibility"`
+	ShareLink  string `json:"share_link"`
+	Users      []int  `json:"users"`
+}
+
+// BatchItem результат операции над одним роботом пакета. Error — ошибка элемента, если операция не выполнена.
+type BatchItem struct {
+	RobotID int    `json:"robot_id"`
+	Status  int    `json:"status"`
+	Robot   *Robot `json:"robot"`
+	Error   *Error `json:"-"`
+}
+
+// BatchResult результат пакетной операции, элементы идут в порядке запроса.
+type BatchResult struct {
+	Atomic    bool        `json:"atomic"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Results   []BatchItem `json:"results"`
+}
+
+func (b *BatchItem) UnmarshalJSON(data []byte) error {
+	type alias BatchItem
+
+	aux := struct {
+		*alias
+		Code   string       `json:"code"`
+		Detail string       `json:"error"`
+		Fields []FieldError `json:"fields"`
+	}{alias: (*alias)(b)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Detail != "" {
+		b.Error = &Error{Status: b.Status, Code: aux.Code, Detail: aux.Detail, Fields: aux.Fields}
+	}
+
+	return nil
+}
+
+// RobotQuery фильтры, сортировка и страница списка роботов. Пустые поля не передаются.
+type RobotQuery struct {
+	Ticker       string
+	Sort         string
+	Desc         bool
+	User         *int
+	Parent       *int
+	ClonedFrom   *int
+	Template     *int
+	IsActive     *bool
+	IsFavourite  *bool //nolint:misspell
+	MinYield     *float64
+	MaxYield     *float64
+	MinBuyPrice  *float64
+	MaxBuyPrice  *float64
+	MinSellPrice *float64
+	MaxSellPrice *float64
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+	Limit        int
+	Cursor       string
+}
+
+// values возвращает параметры запроса списка роботов.
+func (q *RobotQuery) values() url.Values {
+	v := url.Values{}
+	if q == nil {
+		return v
+	}
+
+	setString(v, "ticker", q.Ticker)
+	setString(v, "sort", q.Sort)
+	setString(v, "cursor", q.Cursor)
+
+	if q.Desc {
+		v.Set("order", "desc")
+	}
+
+	for name, value := range map[string]*int{"user": q.User, "parent": q.Parent, "cloned_from": q.ClonedFrom,
+		"template": q.Template} {
+		if value != nil {
+			v.Set(name, strconv.Itoa(*value))
+		}
+	}
+
+	for name, value := range map[string]*bool{"is_active": q.IsActive, "is_favourite": q.IsFavourite} { //nolint:misspell
+		if value != nil {
+			v.Set(name, strconv.FormatBool(*value))
+		}
+	}
+
+	for name, value := range map[string]*float64{"min_yield": q.MinYield, "max_yield": q.MaxYield,
+		"min_buy_price": q.MinBuyPrice, "max_buy_price": q.MaxBuyPrice, "min_sell_price": q.MinSellPrice,
+		"max_sell_price": q.MaxSellPrice} {
+		if value != nil {
+			v.Set(name, strconv.FormatFloat(*value, 'f', -1, 64))
+		}
+	}
+
+	for name, value := range map[string]time.Time{"created_from": q.CreatedFrom, "created_to": q.CreatedTo} {
+		if !value.IsZero() {
+			v.Set(name, value.Format(time.RFC3339))
+		}
+	}
+
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	return v
+}
+
+func setString(v url.Values, name, value string) {
+	if value != "" {
+		v.Set(name, value)
+	}
+}
+
+// RobotPage страница списка роботов. NextCursor пуст на последней странице,
+// иначе его передают в RobotQuery.Cursor, чтобы получить следующую страницу.
+type RobotPage struct {
+	Robots     []Robot
+	NextCursor string
+}
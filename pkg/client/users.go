@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SignUp регистрирует пользователя.
+func (c *Client) SignUp(ctx context.Context, u UserParams) error {
+	_, err := c.do(ctx, call{method: http.MethodPost, path: "/signup", body: u, public: true})
+	return err
+}
+
+// SignIn входит пользователем и запоминает почту и пароль, чтобы входить заново, когда сессия истекает.
+func (c *Client) SignIn(ctx context.Context, email, password string) error {
+	var token struct {
+		Bearer string `json:"bearer"`
+	}
+
+	body := struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}{Email: email, Password: password}
+
+	if _, err := c.do(ctx, call{method: http.MethodPost, path: "/signin", body: body, out: &token, public: true}); err != nil {
+		return err
+	}
+
+	s, err := decodeToken(token.Bearer)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.token, c.session = token.Bearer, s
+	c.email, c.password = email, password
+	c.mutex.Unlock()
+
+	return nil
+}
+
+// User возвращает пользователя текущей сессии.
+func (c *Client) User(ctx context.Context) (*User, error) {
+	path, err := c.userPath(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var u User
+
+	header, err := c.do(ctx, call{method: http.MethodGet, path: path, out: &u})
+	if err != nil {
+		return nil, err
+	}
+
+	u.ETag = header.Get("ETag")
+
+	return &u, nil
+}
+
+// UpdateUser меняет данные пользователя текущей сессии. etag — User.ETag прочитанного пользователя:
+// если данные с тех пор изменили, возвращается ErrPreconditionFailed. Если меняется пароль, клиент запоминает новый.
+func (c *Client) UpdateUser(ctx context.Context, etag string, params UserParams) (*User, error) {
+	path, err := c.userPath(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var u User
+
+	header, err := c.do(ctx, call{method: http.MethodPut, path: path, body: params, out: &u, ifMatch: etag})
+	if err != nil {
+		return nil, err
+	}
+
+	u.ETag = header.Get("ETag")
+
+	c.mutex.Lock()
+	if c.email != "" {
+		c.email, c.password = params.Email, params.Password
+	}
+	c.mutex.Unlock()
+
+	return &u, nil
+}
+
+// OpenAPI возвращает документ OpenAPI сервиса.
+func (c *Client) OpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var doc json.RawMessage
+	_, err := c.do(ctx, call{method: http.MethodGet, path: "/openapi.json", out: &doc, public: true})
+
+	return doc, err
+}
+
+// userPath возвращает путь ресурса пользователя текущей сессии.
+func (c *Client) userPath(ctx context.Context, suffix string) (string, error) {
+	userID, err := c.sessionUserID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/users/%d%s", userID, suffix), nil
+}
+
+// sessionUserID возвращает ID пользователя текущей сессии. Если клиент еще не входил,
+// а почта и пароль заданы через SetCredentials, он сначала входит, чтобы узнать ID.
+func (c *Client) sessionUserID(ctx context.Context) (int, error) {
+	if _, err := c.authToken(ctx); err != nil {
+		return 0, err
+	}
+
+	return c.UserID(), nil
+}
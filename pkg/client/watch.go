@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// RobotWatch подписка на изменения робота через websocket.
+type RobotWatch struct {
+	conn    *websocket.Conn
+	updates chan Robot
+	done    chan struct{}
+
+	mutex sync.Mutex
+	err   error
+	once  sync.Once
+}
+
+// WatchRobot подписывается на изменения робота robotID. Для робота с доступом по ссылке
+// shareToken — токен из ссылки, иначе пустая строка. Сервис сразу присылает текущее состояние робота.
+// Подписка закрывается при отмене ctx или вызове Close.
+func (c *Client) WatchRobot(ctx context.Context, robotID int, shareToken string) (*RobotWatch, error) {
+	token, err := c.authToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u := *c.base
+	u.Path += apiPath + "/wsrobotdetail"
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		if resp != nil && resp.StatusCode >= http.StatusBadRequest {
+			return nil, decodeError(resp)
+		}
+
+		return nil, fmt.Errorf("can't connect to robot updates: %s", err)
+	}
+
+	msg := strconv.Itoa(robotID)
+	if shareToken != "" {
+		msg += " " + shareToken
+	}
+
+	if err = conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("can't subscribe to robot %d: %s", robotID, err)
+	}
+
+	w := &RobotWatch{conn: conn, updates: make(chan Robot), done: make(chan struct{})}
+
+	go w.read()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.Close()
+		case <-w.done:
+		}
+	}()
+
+	return w, nil
+}
+
+// Updates возвращает канал состояний робота. Канал закрывается, когда подписка завершается,
+// причину можно узнать через Err.
+func (w *RobotWatch) Updates() <-chan Robot {
+	return w.updates
+}
+
+// Err возвращает причину завершения подписки: ErrNotFound, если робот недоступен пользователю,
+// ошибку соединения или nil, если подписку закрыли.
+func (w *RobotWatch) Err() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.err
+}
+
+// Close закрывает подписку.
+func (w *RobotWatch) Close() error {
+	var err error
+
+	w.once.Do(func() {
+		close(w.done)
+		err = w.conn.Close()
+	})
+
+	return err
+}
+
+// read читает сообщения сервиса, пока соединение не закроется.
+func (w *RobotWatch) read() {
+	defer close(w.updates)
+	defer w.Close()
+
+	for {
+		_, msg, err := w.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-w.done:
+			default:
+				w.setErr(fmt.Errorf("robot updates: %s", err))
+			}
+
+			return
+		}
+
+		var failure struct {
+			Error string `json:"error"`
+		}
+
+		if err = json.Unmarshal(msg, &failure); err == nil && failure.Error != "" {
+			w.setErr(&Error{Status: http.StatusNotFound, Code: ErrNotFound.Code, Detail: failure.Error})
+			return
+		}
+
+		var rob Robot
+		if err = json.Unmarshal(msg, &rob); err != nil {
+			w.setErr(fmt.Errorf("can't decode robot update: %s", err))
+			return
+		}
+
+		select {
+		case w.updates <- rob:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *RobotWatch) setErr(err error) {
+	w.mutex.Lock()
+	w.err = err
+	w.mutex.Unlock()
+}
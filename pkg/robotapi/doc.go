@@ -0,0 +1,3 @@
+// Package robotapi gRPC API сервиса роботов: RobotService и UserService.
+// Код в robotapi.pb.go сгенерирован из robotapi.proto плагином protoc-gen-go с параметром plugins=grpc.
+package robotapi
@@ -0,0 +1,4342 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.22.0
+// 	protoc        v3.11.4
+// source: pkg/robotapi/robotapi.proto
+
+// Пакет robotapi повторяет REST API /api/v1 для внутренних сервисов. Все методы, кроме SignUp и SignIn,
+// требуют метаданные authorization со значением "Bearer <token>", токен тот же, что у REST API.
+
+package robotapi
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// Problem подробности ошибки в деталях статуса gRPC, code совпадает с кодом ошибки REST API.
+type Problem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code   string        `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Detail string        `protobuf:"bytes,2,opt,name=detail,proto3" json:"detail,omitempty"`
+	Fields []*FieldError `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
+}
+
+func (x *Problem) Reset() {
+	*x = Problem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Problem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Problem) ProtoMessage() {}
+
+func (x *Problem) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Problem.ProtoReflect.Descriptor instead.
+func (*Problem) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Problem) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Problem) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *Problem) GetFields() []*FieldError {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type FieldError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field   string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *FieldError) Reset() {
+	*x = FieldError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldError) ProtoMessage() {}
+
+func (x *FieldError) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldError.ProtoReflect.Descriptor instead.
+func (*FieldError) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FieldError) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type User struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName string `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName  string `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	// birthday дата в формате 2006-01-02, пустая, если не задана.
+	Birthday string `protobuf:"bytes,4,opt,name=birthday,proto3" json:"birthday,omitempty"`
+	Email    string `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	// version меняется при каждом изменении данных пользователя.
+	Version int64 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *User) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *User) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *User) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *User) GetBirthday() string {
+	if x != nil {
+		return x.Birthday
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type UserParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FirstName string `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName  string `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Birthday  string `protobuf:"bytes,3,opt,name=birthday,proto3" json:"birthday,omitempty"`
+	Email     string `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Password  string `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	// version версия прочитанного пользователя, обязательна для UpdateUser. SignUp ее не использует.
+	Version int64 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *UserParams) Reset() {
+	*x = UserParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserParams) ProtoMessage() {}
+
+func (x *UserParams) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserParams.ProtoReflect.Descriptor instead.
+func (*UserParams) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UserParams) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *UserParams) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *UserParams) GetBirthday() string {
+	if x != nil {
+		return x.Birthday
+	}
+	return ""
+}
+
+func (x *UserParams) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserParams) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *UserParams) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type SignInRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Email    string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *SignInRequest) Reset() {
+	*x = SignInRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignInRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignInRequest) ProtoMessage() {}
+
+func (x *SignInRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignInRequest.ProtoReflect.Descriptor instead.
+func (*SignInRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SignInRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SignInRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type SignInResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token  string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	UserId int64  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (x *SignInResponse) Reset() {
+	*x = SignInResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignInResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignInResponse) ProtoMessage() {}
+
+func (x *SignInResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignInResponse.ProtoReflect.Descriptor instead.
+func (*SignInResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SignInResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *SignInResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{6}
+}
+
+type Robot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId       int64                `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	OwnerUserId   int64                `protobuf:"varint,2,opt,name=owner_user_id,json=ownerUserId,proto3" json:"owner_user_id,omitempty"`
+	ParentRobotId int64                `protobuf:"varint,3,opt,name=parent_robot_id,json=parentRobotId,proto3" json:"parent_robot_id,omitempty"`
+	ClonedFromId  int64                `protobuf:"varint,4,opt,name=cloned_from_id,json=clonedFromId,proto3" json:"cloned_from_id,omitempty"`
+	TemplateId    int64                `protobuf:"varint,5,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	IsFavourite   bool                 `protobuf:"varint,6,opt,name=is_favourite,json=isFavourite,proto3" json:"is_favourite,omitempty"`
+	IsActive      bool                 `protobuf:"varint,7,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Status        string               `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	Ticker        string               `protobuf:"bytes,9,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	BuyPrice      float64              `protobuf:"fixed64,10,opt,name=buy_price,json=buyPrice,proto3" json:"buy_price,omitempty"`
+	SellPrice     float64              `protobuf:"fixed64,11,opt,name=sell_price,json=sellPrice,proto3" json:"sell_price,omitempty"`
+	PlanStart     *timestamp.Timestamp `protobuf:"bytes,12,opt,name=plan_start,json=planStart,proto3" json:"plan_start,omitempty"`
+	PlanEnd       *timestamp.Timestamp `protobuf:"bytes,13,opt,name=plan_end,json=planEnd,proto3" json:"plan_end,omitempty"`
+	PlanYield     float64              `protobuf:"fixed64,14,opt,name=plan_yield,json=planYield,proto3" json:"plan_yield,omitempty"`
+	FactYield     float64              `protobuf:"fixed64,15,opt,name=fact_yield,json=factYield,proto3" json:"fact_yield,omitempty"`
+	DealsCount    int64                `protobuf:"varint,16,opt,name=deals_count,json=dealsCount,proto3" json:"deals_count,omitempty"`
+	AutoClose     bool                 `protobuf:"varint,17,opt,name=auto_close,json=autoClose,proto3" json:"auto_close,omitempty"`
+	Schedule      string               `protobuf:"bytes,18,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Version       int64                `protobuf:"varint,19,opt,name=version,proto3" json:"version,omitempty"`
+	IsMirror      bool                 `protobuf:"varint,20,opt,name=is_mirror,json=isMirror,proto3" json:"is_mirror,omitempty"`
+	Lots          int64                `protobuf:"varint,21,opt,name=lots,proto3" json:"lots,omitempty"`
+	Followers     int64                `protobuf:"varint,22,opt,name=followers,proto3" json:"followers,omitempty"`
+	Visibility    string               `protobuf:"bytes,23,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	ActivatedAt   *timestamp.Timestamp `protobuf:"bytes,24,opt,name=activated_at,json=activatedAt,proto3" json:"activated_at,omitempty"`
+	DeactivatedAt *timestamp.Timestamp `protobuf:"bytes,25,opt,name=deactivated_at,json=deactivatedAt,proto3" json:"deactivated_at,omitempty"`
+	CreatedAt     *timestamp.Timestamp `protobuf:"bytes,26,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeletedAt     *timestamp.Timestamp `protobuf:"bytes,27,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// actions действия, которые сейчас допускает состояние робота.
+	Actions []string `protobuf:"bytes,28,rep,name=actions,proto3" json:"actions,omitempty"`
+	// revision меняется при каждом изменении робота, в отличие от version, которая считает изменения параметров.
+	Revision int64 `protobuf:"varint,29,opt,name=revision,proto3" json:"revision,omitempty"`
+}
+
+func (x *Robot) Reset() {
+	*x = Robot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Robot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Robot) ProtoMessage() {}
+
+func (x *Robot) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Robot.ProtoReflect.Descriptor instead.
+func (*Robot) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Robot) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *Robot) GetOwnerUserId() int64 {
+	if x != nil {
+		return x.OwnerUserId
+	}
+	return 0
+}
+
+func (x *Robot) GetParentRobotId() int64 {
+	if x != nil {
+		return x.ParentRobotId
+	}
+	return 0
+}
+
+func (x *Robot) GetClonedFromId() int64 {
+	if x != nil {
+		return x.ClonedFromId
+	}
+	return 0
+}
+
+func (x *Robot) GetTemplateId() int64 {
+	if x != nil {
+		return x.TemplateId
+	}
+	return 0
+}
+
+func (x *Robot) GetIsFavourite() bool {
+	if x != nil {
+		return x.IsFavourite
+	}
+	return false
+}
+
+func (x *Robot) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *Robot) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Robot) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *Robot) GetBuyPrice() float64 {
+	if x != nil {
+		return x.BuyPrice
+	}
+	return 0
+}
+
+func (x *Robot) GetSellPrice() float64 {
+	if x != nil {
+		return x.SellPrice
+	}
+	return 0
+}
+
+func (x *Robot) GetPlanStart() *timestamp.Timestamp {
+	if x != nil {
+		return x.PlanStart
+	}
+	return nil
+}
+
+func (x *Robot) GetPlanEnd() *timestamp.Timestamp {
+	if x != nil {
+		return x.PlanEnd
+	}
+	return nil
+}
+
+func (x *Robot) GetPlanYield() float64 {
+	if x != nil {
+		return x.PlanYield
+	}
+	return 0
+}
+
+func (x *Robot) GetFactYield() float64 {
+	if x != nil {
+		return x.FactYield
+	}
+	return 0
+}
+
+func (x *Robot) GetDealsCount() int64 {
+	if x != nil {
+		return x.DealsCount
+	}
+	return 0
+}
+
+func (x *Robot) GetAutoClose() bool {
+	if x != nil {
+		return x.AutoClose
+	}
+	return false
+}
+
+func (x *Robot) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *Robot) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Robot) GetIsMirror() bool {
+	if x != nil {
+		return x.IsMirror
+	}
+	return false
+}
+
+func (x *Robot) GetLots() int64 {
+	if x != nil {
+		return x.Lots
+	}
+	return 0
+}
+
+func (x *Robot) GetFollowers() int64 {
+	if x != nil {
+		return x.Followers
+	}
+	return 0
+}
+
+func (x *Robot) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *Robot) GetActivatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.ActivatedAt
+	}
+	return nil
+}
+
+func (x *Robot) GetDeactivatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.DeactivatedAt
+	}
+	return nil
+}
+
+func (x *Robot) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Robot) GetDeletedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Robot) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *Robot) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// RobotParams параметры робота, которые задает пользователь. Незаданные поля при создании получают
+// значения по умолчанию, а при изменении остаются прежними.
+type RobotParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker     *wrappers.StringValue `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	BuyPrice   *wrappers.DoubleValue `protobuf:"bytes,2,opt,name=buy_price,json=buyPrice,proto3" json:"buy_price,omitempty"`
+	SellPrice  *wrappers.DoubleValue `protobuf:"bytes,3,opt,name=sell_price,json=sellPrice,proto3" json:"sell_price,omitempty"`
+	PlanStart  *timestamp.Timestamp  `protobuf:"bytes,4,opt,name=plan_start,json=planStart,proto3" json:"plan_start,omitempty"`
+	PlanEnd    *timestamp.Timestamp  `protobuf:"bytes,5,opt,name=plan_end,json=planEnd,proto3" json:"plan_end,omitempty"`
+	PlanYield  *wrappers.DoubleValue `protobuf:"bytes,6,opt,name=plan_yield,json=planYield,proto3" json:"plan_yield,omitempty"`
+	AutoClose  *wrappers.BoolValue   `protobuf:"bytes,7,opt,name=auto_close,json=autoClose,proto3" json:"auto_close,omitempty"`
+	Schedule   *wrappers.StringValue `protobuf:"bytes,8,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Lots       *wrappers.Int64Value  `protobuf:"bytes,9,opt,name=lots,proto3" json:"lots,omitempty"`
+	Visibility *wrappers.StringValue `protobuf:"bytes,10,opt,name=visibility,proto3" json:"visibility,omitempty"`
+}
+
+func (x *RobotParams) Reset() {
+	*x = RobotParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RobotParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RobotParams) ProtoMessage() {}
+
+func (x *RobotParams) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RobotParams.ProtoReflect.Descriptor instead.
+func (*RobotParams) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RobotParams) GetTicker() *wrappers.StringValue {
+	if x != nil {
+		return x.Ticker
+	}
+	return nil
+}
+
+func (x *RobotParams) GetBuyPrice() *wrappers.DoubleValue {
+	if x != nil {
+		return x.BuyPrice
+	}
+	return nil
+}
+
+func (x *RobotParams) GetSellPrice() *wrappers.DoubleValue {
+	if x != nil {
+		return x.SellPrice
+	}
+	return nil
+}
+
+func (x *RobotParams) GetPlanStart() *timestamp.Timestamp {
+	if x != nil {
+		return x.PlanStart
+	}
+	return nil
+}
+
+func (x *RobotParams) GetPlanEnd() *timestamp.Timestamp {
+	if x != nil {
+		return x.PlanEnd
+	}
+	return nil
+}
+
+func (x *RobotParams) GetPlanYield() *wrappers.DoubleValue {
+	if x != nil {
+		return x.PlanYield
+	}
+	return nil
+}
+
+func (x *RobotParams) GetAutoClose() *wrappers.BoolValue {
+	if x != nil {
+		return x.AutoClose
+	}
+	return nil
+}
+
+func (x *RobotParams) GetSchedule() *wrappers.StringValue {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+func (x *RobotParams) GetLots() *wrappers.Int64Value {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+func (x *RobotParams) GetVisibility() *wrappers.StringValue {
+	if x != nil {
+		return x.Visibility
+	}
+	return nil
+}
+
+type RobotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId int64 `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+}
+
+func (x *RobotRequest) Reset() {
+	*x = RobotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RobotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RobotRequest) ProtoMessage() {}
+
+func (x *RobotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RobotRequest.ProtoReflect.Descriptor instead.
+func (*RobotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RobotRequest) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+type GetRobotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId int64 `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	// share_token токен из ссылки на робота с видимостью link.
+	ShareToken string `protobuf:"bytes,2,opt,name=share_token,json=shareToken,proto3" json:"share_token,omitempty"`
+}
+
+func (x *GetRobotRequest) Reset() {
+	*x = GetRobotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRobotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRobotRequest) ProtoMessage() {}
+
+func (x *GetRobotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRobotRequest.ProtoReflect.Descriptor instead.
+func (*GetRobotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetRobotRequest) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *GetRobotRequest) GetShareToken() string {
+	if x != nil {
+		return x.ShareToken
+	}
+	return ""
+}
+
+type EditRobotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId int64        `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	Params  *RobotParams `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	// revision ревизия прочитанного робота, обязательна для EditRobot. CloneRobot ее не использует.
+	Revision int64 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+}
+
+func (x *EditRobotRequest) Reset() {
+	*x = EditRobotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EditRobotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditRobotRequest) ProtoMessage() {}
+
+func (x *EditRobotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditRobotRequest.ProtoReflect.Descriptor instead.
+func (*EditRobotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EditRobotRequest) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *EditRobotRequest) GetParams() *RobotParams {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *EditRobotRequest) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// FavouriteRobotRequest mirror включает повторение сделок робота в размере lots лотов.
+type FavouriteRobotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId    int64  `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	Mirror     bool   `protobuf:"varint,2,opt,name=mirror,proto3" json:"mirror,omitempty"`
+	Lots       int64  `protobuf:"varint,3,opt,name=lots,proto3" json:"lots,omitempty"`
+	ShareToken string `protobuf:"bytes,4,opt,name=share_token,json=shareToken,proto3" json:"share_token,omitempty"`
+}
+
+func (x *FavouriteRobotRequest) Reset() {
+	*x = FavouriteRobotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FavouriteRobotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FavouriteRobotRequest) ProtoMessage() {}
+
+func (x *FavouriteRobotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FavouriteRobotRequest.ProtoReflect.Descriptor instead.
+func (*FavouriteRobotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FavouriteRobotRequest) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *FavouriteRobotRequest) GetMirror() bool {
+	if x != nil {
+		return x.Mirror
+	}
+	return false
+}
+
+func (x *FavouriteRobotRequest) GetLots() int64 {
+	if x != nil {
+		return x.Lots
+	}
+	return 0
+}
+
+func (x *FavouriteRobotRequest) GetShareToken() string {
+	if x != nil {
+		return x.ShareToken
+	}
+	return ""
+}
+
+type ShareRobotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId int64 `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	UserId  int64 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (x *ShareRobotRequest) Reset() {
+	*x = ShareRobotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShareRobotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareRobotRequest) ProtoMessage() {}
+
+func (x *ShareRobotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareRobotRequest.ProtoReflect.Descriptor instead.
+func (*ShareRobotRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ShareRobotRequest) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *ShareRobotRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type PurgeRobotResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PurgeRobotResponse) Reset() {
+	*x = PurgeRobotResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeRobotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeRobotResponse) ProtoMessage() {}
+
+func (x *PurgeRobotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeRobotResponse.ProtoReflect.Descriptor instead.
+func (*PurgeRobotResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{14}
+}
+
+// ListRobotsRequest фильтры, сортировка и страница, как в query-параметрах GET /api/v1/robots.
+// mine выбирает только роботов пользователя текущей сессии.
+type ListRobotsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mine          bool                  `protobuf:"varint,1,opt,name=mine,proto3" json:"mine,omitempty"`
+	Ticker        string                `protobuf:"bytes,2,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	OwnerUserId   *wrappers.Int64Value  `protobuf:"bytes,3,opt,name=owner_user_id,json=ownerUserId,proto3" json:"owner_user_id,omitempty"`
+	ParentRobotId *wrappers.Int64Value  `protobuf:"bytes,4,opt,name=parent_robot_id,json=parentRobotId,proto3" json:"parent_robot_id,omitempty"`
+	IsActive      *wrappers.BoolValue   `protobuf:"bytes,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	IsFavourite   *wrappers.BoolValue   `protobuf:"bytes,6,opt,name=is_favourite,json=isFavourite,proto3" json:"is_favourite,omitempty"`
+	MinYield      *wrappers.DoubleValue `protobuf:"bytes,7,opt,name=min_yield,json=minYield,proto3" json:"min_yield,omitempty"`
+	MaxYield      *wrappers.DoubleValue `protobuf:"bytes,8,opt,name=max_yield,json=maxYield,proto3" json:"max_yield,omitempty"`
+	Sort          string                `protobuf:"bytes,9,opt,name=sort,proto3" json:"sort,omitempty"`
+	Desc          bool                  `protobuf:"varint,10,opt,name=desc,proto3" json:"desc,omitempty"`
+	Limit         int64                 `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                `protobuf:"bytes,12,opt,name=cursor,proto3" json:"cursor,omitempty"`
+}
+
+func (x *ListRobotsRequest) Reset() {
+	*x = ListRobotsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRobotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRobotsRequest) ProtoMessage() {}
+
+func (x *ListRobotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRobotsRequest.ProtoReflect.Descriptor instead.
+func (*ListRobotsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListRobotsRequest) GetMine() bool {
+	if x != nil {
+		return x.Mine
+	}
+	return false
+}
+
+func (x *ListRobotsRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *ListRobotsRequest) GetOwnerUserId() *wrappers.Int64Value {
+	if x != nil {
+		return x.OwnerUserId
+	}
+	return nil
+}
+
+func (x *ListRobotsRequest) GetParentRobotId() *wrappers.Int64Value {
+	if x != nil {
+		return x.ParentRobotId
+	}
+	return nil
+}
+
+func (x *ListRobotsRequest) GetIsActive() *wrappers.BoolValue {
+	if x != nil {
+		return x.IsActive
+	}
+	return nil
+}
+
+func (x *ListRobotsRequest) GetIsFavourite() *wrappers.BoolValue {
+	if x != nil {
+		return x.IsFavourite
+	}
+	return nil
+}
+
+func (x *ListRobotsRequest) GetMinYield() *wrappers.DoubleValue {
+	if x != nil {
+		return x.MinYield
+	}
+	return nil
+}
+
+func (x *ListRobotsRequest) GetMaxYield() *wrappers.DoubleValue {
+	if x != nil {
+		return x.MaxYield
+	}
+	return nil
+}
+
+func (x *ListRobotsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListRobotsRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *ListRobotsRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRobotsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListDeletedRobotsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListDeletedRobotsRequest) Reset() {
+	*x = ListDeletedRobotsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeletedRobotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeletedRobotsRequest) ProtoMessage() {}
+
+func (x *ListDeletedRobotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeletedRobotsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeletedRobotsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{16}
+}
+
+type ListRobotsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Robots []*Robot `protobuf:"bytes,1,rep,name=robots,proto3" json:"robots,omitempty"`
+	// next_cursor курсор следующей страницы, пустой на последней странице.
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+}
+
+func (x *ListRobotsResponse) Reset() {
+	*x = ListRobotsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRobotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRobotsResponse) ProtoMessage() {}
+
+func (x *ListRobotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRobotsResponse.ProtoReflect.Descriptor instead.
+func (*ListRobotsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListRobotsResponse) GetRobots() []*Robot {
+	if x != nil {
+		return x.Robots
+	}
+	return nil
+}
+
+func (x *ListRobotsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type LeaderboardRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metric string `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	Period string `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	Limit  int64  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *LeaderboardRequest) Reset() {
+	*x = LeaderboardRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaderboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderboardRequest) ProtoMessage() {}
+
+func (x *LeaderboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderboardRequest.ProtoReflect.Descriptor instead.
+func (*LeaderboardRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LeaderboardRequest) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *LeaderboardRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *LeaderboardRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type LeaderboardResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stats []*Stats `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+}
+
+func (x *LeaderboardResponse) Reset() {
+	*x = LeaderboardResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaderboardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderboardResponse) ProtoMessage() {}
+
+func (x *LeaderboardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderboardResponse.ProtoReflect.Descriptor instead.
+func (*LeaderboardResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *LeaderboardResponse) GetStats() []*Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type RobotStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId    int64  `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	Period     string `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	ShareToken string `protobuf:"bytes,3,opt,name=share_token,json=shareToken,proto3" json:"share_token,omitempty"`
+}
+
+func (x *RobotStatsRequest) Reset() {
+	*x = RobotStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RobotStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RobotStatsRequest) ProtoMessage() {}
+
+func (x *RobotStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RobotStatsRequest.ProtoReflect.Descriptor instead.
+func (*RobotStatsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RobotStatsRequest) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *RobotStatsRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *RobotStatsRequest) GetShareToken() string {
+	if x != nil {
+		return x.ShareToken
+	}
+	return ""
+}
+
+type Stats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId     int64   `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	OwnerUserId int64   `protobuf:"varint,2,opt,name=owner_user_id,json=ownerUserId,proto3" json:"owner_user_id,omitempty"`
+	Ticker      string  `protobuf:"bytes,3,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Yield       float64 `protobuf:"fixed64,4,opt,name=yield,proto3" json:"yield,omitempty"`
+	Sharpe      float64 `protobuf:"fixed64,5,opt,name=sharpe,proto3" json:"sharpe,omitempty"`
+	Deals       int64   `protobuf:"varint,6,opt,name=deals,proto3" json:"deals,omitempty"`
+	Drawdown    float64 `protobuf:"fixed64,7,opt,name=drawdown,proto3" json:"drawdown,omitempty"`
+	Followers   int64   `protobuf:"varint,8,opt,name=followers,proto3" json:"followers,omitempty"`
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Stats) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *Stats) GetOwnerUserId() int64 {
+	if x != nil {
+		return x.OwnerUserId
+	}
+	return 0
+}
+
+func (x *Stats) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *Stats) GetYield() float64 {
+	if x != nil {
+		return x.Yield
+	}
+	return 0
+}
+
+func (x *Stats) GetSharpe() float64 {
+	if x != nil {
+		return x.Sharpe
+	}
+	return 0
+}
+
+func (x *Stats) GetDeals() int64 {
+	if x != nil {
+		return x.Deals
+	}
+	return 0
+}
+
+func (x *Stats) GetDrawdown() float64 {
+	if x != nil {
+		return x.Drawdown
+	}
+	return 0
+}
+
+func (x *Stats) GetFollowers() int64 {
+	if x != nil {
+		return x.Followers
+	}
+	return 0
+}
+
+type Shares struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Visibility string  `protobuf:"bytes,1,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	ShareLink  string  `protobuf:"bytes,2,opt,name=share_link,json=shareLink,proto3" json:"share_link,omitempty"`
+	Users      []int64 `protobuf:"varint,3,rep,packed,name=users,proto3" json:"users,omitempty"`
+}
+
+func (x *Shares) Reset() {
+	*x = Shares{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Shares) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shares) ProtoMessage() {}
+
+func (x *Shares) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shares.ProtoReflect.Descriptor instead.
+func (*Shares) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Shares) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *Shares) GetShareLink() string {
+	if x != nil {
+		return x.ShareLink
+	}
+	return ""
+}
+
+func (x *Shares) GetUsers() []int64 {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type Transition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	RobotId    int64                `protobuf:"varint,2,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	Action     string               `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	FromStatus string               `protobuf:"bytes,4,opt,name=from_status,json=fromStatus,proto3" json:"from_status,omitempty"`
+	ToStatus   string               `protobuf:"bytes,5,opt,name=to_status,json=toStatus,proto3" json:"to_status,omitempty"`
+	Source     string               `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	CreatedAt  *timestamp.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Transition) Reset() {
+	*x = Transition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Transition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transition) ProtoMessage() {}
+
+func (x *Transition) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transition.ProtoReflect.Descriptor instead.
+func (*Transition) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Transition) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Transition) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *Transition) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Transition) GetFromStatus() string {
+	if x != nil {
+		return x.FromStatus
+	}
+	return ""
+}
+
+func (x *Transition) GetToStatus() string {
+	if x != nil {
+		return x.ToStatus
+	}
+	return ""
+}
+
+func (x *Transition) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Transition) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type RobotTransitionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Transitions []*Transition `protobuf:"bytes,1,rep,name=transitions,proto3" json:"transitions,omitempty"`
+}
+
+func (x *RobotTransitionsResponse) Reset() {
+	*x = RobotTransitionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RobotTransitionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RobotTransitionsResponse) ProtoMessage() {}
+
+func (x *RobotTransitionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RobotTransitionsResponse.ProtoReflect.Descriptor instead.
+func (*RobotTransitionsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RobotTransitionsResponse) GetTransitions() []*Transition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+type Version struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RobotId   int64                `protobuf:"varint,1,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	Version   int64                `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Ticker    string               `protobuf:"bytes,3,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	BuyPrice  float64              `protobuf:"fixed64,4,opt,name=buy_price,json=buyPrice,proto3" json:"buy_price,omitempty"`
+	SellPrice float64              `protobuf:"fixed64,5,opt,name=sell_price,json=sellPrice,proto3" json:"sell_price,omitempty"`
+	PlanStart *timestamp.Timestamp `protobuf:"bytes,6,opt,name=plan_start,json=planStart,proto3" json:"plan_start,omitempty"`
+	PlanEnd   *timestamp.Timestamp `protobuf:"bytes,7,opt,name=plan_end,json=planEnd,proto3" json:"plan_end,omitempty"`
+	PlanYield float64              `protobuf:"fixed64,8,opt,name=plan_yield,json=planYield,proto3" json:"plan_yield,omitempty"`
+	AutoClose bool                 `protobuf:"varint,9,opt,name=auto_close,json=autoClose,proto3" json:"auto_close,omitempty"`
+	Schedule  string               `protobuf:"bytes,10,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Lots      int64                `protobuf:"varint,11,opt,name=lots,proto3" json:"lots,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Version) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *Version) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Version) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *Version) GetBuyPrice() float64 {
+	if x != nil {
+		return x.BuyPrice
+	}
+	return 0
+}
+
+func (x *Version) GetSellPrice() float64 {
+	if x != nil {
+		return x.SellPrice
+	}
+	return 0
+}
+
+func (x *Version) GetPlanStart() *timestamp.Timestamp {
+	if x != nil {
+		return x.PlanStart
+	}
+	return nil
+}
+
+func (x *Version) GetPlanEnd() *timestamp.Timestamp {
+	if x != nil {
+		return x.PlanEnd
+	}
+	return nil
+}
+
+func (x *Version) GetPlanYield() float64 {
+	if x != nil {
+		return x.PlanYield
+	}
+	return 0
+}
+
+func (x *Version) GetAutoClose() bool {
+	if x != nil {
+		return x.AutoClose
+	}
+	return false
+}
+
+func (x *Version) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *Version) GetLots() int64 {
+	if x != nil {
+		return x.Lots
+	}
+	return 0
+}
+
+func (x *Version) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type RobotVersionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Versions []*Version `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+}
+
+func (x *RobotVersionsResponse) Reset() {
+	*x = RobotVersionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RobotVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RobotVersionsResponse) ProtoMessage() {}
+
+func (x *RobotVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RobotVersionsResponse.ProtoReflect.Descriptor instead.
+func (*RobotVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RobotVersionsResponse) GetVersions() []*Version {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type Deal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	RobotId   int64                `protobuf:"varint,2,opt,name=robot_id,json=robotId,proto3" json:"robot_id,omitempty"`
+	Version   int64                `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Side      string               `protobuf:"bytes,4,opt,name=side,proto3" json:"side,omitempty"`
+	Price     float64              `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Lots      int64                `protobuf:"varint,6,opt,name=lots,proto3" json:"lots,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Deal) Reset() {
+	*x = Deal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Deal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deal) ProtoMessage() {}
+
+func (x *Deal) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deal.ProtoReflect.Descriptor instead.
+func (*Deal) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Deal) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Deal) GetRobotId() int64 {
+	if x != nil {
+		return x.RobotId
+	}
+	return 0
+}
+
+func (x *Deal) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Deal) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Deal) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Deal) GetLots() int64 {
+	if x != nil {
+		return x.Lots
+	}
+	return 0
+}
+
+func (x *Deal) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type RobotDealsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deals []*Deal `protobuf:"bytes,1,rep,name=deals,proto3" json:"deals,omitempty"`
+}
+
+func (x *RobotDealsResponse) Reset() {
+	*x = RobotDealsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_robotapi_robotapi_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RobotDealsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RobotDealsResponse) ProtoMessage() {}
+
+func (x *RobotDealsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_robotapi_robotapi_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RobotDealsResponse.ProtoReflect.Descriptor instead.
+func (*RobotDealsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_robotapi_robotapi_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RobotDealsResponse) GetDeals() []*Deal {
+	if x != nil {
+		return x.Deals
+	}
+	return nil
+}
+
+var File_pkg_robotapi_robotapi_proto protoreflect.FileDescriptor
+
+var file_pkg_robotapi_robotapi_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x70, 0x6b, 0x67, 0x2f, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2f, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65,
+	0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x63, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x62,
+	0x6c, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12,
+	0x2c, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0x3c, 0x0a,
+	0x0a, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9e, 0x01, 0x0a, 0x04,
+	0x55, 0x73, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x62, 0x69, 0x72, 0x74, 0x68, 0x64, 0x61, 0x79, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x62, 0x69, 0x72, 0x74, 0x68, 0x64, 0x61, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61,
+	0x69, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xb0, 0x01, 0x0a,
+	0x0a, 0x55, 0x73, 0x65, 0x72, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c,
+	0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x69, 0x72, 0x74, 0x68,
+	0x64, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x69, 0x72, 0x74, 0x68,
+	0x64, 0x61, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22,
+	0x41, 0x0a, 0x0d, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x22, 0x3f, 0x0a, 0x0e, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x22, 0x10, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xa4, 0x08, 0x0a, 0x05, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x26,
+	0x0a, 0x0f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x52,
+	0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x0e, 0x63, 0x6c, 0x6f, 0x6e, 0x65, 0x64,
+	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c,
+	0x63, 0x6c, 0x6f, 0x6e, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b,
+	0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x49, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x69, 0x73, 0x5f, 0x66, 0x61, 0x76, 0x6f, 0x75, 0x72, 0x69, 0x74, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x73, 0x46, 0x61, 0x76, 0x6f, 0x75, 0x72, 0x69, 0x74, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x1b, 0x0a,
+	0x09, 0x62, 0x75, 0x79, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x08, 0x62, 0x75, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65,
+	0x6c, 0x6c, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09,
+	0x73, 0x65, 0x6c, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x70, 0x6c, 0x61,
+	0x6e, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x6e, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x65, 0x6e, 0x64,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x6e, 0x45, 0x6e, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x6c, 0x61, 0x6e, 0x5f, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x70, 0x6c, 0x61, 0x6e, 0x59, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x61,
+	0x63, 0x74, 0x5f, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09,
+	0x66, 0x61, 0x63, 0x74, 0x59, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x61,
+	0x6c, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x64, 0x65, 0x61, 0x6c, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75,
+	0x74, 0x6f, 0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x61, 0x75, 0x74, 0x6f, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x13, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04,
+	0x6c, 0x6f, 0x74, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6c, 0x6f, 0x74, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x73, 0x18, 0x16, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x17, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x3d,
+	0x0a, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x18,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x41, 0x0a,
+	0x0e, 0x64, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0d, 0x64, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x1a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x64,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x64, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x1c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x1d, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xce, 0x04, 0x0a,
+	0x0b, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x34, 0x0a, 0x06,
+	0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b,
+	0x65, 0x72, 0x12, 0x39, 0x0a, 0x09, 0x62, 0x75, 0x79, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x52, 0x08, 0x62, 0x75, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a,
+	0x0a, 0x73, 0x65, 0x6c, 0x6c, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
+	0x09, 0x73, 0x65, 0x6c, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x70, 0x6c,
+	0x61, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x6e,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x65, 0x6e,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x6e, 0x45, 0x6e, 0x64, 0x12, 0x3b, 0x0a, 0x0a,
+	0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x09,
+	0x70, 0x6c, 0x61, 0x6e, 0x59, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x39, 0x0a, 0x0a, 0x61, 0x75, 0x74,
+	0x6f, 0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x42, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x09, 0x61, 0x75, 0x74, 0x6f, 0x43,
+	0x6c, 0x6f, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x2f,
+	0x0a, 0x04, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x49,
+	0x6e, 0x74, 0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x04, 0x6c, 0x6f, 0x74, 0x73, 0x12,
+	0x3c, 0x0a, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22, 0x29, 0x0a,
+	0x0c, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x22, 0x4d, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x52,
+	0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x68, 0x61,
+	0x72, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x78, 0x0a, 0x10, 0x45, 0x64, 0x69, 0x74, 0x52,
+	0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x2d, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x7f, 0x0a, 0x15, 0x46, 0x61, 0x76, 0x6f, 0x75, 0x72, 0x69, 0x74, 0x65, 0x52, 0x6f,
+	0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6c, 0x6f, 0x74,
+	0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x22, 0x47, 0x0a, 0x11, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x62, 0x6f, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x6f, 0x62, 0x6f, 0x74,
+	0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x14, 0x0a, 0x12, 0x50,
+	0x75, 0x72, 0x67, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x89, 0x04, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x69, 0x6e, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6d, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74,
+	0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63,
+	0x6b, 0x65, 0x72, 0x12, 0x3f, 0x0a, 0x0d, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x49, 0x6e, 0x74,
+	0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x55, 0x73,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x43, 0x0a, 0x0f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x49, 0x6e, 0x74, 0x36, 0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0d, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x37, 0x0a, 0x09, 0x69, 0x73, 0x5f,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x42,
+	0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x08, 0x69, 0x73, 0x41, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x69, 0x73, 0x5f, 0x66, 0x61, 0x76, 0x6f, 0x75, 0x72, 0x69,
+	0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x42, 0x6f, 0x6f, 0x6c, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x69, 0x73, 0x46, 0x61, 0x76, 0x6f, 0x75, 0x72, 0x69, 0x74,
+	0x65, 0x12, 0x39, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x59, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x39, 0x0a, 0x09,
+	0x6d, 0x61, 0x78, 0x5f, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x08, 0x6d,
+	0x61, 0x78, 0x59, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x65, 0x73, 0x63, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0x1a, 0x0a,
+	0x18, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x52, 0x6f, 0x62, 0x6f,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x5e, 0x0a, 0x12, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x27, 0x0a, 0x06, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x52, 0x06, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74,
+	0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e,
+	0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x22, 0x5a, 0x0a, 0x12, 0x4c, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x3c, 0x0a, 0x13, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62,
+	0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x22, 0x67, 0x0a, 0x11, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x62, 0x6f,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x6f, 0x62, 0x6f,
+	0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xdc, 0x01, 0x0a,
+	0x05, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x49,
+	0x64, 0x12, 0x22, 0x0a, 0x0d, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x55,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x14, 0x0a,
+	0x05, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x79, 0x69,
+	0x65, 0x6c, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x72, 0x70, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x06, 0x73, 0x68, 0x61, 0x72, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x64,
+	0x65, 0x61, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x64, 0x65, 0x61, 0x6c,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x72, 0x61, 0x77, 0x64, 0x6f, 0x77, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x08, 0x64, 0x72, 0x61, 0x77, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x1c, 0x0a,
+	0x09, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x73, 0x22, 0x5d, 0x0a, 0x06, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x6c,
+	0x69, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x03, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0xe0, 0x01, 0x0a, 0x0a, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x62,
+	0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x6f, 0x62,
+	0x6f, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b,
+	0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x66, 0x72, 0x6f, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a,
+	0x09, 0x74, 0x6f, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x74, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x52, 0x0a,
+	0x18, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0xad, 0x03, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a,
+	0x08, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x75,
+	0x79, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x62,
+	0x75, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x6c, 0x6c, 0x5f,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x73, 0x65, 0x6c,
+	0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x6e, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x07, 0x70, 0x6c, 0x61, 0x6e, 0x45, 0x6e, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x6c, 0x61, 0x6e,
+	0x5f, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x70, 0x6c,
+	0x61, 0x6e, 0x59, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x74, 0x6f, 0x5f,
+	0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x75, 0x74,
+	0x6f, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x6c, 0x6f, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x22, 0x46, 0x0a, 0x15, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xc4, 0x01, 0x0a, 0x04, 0x44, 0x65,
+	0x61, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x64, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x69, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x6c, 0x6f, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x3a, 0x0a, 0x12, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x44, 0x65, 0x61, 0x6c, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x05, 0x64, 0x65, 0x61, 0x6c, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x44, 0x65, 0x61, 0x6c, 0x52, 0x05, 0x64, 0x65, 0x61, 0x6c, 0x73, 0x32, 0xe3, 0x01, 0x0a,
+	0x0b, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2e, 0x0a, 0x06,
+	0x53, 0x69, 0x67, 0x6e, 0x55, 0x70, 0x12, 0x14, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0e, 0x2e, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x12, 0x3b, 0x0a, 0x06,
+	0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x12, 0x17, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x18, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x49,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x47, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e,
+	0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x12, 0x32,
+	0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x14, 0x2e, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x1a, 0x0e, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x32, 0xc8, 0x0b, 0x0a, 0x0c, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x73, 0x12, 0x1b, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f,
+	0x62, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x11,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x73, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x61,
+	0x72, 0x64, 0x12, 0x1c, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1d, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x35, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x15,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x50,
+	0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x36, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x62,
+	0x6f, 0x74, 0x12, 0x19, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65,
+	0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e,
+	0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x38,
+	0x0a, 0x09, 0x45, 0x64, 0x69, 0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x1a, 0x2e, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x45, 0x64, 0x69, 0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x16, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x12, 0x37, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x12, 0x16, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74,
+	0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x42, 0x0a, 0x0a, 0x50, 0x75, 0x72,
+	0x67, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x16, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61,
+	0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65,
+	0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a,
+	0x0d, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x16,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x3a, 0x0a, 0x0f, 0x44, 0x65, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x16, 0x2e, 0x72, 0x6f, 0x62,
+	0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f,
+	0x62, 0x6f, 0x74, 0x12, 0x34, 0x0a, 0x09, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x6f, 0x62, 0x6f, 0x74,
+	0x12, 0x16, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74,
+	0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x42, 0x0a, 0x0e, 0x46, 0x61, 0x76,
+	0x6f, 0x75, 0x72, 0x69, 0x74, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x1f, 0x2e, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x61, 0x76, 0x6f, 0x75, 0x72, 0x69, 0x74, 0x65,
+	0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72,
+	0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x38, 0x0a,
+	0x0d, 0x55, 0x6e, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x16,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x43, 0x6c, 0x6f, 0x6e, 0x65,
+	0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12, 0x1a, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x64, 0x69, 0x74, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62,
+	0x6f, 0x74, 0x12, 0x4e, 0x0a, 0x10, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70,
+	0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x48, 0x0a, 0x0d, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x16, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52,
+	0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0a,
+	0x52, 0x6f, 0x62, 0x6f, 0x74, 0x44, 0x65, 0x61, 0x6c, 0x73, 0x12, 0x16, 0x2e, 0x72, 0x6f, 0x62,
+	0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f,
+	0x62, 0x6f, 0x74, 0x44, 0x65, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3a, 0x0a, 0x0a, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1b,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x37, 0x0a, 0x0b,
+	0x52, 0x6f, 0x62, 0x6f, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x0a, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x6f,
+	0x62, 0x6f, 0x74, 0x12, 0x1b, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x10, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x73, 0x12, 0x3d, 0x0a, 0x0c, 0x55, 0x6e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x52, 0x6f, 0x62,
+	0x6f, 0x74, 0x12, 0x1b, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x10, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65,
+	0x73, 0x12, 0x3a, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x12,
+	0x19, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f,
+	0x62, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x6f, 0x62,
+	0x6f, 0x74, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x30, 0x01, 0x42, 0x3c, 0x5a,
+	0x3a, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x68, 0x69, 0x74, 0x63,
+	0x68, 0x70, 0x6f, 0x63, 0x6b, 0x2f, 0x74, 0x66, 0x73, 0x2d, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x2d, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61,
+	0x70, 0x69, 0x3b, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_robotapi_robotapi_proto_rawDescOnce sync.Once
+	file_pkg_robotapi_robotapi_proto_rawDescData = file_pkg_robotapi_robotapi_proto_rawDesc
+)
+
+func file_pkg_robotapi_robotapi_proto_rawDescGZIP() []byte {
+	file_pkg_robotapi_robotapi_proto_rawDescOnce.Do(func() {
+		file_pkg_robotapi_robotapi_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_robotapi_robotapi_proto_rawDescData)
+	})
+	return file_pkg_robotapi_robotapi_proto_rawDescData
+}
+
+var file_pkg_robotapi_robotapi_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_pkg_robotapi_robotapi_proto_goTypes = []interface{}{
+	(*Problem)(nil),                  // 0: robotapi.Problem
+	(*FieldError)(nil),               // 1: robotapi.FieldError
+	(*User)(nil),                     // 2: robotapi.User
+	(*UserParams)(nil),               // 3: robotapi.UserParams
+	(*SignInRequest)(nil),            // 4: robotapi.SignInRequest
+	(*SignInResponse)(nil),           // 5: robotapi.SignInResponse
+	(*GetUserRequest)(nil),           // 6: robotapi.GetUserRequest
+	(*Robot)(nil),                    // 7: robotapi.Robot
+	(*RobotParams)(nil),              // 8: robotapi.RobotParams
+	(*RobotRequest)(nil),             // 9: robotapi.RobotRequest
+	(*GetRobotRequest)(nil),          // 10: robotapi.GetRobotRequest
+	(*EditRobotRequest)(nil),         // 11: robotapi.EditRobotRequest
+	(*FavouriteRobotRequest)(nil),    // 12: robotapi.FavouriteRobotRequest
+	(*ShareRobotRequest)(nil),        // 13: robotapi.ShareRobotRequest
+	(*PurgeRobotResponse)(nil),       // 14: robotapi.PurgeRobotResponse
+	(*ListRobotsRequest)(nil),        // 15: robotapi.ListRobotsRequest
+	(*ListDeletedRobotsRequest)(nil), // 16: robotapi.ListDeletedRobotsRequest
+	(*ListRobotsResponse)(nil),       // 17: robotapi.ListRobotsResponse
+	(*LeaderboardRequest)(nil),       // 18: robotapi.LeaderboardRequest
+	(*LeaderboardResponse)(nil),      // 19: robotapi.LeaderboardResponse
+	(*RobotStatsRequest)(nil),        // 20: robotapi.RobotStatsRequest
+	(*Stats)(nil),                    // 21: robotapi.Stats
+	(*Shares)(nil),                   // 22: robotapi.Shares
+	(*Transition)(nil),               // 23: robotapi.Transition
+	(*RobotTransitionsResponse)(nil), // 24: robotapi.RobotTransitionsResponse
+	(*Version)(nil),                  // 25: robotapi.Version
+	(*RobotVersionsResponse)(nil),    // 26: robotapi.RobotVersionsResponse
+	(*Deal)(nil),                     // 27: robotapi.Deal
+	(*RobotDealsResponse)(nil),       // 28: robotapi.RobotDealsResponse
+	(*timestamp.Timestamp)(nil),      // 29: google.protobuf.Timestamp
+	(*wrappers.StringValue)(nil),     // 30: google.protobuf.StringValue
+	(*wrappers.DoubleValue)(nil),     // 31: google.protobuf.DoubleValue
+	(*wrappers.BoolValue)(nil),       // 32: google.protobuf.BoolValue
+	(*wrappers.Int64Value)(nil),      // 33: google.protobuf.Int64Value
+}
+var file_pkg_robotapi_robotapi_proto_depIdxs = []int32{
+	1,  // 0: robotapi.Problem.fields:type_name -> robotapi.FieldError
+	29, // 1: robotapi.Robot.plan_start:type_name -> google.protobuf.Timestamp
+	29, // 2: robotapi.Robot.plan_end:type_name -> google.protobuf.Timestamp
+	29, // 3: robotapi.Robot.activated_at:type_name -> google.protobuf.Timestamp
+	29, // 4: robotapi.Robot.deactivated_at:type_name -> google.protobuf.Timestamp
+	29, // 5: robotapi.Robot.created_at:type_name -> google.protobuf.Timestamp
+	29, // 6: robotapi.Robot.deleted_at:type_name -> google.protobuf.Timestamp
+	30, // 7: robotapi.RobotParams.ticker:type_name -> google.protobuf.StringValue
+	31, // 8: robotapi.RobotParams.buy_price:type_name -> google.protobuf.DoubleValue
+	31, // 9: robotapi.RobotParams.sell_price:type_name -> google.protobuf.DoubleValue
+	29, // 10: robotapi.RobotParams.plan_start:type_name -> google.protobuf.Timestamp
+	29, // 11: robotapi.RobotParams.plan_end:type_name -> google.protobuf.Timestamp
+	31, // 12: robotapi.RobotParams.plan_yield:type_name -> google.protobuf.DoubleValue
+	32, // 13: robotapi.RobotParams.auto_close:type_name -> google.protobuf.BoolValue
+	30, // 14: robotapi.RobotParams.schedule:type_name -> google.protobuf.StringValue
+	33, // 15: robotapi.RobotParams.lots:type_name -> google.protobuf.Int64Value
+	30, // 16: robotapi.RobotParams.visibility:type_name -> google.protobuf.StringValue
+	8,  // 17: robotapi.EditRobotRequest.params:type_name -> robotapi.RobotParams
+	33, // 18: robotapi.ListRobotsRequest.owner_user_id:type_name -> google.protobuf.Int64Value
+	33, // 19: robotapi.ListRobotsRequest.parent_robot_id:type_name -> google.protobuf.Int64Value
+	32, // 20: robotapi.ListRobotsRequest.is_active:type_name -> google.protobuf.BoolValue
+	32, // 21: robotapi.ListRobotsRequest.is_favourite:type_name -> google.protobuf.BoolValue
+	31, // 22: robotapi.ListRobotsRequest.min_yield:type_name -> google.protobuf.DoubleValue
+	31, // 23: robotapi.ListRobotsRequest.max_yield:type_name -> google.protobuf.DoubleValue
+	7,  // 24: robotapi.ListRobotsResponse.robots:type_name -> robotapi.Robot
+	21, // 25: robotapi.LeaderboardResponse.stats:type_name -> robotapi.Stats
+	29, // 26: robotapi.Transition.created_at:type_name -> google.protobuf.Timestamp
+	23, // 27: robotapi.RobotTransitionsResponse.transitions:type_name -> robotapi.Transition
+	29, // 28: robotapi.Version.plan_start:type_name -> google.protobuf.Timestamp
+	29, // 29: robotapi.Version.plan_end:type_name -> google.protobuf.Timestamp
+	29, // 30: robotapi.Version.created_at:type_name -> google.protobuf.Timestamp
+	25, // 31: robotapi.RobotVersionsResponse.versions:type_name -> robotapi.Version
+	29, // 32: robotapi.Deal.created_at:type_name -> google.protobuf.Timestamp
+	27, // 33: robotapi.RobotDealsResponse.deals:type_name -> robotapi.Deal
+	3,  // 34: robotapi.UserService.SignUp:input_type -> robotapi.UserParams
+	4,  // 35: robotapi.UserService.SignIn:input_type -> robotapi.SignInRequest
+	6,  // 36: robotapi.UserService.GetUser:input_type -> robotapi.GetUserRequest
+	3,  // 37: robotapi.UserService.UpdateUser:input_type -> robotapi.UserParams
+	15, // 38: robotapi.RobotService.ListRobots:input_type -> robotapi.ListRobotsRequest
+	16, // 39: robotapi.RobotService.ListDeletedRobots:input_type -> robotapi.ListDeletedRobotsRequest
+	18, // 40: robotapi.RobotService.Leaderboard:input_type -> robotapi.LeaderboardRequest
+	8,  // 41: robotapi.RobotService.CreateRobot:input_type -> robotapi.RobotParams
+	10, // 42: robotapi.RobotService.GetRobot:input_type -> robotapi.GetRobotRequest
+	11, // 43: robotapi.RobotService.EditRobot:input_type -> robotapi.EditRobotRequest
+	9,  // 44: robotapi.RobotService.DeleteRobot:input_type -> robotapi.RobotRequest
+	9,  // 45: robotapi.RobotService.RestoreRobot:input_type -> robotapi.RobotRequest
+	9,  // 46: robotapi.RobotService.PurgeRobot:input_type -> robotapi.RobotRequest
+	9,  // 47: robotapi.RobotService.ActivateRobot:input_type -> robotapi.RobotRequest
+	9,  // 48: robotapi.RobotService.DeactivateRobot:input_type -> robotapi.RobotRequest
+	9,  // 49: robotapi.RobotService.StopRobot:input_type -> robotapi.RobotRequest
+	12, // 50: robotapi.RobotService.FavouriteRobot:input_type -> robotapi.FavouriteRobotRequest
+	9,  // 51: robotapi.RobotService.UnfollowRobot:input_type -> robotapi.RobotRequest
+	11, // 52: robotapi.RobotService.CloneRobot:input_type -> robotapi.EditRobotRequest
+	9,  // 53: robotapi.RobotService.RobotTransitions:input_type -> robotapi.RobotRequest
+	9,  // 54: robotapi.RobotService.RobotVersions:input_type -> robotapi.RobotRequest
+	9,  // 55: robotapi.RobotService.RobotDeals:input_type -> robotapi.RobotRequest
+	20, // 56: robotapi.RobotService.RobotStats:input_type -> robotapi.RobotStatsRequest
+	9,  // 57: robotapi.RobotService.RobotShares:input_type -> robotapi.RobotRequest
+	13, // 58: robotapi.RobotService.ShareRobot:input_type -> robotapi.ShareRobotRequest
+	13, // 59: robotapi.RobotService.UnshareRobot:input_type -> robotapi.ShareRobotRequest
+	10, // 60: robotapi.RobotService.WatchRobot:input_type -> robotapi.GetRobotRequest
+	2,  // 61: robotapi.UserService.SignUp:output_type -> robotapi.User
+	5,  // 62: robotapi.UserService.SignIn:output_type -> robotapi.SignInResponse
+	2,  // 63: robotapi.UserService.GetUser:output_type -> robotapi.User
+	2,  // 64: robotapi.UserService.UpdateUser:output_type -> robotapi.User
+	17, // 65: robotapi.RobotService.ListRobots:output_type -> robotapi.ListRobotsResponse
+	17, // 66: robotapi.RobotService.ListDeletedRobots:output_type -> robotapi.ListRobotsResponse
+	19, // 67: robotapi.RobotService.Leaderboard:output_type -> robotapi.LeaderboardResponse
+	7,  // 68: robotapi.RobotService.CreateRobot:output_type -> robotapi.Robot
+	7,  // 69: robotapi.RobotService.GetRobot:output_type -> robotapi.Robot
+	7,  // 70: robotapi.RobotService.EditRobot:output_type -> robotapi.Robot
+	7,  // 71: robotapi.RobotService.DeleteRobot:output_type -> robotapi.Robot
+	7,  // 72: robotapi.RobotService.RestoreRobot:output_type -> robotapi.Robot
+	14, // 73: robotapi.RobotService.PurgeRobot:output_type -> robotapi.PurgeRobotResponse
+	7,  // 74: robotapi.RobotService.ActivateRobot:output_type -> robotapi.Robot
+	7,  // 75: robotapi.RobotService.DeactivateRobot:output_type -> robotapi.Robot
+	7,  // 76: robotapi.RobotService.StopRobot:output_type -> robotapi.Robot
+	7,  // 77: robotapi.RobotService.FavouriteRobot:output_type -> robotapi.Robot
+	7,  // 78: robotapi.RobotService.UnfollowRobot:output_type -> robotapi.Robot
+	7,  // 79: robotapi.RobotService.CloneRobot:output_type -> robotapi.Robot
+	24, // 80: robotapi.RobotService.RobotTransitions:output_type -> robotapi.RobotTransitionsResponse
+	26, // 81: robotapi.RobotService.RobotVersions:output_type -> robotapi.RobotVersionsResponse
+	28, // 82: robotapi.RobotService.RobotDeals:output_type -> robotapi.RobotDealsResponse
+	21, // 83: robotapi.RobotService.RobotStats:output_type -> robotapi.Stats
+	22, // 84: robotapi.RobotService.RobotShares:output_type -> robotapi.Shares
+	22, // 85: robotapi.RobotService.ShareRobot:output_type -> robotapi.Shares
+	22, // 86: robotapi.RobotService.UnshareRobot:output_type -> robotapi.Shares
+	7,  // 87: robotapi.RobotService.WatchRobot:output_type -> robotapi.Robot
+	61, // [61:88] is the sub-list for method output_type
+	34, // [34:61] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
+}
+
+func init() { file_pkg_robotapi_robotapi_proto_init() }
+func file_pkg_robotapi_robotapi_proto_init() {
+	if File_pkg_robotapi_robotapi_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_robotapi_robotapi_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Problem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FieldError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*User); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignInRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignInResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Robot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RobotParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RobotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRobotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EditRobotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FavouriteRobotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShareRobotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PurgeRobotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRobotsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeletedRobotsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRobotsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaderboardRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaderboardResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RobotStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Stats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Shares); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Transition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RobotTransitionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Version); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RobotVersionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Deal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_robotapi_robotapi_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RobotDealsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_robotapi_robotapi_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_pkg_robotapi_robotapi_proto_goTypes,
+		DependencyIndexes: file_pkg_robotapi_robotapi_proto_depIdxs,
+		MessageInfos:      file_pkg_robotapi_robotapi_proto_msgTypes,
+	}.Build()
+	File_pkg_robotapi_robotapi_proto = out.File
+	file_pkg_robotapi_robotapi_proto_rawDesc = nil
+	file_pkg_robotapi_robotapi_proto_goTypes = nil
+	file_pkg_robotapi_robotapi_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type UserServiceClient interface {
+	// SignUp регистрирует пользователя.
+	SignUp(ctx context.Context, in *UserParams, opts ...grpc.CallOption) (*User, error)
+	// SignIn открывает сессию пользователя и возвращает ее токен.
+	SignIn(ctx context.Context, in *SignInRequest, opts ...grpc.CallOption) (*SignInResponse, error)
+	// GetUser возвращает пользователя текущей сессии.
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	// UpdateUser заменяет данные пользователя текущей сессии. Запрос передает version прочитанного пользователя,
+	// если пользователя с тех пор изменили, возвращается код Aborted.
+	UpdateUser(ctx context.Context, in *UserParams, opts ...grpc.CallOption) (*User, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) SignUp(ctx context.Context, in *UserParams, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/robotapi.UserService/SignUp", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SignIn(ctx context.Context, in *SignInRequest, opts ...grpc.CallOption) (*SignInResponse, error) {
+	out := new(SignInResponse)
+	err := c.cc.Invoke(ctx, "/robotapi.UserService/SignIn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/robotapi.UserService/GetUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateUser(ctx context.Context, in *UserParams, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/robotapi.UserService/UpdateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+type UserServiceServer interface {
+	// SignUp регистрирует пользователя.
+	SignUp(context.Context, *UserParams) (*User, error)
+	// SignIn открывает сессию пользователя и возвращает ее токен.
+	SignIn(context.Context, *SignInRequest) (*SignInResponse, error)
+	// GetUser возвращает пользователя текущей сессии.
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	// UpdateUser заменяет данные пользователя текущей сессии. Запрос передает version прочитанного пользователя,
+	// если пользователя с тех пор изменили, возвращается код Aborted.
+	UpdateUser(context.Context, *UserParams) (*User, error)
+}
+
+// UnimplementedUserServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedUserServiceServer struct {
+}
+
+func (*UnimplementedUserServiceServer) SignUp(context.Context, *UserParams) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignUp not implemented")
+}
+func (*UnimplementedUserServiceServer) SignIn(context.Context, *SignInRequest) (*SignInResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignIn not implemented")
+}
+func (*UnimplementedUserServiceServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (*UnimplementedUserServiceServer) UpdateUser(context.Context, *UserParams) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+
+func RegisterUserServiceServer(s *grpc.Server, srv UserServiceServer) {
+	s.RegisterService(&_UserService_serviceDesc, srv)
+}
+
+func _UserService_SignUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SignUp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.UserService/SignUp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SignUp(ctx, req.(*UserParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SignIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignInRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SignIn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.UserService/SignIn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SignIn(ctx, req.(*SignInRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.UserService/GetUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.UserService/UpdateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateUser(ctx, req.(*UserParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _UserService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "robotapi.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignUp",
+			Handler:    _UserService_SignUp_Handler,
+		},
+		{
+			MethodName: "SignIn",
+			Handler:    _UserService_SignIn_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UserService_GetUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _UserService_UpdateUser_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/robotapi/robotapi.proto",
+}
+
+// RobotServiceClient is the client API for RobotService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RobotServiceClient interface {
+	// ListRobots возвращает страницу роботов, которых видит пользователь.
+	ListRobots(ctx context.Context, in *ListRobotsRequest, opts ...grpc.CallOption) (*ListRobotsResponse, error)
+	// ListDeletedRobots возвращает корзину пользователя.
+	ListDeletedRobots(ctx context.Context, in *ListDeletedRobotsRequest, opts ...grpc.CallOption) (*ListRobotsResponse, error)
+	// Leaderboard возвращает рейтинг публичных роботов.
+	Leaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	// CreateRobot создает робота пользователя.
+	CreateRobot(ctx context.Context, in *RobotParams, opts ...grpc.CallOption) (*Robot, error)
+	// GetRobot возвращает робота, которого видит пользователь.
+	GetRobot(ctx context.Context, in *GetRobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// EditRobot меняет заданные параметры робота и возвращает его новую версию. Запрос передает revision
+	// прочитанного робота, если робота с тех пор изменили, возвращается код Aborted.
+	EditRobot(ctx context.Context, in *EditRobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// DeleteRobot помещает робота в корзину, торгующий робот сначала останавливается.
+	DeleteRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// RestoreRobot возвращает робота из корзины.
+	RestoreRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// PurgeRobot окончательно удаляет робота из корзины.
+	PurgeRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*PurgeRobotResponse, error)
+	// ActivateRobot запускает робота.
+	ActivateRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// DeactivateRobot приостанавливает робота.
+	DeactivateRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// StopRobot завершает работу робота.
+	StopRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// FavouriteRobot добавляет копию робота в избранное пользователя и возвращает копию.
+	FavouriteRobot(ctx context.Context, in *FavouriteRobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// UnfollowRobot отключает повторение родителя у робота.
+	UnfollowRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// CloneRobot создает копию робота, заданные параметры переопределяют поля копии.
+	CloneRobot(ctx context.Context, in *EditRobotRequest, opts ...grpc.CallOption) (*Robot, error)
+	// RobotTransitions возвращает историю переходов робота.
+	RobotTransitions(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*RobotTransitionsResponse, error)
+	// RobotVersions возвращает историю параметров робота.
+	RobotVersions(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*RobotVersionsResponse, error)
+	// RobotDeals возвращает сделки робота.
+	RobotDeals(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*RobotDealsResponse, error)
+	// RobotStats возвращает статистику робота за период.
+	RobotStats(ctx context.Context, in *RobotStatsRequest, opts ...grpc.CallOption) (*Stats, error)
+	// RobotShares возвращает видимость робота, ссылку для доступа и пользователей, которым робот открыт.
+	RobotShares(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Shares, error)
+	// ShareRobot открывает робота пользователю.
+	ShareRobot(ctx context.Context, in *ShareRobotRequest, opts ...grpc.CallOption) (*Shares, error)
+	// UnshareRobot закрывает робота от пользователя.
+	UnshareRobot(ctx context.Context, in *ShareRobotRequest, opts ...grpc.CallOption) (*Shares, error)
+	// WatchRobot отправляет робота сразу и после каждого его изменения. Поток завершается с кодом NotFound,
+	// когда пользователь перестает видеть робота.
+	WatchRobot(ctx context.Context, in *GetRobotRequest, opts ...grpc.CallOption) (RobotService_WatchRobotClient, error)
+}
+
+type robotServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRobotServiceClient(cc grpc.ClientConnInterface) RobotServiceClient {
+	return &robotServiceClient{cc}
+}
+
+func (c *robotServiceClient) ListRobots(ctx context.Context, in *ListRobotsRequest, opts ...grpc.CallOption) (*ListRobotsResponse, error) {
+	out := new(ListRobotsResponse)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/ListRobots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) ListDeletedRobots(ctx context.Context, in *ListDeletedRobotsRequest, opts ...grpc.CallOption) (*ListRobotsResponse, error) {
+	out := new(ListRobotsResponse)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/ListDeletedRobots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) Leaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/Leaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) CreateRobot(ctx context.Context, in *RobotParams, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/CreateRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) GetRobot(ctx context.Context, in *GetRobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/GetRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) EditRobot(ctx context.Context, in *EditRobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/EditRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) DeleteRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/DeleteRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) RestoreRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/RestoreRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) PurgeRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*PurgeRobotResponse, error) {
+	out := new(PurgeRobotResponse)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/PurgeRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) ActivateRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/ActivateRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) DeactivateRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/DeactivateRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) StopRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/StopRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) FavouriteRobot(ctx context.Context, in *FavouriteRobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/FavouriteRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) UnfollowRobot(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/UnfollowRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) CloneRobot(ctx context.Context, in *EditRobotRequest, opts ...grpc.CallOption) (*Robot, error) {
+	out := new(Robot)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/CloneRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) RobotTransitions(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*RobotTransitionsResponse, error) {
+	out := new(RobotTransitionsResponse)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/RobotTransitions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) RobotVersions(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*RobotVersionsResponse, error) {
+	out := new(RobotVersionsResponse)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/RobotVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) RobotDeals(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*RobotDealsResponse, error) {
+	out := new(RobotDealsResponse)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/RobotDeals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) RobotStats(ctx context.Context, in *RobotStatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/RobotStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) RobotShares(ctx context.Context, in *RobotRequest, opts ...grpc.CallOption) (*Shares, error) {
+	out := new(Shares)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/RobotShares", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) ShareRobot(ctx context.Context, in *ShareRobotRequest, opts ...grpc.CallOption) (*Shares, error) {
+	out := new(Shares)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/ShareRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) UnshareRobot(ctx context.Context, in *ShareRobotRequest, opts ...grpc.CallOption) (*Shares, error) {
+	out := new(Shares)
+	err := c.cc.Invoke(ctx, "/robotapi.RobotService/UnshareRobot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotServiceClient) WatchRobot(ctx context.Context, in *GetRobotRequest, opts ...grpc.CallOption) (RobotService_WatchRobotClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_RobotService_serviceDesc.Streams[0], "/robotapi.RobotService/WatchRobot", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &robotServiceWatchRobotClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RobotService_WatchRobotClient interface {
+	Recv() (*Robot, error)
+	grpc.ClientStream
+}
+
+type robotServiceWatchRobotClient struct {
+	grpc.ClientStream
+}
+
+func (x *robotServiceWatchRobotClient) Recv() (*Robot, error) {
+	m := new(Robot)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RobotServiceServer is the server API for RobotService service.
+type RobotServiceServer interface {
+	// ListRobots возвращает страницу роботов, которых видит пользователь.
+	ListRobots(context.Context, *ListRobotsRequest) (*ListRobotsResponse, error)
+	// ListDeletedRobots возвращает корзину пользователя.
+	ListDeletedRobots(context.Context, *ListDeletedRobotsRequest) (*ListRobotsResponse, error)
+	// Leaderboard возвращает рейтинг публичных роботов.
+	Leaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error)
+	// CreateRobot создает робота пользователя.
+	CreateRobot(context.Context, *RobotParams) (*Robot, error)
+	// GetRobot возвращает робота, которого видит пользователь.
+	GetRobot(context.Context, *GetRobotRequest) (*Robot, error)
+	// EditRobot меняет заданные параметры робота и возвращает его новую версию. Запрос передает revision
+	// прочитанного робота, если робота с тех пор изменили, возвращается код Aborted.
+	EditRobot(context.Context, *EditRobotRequest) (*Robot, error)
+	// DeleteRobot помещает робота в корзину, торгующий робот сначала останавливается.
+	DeleteRobot(context.Context, *RobotRequest) (*Robot, error)
+	// RestoreRobot возвращает робота из корзины.
+	RestoreRobot(context.Context, *RobotRequest) (*Robot, error)
+	// PurgeRobot окончательно удаляет робота из корзины.
+	PurgeRobot(context.Context, *RobotRequest) (*PurgeRobotResponse, error)
+	// ActivateRobot запускает робота.
+	ActivateRobot(context.Context, *RobotRequest) (*Robot, error)
+	// DeactivateRobot приостанавливает робота.
+	DeactivateRobot(context.Context, *RobotRequest) (*Robot, error)
+	// StopRobot завершает работу робота.
+	StopRobot(context.Context, *RobotRequest) (*Robot, error)
+	// FavouriteRobot добавляет копию робота в избранное пользователя и возвращает копию.
+	FavouriteRobot(context.Context, *FavouriteRobotRequest) (*Robot, error)
+	// UnfollowRobot отключает повторение родителя у робота.
+	UnfollowRobot(context.Context, *RobotRequest) (*Robot, error)
+	// CloneRobot создает копию робота, заданные параметры переопределяют поля копии.
+	CloneRobot(context.Context, *EditRobotRequest) (*Robot, error)
+	// RobotTransitions возвращает историю переходов робота.
+	RobotTransitions(context.Context, *RobotRequest) (*RobotTransitionsResponse, error)
+	// RobotVersions возвращает историю параметров робота.
+	RobotVersions(context.Context, *RobotRequest) (*RobotVersionsResponse, error)
+	// RobotDeals возвращает сделки робота.
+	RobotDeals(context.Context, *RobotRequest) (*RobotDealsResponse, error)
+	// RobotStats возвращает статистику робота за период.
+	RobotStats(context.Context, *RobotStatsRequest) (*Stats, error)
+	// RobotShares возвращает видимость робота, ссылку для доступа и пользователей, которым робот открыт.
+	RobotShares(context.Context, *RobotRequest) (*Shares, error)
+	// ShareRobot открывает робота пользователю.
+	ShareRobot(context.Context, *ShareRobotRequest) (*Shares, error)
+	// UnshareRobot закрывает робота от пользователя.
+	UnshareRobot(context.Context, *ShareRobotRequest) (*Shares, error)
+	// WatchRobot отправляет робота сразу и после каждого его изменения. Поток завершается с кодом NotFound,
+	// когда пользователь перестает видеть робота.
+	WatchRobot(*GetRobotRequest, RobotService_WatchRobotServer) error
+}
+
+// UnimplementedRobotServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedRobotServiceServer struct {
+}
+
+func (*UnimplementedRobotServiceServer) ListRobots(context.Context, *ListRobotsRequest) (*ListRobotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRobots not implemented")
+}
+func (*UnimplementedRobotServiceServer) ListDeletedRobots(context.Context, *ListDeletedRobotsRequest) (*ListRobotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedRobots not implemented")
+}
+func (*UnimplementedRobotServiceServer) Leaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Leaderboard not implemented")
+}
+func (*UnimplementedRobotServiceServer) CreateRobot(context.Context, *RobotParams) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) GetRobot(context.Context, *GetRobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) EditRobot(context.Context, *EditRobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) DeleteRobot(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) RestoreRobot(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) PurgeRobot(context.Context, *RobotRequest) (*PurgeRobotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) ActivateRobot(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivateRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) DeactivateRobot(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) StopRobot(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) FavouriteRobot(context.Context, *FavouriteRobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FavouriteRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) UnfollowRobot(context.Context, *RobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfollowRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) CloneRobot(context.Context, *EditRobotRequest) (*Robot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) RobotTransitions(context.Context, *RobotRequest) (*RobotTransitionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RobotTransitions not implemented")
+}
+func (*UnimplementedRobotServiceServer) RobotVersions(context.Context, *RobotRequest) (*RobotVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RobotVersions not implemented")
+}
+func (*UnimplementedRobotServiceServer) RobotDeals(context.Context, *RobotRequest) (*RobotDealsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RobotDeals not implemented")
+}
+func (*UnimplementedRobotServiceServer) RobotStats(context.Context, *RobotStatsRequest) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RobotStats not implemented")
+}
+func (*UnimplementedRobotServiceServer) RobotShares(context.Context, *RobotRequest) (*Shares, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RobotShares not implemented")
+}
+func (*UnimplementedRobotServiceServer) ShareRobot(context.Context, *ShareRobotRequest) (*Shares, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) UnshareRobot(context.Context, *ShareRobotRequest) (*Shares, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnshareRobot not implemented")
+}
+func (*UnimplementedRobotServiceServer) WatchRobot(*GetRobotRequest, RobotService_WatchRobotServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRobot not implemented")
+}
+
+func RegisterRobotServiceServer(s *grpc.Server, srv RobotServiceServer) {
+	s.RegisterService(&_RobotService_serviceDesc, srv)
+}
+
+func _RobotService_ListRobots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRobotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).ListRobots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/ListRobots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).ListRobots(ctx, req.(*ListRobotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_ListDeletedRobots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedRobotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).ListDeletedRobots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/ListDeletedRobots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).ListDeletedRobots(ctx, req.(*ListDeletedRobotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_Leaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).Leaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/Leaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).Leaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_CreateRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).CreateRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/CreateRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).CreateRobot(ctx, req.(*RobotParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_GetRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).GetRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/GetRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).GetRobot(ctx, req.(*GetRobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_EditRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditRobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).EditRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/EditRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).EditRobot(ctx, req.(*EditRobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_DeleteRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).DeleteRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/DeleteRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).DeleteRobot(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_RestoreRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).RestoreRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/RestoreRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).RestoreRobot(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_PurgeRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).PurgeRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/PurgeRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).PurgeRobot(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_ActivateRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).ActivateRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/ActivateRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).ActivateRobot(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_DeactivateRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).DeactivateRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/DeactivateRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).DeactivateRobot(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_StopRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).StopRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/StopRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).StopRobot(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_FavouriteRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FavouriteRobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).FavouriteRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/FavouriteRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).FavouriteRobot(ctx, req.(*FavouriteRobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_UnfollowRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).UnfollowRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/UnfollowRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).UnfollowRobot(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_CloneRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditRobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).CloneRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/CloneRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).CloneRobot(ctx, req.(*EditRobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_RobotTransitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).RobotTransitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/RobotTransitions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).RobotTransitions(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_RobotVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).RobotVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/RobotVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).RobotVersions(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_RobotDeals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).RobotDeals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/RobotDeals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).RobotDeals(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_RobotStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).RobotStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/RobotStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).RobotStats(ctx, req.(*RobotStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_RobotShares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).RobotShares(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/RobotShares",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).RobotShares(ctx, req.(*RobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_ShareRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareRobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).ShareRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/ShareRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).ShareRobot(ctx, req.(*ShareRobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_UnshareRobot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareRobotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotServiceServer).UnshareRobot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/robotapi.RobotService/UnshareRobot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotServiceServer).UnshareRobot(ctx, req.(*ShareRobotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotService_WatchRobot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetRobotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RobotServiceServer).WatchRobot(m, &robotServiceWatchRobotServer{stream})
+}
+
+type RobotService_WatchRobotServer interface {
+	Send(*Robot) error
+	grpc.ServerStream
+}
+
+type robotServiceWatchRobotServer struct {
+	grpc.ServerStream
+}
+
+func (x *robotServiceWatchRobotServer) Send(m *Robot) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _RobotService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "robotapi.RobotService",
+	HandlerType: (*RobotServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRobots",
+			Handler:    _RobotService_ListRobots_Handler,
+		},
+		{
+			MethodName: "ListDeletedRobots",
+			Handler:    _RobotService_ListDeletedRobots_Handler,
+		},
+		{
+			MethodName: "Leaderboard",
+			Handler:    _RobotService_Leaderboard_Handler,
+		},
+		{
+			MethodName: "CreateRobot",
+			Handler:    _RobotService_CreateRobot_Handler,
+		},
+		{
+			MethodName: "GetRobot",
+			Handler:    _RobotService_GetRobot_Handler,
+		},
+		{
+			MethodName: "EditRobot",
+			Handler:    _RobotService_EditRobot_Handler,
+		},
+		{
+			MethodName: "DeleteRobot",
+			Handler:    _RobotService_DeleteRobot_Handler,
+		},
+		{
+			MethodName: "RestoreRobot",
+			Handler:    _RobotService_RestoreRobot_Handler,
+		},
+		{
+			MethodName: "PurgeRobot",
+			Handler:    _RobotService_PurgeRobot_Handler,
+		},
+		{
+			MethodName: "ActivateRobot",
+			Handler:    _RobotService_ActivateRobot_Handler,
+		},
+		{
+			MethodName: "DeactivateRobot",
+			Handler:    _RobotService_DeactivateRobot_Handler,
+		},
+		{
+			MethodName: "StopRobot",
+			Handler:    _RobotService_StopRobot_Handler,
+		},
+		{
+			MethodName: "FavouriteRobot",
+			Handler:    _RobotService_FavouriteRobot_Handler,
+		},
+		{
+			MethodName: "UnfollowRobot",
+			Handler:    _RobotService_UnfollowRobot_Handler,
+		},
+		{
+			MethodName: "CloneRobot",
+			Handler:    _RobotService_CloneRobot_Handler,
+		},
+		{
+			MethodName: "RobotTransitions",
+			Handler:    _RobotService_RobotTransitions_Handler,
+		},
+		{
+			MethodName: "RobotVersions",
+			Handler:    _RobotService_RobotVersions_Handler,
+		},
+		{
+			MethodName: "RobotDeals",
+			Handler:    _RobotService_RobotDeals_Handler,
+		},
+		{
+			MethodName: "RobotStats",
+			Handler:    _RobotService_RobotStats_Handler,
+		},
+		{
+			MethodName: "RobotShares",
+			Handler:    _RobotService_RobotShares_Handler,
+		},
+		{
+			MethodName: "ShareRobot",
+			Handler:    _RobotService_ShareRobot_Handler,
+		},
+		{
+			MethodName: "UnshareRobot",
+			Handler:    _RobotService_UnshareRobot_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRobot",
+			Handler:       _RobotService_WatchRobot_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/robotapi/robotapi.proto",
+}
@@ -38,4 +38,10 @@ func init() { //nolint:gochecknoinits
 			return t.ViewHTML()
 		},
 	}).ParseFiles(filePrefix+"/robotdetail.html", filePrefix+"/base.html"))
+
+	Templates["leaderboard"] = template.Must(template.New("").Funcs(template.FuncMap{
+		"place": func(i int) int {
+			return i + 1
+		},
+	}).ParseFiles(filePrefix+"/leaderboard.html", filePrefix+"/base.html"))
 }